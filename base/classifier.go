@@ -1,9 +1,14 @@
 package base
 
 import (
+	"errors"
 	"github.com/gonum/matrix/mat64"
 )
 
+// NoTrainingDataError is returned when a model or transformer is
+// used before Fit().
+var NoTrainingDataError = errors.New("you need to Fit() before you can Predict() or Transform()")
+
 // Classifier implementations predict categorical class labels.
 type Classifier interface {
 	// Takes a set of Instances, copies the class Attribute
@@ -18,6 +23,21 @@ type Classifier interface {
 	String() string
 }
 
+// OnlineClassifier implementations can also be trained incrementally,
+// one batch of Instances at a time, so the training data never needs
+// to be held in memory at once. Calling Fit discards anything learned
+// by PartialFit, but calling PartialFit after Fit carries on training
+// the fitted model.
+type OnlineClassifier interface {
+	// Takes a batch of Instances and updates the Classifier's
+	// internal structures with them. Since a single batch
+	// may not contain every class, classes lists all of the
+	// class values which can appear: it's required on the
+	// first call and ignored afterwards.
+	PartialFit(batch FixedDataGrid, classes []string) error
+	String() string
+}
+
 // BaseClassifier stores options common to every classifier.
 type BaseClassifier struct {
 	TrainingData *DataGrid
@@ -119,58 +119,62 @@ func ParseCSVSniffAttributeNames(filepath string, hasHeaders bool) []string {
 
 }
 
-// ParseCSVSniffAttributeTypes returns a slice of appropriately-typed Attributes.
-//
-// The type of a given attribute is determined by looking at the first data row
-// of the CSV.
-func ParseCSVSniffAttributeTypes(filepath string, hasHeaders bool) []Attribute {
-	var attrs []Attribute
-	// Open file
+// csvSampleFile returns the names of the columns of a CSV file, and
+// up to csvSampleRows rows of data.
+func csvSampleFile(filepath string, hasHeaders bool) ([]string, [][]string, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
 	}
 	defer file.Close()
-	// Create the CSV reader
+
 	reader := csv.NewReader(file)
-	if hasHeaders {
-		// Skip the headers
-		_, err := reader.Read()
-		if err != nil {
-			panic(err)
+	var names []string
+	records := make([][]string, 0)
+	for len(records) < csvSampleRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
 		}
+		for i, v := range record {
+			record[i] = strings.TrimSpace(v)
+		}
+		if names == nil && hasHeaders {
+			names = record
+			continue
+		}
+		records = append(records, record)
 	}
-	// Read the first line of the file
-	columns, err := reader.Read()
-	if err != nil {
-		panic(err)
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("No rows to read")
 	}
-
-	for _, entry := range columns {
-		// Match the Attribute type with regular expressions
-		entry = strings.Trim(entry, " ")
-		matched, err := regexp.MatchString("^[-+]?[0-9]*\\.?[0-9]+([eE][-+]?[0-9]+)?$", entry)
-		if err != nil {
-			panic(err)
-		}
-		if matched {
-			attrs = append(attrs, NewFloatAttribute(""))
-		} else {
-			attrs = append(attrs, new(CategoricalAttribute))
+	if names == nil {
+		names = make([]string, len(records[0]))
+		for i := range names {
+			names[i] = fmt.Sprintf("%d", i)
 		}
 	}
+	return names, records, nil
+}
 
-	// Estimate file precision
-	maxP, err := ParseCSVEstimateFilePrecision(filepath)
+// ParseCSVSniffAttributeTypes returns a slice of appropriately-typed Attributes.
+//
+// The type of a given attribute is inferred from a sample of the data
+// rows of the CSV (see ParseCSVToInstancesFromReader).
+func ParseCSVSniffAttributeTypes(filepath string, hasHeaders bool) []Attribute {
+	names, records, err := csvSampleFile(filepath, hasHeaders)
+	if err != nil {
+		panic(err)
+	}
+	attrs, err := csvInferAttributes(names, records, CSVOptions{})
 	if err != nil {
 		panic(err)
 	}
 	for _, a := range attrs {
-		if f, ok := a.(*FloatAttribute); ok {
-			f.Precision = maxP
-		}
+		a.SetName("")
 	}
-
 	return attrs
 }
 
@@ -204,7 +208,11 @@ func ParseCSVBuildInstancesFromReader(r io.Reader, attrs []Attribute, hasHeader
 			}
 		}
 		for i, v := range record {
-			u.Set(specs[i], rowCounter, specs[i].attr.GetSysValFromString(strings.TrimSpace(v)))
+			val, err := csvSysVal(specs[i].attr, strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("Error at line %d (error %s)", rowCounter, err)
+			}
+			u.Set(specs[i], rowCounter, val)
 		}
 		rowCounter++
 	}
@@ -213,7 +221,7 @@ func ParseCSVBuildInstancesFromReader(r io.Reader, attrs []Attribute, hasHeader
 }
 
 // ParseCSVToInstances reads the CSV file given by filepath and returns
-// the read Instances.
+// the read Instances (see ParseCSVToInstancesFromReader).
 func ParseCSVToInstances(filepath string, hasHeaders bool) (instances *DenseInstances, err error) {
 
 	// Open the file
@@ -223,35 +231,7 @@ func ParseCSVToInstances(filepath string, hasHeaders bool) (instances *DenseInst
 	}
 	defer f.Close()
 
-	// Read the number of rows in the file
-	rowCount, err := ParseCSVGetRows(filepath)
-	if err != nil {
-		return nil, err
-	}
-
-	if hasHeaders {
-		rowCount--
-	}
-
-	// Read the row headers
-	attrs := ParseCSVGetAttributes(filepath, hasHeaders)
-	specs := make([]AttributeSpec, len(attrs))
-	// Allocate the Instances to return
-	instances = NewDenseInstances()
-	for i, a := range attrs {
-		spec := instances.AddAttribute(a)
-		specs[i] = spec
-	}
-	instances.Extend(rowCount)
-
-	err = ParseCSVBuildInstancesFromReader(f, attrs, hasHeaders, instances)
-	if err != nil {
-		return nil, err
-	}
-
-	instances.AddClassAttribute(attrs[len(attrs)-1])
-
-	return instances, nil
+	return ParseCSVToInstancesFromReader(f, CSVOptions{HasHeaders: hasHeaders})
 }
 
 // ParseUtilsMatchAttrs tries to match the set of Attributes read from one file with
@@ -308,8 +288,9 @@ func ParseCSVToTemplatedInstances(filepath string, hasHeaders bool, template *De
 
 // ParseCSVToInstancesWithAttributeGroups reads the CSV file given by filepath,
 // and returns the read DenseInstances, but also makes sure to group any Attributes
-// specified in the first argument and also any class Attributes specified in the second
-func ParseCSVToInstancesWithAttributeGroups(filepath string, attrGroups, classAttrGroups map[string]string, attrOverrides map[int]Attribute, hasHeaders bool) (instances *DenseInstances, err error) {
+// specified in the first argument and also any class Attributes specified in the second.
+// The schema can set the type of columns (and make them class Attributes) by name.
+func ParseCSVToInstancesWithAttributeGroups(filepath string, attrGroups, classAttrGroups map[string]string, schema CSVSchema, hasHeaders bool) (instances *DenseInstances, err error) {
 
 	// Open file
 	f, err := os.Open(filepath)
@@ -318,85 +299,60 @@ func ParseCSVToInstancesWithAttributeGroups(filepath string, attrGroups, classAt
 	}
 	defer f.Close()
 
-	// Read row count
-	rowCount, err := ParseCSVGetRows(filepath)
-	if err != nil {
-		return nil, err
-	}
-
-	// Read the row headers
-	attrs := ParseCSVGetAttributes(filepath, hasHeaders)
-	for i := range attrs {
-		if a, ok := attrOverrides[i]; ok {
-			attrs[i] = a
+	layout := func(instances *DenseInstances, names []string, attrs []Attribute) ([]csvColumn, error) {
+		//
+		// Create all AttributeGroups
+		agsToCreate := make(map[string]int)
+		combinedAgs := make(map[string]string)
+		for a := range attrGroups {
+			agsToCreate[attrGroups[a]] = 0
+			combinedAgs[a] = attrGroups[a]
+		}
+		for a := range classAttrGroups {
+			agsToCreate[classAttrGroups[a]] = 8
+			combinedAgs[a] = classAttrGroups[a]
 		}
-	}
-
-	specs := make([]AttributeSpec, len(attrs))
-	// Allocate the Instances to return
-	instances = NewDenseInstances()
-
-	//
-	// Create all AttributeGroups
-	agsToCreate := make(map[string]int)
-	combinedAgs := make(map[string]string)
-	for a := range attrGroups {
-		agsToCreate[attrGroups[a]] = 0
-		combinedAgs[a] = attrGroups[a]
-	}
-	for a := range classAttrGroups {
-		agsToCreate[classAttrGroups[a]] = 8
-		combinedAgs[a] = classAttrGroups[a]
-	}
 
-	// Decide the sizes
-	for _, a := range attrs {
-		if ag, ok := combinedAgs[a.GetName()]; ok {
-			if _, ok := a.(*BinaryAttribute); ok {
-				agsToCreate[ag] = 0
-			} else {
-				agsToCreate[ag] = 8
+		// Decide the sizes
+		for _, a := range attrs {
+			if ag, ok := combinedAgs[a.GetName()]; ok {
+				if _, ok := a.(*BinaryAttribute); ok {
+					agsToCreate[ag] = 0
+				} else {
+					agsToCreate[ag] = 8
+				}
 			}
 		}
-	}
 
-	// Create them
-	for i := range agsToCreate {
-		size := agsToCreate[i]
-		err = instances.CreateAttributeGroup(i, size)
-		if err != nil {
-			panic(err)
+		// Create them
+		for i := range agsToCreate {
+			size := agsToCreate[i]
+			if err := instances.CreateAttributeGroup(i, size); err != nil {
+				return nil, err
+			}
 		}
-	}
 
-	// Add the Attributes to them
-	for i, a := range attrs {
-		var spec AttributeSpec
-		if ag, ok := combinedAgs[a.GetName()]; ok {
-			spec, err = instances.AddAttributeToAttributeGroup(a, ag)
-			if err != nil {
-				panic(err)
+		// Add the Attributes to them
+		columns := make([]csvColumn, len(attrs))
+		for i, a := range attrs {
+			var spec AttributeSpec
+			if ag, ok := combinedAgs[a.GetName()]; ok {
+				spec, err = instances.AddAttributeToAttributeGroup(a, ag)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				spec = instances.AddAttribute(a)
 			}
-			specs[i] = spec
-		} else {
-			spec = instances.AddAttribute(a)
-		}
-		specs[i] = spec
-		if _, ok := classAttrGroups[a.GetName()]; ok {
-			err = instances.AddClassAttribute(a)
-			if err != nil {
-				panic(err)
+			columns[i] = csvColumn{i, a, spec}
+			if _, ok := classAttrGroups[a.GetName()]; ok || schema[a.GetName()].Class {
+				if err := instances.AddClassAttribute(a); err != nil {
+					return nil, err
+				}
 			}
 		}
+		return columns, nil
 	}
-	// Allocate
-	instances.Extend(rowCount)
-
-	err = ParseCSVBuildInstancesFromReader(f, attrs, hasHeaders, instances)
-	if err != nil {
-		return nil, err
-	}
-
-	return instances, nil
 
+	return parseCSVFromReader(f, CSVOptions{HasHeaders: hasHeaders, Schema: schema}, layout)
 }
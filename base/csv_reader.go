@@ -0,0 +1,374 @@
+package base
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// csvFloatRegexp matches values which are read as FloatAttributes.
+var csvFloatRegexp = regexp.MustCompile("^[-+]?[0-9]*\\.?[0-9]+([eE][-+]?[0-9]+)?$")
+
+// csvIntegerRegexp matches numeric values which are whole numbers.
+var csvIntegerRegexp = regexp.MustCompile("^[-+]?[0-9]+$")
+
+// csvSampleRows is the number of data rows read ahead to infer the type
+// of each column, unless CSVOptions.SampleRows says otherwise.
+const csvSampleRows = 1000
+
+// csvIsMissing returns whether a value marks a missing value, which
+// doesn't count against a column being numeric.
+func csvIsMissing(value string) bool {
+	switch strings.ToUpper(value) {
+	case "", "?", "NA", "N/A", "NAN", "NULL", "NONE":
+		return true
+	}
+	return false
+}
+
+// CSVColumn overrides what's inferred about a column of CSV data.
+type CSVColumn struct {
+	// Read the column into this Attribute (which is renamed after
+	// the column) instead of inferring its type.
+	Attribute Attribute
+	// Make the column a class Attribute.
+	Class bool
+}
+
+// CSVSchema describes columns of CSV data by name. Without headers,
+// columns are named after their position, starting from "0".
+type CSVSchema map[string]CSVColumn
+
+// CSVOptions controls how ParseCSVToInstancesFromReader reads its input.
+// The zero value reads comma-separated values without a header row.
+type CSVOptions struct {
+	// Separates the values on each row (',' if zero).
+	Delimiter rune
+	// Rows which start with this character are ignored (none if zero).
+	Comment rune
+	// Whether the first row names the columns. Otherwise, they're
+	// named after their position, starting from 0.
+	HasHeaders bool
+	// Allow quotes in unquoted values, and unescaped quotes in quoted
+	// ones.
+	LazyQuotes bool
+	// Positions of columns to leave out, starting from 0.
+	SkipColumns []int
+	// Names of columns to leave out (needs HasHeaders).
+	SkipColumnNames []string
+	// Forces the type of columns, and chooses the class Attributes.
+	// If no column is a class, the last column which isn't skipped
+	// becomes the class Attribute.
+	Schema CSVSchema
+	// Number of rows read to infer the type of each column (1000 if
+	// zero).
+	SampleRows int
+	// Read whole-number columns with at most this many different
+	// values (which repeat) as CategoricalAttributes. 0 disables this.
+	MaxCategories int
+	// Read columns whose values are all 0 or 1 as BinaryAttributes.
+	DetectBinary bool
+}
+
+// csvColumn is a column of the input which is kept.
+type csvColumn struct {
+	index int
+	attr  Attribute
+	spec  AttributeSpec
+}
+
+// csvKeptColumns returns the position of each column which isn't
+// skipped.
+func csvKeptColumns(names []string, options CSVOptions) ([]int, error) {
+	skip := make(map[int]bool)
+	for _, i := range options.SkipColumns {
+		if i < 0 || i >= len(names) {
+			return nil, fmt.Errorf("Can't skip column %d of %d", i, len(names))
+		}
+		skip[i] = true
+	}
+	for _, name := range options.SkipColumnNames {
+		if !options.HasHeaders {
+			return nil, fmt.Errorf("Columns can only be skipped by name with headers")
+		}
+		found := false
+		for i, n := range names {
+			if n == name {
+				skip[i] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Can't skip column %s: not found", name)
+		}
+	}
+	ret := make([]int, 0, len(names))
+	for i := range names {
+		if !skip[i] {
+			ret = append(ret, i)
+		}
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("Every column was skipped")
+	}
+	return ret, nil
+}
+
+// csvValuePrecision returns the number of digits after the decimal
+// point of a value matched by csvFloatRegexp.
+func csvValuePrecision(value string) int {
+	if e := strings.IndexAny(value, "eE"); e != -1 {
+		value = value[:e]
+	}
+	if p := strings.Index(value, "."); p != -1 {
+		return len(value) - p - 1
+	}
+	return 0
+}
+
+// csvInferAttribute returns an unnamed Attribute suited to a sample
+// of the values of a column.
+func csvInferAttribute(values []string, options CSVOptions) Attribute {
+	present := 0
+	integers := true
+	binary := true
+	precision := 0
+	distinct := make(map[string]bool)
+	for _, v := range values {
+		if csvIsMissing(v) {
+			binary = false
+			continue
+		}
+		if !csvFloatRegexp.MatchString(v) {
+			return NewCategoricalAttribute()
+		}
+		present++
+		integers = integers && csvIntegerRegexp.MatchString(v)
+		binary = binary && (v == "0" || v == "1")
+		if p := csvValuePrecision(v); p > precision {
+			precision = p
+		}
+		distinct[v] = true
+	}
+	switch {
+	case present == 0:
+		return NewCategoricalAttribute()
+	case options.DetectBinary && binary:
+		return NewBinaryAttribute("")
+	case integers && len(distinct) <= options.MaxCategories && len(distinct) < present:
+		return NewCategoricalAttribute()
+	}
+	ret := NewFloatAttribute("")
+	ret.Precision = precision
+	return ret
+}
+
+// csvInferAttributes returns an Attribute for each column of a sample
+// of records, named after the column, using the schema where given.
+func csvInferAttributes(names []string, records [][]string, options CSVOptions) ([]Attribute, error) {
+	index := make(map[string]int)
+	for i, name := range names {
+		index[name] = i
+	}
+	for name := range options.Schema {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("Schema column %s not found", name)
+		}
+	}
+	ret := make([]Attribute, len(names))
+	values := make([]string, len(records))
+	for i, name := range names {
+		if c, ok := options.Schema[name]; ok && c.Attribute != nil {
+			ret[i] = c.Attribute
+		} else {
+			for r, record := range records {
+				values[r] = record[i]
+			}
+			ret[i] = csvInferAttribute(values, options)
+		}
+		ret[i].SetName(name)
+	}
+	return ret, nil
+}
+
+// csvSysVal converts a value read from CSV data for the given
+// Attribute, returning an error rather than panicking if it can't be.
+// Missing values of FloatAttributes are read as NaN.
+func csvSysVal(attr Attribute, value string) ([]byte, error) {
+	switch a := attr.(type) {
+	case *FloatAttribute:
+		if csvIsMissing(value) {
+			return PackFloatToBytes(math.NaN()), nil
+		}
+		ret, err := a.CheckSysValFromString(value)
+		if err != nil {
+			return nil, fmt.Errorf("Can't read %q as a number for %s (a CSVSchema can set its type)", value, a.GetName())
+		}
+		return ret, nil
+	case *BinaryAttribute:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("Can't read %q as 0 or 1 for %s (a CSVSchema can set its type)", value, a.GetName())
+		}
+	}
+	return attr.GetSysValFromString(value), nil
+}
+
+// ParseCSVToInstancesFromReader reads CSV data from r in a single pass,
+// so it also works with input which can't be re-read (such as stdin,
+// HTTP bodies or decompressed files). The DenseInstances returned
+// grow as the rows are read.
+//
+// The type of each column is inferred from the first SampleRows rows,
+// unless options.Schema sets it. Columns whose values are all numbers
+// or missing (such as "", "?" or "N/A") give FloatAttributes, with
+// missing values read as NaN. Anything else gives a
+// CategoricalAttribute, and options can also pick out binary and
+// low-cardinality columns. Values are trimmed of surrounding spaces.
+//
+// An error is returned if a value after the sample doesn't suit its
+// column's type, or if the rows have different numbers of values.
+func ParseCSVToInstancesFromReader(r io.Reader, options CSVOptions) (*DenseInstances, error) {
+	return parseCSVFromReader(r, options, options.layout)
+}
+
+// csvLayout adds the Attributes inferred for each named column to
+// instances, and returns the columns to read into them.
+type csvLayout func(instances *DenseInstances, names []string, attrs []Attribute) ([]csvColumn, error)
+
+// layout adds the Attributes of the columns which aren't skipped, and
+// makes the class Attributes those the Schema picks out (or the last
+// column).
+func (options CSVOptions) layout(instances *DenseInstances, names []string, attrs []Attribute) ([]csvColumn, error) {
+	kept, err := csvKeptColumns(names, options)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]csvColumn, len(kept))
+	isKept := make(map[int]bool)
+	for j, i := range kept {
+		columns[j] = csvColumn{i, attrs[i], instances.AddAttribute(attrs[i])}
+		isKept[i] = true
+	}
+	hasClass := false
+	for i, name := range names {
+		if !options.Schema[name].Class {
+			continue
+		}
+		if !isKept[i] {
+			return nil, fmt.Errorf("Class column %s can't be skipped", name)
+		}
+		if err := instances.AddClassAttribute(attrs[i]); err != nil {
+			return nil, err
+		}
+		hasClass = true
+	}
+	if !hasClass {
+		instances.AddClassAttribute(columns[len(columns)-1].attr)
+	}
+	return columns, nil
+}
+
+// parseCSVFromReader reads CSV data from r in a single pass (see
+// ParseCSVToInstancesFromReader), using layout to create the
+// Attributes once their types are known.
+func parseCSVFromReader(r io.Reader, options CSVOptions, layout csvLayout) (*DenseInstances, error) {
+	reader := csv.NewReader(r)
+	if options.Delimiter != 0 {
+		reader.Comma = options.Delimiter
+	}
+	reader.Comment = options.Comment
+	reader.LazyQuotes = options.LazyQuotes
+	sampleRows := options.SampleRows
+	if sampleRows <= 0 {
+		sampleRows = csvSampleRows
+	}
+
+	// Read the names, and enough rows to infer the types
+	var names []string
+	buffered := make([][]string, 0)
+	for len(buffered) < sampleRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		for i, v := range record {
+			record[i] = strings.TrimSpace(v)
+		}
+		if names == nil && options.HasHeaders {
+			names = record
+			continue
+		}
+		buffered = append(buffered, record)
+	}
+	if len(buffered) == 0 {
+		return nil, fmt.Errorf("No rows to read")
+	}
+	if names == nil {
+		names = make([]string, len(buffered[0]))
+		for i := range names {
+			names[i] = fmt.Sprintf("%d", i)
+		}
+	}
+	attrs, err := csvInferAttributes(names, buffered, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the Attributes
+	instances := NewDenseInstances()
+	columns, err := layout(instances, names, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store each row, doubling the storage whenever it runs out
+	capacity, row := 0, 0
+	store := func(record []string) error {
+		if row == capacity {
+			grow := capacity
+			if grow == 0 {
+				grow = 64
+			}
+			if err := instances.Extend(grow); err != nil {
+				return err
+			}
+			capacity += grow
+		}
+		for _, c := range columns {
+			val, err := csvSysVal(c.attr, record[c.index])
+			if err != nil {
+				return fmt.Errorf("Row %d: %s", row+1, err)
+			}
+			instances.Set(c.spec, row, val)
+		}
+		row++
+		return nil
+	}
+	for _, record := range buffered {
+		if err := store(record); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		for i, v := range record {
+			record[i] = strings.TrimSpace(v)
+		}
+		if err := store(record); err != nil {
+			return nil, err
+		}
+	}
+	instances.truncate(row)
+	return instances, nil
+}
@@ -0,0 +1,300 @@
+package base
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseCSVToInstancesFromReader(t *testing.T) {
+	Convey("Reading the iris dataset from a stream", t, func() {
+		raw, err := ioutil.ReadFile("../examples/datasets/iris_headers.csv")
+		So(err, ShouldBeNil)
+		expected, err := ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		var compressed bytes.Buffer
+		w := gzip.NewWriter(&compressed)
+		_, err = w.Write(raw)
+		So(err, ShouldBeNil)
+		So(w.Close(), ShouldBeNil)
+		r, err := gzip.NewReader(&compressed)
+		So(err, ShouldBeNil)
+
+		inst, err := ParseCSVToInstancesFromReader(r, CSVOptions{HasHeaders: true})
+		So(err, ShouldBeNil)
+
+		Convey("It should read every row once", func() {
+			cols, rows := inst.Size()
+			So(cols, ShouldEqual, 5)
+			So(rows, ShouldEqual, 150)
+			for _, i := range []int{0, 63, 64, 127, 128, 149} {
+				So(inst.RowString(i), ShouldEqual, expected.RowString(i))
+			}
+		})
+
+		Convey("It should name and type the Attributes", func() {
+			attrs := inst.AllAttributes()
+			So(attrs[0].GetName(), ShouldEqual, "Sepal length")
+			So(attrs[0].GetType(), ShouldEqual, Float64Type)
+			So(attrs[0].(*FloatAttribute).Precision, ShouldEqual, 1)
+			So(attrs[4].GetName(), ShouldEqual, "Species")
+			So(attrs[4].GetType(), ShouldEqual, CategoricalType)
+			So(inst.AllClassAttributes(), ShouldResemble, attrs[4:])
+		})
+	})
+
+	Convey("Given data with comments, quotes and another delimiter", t, func() {
+		data := "# A comment\nid;name;score;label\n# Another\n1;\"Smith; J\";0.25;a\n2; Jones ;1.5;b\n3;\"O\"\"Brien\";-2;a\n"
+
+		Convey("Every row and column should be read", func() {
+			inst, err := ParseCSVToInstancesFromReader(strings.NewReader(data), CSVOptions{
+				Delimiter:  ';',
+				Comment:    '#',
+				HasHeaders: true,
+			})
+			So(err, ShouldBeNil)
+			_, rows := inst.Size()
+			So(rows, ShouldEqual, 3)
+			So(inst.RowString(0), ShouldEqual, "1 0.25 Smith; J a")
+			So(inst.RowString(1), ShouldEqual, "2 1.50 Jones b")
+			So(inst.RowString(2), ShouldEqual, "3 -2.00 O\"Brien a")
+		})
+
+		Convey("Columns should be skipped by position or by name", func() {
+			inst, err := ParseCSVToInstancesFromReader(strings.NewReader(data), CSVOptions{
+				Delimiter:       ';',
+				Comment:         '#',
+				HasHeaders:      true,
+				SkipColumns:     []int{0},
+				SkipColumnNames: []string{"label"},
+			})
+			So(err, ShouldBeNil)
+			attrs := inst.AllAttributes()
+			So(len(attrs), ShouldEqual, 2)
+			So(attrs[0].GetName(), ShouldEqual, "name")
+			So(attrs[1].GetName(), ShouldEqual, "score")
+			So(inst.AllClassAttributes(), ShouldResemble, attrs[1:])
+		})
+
+		Convey("Skipping unknown columns should fail", func() {
+			_, err := ParseCSVToInstancesFromReader(strings.NewReader(data), CSVOptions{
+				Delimiter:       ';',
+				Comment:         '#',
+				HasHeaders:      true,
+				SkipColumnNames: []string{"missing"},
+			})
+			So(err, ShouldNotBeNil)
+			_, err = ParseCSVToInstancesFromReader(strings.NewReader(data), CSVOptions{
+				Delimiter:   ';',
+				Comment:     '#',
+				HasHeaders:  true,
+				SkipColumns: []int{4},
+			})
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Without headers, columns should be named by position", t, func() {
+		inst, err := ParseCSVToInstancesFromReader(strings.NewReader("1,x\n2,y\n"), CSVOptions{})
+		So(err, ShouldBeNil)
+		attrs := inst.AllAttributes()
+		So(attrs[0].GetName(), ShouldEqual, "0")
+		So(attrs[1].GetName(), ShouldEqual, "1")
+	})
+
+	Convey("Bad input should return errors rather than panicking", t, func() {
+		Convey("Like a value after the sample which isn't numeric in a numeric column", func() {
+			_, err := ParseCSVToInstancesFromReader(strings.NewReader("1,a\n2,b\nx,c\n"), CSVOptions{SampleRows: 2})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "\"x\"")
+		})
+
+		Convey("Like rows of different lengths", func() {
+			_, err := ParseCSVToInstancesFromReader(strings.NewReader("1,a\n2,b,c\n"), CSVOptions{})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Like a stray quote", func() {
+			_, err := ParseCSVToInstancesFromReader(strings.NewReader("1,a\"b\n"), CSVOptions{})
+			So(err, ShouldNotBeNil)
+
+			Convey("Unless quotes are lazy", func() {
+				inst, err := ParseCSVToInstancesFromReader(strings.NewReader("1,a\"b\n"), CSVOptions{LazyQuotes: true})
+				So(err, ShouldBeNil)
+				So(inst.RowString(0), ShouldEqual, "1 a\"b")
+			})
+		})
+
+		Convey("Like having no rows", func() {
+			_, err := ParseCSVToInstancesFromReader(strings.NewReader("a,b\n"), CSVOptions{HasHeaders: true})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+// typedData has an id column, a numeric column with missing values, a
+// column which only turns out not to be numeric on its last row, a
+// binary column, a low-cardinality integer column and a class.
+const typedData = `id,reading,code,flag,grade,label
+101,0.5,1,0,1,a
+102,N/A,2,1,2,b
+103,1.25,3,1,3,a
+104,,4,0,1,b
+105,2,5,1,2,a
+106,?,X7,0,3,b
+`
+
+func TestCSVTypeInference(t *testing.T) {
+	Convey("Given columns whose types need more than one row to infer", t, func() {
+		read := func(options CSVOptions) (*DenseInstances, map[string]Attribute, error) {
+			options.HasHeaders = true
+			inst, err := ParseCSVToInstancesFromReader(strings.NewReader(typedData), options)
+			if err != nil {
+				return nil, nil, err
+			}
+			attrs := make(map[string]Attribute)
+			for _, a := range inst.AllAttributes() {
+				attrs[a.GetName()] = a
+			}
+			return inst, attrs, nil
+		}
+
+		Convey("By default, only numeric columns should be read as numbers", func() {
+			inst, attrs, err := read(CSVOptions{})
+			So(err, ShouldBeNil)
+			So(attrs["id"].GetType(), ShouldEqual, Float64Type)
+			So(attrs["reading"].GetType(), ShouldEqual, Float64Type)
+			So(attrs["reading"].(*FloatAttribute).Precision, ShouldEqual, 2)
+			So(attrs["code"].GetType(), ShouldEqual, CategoricalType)
+			So(attrs["flag"].GetType(), ShouldEqual, Float64Type)
+			So(attrs["grade"].GetType(), ShouldEqual, Float64Type)
+
+			Convey("With missing values read as NaN", func() {
+				spec, err := inst.GetAttribute(attrs["reading"])
+				So(err, ShouldBeNil)
+				So(UnpackBytesToFloat(inst.Get(spec, 0)), ShouldEqual, 0.5)
+				for _, row := range []int{1, 3, 5} {
+					So(math.IsNaN(UnpackBytesToFloat(inst.Get(spec, row))), ShouldBeTrue)
+				}
+			})
+		})
+
+		Convey("Binary and low-cardinality columns should be detected on request", func() {
+			_, attrs, err := read(CSVOptions{DetectBinary: true, MaxCategories: 3})
+			So(err, ShouldBeNil)
+			So(attrs["flag"].GetType(), ShouldEqual, BinaryType)
+			So(attrs["grade"].GetType(), ShouldEqual, CategoricalType)
+			So(attrs["grade"].(*CategoricalAttribute).GetValues(), ShouldResemble, []string{"1", "2", "3"})
+
+			Convey("But not columns whose values never repeat", func() {
+				So(attrs["id"].GetType(), ShouldEqual, Float64Type)
+			})
+		})
+
+		Convey("A schema should force types and choose the class Attributes", func() {
+			inst, attrs, err := read(CSVOptions{Schema: CSVSchema{
+				"id":    {Attribute: NewCategoricalAttribute()},
+				"flag":  {Class: true},
+				"label": {Class: true},
+			}})
+			So(err, ShouldBeNil)
+			So(attrs["id"].GetType(), ShouldEqual, CategoricalType)
+			So(inst.RowString(0), ShouldContainSubstring, "101")
+			classes := make(map[string]bool)
+			for _, a := range inst.AllClassAttributes() {
+				classes[a.GetName()] = true
+			}
+			So(classes, ShouldResemble, map[string]bool{"flag": true, "label": true})
+		})
+
+		Convey("Invalid schemas should be rejected", func() {
+			_, _, err := read(CSVOptions{Schema: CSVSchema{"missing": {Class: true}}})
+			So(err, ShouldNotBeNil)
+			_, _, err = read(CSVOptions{
+				Schema:          CSVSchema{"label": {Class: true}},
+				SkipColumnNames: []string{"label"},
+			})
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Sniffing the types of a file should look beyond the first row", t, func() {
+		f, err := ioutil.TempFile("", "typedData")
+		So(err, ShouldBeNil)
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(typedData)
+		So(err, ShouldBeNil)
+		So(f.Close(), ShouldBeNil)
+
+		attrs := ParseCSVGetAttributes(f.Name(), true)
+		So(attrs[1].GetType(), ShouldEqual, Float64Type)
+		So(attrs[2].GetName(), ShouldEqual, "code")
+		So(attrs[2].GetType(), ShouldEqual, CategoricalType)
+
+		Convey("And the schema should be used when grouping Attributes", func() {
+			inst, err := ParseCSVToInstancesWithAttributeGroups(
+				f.Name(),
+				map[string]string{},
+				map[string]string{"label": "ClassGroup"},
+				CSVSchema{"grade": {Attribute: NewCategoricalAttribute(), Class: true}},
+				true,
+			)
+			So(err, ShouldBeNil)
+			So(len(inst.AllClassAttributes()), ShouldEqual, 2)
+			for _, a := range inst.AllAttributes() {
+				if a.GetName() == "grade" {
+					So(a.GetType(), ShouldEqual, CategoricalType)
+				}
+			}
+		})
+	})
+}
+
+func TestParseCSVToInstancesWithAttributeGroups(t *testing.T) {
+	Convey("Reading the iris dataset into AttributeGroups", t, func() {
+		expected, err := ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		inst, err := ParseCSVToInstancesWithAttributeGroups(
+			"../examples/datasets/iris_headers.csv",
+			map[string]string{"Sepal length": "Sepal", "Sepal width": "Sepal"},
+			map[string]string{"Species": "ClassGroup"},
+			nil,
+			true,
+		)
+		So(err, ShouldBeNil)
+
+		Convey("It should read every row once", func() {
+			_, rows := inst.Size()
+			So(rows, ShouldEqual, 150)
+			for _, a := range expected.AllAttributes() {
+				want, err := expected.GetAttribute(a)
+				So(err, ShouldBeNil)
+				var got AttributeSpec
+				for _, b := range inst.AllAttributes() {
+					if b.GetName() == a.GetName() {
+						got, err = inst.GetAttribute(b)
+						So(err, ShouldBeNil)
+					}
+				}
+				for i := 0; i < rows; i++ {
+					So(got.GetAttribute().GetStringFromSysVal(inst.Get(got, i)), ShouldEqual, a.GetStringFromSysVal(expected.Get(want, i)))
+				}
+			}
+		})
+
+		Convey("It should group the Attributes", func() {
+			So(len(inst.AllClassAttributes()), ShouldEqual, 1)
+			So(inst.AllClassAttributes()[0].GetName(), ShouldEqual, "Species")
+			ag, err := inst.GetAttributeGroup("Sepal")
+			So(err, ShouldBeNil)
+			So(len(ag.Attributes()), ShouldEqual, 2)
+		})
+	})
+}
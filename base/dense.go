@@ -400,6 +400,21 @@ func (inst *DenseInstances) Extend(rows int) error {
 	return nil
 }
 
+// truncate discards every row from the given one onwards, which
+// allows storage to be allocated ahead of time and trimmed afterwards.
+func (inst *DenseInstances) truncate(rows int) {
+	inst.lock.Lock()
+	defer inst.lock.Unlock()
+
+	if rows >= inst.maxRow {
+		return
+	}
+	for _, p := range inst.ags {
+		p.setStorage(p.Storage()[:rows*p.RowSizeInBytes()])
+	}
+	inst.maxRow = rows
+}
+
 // Set sets a particular Attribute (given as an AttributeSpec) on a particular
 // row to a particular value.
 //
@@ -13,13 +13,13 @@ func TestHighDimensionalInstancesLoad(t *testing.T) {
 }
 func TestHighDimensionalInstancesLoad2(t *testing.T) {
 	Convey("Given a high-dimensional dataset...", t, func() {
-		// Create the class Attribute
-		classAttrs := make(map[int]Attribute)
-		classAttrs[0] = NewCategoricalAttribute()
-		classAttrs[0].SetName("Number")
+		// Read the class Attribute as categorical
+		schema := CSVSchema{
+			"label": {Attribute: NewCategoricalAttribute()},
+		}
 		// Setup the class Attribute to be in its own group
 		classAttrGroups := make(map[string]string)
-		classAttrGroups["Number"] = "ClassGroup"
+		classAttrGroups["label"] = "ClassGroup"
 		// The rest can go in a default group
 		attrGroups := make(map[string]string)
 
@@ -27,7 +27,7 @@ func TestHighDimensionalInstancesLoad2(t *testing.T) {
 			"../examples/datasets/mnist_train.csv",
 			attrGroups,
 			classAttrGroups,
-			classAttrs,
+			schema,
 			true,
 		)
 		So(err, ShouldEqual, nil)
@@ -0,0 +1,258 @@
+package base
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Derivers compute new Attributes from the values of several existing
+// ones (unlike Filters, which transform Attributes one at a time).
+type Deriver interface {
+	// Returns the Attributes whose values are needed
+	GetInputAttributes() []Attribute
+	// Returns the new Attributes
+	GetDerivedAttributes() []Attribute
+	// Gets a string for printing
+	String() string
+	// Accepts a derived Attribute and the values of the input
+	// Attributes (in order) and returns its value
+	Derive(Attribute, [][]byte) []byte
+}
+
+// LazilyDerivedInstances add the Attributes computed by a Deriver to
+// an underlying FixedDataGrid. Like LazilyFilteredInstances, values are
+// only computed when they're read. Every Attribute of the underlying
+// FixedDataGrid is passed through without modification.
+type LazilyDerivedInstances struct {
+	deriver    Deriver
+	src        FixedDataGrid
+	inputs     []AttributeSpec
+	derived    []Attribute
+	classAttrs map[Attribute]bool
+}
+
+// NewLazilyDerivedInstances returns a new FixedDataGrid containing
+// every Attribute of src, followed by those derived by d.
+func NewLazilyDerivedInstances(src FixedDataGrid, d Deriver) (*LazilyDerivedInstances, error) {
+	inputs, err := resolveAttributesChecked(src, d.GetInputAttributes())
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, a := range src.AllAttributes() {
+		names[a.GetName()] = true
+	}
+	for _, a := range d.GetDerivedAttributes() {
+		if names[a.GetName()] {
+			return nil, fmt.Errorf("Derived Attribute %s clashes with an existing Attribute", a.GetName())
+		}
+		names[a.GetName()] = true
+	}
+
+	ret := &LazilyDerivedInstances{
+		d,
+		src,
+		inputs,
+		d.GetDerivedAttributes(),
+		make(map[Attribute]bool),
+	}
+
+	// Transfer class Attributes
+	for _, a := range src.AllClassAttributes() {
+		ret.AddClassAttribute(a)
+	}
+	return ret, nil
+}
+
+// resolveAttributesChecked is ResolveAttributes, but returns an error
+// rather than panicking if an Attribute can't be found.
+func resolveAttributesChecked(from DataGrid, attrs []Attribute) ([]AttributeSpec, error) {
+	ret := make([]AttributeSpec, len(attrs))
+	for i, a := range attrs {
+		spec, err := from.GetAttribute(a)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = spec
+	}
+	return ret, nil
+}
+
+// derivedIndex returns the position of a among the derived Attributes,
+// or -1 if it comes from the underlying FixedDataGrid.
+func (l *LazilyDerivedInstances) derivedIndex(a Attribute) int {
+	for i, d := range l.derived {
+		if d == a {
+			return i
+		}
+	}
+	for i, d := range l.derived {
+		if d.Equals(a) {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetAttribute returns an AttributeSpecification for a given Attribute
+func (l *LazilyDerivedInstances) GetAttribute(target Attribute) (AttributeSpec, error) {
+	if i := l.derivedIndex(target); i >= 0 {
+		return AttributeSpec{-1, i, l.derived[i]}, nil
+	}
+	return l.src.GetAttribute(target)
+}
+
+// isDerived returns true if as refers to a derived Attribute.
+func (l *LazilyDerivedInstances) isDerived(as AttributeSpec) bool {
+	return as.pond == -1 && as.position < len(l.derived) && l.derived[as.position] == as.attr
+}
+
+// AllAttributes returns every Attribute defined in the source datagrid,
+// followed by the derived Attributes.
+func (l *LazilyDerivedInstances) AllAttributes() []Attribute {
+	ret := make([]Attribute, 0)
+	ret = append(ret, l.src.AllAttributes()...)
+	return append(ret, l.derived...)
+}
+
+// AddClassAttribute adds a given Attribute to the set of defined
+// class Attributes.
+func (l *LazilyDerivedInstances) AddClassAttribute(cls Attribute) error {
+	if _, err := l.GetAttribute(cls); err != nil {
+		return fmt.Errorf("Attribute %s could not be resolved", cls)
+	}
+	if i := l.derivedIndex(cls); i >= 0 {
+		cls = l.derived[i]
+	}
+	l.classAttrs[cls] = true
+	return nil
+}
+
+// RemoveClassAttribute removes a given Attribute from the set of
+// defined class Attributes.
+func (l *LazilyDerivedInstances) RemoveClassAttribute(cls Attribute) error {
+	if _, err := l.GetAttribute(cls); err != nil {
+		return fmt.Errorf("Attribute %s could not be resolved", cls)
+	}
+	if i := l.derivedIndex(cls); i >= 0 {
+		cls = l.derived[i]
+	}
+	l.classAttrs[cls] = false
+	return nil
+}
+
+// AllClassAttributes returns details of all Attributes currently specified
+// as being class Attributes.
+func (l *LazilyDerivedInstances) AllClassAttributes() []Attribute {
+	ret := make([]Attribute, 0)
+	for a := range l.classAttrs {
+		if l.classAttrs[a] {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// Get returns the byte slice stored (or derived) at a given
+// AttributeSpec and row.
+func (l *LazilyDerivedInstances) Get(as AttributeSpec, row int) []byte {
+	if !l.isDerived(as) {
+		return l.src.Get(as, row)
+	}
+	inputs := make([][]byte, len(l.inputs))
+	for i, a := range l.inputs {
+		inputs[i] = l.src.Get(a, row)
+	}
+	return l.deriver.Derive(as.attr, inputs)
+}
+
+// MapOverRows maps an iteration mapFunc over the bytes contained in the
+// source FixedDataGrid, along with any derived values requested.
+func (l *LazilyDerivedInstances) MapOverRows(asv []AttributeSpec, mapFunc func([][]byte, int) (bool, error)) error {
+
+	// Fetch the requested underlying Attributes, followed by the
+	// inputs if anything needs deriving
+	srcAsv := make([]AttributeSpec, 0)
+	positions := make([]int, len(asv))
+	anyDerived := false
+	for i, a := range asv {
+		if l.isDerived(a) {
+			anyDerived = true
+			positions[i] = -1
+		} else {
+			positions[i] = len(srcAsv)
+			srcAsv = append(srcAsv, a)
+		}
+	}
+	inputOffset := len(srcAsv)
+	if anyDerived {
+		srcAsv = append(srcAsv, l.inputs...)
+	}
+
+	newRowBuf := make([][]byte, len(asv))
+	return l.src.MapOverRows(srcAsv, func(oldRow [][]byte, rowNo int) (bool, error) {
+		for i, a := range asv {
+			if positions[i] >= 0 {
+				newRowBuf[i] = oldRow[positions[i]]
+			} else {
+				newRowBuf[i] = l.deriver.Derive(a.attr, oldRow[inputOffset:])
+			}
+		}
+		return mapFunc(newRowBuf, rowNo)
+	})
+}
+
+// RowString returns a string representation of a given row.
+func (l *LazilyDerivedInstances) RowString(row int) string {
+	var buffer bytes.Buffer
+	for i, a := range ResolveAllAttributes(l) {
+		if i > 0 {
+			buffer.WriteString(" ")
+		}
+		buffer.WriteString(a.attr.GetStringFromSysVal(l.Get(a, row)))
+	}
+	return buffer.String()
+}
+
+// Size returns the number of Attributes (including those derived) and
+// rows.
+func (l *LazilyDerivedInstances) Size() (int, int) {
+	cols, rows := l.src.Size()
+	return cols + len(l.derived), rows
+}
+
+// String returns a human-readable summary of this FixedDataGrid.
+func (l *LazilyDerivedInstances) String() string {
+	var buffer bytes.Buffer
+
+	// Decide on rows to print
+	_, rows := l.Size()
+	maxRows := 5
+	if rows < maxRows {
+		maxRows = rows
+	}
+
+	as := ResolveAllAttributes(l)
+
+	buffer.WriteString("Lazily derived instances using ")
+	buffer.WriteString(fmt.Sprintf("%s\n", l.deriver))
+	buffer.WriteString(fmt.Sprintf("Attributes: \n"))
+	for _, a := range as {
+		prefix := "\t"
+		if l.classAttrs[a.attr] {
+			prefix = "*\t"
+		}
+		buffer.WriteString(fmt.Sprintf("%s%s\n", prefix, a.attr))
+	}
+
+	buffer.WriteString("\nData:\n")
+	for i := 0; i < maxRows; i++ {
+		buffer.WriteString("\t")
+		for _, a := range as {
+			buffer.WriteString(fmt.Sprintf("%s ", a.attr.GetStringFromSysVal(l.Get(a, i))))
+		}
+		buffer.WriteString("\n")
+	}
+
+	return buffer.String()
+}
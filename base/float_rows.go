@@ -0,0 +1,69 @@
+package base
+
+import (
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+)
+
+// CopyFloatRows copies the values of the given FloatAttributes from
+// every row of grid into a single slice, row by row, so that row i
+// occupies ret[i*len(attrs) : (i+1)*len(attrs)].
+//
+// DenseInstances, and InstancesViews of them, are read straight from
+// AttributeGroup storage, which is much faster than MapOverRows.
+func CopyFloatRows(grid FixedDataGrid, attrs []Attribute) ([]float64, error) {
+	for _, a := range attrs {
+		if _, ok := a.(*FloatAttribute); !ok {
+			return nil, fmt.Errorf("%s is not a FloatAttribute", a)
+		}
+	}
+	specs, err := resolveAttributesChecked(grid, attrs)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := grid.Size()
+	cols := len(attrs)
+	ret := make([]float64, rows*cols)
+
+	// Find the underlying storage, and how rows map onto it
+	var dense *DenseInstances
+	resolve := func(row int) int { return row }
+	switch g := grid.(type) {
+	case *DenseInstances:
+		dense = g
+	case *InstancesView:
+		if d, ok := g.src.(*DenseInstances); ok {
+			dense = d
+			resolve = g.resolveRow
+		}
+	}
+
+	if dense == nil {
+		err = grid.MapOverRows(specs, func(row [][]byte, rowNo int) (bool, error) {
+			for j, v := range row {
+				ret[rowNo*cols+j] = UnpackBytesToFloat(v)
+			}
+			return true, nil
+		})
+		return ret, err
+	}
+
+	for j, s := range specs {
+		ag := dense.ags[s.pond]
+		for i := 0; i < rows; i++ {
+			ret[i*cols+j] = UnpackBytesToFloat(ag.get(s.position, resolve(i)))
+		}
+	}
+	return ret, nil
+}
+
+// CopyFloatMatrix is CopyFloatRows, but returns the values as a
+// rows x len(attrs) matrix.
+func CopyFloatMatrix(grid FixedDataGrid, attrs []Attribute) (*mat64.Dense, error) {
+	values, err := CopyFloatRows(grid, attrs)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := grid.Size()
+	return mat64.NewDense(rows, len(attrs), values), nil
+}
@@ -0,0 +1,70 @@
+package base
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestCopyFloatRows(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		inst, err := ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		attrs := NonClassFloatAttributes(inst)
+
+		// expected reads the values the slow way
+		expected := func(grid FixedDataGrid) []float64 {
+			_, rows := grid.Size()
+			ret := make([]float64, rows*len(attrs))
+			specs := ResolveAttributes(grid, attrs)
+			for i := 0; i < rows; i++ {
+				for j, s := range specs {
+					ret[i*len(attrs)+j] = UnpackBytesToFloat(grid.Get(s, i))
+				}
+			}
+			return ret
+		}
+
+		Convey("Every row of DenseInstances should be copied in order", func() {
+			values, err := CopyFloatRows(inst, attrs)
+			So(err, ShouldBeNil)
+			So(len(values), ShouldEqual, 150*4)
+			So(values, ShouldResemble, expected(inst))
+		})
+
+		Convey("InstancesViews should be copied in their own row order", func() {
+			view := NewInstancesViewFromVisible(inst, []int{149, 3, 77}, inst.AllAttributes())
+			values, err := CopyFloatRows(view, attrs)
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, expected(view))
+		})
+
+		Convey("Other FixedDataGrids should be copied too", func() {
+			view := NewInstancesViewFromVisible(inst, []int{149, 3, 77}, inst.AllAttributes())
+			viewOfView := NewInstancesViewFromVisible(view, []int{2, 0}, inst.AllAttributes())
+			values, err := CopyFloatRows(viewOfView, attrs)
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, expected(viewOfView))
+		})
+
+		Convey("Attributes which aren't FloatAttributes should be rejected", func() {
+			_, err := CopyFloatRows(inst, inst.AllClassAttributes())
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("CopyFloatMatrix should give one matrix row per row", func() {
+			view := NewInstancesViewFromVisible(inst, []int{149, 3, 77}, inst.AllAttributes())
+			m, err := CopyFloatMatrix(view, attrs)
+			So(err, ShouldBeNil)
+			rows, cols := m.Dims()
+			So(rows, ShouldEqual, 3)
+			So(cols, ShouldEqual, 4)
+			values := expected(view)
+			for i := 0; i < rows; i++ {
+				So(m.RowView(i), ShouldResemble, values[i*cols:(i+1)*cols])
+			}
+
+			_, err = CopyFloatMatrix(inst, inst.AllClassAttributes())
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -2,6 +2,7 @@ package base
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 )
 
@@ -195,6 +196,44 @@ func GetClassDistribution(inst FixedDataGrid) map[string]int {
 	return ret
 }
 
+// GetClassWeights returns the weight of each class value, for
+// classifiers which can weight some classes more heavily than others
+// (such as when some classes are much rarer than the rest).
+//
+// If weights is non-nil it's used as given, and any class it doesn't
+// mention is weighted 1. Otherwise, if balanced is true, each class in
+// inst is weighted in inverse proportion to its frequency (rows /
+// (classes * count)), so that every class carries the same total
+// weight. Otherwise nil is returned, which means every class should be
+// weighted equally.
+func GetClassWeights(inst FixedDataGrid, weights map[string]float64, balanced bool) (map[string]float64, error) {
+	if weights != nil {
+		ret := make(map[string]float64)
+		for c, w := range weights {
+			if w <= 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+				return nil, fmt.Errorf("Weight for class %s must be positive and finite", c)
+			}
+			ret[c] = w
+		}
+		for c := range GetClassDistribution(inst) {
+			if _, ok := ret[c]; !ok {
+				ret[c] = 1
+			}
+		}
+		return ret, nil
+	}
+	if !balanced {
+		return nil, nil
+	}
+	dist := GetClassDistribution(inst)
+	_, rows := inst.Size()
+	ret := make(map[string]float64)
+	for c, count := range dist {
+		ret[c] = float64(rows) / float64(len(dist)*count)
+	}
+	return ret, nil
+}
+
 // GetClassDistributionAfterThreshold returns the class distribution
 // after a speculative split on a given Attribute using a threshold.
 func GetClassDistributionAfterThreshold(inst FixedDataGrid, at Attribute, val float64) map[string]map[string]int {
@@ -109,3 +109,36 @@ func TestCategoricalEquality(t *testing.T) {
 		So(c2.Equals(c1), ShouldBeFalse) // Violates the fact that Attributes must appear in the same order
 	})
 }
+
+func TestGetClassWeights(t *testing.T) {
+	Convey("Given the PlayTennis dataset", t, func() {
+		inst, err := ParseCSVToInstances("../examples/datasets/tennis.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("Without weights, every class should be weighted equally", func() {
+			weights, err := GetClassWeights(inst, nil, false)
+			So(err, ShouldBeNil)
+			So(weights, ShouldBeNil)
+		})
+
+		Convey("Balanced weights should equalise the classes", func() {
+			weights, err := GetClassWeights(inst, nil, true)
+			So(err, ShouldBeNil)
+			So(weights["yes"], ShouldAlmostEqual, 14.0/18.0)
+			So(weights["no"], ShouldAlmostEqual, 14.0/10.0)
+			So(weights["yes"]*9, ShouldAlmostEqual, weights["no"]*5)
+		})
+
+		Convey("Given weights should take precedence", func() {
+			weights, err := GetClassWeights(inst, map[string]float64{"no": 3}, true)
+			So(err, ShouldBeNil)
+			So(weights["no"], ShouldEqual, 3)
+			So(weights["yes"], ShouldEqual, 1)
+		})
+
+		Convey("Non-positive weights should be rejected", func() {
+			_, err := GetClassWeights(inst, map[string]float64{"no": 0}, false)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
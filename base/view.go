@@ -221,6 +221,25 @@ func (v *InstancesView) MapOverRows(as []AttributeSpec, rowFunc func([][]byte, i
 			}
 		}
 		return nil
+	} else if v.rows != nil {
+		// Rows which aren't mapped appear as normal, so visit
+		// every row and resolve each one
+		_, rows := v.Size()
+		rowBuf := make([][]byte, len(as))
+		for r := 0; r < rows; r++ {
+			row := v.resolveRow(r)
+			for i, a := range as {
+				rowBuf[i] = v.src.Get(a, row)
+			}
+			ok, err := rowFunc(rowBuf, r)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+		}
+		return nil
 	} else {
 		return v.src.MapOverRows(as, rowFunc)
 	}
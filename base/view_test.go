@@ -26,6 +26,19 @@ func TestInstancesViewRows(t *testing.T) {
 				So(width, ShouldEqual, 5)
 				So(height, ShouldEqual, 150)
 			})
+			Convey("MapOverRows should see the remapped row...", func() {
+				specs := ResolveAttributes(instView, instView.AllAttributes())
+				visited := 0
+				err := instView.MapOverRows(specs, func(row [][]byte, rowNo int) (bool, error) {
+					visited++
+					for i, s := range specs {
+						So(row[i], ShouldResemble, instView.Get(s, rowNo))
+					}
+					return true, nil
+				})
+				So(err, ShouldBeNil)
+				So(visited, ShouldEqual, 150)
+			})
 		})
 	})
 }
@@ -0,0 +1,34 @@
+// Package clustering implements unsupervised models which group
+// instances together without reference to a class Attribute.
+//
+// GaussianMixture:
+//
+//	Fits a mixture of multivariate Gaussians to the non-class
+//	FloatAttributes of a FixedDataGrid using Expectation
+//	Maximisation.
+package clustering
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// NotEnoughDataError is returned when there are fewer rows than components.
+var NotEnoughDataError = errors.New("not enough rows to support this many components")
+
+// generateClusterVector returns a DenseInstances with a single
+// CategoricalAttribute (marked as the class) which can hold the
+// cluster assignment for each of the given number of rows.
+func generateClusterVector(rows, clusters int) (*base.DenseInstances, base.AttributeSpec) {
+	ret := base.NewDenseInstances()
+	attr := base.NewCategoricalAttribute()
+	attr.SetName("Cluster")
+	for i := 0; i < clusters; i++ {
+		attr.GetSysValFromString(fmt.Sprintf("%d", i))
+	}
+	spec := ret.AddAttribute(attr)
+	ret.AddClassAttribute(attr)
+	ret.Extend(rows)
+	return ret, spec
+}
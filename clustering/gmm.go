@@ -0,0 +1,478 @@
+package clustering
+
+import (
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/utilities"
+	"math"
+	"math/rand"
+)
+
+// CovarianceType controls how each GaussianMixture component's
+// covariance matrix is parameterised.
+type CovarianceType int
+
+const (
+	// FullCovariance gives each component its own unrestricted
+	// covariance matrix.
+	FullCovariance CovarianceType = iota
+	// DiagonalCovariance gives each component its own diagonal
+	// covariance matrix (i.e. features are uncorrelated).
+	DiagonalCovariance
+	// SphericalCovariance gives each component a single variance
+	// shared by every feature.
+	SphericalCovariance
+)
+
+// String returns a human-readable name for the CovarianceType.
+func (c CovarianceType) String() string {
+	switch c {
+	case FullCovariance:
+		return "full"
+	case DiagonalCovariance:
+		return "diagonal"
+	case SphericalCovariance:
+		return "spherical"
+	}
+	return fmt.Sprintf("CovarianceType(%d)", int(c))
+}
+
+// GaussianMixture models the non-class FloatAttributes of a
+// FixedDataGrid as being drawn from a weighted sum of multivariate
+// Gaussian distributions. The weights, means and covariances are
+// estimated using Expectation Maximisation:
+//
+// E-step: compute the responsibility of each component k for each row i
+//
+// r_{ik} = \frac{w_k N(x_i|\mu_k, \Sigma_k)}{\sum_j w_j N(x_i|\mu_j, \Sigma_j)}
+//
+// M-step: re-estimate w_k, \mu_k and \Sigma_k from the responsibilities.
+//
+// The two steps are repeated until the average log-likelihood of the
+// training data changes by less than Tolerance or MaxIterations is hit.
+//
+// For more information:
+//
+// C. M. Bishop (2006). Pattern Recognition and Machine Learning.
+// Springer, pp. 430-439.
+type GaussianMixture struct {
+	// Number of Gaussian components.
+	Components int
+	// Parameterisation of the covariance matrices.
+	Covariance CovarianceType
+	// Maximum number of EM iterations.
+	MaxIterations int
+	// Convergence threshold on the change in mean log-likelihood.
+	Tolerance float64
+	// Non-negative value added to the covariance diagonals so that
+	// they remain positive definite.
+	Regularisation float64
+	// Mixing weight of each component.
+	weights []float64
+	// Component means, one row per component.
+	means *mat64.Dense
+	// Component covariance matrices.
+	covariances []*mat64.Dense
+	// Matrices P_k such that (x - \mu_k)^T \Sigma_k^{-1} (x - \mu_k)
+	// = ||(x - \mu_k)^T P_k||^2.
+	projections []*mat64.Dense
+	// log |\Sigma_k|
+	logDets []float64
+	// Attributes used to Fit
+	attrs []base.Attribute
+	// Total log-likelihood of the training data
+	logLikelihood float64
+	iterations    int
+	converged     bool
+	fitted        bool
+}
+
+// NewGaussianMixture returns a GaussianMixture with the given number of
+// components and covariance parameterisation.
+func NewGaussianMixture(components int, covariance CovarianceType) *GaussianMixture {
+	return &GaussianMixture{
+		Components:     components,
+		Covariance:     covariance,
+		MaxIterations:  100,
+		Tolerance:      1e-3,
+		Regularisation: 1e-6,
+	}
+}
+
+// String returns a human-readable summary of this model.
+func (g *GaussianMixture) String() string {
+	return fmt.Sprintf("GaussianMixture(%d component(s), %s covariance)", g.Components, g.Covariance)
+}
+
+// Fit estimates the parameters of each component from the non-class
+// FloatAttributes of X.
+func (g *GaussianMixture) Fit(X base.FixedDataGrid) error {
+	if g.Components < 1 {
+		return fmt.Errorf("Must have at least one component")
+	}
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return fmt.Errorf("No FloatAttributes to fit")
+	}
+	_, rows := X.Size()
+	if rows < g.Components {
+		return NotEnoughDataError
+	}
+
+	data, err := base.CopyFloatMatrix(X, attrs)
+	if err != nil {
+		return err
+	}
+	g.attrs = attrs
+
+	// Initialise by hard-assigning each row to its nearest seed
+	if err := g.mStep(data, g.initialResponsibilities(data)); err != nil {
+		return err
+	}
+
+	g.converged = false
+	prev := math.Inf(-1)
+	for g.iterations = 0; g.iterations < g.MaxIterations; g.iterations++ {
+		resp, ll := g.eStep(data)
+		if math.Abs(ll-prev)/float64(rows) < g.Tolerance {
+			g.converged = true
+			break
+		}
+		prev = ll
+		if err := g.mStep(data, resp); err != nil {
+			return err
+		}
+	}
+
+	_, g.logLikelihood = g.eStep(data)
+	g.fitted = true
+	return nil
+}
+
+// initialResponsibilities picks seed means using the k-means++ strategy
+// and assigns each row entirely to the closest seed.
+func (g *GaussianMixture) initialResponsibilities(data *mat64.Dense) *mat64.Dense {
+	rows, _ := data.Dims()
+	seeds := make([]int, 0, g.Components)
+	seeds = append(seeds, rand.Intn(rows))
+
+	closest := make([]float64, rows)
+	for i := range closest {
+		closest[i] = math.Inf(1)
+	}
+	for len(seeds) < g.Components {
+		last := data.RowView(seeds[len(seeds)-1])
+		total := 0.0
+		for i := 0; i < rows; i++ {
+			d := squaredDistance(data.RowView(i), last)
+			if d < closest[i] {
+				closest[i] = d
+			}
+			total += closest[i]
+		}
+		next := rand.Intn(rows)
+		if total > 0 {
+			target := rand.Float64() * total
+			for i := 0; i < rows; i++ {
+				target -= closest[i]
+				if target <= 0 {
+					next = i
+					break
+				}
+			}
+		}
+		seeds = append(seeds, next)
+	}
+
+	resp := mat64.NewDense(rows, g.Components, nil)
+	for i := 0; i < rows; i++ {
+		best, bestDist := 0, math.Inf(1)
+		for k, s := range seeds {
+			d := squaredDistance(data.RowView(i), data.RowView(s))
+			if d < bestDist {
+				best, bestDist = k, d
+			}
+		}
+		resp.Set(i, best, 1.0)
+	}
+	return resp
+}
+
+// mStep re-estimates the weights, means and covariances from the
+// given responsibilities.
+func (g *GaussianMixture) mStep(data, resp *mat64.Dense) error {
+	rows, cols := data.Dims()
+	k := g.Components
+
+	g.weights = make([]float64, k)
+	g.means = mat64.NewDense(k, cols, nil)
+	g.covariances = make([]*mat64.Dense, k)
+	g.projections = make([]*mat64.Dense, k)
+	g.logDets = make([]float64, k)
+
+	for c := 0; c < k; c++ {
+		// Effective number of rows assigned to this component
+		nk := 0.0
+		for i := 0; i < rows; i++ {
+			nk += resp.At(i, c)
+		}
+		nk += 10 * math.SmallestNonzeroFloat64
+		g.weights[c] = nk / float64(rows)
+
+		mean := g.means.RowView(c)
+		for i := 0; i < rows; i++ {
+			r := resp.At(i, c)
+			for j, v := range data.RowView(i) {
+				mean[j] += r * v
+			}
+		}
+		for j := range mean {
+			mean[j] /= nk
+		}
+
+		cov := mat64.NewDense(cols, cols, nil)
+		for i := 0; i < rows; i++ {
+			r := resp.At(i, c)
+			if r == 0 {
+				continue
+			}
+			row := data.RowView(i)
+			for a := 0; a < cols; a++ {
+				da := row[a] - mean[a]
+				if g.Covariance == FullCovariance {
+					for b := a; b < cols; b++ {
+						cov.Set(a, b, cov.At(a, b)+r*da*(row[b]-mean[b]))
+					}
+				} else {
+					cov.Set(a, a, cov.At(a, a)+r*da*da)
+				}
+			}
+		}
+		for a := 0; a < cols; a++ {
+			for b := a; b < cols; b++ {
+				v := cov.At(a, b) / nk
+				cov.Set(a, b, v)
+				cov.Set(b, a, v)
+			}
+		}
+		if g.Covariance == SphericalCovariance {
+			variance := 0.0
+			for a := 0; a < cols; a++ {
+				variance += cov.At(a, a)
+			}
+			variance /= float64(cols)
+			for a := 0; a < cols; a++ {
+				cov.Set(a, a, variance)
+			}
+		}
+		for a := 0; a < cols; a++ {
+			cov.Set(a, a, cov.At(a, a)+g.Regularisation)
+		}
+		g.covariances[c] = cov
+
+		proj, logDet, err := utilities.InverseFactor(cov)
+		if err != nil {
+			return fmt.Errorf("Covariance of component %d: %s, try increasing Regularisation", c, err)
+		}
+		g.projections[c] = proj
+		g.logDets[c] = logDet
+	}
+	return nil
+}
+
+// weightedLogProb returns a rows x Components matrix containing
+// log(w_k) + log N(x_i|\mu_k, \Sigma_k).
+func (g *GaussianMixture) weightedLogProb(data *mat64.Dense) *mat64.Dense {
+	rows, cols := data.Dims()
+	ret := mat64.NewDense(rows, g.Components, nil)
+	centred := make([]float64, cols)
+	for c := 0; c < g.Components; c++ {
+		mean := g.means.RowView(c)
+		proj := g.projections[c]
+		norm := math.Log(g.weights[c]) - 0.5*(float64(cols)*math.Log(2*math.Pi)+g.logDets[c])
+		for i := 0; i < rows; i++ {
+			for j, v := range data.RowView(i) {
+				centred[j] = v - mean[j]
+			}
+			maha := 0.0
+			for b := 0; b < cols; b++ {
+				y := 0.0
+				for a := 0; a < cols; a++ {
+					y += centred[a] * proj.At(a, b)
+				}
+				maha += y * y
+			}
+			ret.Set(i, c, norm-0.5*maha)
+		}
+	}
+	return ret
+}
+
+// eStep returns the responsibility matrix and the total log-likelihood.
+func (g *GaussianMixture) eStep(data *mat64.Dense) (*mat64.Dense, float64) {
+	logProb := g.weightedLogProb(data)
+	rows, _ := logProb.Dims()
+	total := 0.0
+	for i := 0; i < rows; i++ {
+		row := logProb.RowView(i)
+		norm := utilities.LogSumExp(row)
+		total += norm
+		for c := range row {
+			row[c] = math.Exp(row[c] - norm)
+		}
+	}
+	return logProb, total
+}
+
+// Predict assigns each row of X to the component with the highest
+// responsibility. The result contains a single CategoricalAttribute
+// whose values are the component indices.
+func (g *GaussianMixture) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	resp, err := g.responsibilities(X)
+	if err != nil {
+		return nil, err
+	}
+	rows, _ := resp.Dims()
+	ret, spec := generateClusterVector(rows, g.Components)
+	for i := 0; i < rows; i++ {
+		best := 0
+		for c, v := range resp.RowView(i) {
+			if v > resp.At(i, best) {
+				best = c
+			}
+		}
+		ret.Set(spec, i, base.PackU64ToBytes(uint64(best)))
+	}
+	return ret, nil
+}
+
+// PredictProba returns the responsibility of each component for each
+// row of X as a grid of FloatAttributes named Component_0, Component_1...
+// Each row sums to one.
+func (g *GaussianMixture) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	resp, err := g.responsibilities(X)
+	if err != nil {
+		return nil, err
+	}
+	rows, _ := resp.Dims()
+	ret := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, g.Components)
+	for c := range specs {
+		specs[c] = ret.AddAttribute(base.NewFloatAttribute(fmt.Sprintf("Component_%d", c)))
+	}
+	ret.Extend(rows)
+	for i := 0; i < rows; i++ {
+		for c, v := range resp.RowView(i) {
+			ret.Set(specs[c], i, base.PackFloatToBytes(v))
+		}
+	}
+	return ret, nil
+}
+
+func (g *GaussianMixture) responsibilities(X base.FixedDataGrid) (*mat64.Dense, error) {
+	if !g.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	data, err := base.CopyFloatMatrix(X, g.attrs)
+	if err != nil {
+		return nil, err
+	}
+	resp, _ := g.eStep(data)
+	return resp, nil
+}
+
+// LogLikelihood returns the total log-likelihood of X under the model.
+func (g *GaussianMixture) LogLikelihood(X base.FixedDataGrid) (float64, error) {
+	if !g.fitted {
+		return 0, base.NoTrainingDataError
+	}
+	data, err := base.CopyFloatMatrix(X, g.attrs)
+	if err != nil {
+		return 0, err
+	}
+	_, ll := g.eStep(data)
+	return ll, nil
+}
+
+// BIC returns the Bayesian Information Criterion of the model on X.
+// Lower is better: use it to compare models with different Components.
+func (g *GaussianMixture) BIC(X base.FixedDataGrid) (float64, error) {
+	ll, err := g.LogLikelihood(X)
+	if err != nil {
+		return 0, err
+	}
+	_, rows := X.Size()
+	return -2*ll + float64(g.parameterCount())*math.Log(float64(rows)), nil
+}
+
+// AIC returns the Akaike Information Criterion of the model on X.
+// Lower is better.
+func (g *GaussianMixture) AIC(X base.FixedDataGrid) (float64, error) {
+	ll, err := g.LogLikelihood(X)
+	if err != nil {
+		return 0, err
+	}
+	return -2*ll + 2*float64(g.parameterCount()), nil
+}
+
+// parameterCount returns the number of free parameters in the model.
+func (g *GaussianMixture) parameterCount() int {
+	k, d := g.Components, len(g.attrs)
+	var covParams int
+	switch g.Covariance {
+	case FullCovariance:
+		covParams = k * d * (d + 1) / 2
+	case DiagonalCovariance:
+		covParams = k * d
+	case SphericalCovariance:
+		covParams = k
+	}
+	return (k - 1) + k*d + covParams
+}
+
+// TrainingLogLikelihood returns the total log-likelihood of the
+// training data after the final EM iteration.
+func (g *GaussianMixture) TrainingLogLikelihood() float64 {
+	return g.logLikelihood
+}
+
+// Converged reports whether EM converged before MaxIterations.
+func (g *GaussianMixture) Converged() bool {
+	return g.converged
+}
+
+// Iterations returns the number of EM iterations performed by Fit.
+func (g *GaussianMixture) Iterations() int {
+	return g.iterations
+}
+
+// Weights returns the mixing weight of each component.
+func (g *GaussianMixture) Weights() []float64 {
+	return g.weights
+}
+
+// Means returns a Components x Attributes matrix of component means.
+func (g *GaussianMixture) Means() *mat64.Dense {
+	return g.means
+}
+
+// Covariances returns each component's covariance matrix.
+func (g *GaussianMixture) Covariances() []*mat64.Dense {
+	return g.covariances
+}
+
+// Attributes returns the FloatAttributes the model was fitted on,
+// in the same order as the columns of Means.
+func (g *GaussianMixture) Attributes() []base.Attribute {
+	return g.attrs
+}
+
+func squaredDistance(a, b []float64) float64 {
+	ret := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		ret += d * d
+	}
+	return ret
+}
@@ -0,0 +1,147 @@
+package clustering
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// generateBlobs creates two well-separated Gaussian blobs
+// centred on (0, 0) and (10, 10).
+func generateBlobs(perBlob int) *base.DenseInstances {
+	inst := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, 2)
+	for i := range specs {
+		specs[i] = inst.AddAttribute(base.NewFloatAttribute(fmt.Sprintf("%d", i)))
+	}
+	inst.Extend(2 * perBlob)
+	for i := 0; i < 2*perBlob; i++ {
+		centre := 0.0
+		if i >= perBlob {
+			centre = 10.0
+		}
+		for _, s := range specs {
+			inst.Set(s, i, base.PackFloatToBytes(centre+rand.NormFloat64()))
+		}
+	}
+	return inst
+}
+
+func TestGaussianMixture(t *testing.T) {
+	Convey("Given two well-separated blobs", t, func() {
+		rand.Seed(42)
+		inst := generateBlobs(100)
+
+		for _, cov := range []CovarianceType{FullCovariance, DiagonalCovariance, SphericalCovariance} {
+			Convey(fmt.Sprintf("A 2-component model with %s covariance", cov), func() {
+				g := NewGaussianMixture(2, cov)
+				So(g.Fit(inst), ShouldBeNil)
+
+				Convey("Should converge", func() {
+					So(g.Converged(), ShouldBeTrue)
+				})
+
+				Convey("Should split the weight evenly", func() {
+					So(g.Weights()[0], ShouldAlmostEqual, 0.5, 0.01)
+					So(g.Weights()[1], ShouldAlmostEqual, 0.5, 0.01)
+				})
+
+				Convey("Should find both centres", func() {
+					means := g.Means()
+					lo, hi := 0, 1
+					if means.At(0, 0) > means.At(1, 0) {
+						lo, hi = 1, 0
+					}
+					for j := 0; j < 2; j++ {
+						So(means.At(lo, j), ShouldAlmostEqual, 0.0, 0.5)
+						So(means.At(hi, j), ShouldAlmostEqual, 10.0, 0.5)
+					}
+				})
+
+				Convey("Should assign each blob to a single component", func() {
+					pred, err := g.Predict(inst)
+					So(err, ShouldBeNil)
+					first := base.GetClass(pred, 0)
+					last := base.GetClass(pred, 199)
+					So(first, ShouldNotEqual, last)
+					for i := 0; i < 200; i++ {
+						if i < 100 {
+							So(base.GetClass(pred, i), ShouldEqual, first)
+						} else {
+							So(base.GetClass(pred, i), ShouldEqual, last)
+						}
+					}
+				})
+
+				Convey("Responsibilities should sum to one", func() {
+					proba, err := g.PredictProba(inst)
+					So(err, ShouldBeNil)
+					specs := base.ResolveAllAttributes(proba)
+					So(len(specs), ShouldEqual, 2)
+					proba.MapOverRows(specs, func(row [][]byte, i int) (bool, error) {
+						sum := 0.0
+						for _, v := range row {
+							sum += base.UnpackBytesToFloat(v)
+						}
+						So(sum, ShouldAlmostEqual, 1.0, 1e-9)
+						return true, nil
+					})
+				})
+
+				Convey("LogLikelihood should match training", func() {
+					ll, err := g.LogLikelihood(inst)
+					So(err, ShouldBeNil)
+					So(ll, ShouldAlmostEqual, g.TrainingLogLikelihood(), 1e-9)
+					So(math.IsNaN(ll), ShouldBeFalse)
+				})
+			})
+		}
+
+		Convey("BIC should prefer two components over one", func() {
+			one := NewGaussianMixture(1, FullCovariance)
+			So(one.Fit(inst), ShouldBeNil)
+			two := NewGaussianMixture(2, FullCovariance)
+			So(two.Fit(inst), ShouldBeNil)
+
+			bicOne, err := one.BIC(inst)
+			So(err, ShouldBeNil)
+			bicTwo, err := two.BIC(inst)
+			So(err, ShouldBeNil)
+			So(bicTwo, ShouldBeLessThan, bicOne)
+
+			aicOne, _ := one.AIC(inst)
+			aicTwo, _ := two.AIC(inst)
+			So(aicTwo, ShouldBeLessThan, aicOne)
+		})
+	})
+
+	Convey("Given the iris dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("A 3-component model should only use the FloatAttributes", func() {
+			g := NewGaussianMixture(3, FullCovariance)
+			So(g.Fit(inst), ShouldBeNil)
+			So(len(g.Attributes()), ShouldEqual, 4)
+			So(len(g.Covariances()), ShouldEqual, 3)
+		})
+	})
+
+	Convey("Given an unfitted model", t, func() {
+		g := NewGaussianMixture(2, FullCovariance)
+		inst := generateBlobs(5)
+
+		Convey("Predict should return an error", func() {
+			_, err := g.Predict(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Fitting with too many components should fail", func() {
+			g.Components = 20
+			So(g.Fit(inst), ShouldEqual, NotEnoughDataError)
+		})
+	})
+}
@@ -0,0 +1,138 @@
+// Package decomposition implements linear dimensionality reduction.
+//
+// PCA:
+//
+//	Centres the non-class FloatAttributes and projects them onto the
+//	directions of greatest variance, optionally whitening the output.
+//
+// TruncatedSVD:
+//
+//	Projects the non-class FloatAttributes onto their leading right
+//	singular vectors without centring them first.
+//
+// Both produce a new FixedDataGrid in which the projected Attributes
+// are replaced by new FloatAttributes, whilst every other Attribute
+// (including the class) is carried across unchanged. Both also support
+// mapping projected data back into the original space.
+package decomposition
+
+import (
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// linearProjection stores the parts common to every linear transformer:
+// output = ((input - mean) x components^T) / scale
+type linearProjection struct {
+	// Attributes consumed by the projection
+	attrs []base.Attribute
+	// Attributes produced by the projection
+	newAttrs []base.Attribute
+	// Subtracted from each input row before projection
+	mean []float64
+	// One row per output Attribute, one column per input Attribute
+	components *mat64.Dense
+	// Each output Attribute is divided by the corresponding value
+	scale  []float64
+	fitted bool
+}
+
+// generateAttributes creates count FloatAttributes called prefix1,
+// prefix2 and so on.
+func generateAttributes(prefix string, count int) []base.Attribute {
+	ret := make([]base.Attribute, count)
+	for i := range ret {
+		ret[i] = base.NewFloatAttribute(fmt.Sprintf("%s%d", prefix, i+1))
+	}
+	return ret
+}
+
+// transform projects the fitted Attributes of X.
+func (l *linearProjection) transform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !l.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	data, err := base.CopyFloatMatrix(X, l.attrs)
+	if err != nil {
+		return nil, err
+	}
+	rows, cols := data.Dims()
+	k, _ := l.components.Dims()
+	out := mat64.NewDense(rows, k, nil)
+	centred := make([]float64, cols)
+	for i := 0; i < rows; i++ {
+		for j, v := range data.RowView(i) {
+			centred[j] = v - l.mean[j]
+		}
+		for c := 0; c < k; c++ {
+			sum := 0.0
+			for j, v := range l.components.RowView(c) {
+				sum += centred[j] * v
+			}
+			out.Set(i, c, sum/l.scale[c])
+		}
+	}
+	return buildOutput(X, l.attrs, l.newAttrs, out), nil
+}
+
+// inverseTransform maps the projected Attributes of X back into
+// the original space.
+func (l *linearProjection) inverseTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !l.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	data, err := base.CopyFloatMatrix(X, l.newAttrs)
+	if err != nil {
+		return nil, err
+	}
+	rows, _ := data.Dims()
+	_, cols := l.components.Dims()
+	out := mat64.NewDense(rows, cols, nil)
+	for i := 0; i < rows; i++ {
+		row := out.RowView(i)
+		copy(row, l.mean)
+		for c, v := range data.RowView(i) {
+			v *= l.scale[c]
+			for j, w := range l.components.RowView(c) {
+				row[j] += v * w
+			}
+		}
+	}
+	return buildOutput(X, l.newAttrs, l.attrs, out), nil
+}
+
+// buildOutput creates a DenseInstances in which the replaced Attributes
+// of X are swapped for the given values of the replacement Attributes.
+// Every other Attribute of X is copied across, as are class Attributes.
+func buildOutput(X base.FixedDataGrid, replaced, replacements []base.Attribute, values *mat64.Dense) *base.DenseInstances {
+	ret := base.NewDenseInstances()
+
+	kept := base.AttributeDifference(X.AllAttributes(), replaced)
+	keptSpecs := make([]base.AttributeSpec, len(kept))
+	for i, a := range kept {
+		keptSpecs[i] = ret.AddAttribute(a)
+	}
+	newSpecs := make([]base.AttributeSpec, len(replacements))
+	for i, a := range replacements {
+		newSpecs[i] = ret.AddAttribute(a)
+	}
+	for _, a := range X.AllClassAttributes() {
+		ret.AddClassAttribute(a)
+	}
+
+	rows, _ := values.Dims()
+	ret.Extend(rows)
+	X.MapOverRows(base.ResolveAttributes(X, kept), func(row [][]byte, i int) (bool, error) {
+		for j, v := range row {
+			ret.Set(keptSpecs[j], i, v)
+		}
+		return true, nil
+	})
+	for i := 0; i < rows; i++ {
+		for j, v := range values.RowView(i) {
+			ret.Set(newSpecs[j], i, base.PackFloatToBytes(v))
+		}
+	}
+	return ret
+}
@@ -0,0 +1,172 @@
+package decomposition
+
+import (
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/utilities"
+	"math"
+)
+
+// PCA performs Principal Component Analysis. Fit centres the non-class
+// FloatAttributes and computes the eigendecomposition of their
+// covariance matrix: the eigenvectors with the largest eigenvalues are
+// the principal axes, and the eigenvalues are the variance explained
+// along each one.
+//
+// Transform replaces the fitted Attributes with new FloatAttributes
+// named PC1, PC2 and so on. If Whiten is set, each component is also
+// divided by its standard deviation so that it has unit variance.
+type PCA struct {
+	linearProjection
+	// Number of components to keep, or 0 to keep them all.
+	Components int
+	// Scale the components to unit variance.
+	Whiten                 bool
+	explainedVariance      []float64
+	explainedVarianceRatio []float64
+}
+
+// NewPCA returns a PCA which keeps the given number of components
+// (0 keeps every component).
+func NewPCA(components int) *PCA {
+	return &PCA{Components: components}
+}
+
+// String returns a human-readable summary.
+func (p *PCA) String() string {
+	return fmt.Sprintf("PCA(%d component(s), whiten: %t)", p.Components, p.Whiten)
+}
+
+// Fit computes the principal axes of the non-class FloatAttributes of X.
+func (p *PCA) Fit(X base.FixedDataGrid) error {
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return fmt.Errorf("No FloatAttributes to fit")
+	}
+	_, rows := X.Size()
+	if rows < 2 {
+		return fmt.Errorf("PCA needs at least two rows")
+	}
+	if p.Components < 0 || p.Components > len(attrs) {
+		return fmt.Errorf("Can't keep %d components of %d Attribute(s)", p.Components, len(attrs))
+	}
+	k := p.Components
+	if k == 0 {
+		k = len(attrs)
+	}
+
+	data, err := base.CopyFloatMatrix(X, attrs)
+	if err != nil {
+		return err
+	}
+	_, cols := data.Dims()
+
+	mean := make([]float64, cols)
+	for i := 0; i < rows; i++ {
+		for j, v := range data.RowView(i) {
+			mean[j] += v
+		}
+	}
+	for j := range mean {
+		mean[j] /= float64(rows)
+	}
+
+	cov := mat64.NewDense(cols, cols, nil)
+	for i := 0; i < rows; i++ {
+		row := data.RowView(i)
+		for a := 0; a < cols; a++ {
+			da := row[a] - mean[a]
+			for b := a; b < cols; b++ {
+				cov.Set(a, b, cov.At(a, b)+da*(row[b]-mean[b]))
+			}
+		}
+	}
+	for a := 0; a < cols; a++ {
+		for b := a; b < cols; b++ {
+			v := cov.At(a, b) / float64(rows-1)
+			cov.Set(a, b, v)
+			cov.Set(b, a, v)
+		}
+	}
+
+	vals, vecs := utilities.SymmetricEigen(cov)
+	total := 0.0
+	for _, v := range vals {
+		if v > 0 {
+			total += v
+		}
+	}
+
+	p.explainedVariance = make([]float64, k)
+	p.explainedVarianceRatio = make([]float64, k)
+	scale := make([]float64, k)
+	components := mat64.NewDense(k, cols, nil)
+	for c := 0; c < k; c++ {
+		v := vals[c]
+		if v < 0 {
+			v = 0
+		}
+		p.explainedVariance[c] = v
+		if total > 0 {
+			p.explainedVarianceRatio[c] = v / total
+		}
+		scale[c] = 1.0
+		if p.Whiten {
+			if v == 0 {
+				return fmt.Errorf("Can't whiten component %d: it has no variance", c+1)
+			}
+			scale[c] = math.Sqrt(v)
+		}
+		components.SetRow(c, vecs.Col(nil, c))
+	}
+
+	p.attrs = attrs
+	p.newAttrs = generateAttributes("PC", k)
+	p.mean = mean
+	p.components = components
+	p.scale = scale
+	p.fitted = true
+	return nil
+}
+
+// Transform projects X onto the principal axes.
+func (p *PCA) Transform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return p.transform(X)
+}
+
+// FitTransform fits the PCA to X and returns the projection of X.
+func (p *PCA) FitTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if err := p.Fit(X); err != nil {
+		return nil, err
+	}
+	return p.Transform(X)
+}
+
+// InverseTransform maps data produced by Transform back into the
+// original space. If fewer components than Attributes were kept,
+// the result is the closest point within the principal subspace.
+func (p *PCA) InverseTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return p.inverseTransform(X)
+}
+
+// GetComponents returns the principal axes, one row per component.
+func (p *PCA) GetComponents() *mat64.Dense {
+	return p.components
+}
+
+// GetExplainedVariance returns the variance along each component.
+func (p *PCA) GetExplainedVariance() []float64 {
+	return p.explainedVariance
+}
+
+// GetExplainedVarianceRatio returns the fraction of the total variance
+// explained by each component.
+func (p *PCA) GetExplainedVarianceRatio() []float64 {
+	return p.explainedVarianceRatio
+}
+
+// GetAttributesAfterTransform returns the FloatAttributes produced by Transform.
+func (p *PCA) GetAttributesAfterTransform() []base.Attribute {
+	return p.newAttrs
+}
@@ -0,0 +1,100 @@
+package decomposition
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestPCA(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("An unfitted PCA should return an error", func() {
+			_, err := NewPCA(2).Transform(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Asking for too many components should fail", func() {
+			So(NewPCA(5).Fit(inst), ShouldNotBeNil)
+		})
+
+		Convey("Fitting every component", func() {
+			p := NewPCA(0)
+			So(p.Fit(inst), ShouldBeNil)
+
+			Convey("Explained variance ratios should match the reference values", func() {
+				ratio := p.GetExplainedVarianceRatio()
+				So(len(ratio), ShouldEqual, 4)
+				So(ratio[0], ShouldAlmostEqual, 0.92461621, 1e-6)
+				So(ratio[1], ShouldAlmostEqual, 0.05301557, 1e-6)
+				So(ratio[2], ShouldAlmostEqual, 0.01718514, 1e-6)
+				So(ratio[3], ShouldAlmostEqual, 0.00518309, 1e-6)
+			})
+
+			Convey("InverseTransform should reconstruct the original data", func() {
+				out, err := p.Transform(inst)
+				So(err, ShouldBeNil)
+				back, err := p.InverseTransform(out)
+				So(err, ShouldBeNil)
+
+				attrs := base.NonClassFloatAttributes(inst)
+				origSpecs := base.ResolveAttributes(inst, attrs)
+				backSpecs := base.ResolveAttributes(back, attrs)
+				_, rows := inst.Size()
+				for i := 0; i < rows; i++ {
+					for j := range attrs {
+						orig := base.UnpackBytesToFloat(inst.Get(origSpecs[j], i))
+						recon := base.UnpackBytesToFloat(back.Get(backSpecs[j], i))
+						So(recon, ShouldAlmostEqual, orig, 1e-9)
+					}
+				}
+			})
+		})
+
+		Convey("Projecting onto two components", func() {
+			p := NewPCA(2)
+			out, err := p.FitTransform(inst)
+			So(err, ShouldBeNil)
+
+			Convey("Should produce PC1 and PC2 plus the class", func() {
+				So(len(base.NonClassAttributes(out)), ShouldEqual, 2)
+				So(base.GetAttributeByName(out, "PC1"), ShouldNotBeNil)
+				So(base.GetAttributeByName(out, "PC2"), ShouldNotBeNil)
+				So(base.GetClass(out, 0), ShouldEqual, base.GetClass(inst, 0))
+			})
+
+			Convey("Each component's variance should be its explained variance", func() {
+				variances := componentVariances(out, p.GetAttributesAfterTransform())
+				for i, v := range p.GetExplainedVariance() {
+					So(variances[i], ShouldAlmostEqual, v, 1e-9)
+				}
+			})
+		})
+
+		Convey("Whitening should give unit variance", func() {
+			p := NewPCA(3)
+			p.Whiten = true
+			out, err := p.FitTransform(inst)
+			So(err, ShouldBeNil)
+			for _, v := range componentVariances(out, p.GetAttributesAfterTransform()) {
+				So(v, ShouldAlmostEqual, 1.0, 1e-9)
+			}
+		})
+	})
+}
+
+// componentVariances returns the sample variance of each Attribute.
+func componentVariances(inst base.FixedDataGrid, attrs []base.Attribute) []float64 {
+	m, err := base.CopyFloatMatrix(inst, attrs)
+	if err != nil {
+		panic(err)
+	}
+	rows, _ := m.Dims()
+	ret := columnVariances(m)
+	for i := range ret {
+		ret[i] *= float64(rows) / float64(rows-1)
+	}
+	return ret
+}
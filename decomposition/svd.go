@@ -0,0 +1,169 @@
+package decomposition
+
+import (
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/utilities"
+	"math"
+)
+
+// TruncatedSVD reduces dimensionality by projecting the non-class
+// FloatAttributes onto the top right singular vectors of the data
+// matrix X = U S V^T. Unlike PCA the data are not centred, which makes
+// it suitable for sparse count data such as term-document matrices
+// (where it's known as Latent Semantic Analysis).
+//
+// The singular vectors are computed from the eigendecomposition of
+// X^T X, whose eigenvalues are the squared singular values.
+//
+// Transform replaces the fitted Attributes with new FloatAttributes
+// named SV1, SV2 and so on.
+type TruncatedSVD struct {
+	linearProjection
+	// Number of components to keep.
+	Components             int
+	singularValues         []float64
+	explainedVariance      []float64
+	explainedVarianceRatio []float64
+}
+
+// NewTruncatedSVD returns a TruncatedSVD which keeps the given number
+// of components.
+func NewTruncatedSVD(components int) *TruncatedSVD {
+	return &TruncatedSVD{Components: components}
+}
+
+// String returns a human-readable summary.
+func (t *TruncatedSVD) String() string {
+	return fmt.Sprintf("TruncatedSVD(%d component(s))", t.Components)
+}
+
+// Fit computes the leading right singular vectors of the non-class
+// FloatAttributes of X.
+func (t *TruncatedSVD) Fit(X base.FixedDataGrid) error {
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return fmt.Errorf("No FloatAttributes to fit")
+	}
+	if t.Components < 1 || t.Components > len(attrs) {
+		return fmt.Errorf("Can't keep %d components of %d Attribute(s)", t.Components, len(attrs))
+	}
+	_, rows := X.Size()
+	if rows < 2 {
+		return fmt.Errorf("TruncatedSVD needs at least two rows")
+	}
+
+	data, err := base.CopyFloatMatrix(X, attrs)
+	if err != nil {
+		return err
+	}
+	var gram, transposed mat64.Dense
+	transposed.TCopy(data)
+	gram.Mul(&transposed, data)
+	vals, vecs := utilities.SymmetricEigen(&gram)
+
+	_, cols := data.Dims()
+	k := t.Components
+	components := mat64.NewDense(k, cols, nil)
+	t.singularValues = make([]float64, k)
+	for c := 0; c < k; c++ {
+		components.SetRow(c, vecs.Col(nil, c))
+		t.singularValues[c] = math.Sqrt(math.Max(vals[c], 0))
+	}
+
+	// Explained variance is the variance of each projected column,
+	// relative to the summed variance of the original columns
+	total := 0.0
+	for _, v := range columnVariances(data) {
+		total += v
+	}
+	var projected mat64.Dense
+	var componentsT mat64.Dense
+	componentsT.TCopy(components)
+	projected.Mul(data, &componentsT)
+	t.explainedVariance = columnVariances(&projected)
+	t.explainedVarianceRatio = make([]float64, k)
+	for c, v := range t.explainedVariance {
+		if total > 0 {
+			t.explainedVarianceRatio[c] = v / total
+		}
+	}
+
+	scale := make([]float64, k)
+	for c := range scale {
+		scale[c] = 1.0
+	}
+
+	t.attrs = attrs
+	t.newAttrs = generateAttributes("SV", k)
+	t.mean = make([]float64, cols)
+	t.components = components
+	t.scale = scale
+	t.fitted = true
+	return nil
+}
+
+// columnVariances returns the (population) variance of each column.
+func columnVariances(m *mat64.Dense) []float64 {
+	rows, cols := m.Dims()
+	ret := make([]float64, cols)
+	for j := 0; j < cols; j++ {
+		mean, sq := 0.0, 0.0
+		for i := 0; i < rows; i++ {
+			mean += m.At(i, j)
+		}
+		mean /= float64(rows)
+		for i := 0; i < rows; i++ {
+			d := m.At(i, j) - mean
+			sq += d * d
+		}
+		ret[j] = sq / float64(rows)
+	}
+	return ret
+}
+
+// Transform projects X onto the leading singular vectors.
+func (t *TruncatedSVD) Transform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return t.transform(X)
+}
+
+// FitTransform fits the TruncatedSVD to X and returns the projection of X.
+func (t *TruncatedSVD) FitTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if err := t.Fit(X); err != nil {
+		return nil, err
+	}
+	return t.Transform(X)
+}
+
+// InverseTransform maps data produced by Transform back into the
+// original space.
+func (t *TruncatedSVD) InverseTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return t.inverseTransform(X)
+}
+
+// GetComponents returns the right singular vectors, one row per component.
+func (t *TruncatedSVD) GetComponents() *mat64.Dense {
+	return t.components
+}
+
+// GetSingularValues returns the singular value of each component.
+func (t *TruncatedSVD) GetSingularValues() []float64 {
+	return t.singularValues
+}
+
+// GetExplainedVariance returns the variance of each projected Attribute.
+func (t *TruncatedSVD) GetExplainedVariance() []float64 {
+	return t.explainedVariance
+}
+
+// GetExplainedVarianceRatio returns the fraction of the total variance
+// explained by each component.
+func (t *TruncatedSVD) GetExplainedVarianceRatio() []float64 {
+	return t.explainedVarianceRatio
+}
+
+// GetAttributesAfterTransform returns the FloatAttributes produced by Transform.
+func (t *TruncatedSVD) GetAttributesAfterTransform() []base.Attribute {
+	return t.newAttrs
+}
@@ -0,0 +1,73 @@
+package decomposition
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestTruncatedSVD(t *testing.T) {
+	Convey("Given a small matrix with known singular values", t, func() {
+		// Singular values are 4 and 3
+		vals := [][]float64{{3, 0}, {0, 4}, {0, 0}}
+		inst := base.NewDenseInstances()
+		specs := []base.AttributeSpec{
+			inst.AddAttribute(base.NewFloatAttribute("a")),
+			inst.AddAttribute(base.NewFloatAttribute("b")),
+		}
+		inst.Extend(len(vals))
+		for i, row := range vals {
+			for j, v := range row {
+				inst.Set(specs[j], i, base.PackFloatToBytes(v))
+			}
+		}
+
+		Convey("Keeping both components", func() {
+			s := NewTruncatedSVD(2)
+			out, err := s.FitTransform(inst)
+			So(err, ShouldBeNil)
+
+			Convey("The singular values should be found in descending order", func() {
+				So(s.GetSingularValues()[0], ShouldAlmostEqual, 4.0, 1e-9)
+				So(s.GetSingularValues()[1], ShouldAlmostEqual, 3.0, 1e-9)
+			})
+
+			Convey("The first component should be the b axis", func() {
+				c := s.GetComponents()
+				So(c.At(0, 0), ShouldAlmostEqual, 0.0, 1e-9)
+				So(c.At(0, 1), ShouldAlmostEqual, 1.0, 1e-9)
+			})
+
+			Convey("The explained variance ratios should sum to one", func() {
+				ratio := s.GetExplainedVarianceRatio()
+				So(ratio[0]+ratio[1], ShouldAlmostEqual, 1.0, 1e-9)
+			})
+
+			Convey("InverseTransform should reconstruct the original data", func() {
+				back, err := s.InverseTransform(out)
+				So(err, ShouldBeNil)
+				backSpecs := base.ResolveAttributes(back, []base.Attribute{
+					base.GetAttributeByName(inst, "a"),
+					base.GetAttributeByName(inst, "b"),
+				})
+				for i, row := range vals {
+					for j, v := range row {
+						So(base.UnpackBytesToFloat(back.Get(backSpecs[j], i)), ShouldAlmostEqual, v, 1e-9)
+					}
+				}
+			})
+		})
+
+		Convey("Keeping one component should produce SV1 only", func() {
+			s := NewTruncatedSVD(1)
+			out, err := s.FitTransform(inst)
+			So(err, ShouldBeNil)
+			So(len(out.AllAttributes()), ShouldEqual, 1)
+			So(base.GetAttributeByName(out, "SV1"), ShouldNotBeNil)
+		})
+
+		Convey("Asking for no components should fail", func() {
+			So(NewTruncatedSVD(0).Fit(inst), ShouldNotBeNil)
+		})
+	})
+}
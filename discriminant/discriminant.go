@@ -0,0 +1,146 @@
+// Package discriminant implements Gaussian discriminant analysis.
+//
+// LDA:
+//
+//	Models each class as a Gaussian with a shared covariance matrix,
+//	which gives linear decision boundaries. It can also be used as a
+//	supervised projection onto at most (classes - 1) FloatAttributes.
+//
+// QDA:
+//
+//	Models each class as a Gaussian with its own covariance matrix,
+//	which gives quadratic decision boundaries.
+//
+// Both are probabilistic: PredictProba returns the posterior
+// probability of each class.
+package discriminant
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/utilities"
+	"math"
+	"sort"
+)
+
+// NotEnoughDataError is returned when a class has too few rows.
+var NotEnoughDataError = errors.New("not enough rows to estimate the covariance")
+
+// classStatistics holds the per-class quantities shared by LDA and QDA.
+type classStatistics struct {
+	// Class values, sorted
+	classes []string
+	// Rows belonging to each class
+	rows [][]int
+	// Fraction of the training data in each class
+	priors []float64
+	// One row per class, one column per Attribute
+	means *mat64.Dense
+}
+
+// validateTrainingData checks that X has a single class Attribute and
+// at least one non-class FloatAttribute, and returns the latter.
+func validateTrainingData(X base.FixedDataGrid) ([]base.Attribute, error) {
+	if len(X.AllClassAttributes()) != 1 {
+		return nil, fmt.Errorf("Only 1 class variable is permitted")
+	}
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return nil, fmt.Errorf("No FloatAttributes to fit")
+	}
+	return attrs, nil
+}
+
+// computeClassStatistics groups the rows of X by class and computes
+// each class' prior and mean.
+func computeClassStatistics(X base.FixedDataGrid, data *mat64.Dense) (*classStatistics, error) {
+	rows, cols := data.Dims()
+	groups := make(map[string][]int)
+	for i := 0; i < rows; i++ {
+		c := base.GetClass(X, i)
+		groups[c] = append(groups[c], i)
+	}
+	if len(groups) < 2 {
+		return nil, fmt.Errorf("Need at least two classes, got %d", len(groups))
+	}
+
+	ret := &classStatistics{}
+	for c := range groups {
+		ret.classes = append(ret.classes, c)
+	}
+	sort.Strings(ret.classes)
+
+	ret.rows = make([][]int, len(ret.classes))
+	ret.priors = make([]float64, len(ret.classes))
+	ret.means = mat64.NewDense(len(ret.classes), cols, nil)
+	for k, c := range ret.classes {
+		ret.rows[k] = groups[c]
+		ret.priors[k] = float64(len(groups[c])) / float64(rows)
+		mean := ret.means.RowView(k)
+		for _, i := range groups[c] {
+			for j, v := range data.RowView(i) {
+				mean[j] += v
+			}
+		}
+		for j := range mean {
+			mean[j] /= float64(len(groups[c]))
+		}
+	}
+	return ret, nil
+}
+
+// shrink blends a covariance matrix towards a multiple of the identity
+// with the same trace: (1 - amount) * cov + amount * (trace / d) * I.
+func shrink(cov *mat64.Dense, amount float64) {
+	if amount == 0 {
+		return
+	}
+	cols, _ := cov.Dims()
+	trace := 0.0
+	for a := 0; a < cols; a++ {
+		trace += cov.At(a, a)
+	}
+	cov.Scale(1-amount, cov)
+	for a := 0; a < cols; a++ {
+		cov.Set(a, a, cov.At(a, a)+amount*trace/float64(cols))
+	}
+}
+
+// generatePredictions returns the class with the highest score
+// in each row.
+func generatePredictions(X base.FixedDataGrid, classes []string, scores *mat64.Dense) base.FixedDataGrid {
+	ret := base.GeneratePredictionVector(X)
+	rows, _ := scores.Dims()
+	for i := 0; i < rows; i++ {
+		best := 0
+		for k, v := range scores.RowView(i) {
+			if v > scores.At(i, best) {
+				best = k
+			}
+		}
+		base.SetClass(ret, i, classes[best])
+	}
+	return ret
+}
+
+// generateProbabilities normalises each row of log-scores into
+// posterior probabilities, one FloatAttribute per class.
+func generateProbabilities(classes []string, scores *mat64.Dense) base.FixedDataGrid {
+	rows, _ := scores.Dims()
+	ret := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, len(classes))
+	for k, c := range classes {
+		specs[k] = ret.AddAttribute(base.NewFloatAttribute(c))
+	}
+	ret.Extend(rows)
+	for i := 0; i < rows; i++ {
+		row := scores.RowView(i)
+		norm := utilities.LogSumExp(row)
+		for k, v := range row {
+			ret.Set(specs[k], i, base.PackFloatToBytes(math.Exp(v-norm)))
+		}
+	}
+	return ret
+}
@@ -0,0 +1,329 @@
+package discriminant
+
+import (
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/utilities"
+	"math"
+)
+
+// LDA performs Linear Discriminant Analysis. Each class is modelled
+// as a Gaussian with its own mean and a covariance matrix pooled over
+// every class, so the log-posterior of each class is a linear function
+// of the input.
+//
+// As well as a classifier, LDA is a supervised projection: Transform
+// maps the non-class FloatAttributes onto the directions which best
+// separate the class means relative to the pooled within-class
+// spread. There are at most (classes - 1) such directions, and the
+// output FloatAttributes are named LD1, LD2 and so on.
+type LDA struct {
+	// Number of components produced by Transform,
+	// or 0 to produce as many as possible.
+	Components int
+	// Amount (between 0 and 1) by which the pooled covariance is
+	// shrunk towards a multiple of the identity. Useful when there
+	// are few rows or the Attributes are strongly correlated.
+	Shrinkage              float64
+	stats                  *classStatistics
+	attrs                  []base.Attribute
+	coefficients           *mat64.Dense
+	intercepts             []float64
+	overallMean            []float64
+	scalings               *mat64.Dense
+	explainedVarianceRatio []float64
+	newAttrs               []base.Attribute
+	fitted                 bool
+}
+
+// NewLDA returns an LDA with no shrinkage which projects onto as many
+// components as possible.
+func NewLDA() *LDA {
+	return &LDA{}
+}
+
+// String returns a human-readable summary.
+func (l *LDA) String() string {
+	return fmt.Sprintf("LDA(shrinkage: %.3f)", l.Shrinkage)
+}
+
+// Fit estimates the class means and pooled covariance from X, along
+// with the discriminant directions used by Transform.
+func (l *LDA) Fit(X base.FixedDataGrid) error {
+	if l.Shrinkage < 0 || l.Shrinkage > 1 {
+		return fmt.Errorf("Shrinkage must be between 0 and 1")
+	}
+	attrs, err := validateTrainingData(X)
+	if err != nil {
+		return err
+	}
+	data, err := base.CopyFloatMatrix(X, attrs)
+	if err != nil {
+		return err
+	}
+	rows, cols := data.Dims()
+	stats, err := computeClassStatistics(X, data)
+	if err != nil {
+		return err
+	}
+	classes := len(stats.classes)
+	if rows <= classes {
+		return NotEnoughDataError
+	}
+	maxComponents := classes - 1
+	if cols < maxComponents {
+		maxComponents = cols
+	}
+	if l.Components < 0 || l.Components > maxComponents {
+		return fmt.Errorf("Can't produce %d components, at most %d are available", l.Components, maxComponents)
+	}
+
+	// Pooled within-class covariance
+	within := mat64.NewDense(cols, cols, nil)
+	for k, members := range stats.rows {
+		mean := stats.means.RowView(k)
+		for _, i := range members {
+			row := data.RowView(i)
+			for a := 0; a < cols; a++ {
+				da := row[a] - mean[a]
+				for b := 0; b < cols; b++ {
+					within.Set(a, b, within.At(a, b)+da*(row[b]-mean[b]))
+				}
+			}
+		}
+	}
+	within.Scale(1/float64(rows-classes), within)
+	shrink(within, l.Shrinkage)
+	whiten, _, err := utilities.InverseFactor(within)
+	if err != nil {
+		return fmt.Errorf("Covariance: %s, try increasing the shrinkage", err)
+	}
+
+	// The discriminant for class k is x^T \Sigma^{-1} \mu_k
+	// - 0.5 \mu_k^T \Sigma^{-1} \mu_k + log(prior_k)
+	var whitenT, precision mat64.Dense
+	whitenT.TCopy(whiten)
+	precision.Mul(whiten, &whitenT)
+	l.coefficients = mat64.NewDense(classes, cols, nil)
+	l.intercepts = make([]float64, classes)
+	for k := 0; k < classes; k++ {
+		mean := stats.means.RowView(k)
+		coef := l.coefficients.RowView(k)
+		for a := 0; a < cols; a++ {
+			for b := 0; b < cols; b++ {
+				coef[a] += precision.At(a, b) * mean[b]
+			}
+		}
+		dot := 0.0
+		for a := range coef {
+			dot += coef[a] * mean[a]
+		}
+		l.intercepts[k] = -0.5*dot + math.Log(stats.priors[k])
+	}
+
+	// Discriminant directions solve the generalised eigenproblem
+	// S_b v = \lambda S_w v. Substituting v = W u, where W W^T = S_w^{-1},
+	// turns it into the symmetric problem (W^T S_b W) u = \lambda u.
+	l.overallMean = make([]float64, cols)
+	for k := 0; k < classes; k++ {
+		for j, v := range stats.means.RowView(k) {
+			l.overallMean[j] += stats.priors[k] * v
+		}
+	}
+	between := mat64.NewDense(cols, cols, nil)
+	for k := 0; k < classes; k++ {
+		mean := stats.means.RowView(k)
+		for a := 0; a < cols; a++ {
+			da := mean[a] - l.overallMean[a]
+			for b := 0; b < cols; b++ {
+				between.Set(a, b, between.At(a, b)+stats.priors[k]*da*(mean[b]-l.overallMean[b]))
+			}
+		}
+	}
+	var tmp, reduced mat64.Dense
+	tmp.Mul(&whitenT, between)
+	reduced.Mul(&tmp, whiten)
+	vals, vecs := utilities.SymmetricEigen(&reduced)
+
+	k := l.Components
+	if k == 0 {
+		k = maxComponents
+	}
+	total := 0.0
+	for _, v := range vals {
+		if v > 0 {
+			total += v
+		}
+	}
+	var directions mat64.Dense
+	directions.Mul(whiten, vecs)
+	l.scalings = mat64.NewDense(k, cols, nil)
+	l.explainedVarianceRatio = make([]float64, k)
+	for c := 0; c < k; c++ {
+		// Choose the sign so that the largest-magnitude weight is positive
+		largest := 0.0
+		for j := 0; j < cols; j++ {
+			if v := directions.At(j, c); math.Abs(v) > math.Abs(largest) {
+				largest = v
+			}
+		}
+		sign := 1.0
+		if largest < 0 {
+			sign = -1.0
+		}
+		for j := 0; j < cols; j++ {
+			l.scalings.Set(c, j, sign*directions.At(j, c))
+		}
+		if total > 0 && vals[c] > 0 {
+			l.explainedVarianceRatio[c] = vals[c] / total
+		}
+	}
+
+	l.newAttrs = make([]base.Attribute, k)
+	for c := range l.newAttrs {
+		l.newAttrs[c] = base.NewFloatAttribute(fmt.Sprintf("LD%d", c+1))
+	}
+	l.stats = stats
+	l.attrs = attrs
+	l.fitted = true
+	return nil
+}
+
+// decisionFunction returns the discriminant of every class for
+// every row of X.
+func (l *LDA) decisionFunction(X base.FixedDataGrid) (*mat64.Dense, error) {
+	if !l.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	data, err := base.CopyFloatMatrix(X, l.attrs)
+	if err != nil {
+		return nil, err
+	}
+	var coefT, scores mat64.Dense
+	coefT.TCopy(l.coefficients)
+	scores.Mul(data, &coefT)
+	rows, _ := scores.Dims()
+	for i := 0; i < rows; i++ {
+		row := scores.RowView(i)
+		for k := range row {
+			row[k] += l.intercepts[k]
+		}
+	}
+	return &scores, nil
+}
+
+// Predict returns the most probable class of each row of X.
+func (l *LDA) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	scores, err := l.decisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	return generatePredictions(X, l.stats.classes, scores), nil
+}
+
+// PredictProba returns the posterior probability of each class,
+// as one FloatAttribute per class value.
+func (l *LDA) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	scores, err := l.decisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	return generateProbabilities(l.stats.classes, scores), nil
+}
+
+// Transform projects X onto the discriminant directions. The fitted
+// Attributes are replaced by LD1, LD2 and so on, whilst every other
+// Attribute (including the class) is carried across unchanged.
+func (l *LDA) Transform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !l.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	data, err := base.CopyFloatMatrix(X, l.attrs)
+	if err != nil {
+		return nil, err
+	}
+	rows, cols := data.Dims()
+	k, _ := l.scalings.Dims()
+	out := mat64.NewDense(rows, k, nil)
+	centred := make([]float64, cols)
+	for i := 0; i < rows; i++ {
+		for j, v := range data.RowView(i) {
+			centred[j] = v - l.overallMean[j]
+		}
+		for c := 0; c < k; c++ {
+			sum := 0.0
+			for j, v := range l.scalings.RowView(c) {
+				sum += centred[j] * v
+			}
+			out.Set(i, c, sum)
+		}
+	}
+
+	ret := base.NewDenseInstances()
+	kept := base.AttributeDifference(X.AllAttributes(), l.attrs)
+	keptSpecs := make([]base.AttributeSpec, len(kept))
+	for i, a := range kept {
+		keptSpecs[i] = ret.AddAttribute(a)
+	}
+	newSpecs := make([]base.AttributeSpec, k)
+	for c, a := range l.newAttrs {
+		newSpecs[c] = ret.AddAttribute(a)
+	}
+	for _, a := range X.AllClassAttributes() {
+		ret.AddClassAttribute(a)
+	}
+	ret.Extend(rows)
+	X.MapOverRows(base.ResolveAttributes(X, kept), func(row [][]byte, i int) (bool, error) {
+		for j, v := range row {
+			ret.Set(keptSpecs[j], i, v)
+		}
+		return true, nil
+	})
+	for i := 0; i < rows; i++ {
+		for c, v := range out.RowView(i) {
+			ret.Set(newSpecs[c], i, base.PackFloatToBytes(v))
+		}
+	}
+	return ret, nil
+}
+
+// FitTransform fits the LDA to X and returns the projection of X.
+func (l *LDA) FitTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if err := l.Fit(X); err != nil {
+		return nil, err
+	}
+	return l.Transform(X)
+}
+
+// GetAttributesAfterTransform returns the FloatAttributes produced by Transform.
+func (l *LDA) GetAttributesAfterTransform() []base.Attribute {
+	return l.newAttrs
+}
+
+// GetScalings returns the discriminant directions, one row per component.
+func (l *LDA) GetScalings() *mat64.Dense {
+	return l.scalings
+}
+
+// GetExplainedVarianceRatio returns the fraction of the between-class
+// variance explained by each component.
+func (l *LDA) GetExplainedVarianceRatio() []float64 {
+	return l.explainedVarianceRatio
+}
+
+// GetClasses returns the class values, in the order used by
+// PredictProba and GetMeans.
+func (l *LDA) GetClasses() []string {
+	return l.stats.classes
+}
+
+// GetMeans returns the mean of each class, one row per class.
+func (l *LDA) GetMeans() *mat64.Dense {
+	return l.stats.means
+}
+
+// GetPriors returns the fraction of the training data in each class.
+func (l *LDA) GetPriors() []float64 {
+	return l.stats.priors
+}
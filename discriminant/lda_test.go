@@ -0,0 +1,92 @@
+package discriminant
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLDA(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("An unfitted LDA should return an error", func() {
+			_, err := NewLDA().Predict(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Asking for more than (classes - 1) components should fail", func() {
+			l := NewLDA()
+			l.Components = 3
+			So(l.Fit(inst), ShouldNotBeNil)
+		})
+
+		Convey("After fitting", func() {
+			l := NewLDA()
+			So(l.Fit(inst), ShouldBeNil)
+
+			Convey("The training data should be classified accurately", func() {
+				predictions, err := l.Predict(inst)
+				So(err, ShouldBeNil)
+				cf, err := evaluation.GetConfusionMatrix(inst, predictions)
+				So(err, ShouldBeNil)
+				So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.97)
+			})
+
+			Convey("Each row's probabilities should sum to one", func() {
+				probs, err := l.PredictProba(inst)
+				So(err, ShouldBeNil)
+				So(len(probs.AllAttributes()), ShouldEqual, 3)
+				specs := base.ResolveAttributes(probs, probs.AllAttributes())
+				_, rows := probs.Size()
+				for i := 0; i < rows; i++ {
+					sum := 0.0
+					for _, s := range specs {
+						sum += base.UnpackBytesToFloat(probs.Get(s, i))
+					}
+					So(sum, ShouldAlmostEqual, 1.0, 1e-9)
+				}
+			})
+
+			Convey("The first discriminant should explain most of the separation", func() {
+				ratio := l.GetExplainedVarianceRatio()
+				So(len(ratio), ShouldEqual, 2)
+				So(ratio[0], ShouldBeGreaterThan, 0.98)
+				So(ratio[0]+ratio[1], ShouldAlmostEqual, 1.0, 1e-9)
+			})
+
+			Convey("Transform should produce LD1 and LD2 plus the class", func() {
+				out, err := l.Transform(inst)
+				So(err, ShouldBeNil)
+				So(len(base.NonClassAttributes(out)), ShouldEqual, 2)
+				So(base.GetClass(out, 0), ShouldEqual, base.GetClass(inst, 0))
+
+				Convey("With unit pooled within-class variance", func() {
+					data, err := base.CopyFloatMatrix(out, l.GetAttributesAfterTransform())
+					So(err, ShouldBeNil)
+					stats, err := computeClassStatistics(out, data)
+					So(err, ShouldBeNil)
+					rows, _ := data.Dims()
+					for c := 0; c < 2; c++ {
+						sq := 0.0
+						for k, members := range stats.rows {
+							for _, i := range members {
+								d := data.At(i, c) - stats.means.At(k, c)
+								sq += d * d
+							}
+						}
+						So(sq/float64(rows-3), ShouldAlmostEqual, 1.0, 1e-9)
+					}
+				})
+			})
+		})
+
+		Convey("Shrinkage outside [0, 1] should fail", func() {
+			l := NewLDA()
+			l.Shrinkage = 1.5
+			So(l.Fit(inst), ShouldNotBeNil)
+		})
+	})
+}
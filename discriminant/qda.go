@@ -0,0 +1,165 @@
+package discriminant
+
+import (
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/utilities"
+	"math"
+)
+
+// QDA performs Quadratic Discriminant Analysis. Each class is modelled
+// as a Gaussian with its own mean and covariance matrix, so the
+// decision boundaries between classes are quadratic.
+type QDA struct {
+	// Amount (between 0 and 1) by which each class covariance is
+	// shrunk towards a multiple of the identity. Needed when a class
+	// has fewer rows than there are Attributes.
+	Regularisation float64
+	stats          *classStatistics
+	attrs          []base.Attribute
+	covariances    []*mat64.Dense
+	projections    []*mat64.Dense
+	logDets        []float64
+	fitted         bool
+}
+
+// NewQDA returns a QDA with no regularisation.
+func NewQDA() *QDA {
+	return &QDA{}
+}
+
+// String returns a human-readable summary.
+func (q *QDA) String() string {
+	return fmt.Sprintf("QDA(regularisation: %.3f)", q.Regularisation)
+}
+
+// Fit estimates the mean and covariance of each class in X.
+func (q *QDA) Fit(X base.FixedDataGrid) error {
+	if q.Regularisation < 0 || q.Regularisation > 1 {
+		return fmt.Errorf("Regularisation must be between 0 and 1")
+	}
+	attrs, err := validateTrainingData(X)
+	if err != nil {
+		return err
+	}
+	data, err := base.CopyFloatMatrix(X, attrs)
+	if err != nil {
+		return err
+	}
+	_, cols := data.Dims()
+	stats, err := computeClassStatistics(X, data)
+	if err != nil {
+		return err
+	}
+
+	classes := len(stats.classes)
+	q.covariances = make([]*mat64.Dense, classes)
+	q.projections = make([]*mat64.Dense, classes)
+	q.logDets = make([]float64, classes)
+	for k, members := range stats.rows {
+		if len(members) < 2 {
+			return NotEnoughDataError
+		}
+		mean := stats.means.RowView(k)
+		cov := mat64.NewDense(cols, cols, nil)
+		for _, i := range members {
+			row := data.RowView(i)
+			for a := 0; a < cols; a++ {
+				da := row[a] - mean[a]
+				for b := 0; b < cols; b++ {
+					cov.Set(a, b, cov.At(a, b)+da*(row[b]-mean[b]))
+				}
+			}
+		}
+		cov.Scale(1/float64(len(members)-1), cov)
+		shrink(cov, q.Regularisation)
+		proj, logDet, err := utilities.InverseFactor(cov)
+		if err != nil {
+			return fmt.Errorf("Covariance of class %s: %s, try increasing the regularisation", stats.classes[k], err)
+		}
+		q.covariances[k] = cov
+		q.projections[k] = proj
+		q.logDets[k] = logDet
+	}
+
+	q.stats = stats
+	q.attrs = attrs
+	q.fitted = true
+	return nil
+}
+
+// decisionFunction returns the log-posterior (up to a constant) of
+// every class for every row of X.
+func (q *QDA) decisionFunction(X base.FixedDataGrid) (*mat64.Dense, error) {
+	if !q.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	data, err := base.CopyFloatMatrix(X, q.attrs)
+	if err != nil {
+		return nil, err
+	}
+	rows, cols := data.Dims()
+	classes := len(q.stats.classes)
+	scores := mat64.NewDense(rows, classes, nil)
+	diff := make([]float64, cols)
+	for i := 0; i < rows; i++ {
+		row := data.RowView(i)
+		for k := 0; k < classes; k++ {
+			for j, m := range q.stats.means.RowView(k) {
+				diff[j] = row[j] - m
+			}
+			// Mahalanobis distance via the whitening projection
+			dist := 0.0
+			for c := 0; c < cols; c++ {
+				sum := 0.0
+				for j, d := range diff {
+					sum += d * q.projections[k].At(j, c)
+				}
+				dist += sum * sum
+			}
+			scores.Set(i, k, math.Log(q.stats.priors[k])-0.5*(q.logDets[k]+dist))
+		}
+	}
+	return scores, nil
+}
+
+// Predict returns the most probable class of each row of X.
+func (q *QDA) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	scores, err := q.decisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	return generatePredictions(X, q.stats.classes, scores), nil
+}
+
+// PredictProba returns the posterior probability of each class,
+// as one FloatAttribute per class value.
+func (q *QDA) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	scores, err := q.decisionFunction(X)
+	if err != nil {
+		return nil, err
+	}
+	return generateProbabilities(q.stats.classes, scores), nil
+}
+
+// GetClasses returns the class values, in the order used by
+// PredictProba, GetMeans and GetCovariances.
+func (q *QDA) GetClasses() []string {
+	return q.stats.classes
+}
+
+// GetMeans returns the mean of each class, one row per class.
+func (q *QDA) GetMeans() *mat64.Dense {
+	return q.stats.means
+}
+
+// GetCovariances returns the (regularised) covariance of each class.
+func (q *QDA) GetCovariances() []*mat64.Dense {
+	return q.covariances
+}
+
+// GetPriors returns the fraction of the training data in each class.
+func (q *QDA) GetPriors() []float64 {
+	return q.stats.priors
+}
@@ -0,0 +1,50 @@
+package discriminant
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestQDA(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("An unfitted QDA should return an error", func() {
+			_, err := NewQDA().PredictProba(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("After fitting", func() {
+			q := NewQDA()
+			So(q.Fit(inst), ShouldBeNil)
+
+			Convey("Each class should have its own covariance", func() {
+				So(len(q.GetCovariances()), ShouldEqual, 3)
+				So(q.GetClasses(), ShouldResemble, []string{"Iris-setosa", "Iris-versicolor", "Iris-virginica"})
+				for _, p := range q.GetPriors() {
+					So(p, ShouldAlmostEqual, 1.0/3.0, 1e-9)
+				}
+			})
+
+			Convey("The training data should be classified accurately", func() {
+				predictions, err := q.Predict(inst)
+				So(err, ShouldBeNil)
+				cf, err := evaluation.GetConfusionMatrix(inst, predictions)
+				So(err, ShouldBeNil)
+				So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.97)
+			})
+		})
+
+		Convey("A class with fewer rows than Attributes needs regularising", func() {
+			small := base.NewInstancesViewFromVisible(inst, []int{0, 1, 50, 51, 100, 101}, inst.AllAttributes())
+			So(NewQDA().Fit(small), ShouldNotBeNil)
+
+			q := NewQDA()
+			q.Regularisation = 0.5
+			So(q.Fit(small), ShouldBeNil)
+		})
+	})
+}
@@ -58,8 +58,7 @@ func NewMultiLinearSVC(loss, penalty string, dual bool, C float64, eps float64,
 // Fit builds the MultiLinearSVC by building n (where n is the number of values
 // the singular CategoricalAttribute can take) seperate one-vs-rest models.
 func (m *MultiLinearSVC) Fit(instances base.FixedDataGrid) error {
-	m.m.Fit(instances)
-	return nil
+	return m.m.Fit(instances)
 }
 
 // Predict issues predictions from the MultiLinearSVC. Each underlying LinearSVC is
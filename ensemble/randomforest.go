@@ -10,11 +10,19 @@ import (
 
 // RandomForest classifies instances using an ensemble
 // of bagged random decision trees.
+//
+// ClassWeights optionally weights each class value when building the
+// trees; classes it doesn't mention are weighted 1. If it's nil, the
+// classes are weighted in inverse proportion to their frequency in the
+// training data when WeightClassesAutomatically is true, and equally
+// otherwise. Every tree uses the same weights.
 type RandomForest struct {
 	base.BaseClassifier
-	ForestSize int
-	Features   int
-	Model      *meta.BaggedModel
+	ForestSize                 int
+	Features                   int
+	Model                      *meta.BaggedModel
+	ClassWeights               map[string]float64
+	WeightClassesAutomatically bool
 }
 
 // NewRandomForest generates and return a new random forests
@@ -26,6 +34,8 @@ func NewRandomForest(forestSize int, features int) *RandomForest {
 		forestSize,
 		features,
 		nil,
+		nil,
+		false,
 	}
 	return ret
 }
@@ -41,10 +51,16 @@ func (f *RandomForest) Fit(on base.FixedDataGrid) error {
 		))
 	}
 
+	weights, err := base.GetClassWeights(on, f.ClassWeights, f.WeightClassesAutomatically)
+	if err != nil {
+		return err
+	}
+
 	f.Model = new(meta.BaggedModel)
 	f.Model.RandomFeatures = f.Features
 	for i := 0; i < f.ForestSize; i++ {
 		tree := trees.NewID3DecisionTree(0.00)
+		tree.ClassWeights = weights
 		f.Model.AddModel(tree)
 	}
 	f.Model.Fit(on)
@@ -56,6 +72,33 @@ func (f *RandomForest) Predict(with base.FixedDataGrid) (base.FixedDataGrid, err
 	return f.Model.Predict(with), nil
 }
 
+// GetFeatureImportances returns the mean importance of each Attribute
+// over the trees of the forest, scaled to sum to 1 (see
+// trees.DecisionTreeNode.GetFeatureImportances).
+func (f *RandomForest) GetFeatureImportances() map[base.Attribute]float64 {
+	ret := make(map[base.Attribute]float64)
+	if f.Model == nil {
+		return ret
+	}
+	total := 0.0
+	for _, m := range f.Model.Models {
+		tree, ok := m.(*trees.ID3DecisionTree)
+		if !ok {
+			continue
+		}
+		for a, v := range tree.GetFeatureImportances() {
+			ret[a] += v
+			total += v
+		}
+	}
+	if total > 0 {
+		for a := range ret {
+			ret[a] /= total
+		}
+	}
+	return ret
+}
+
 // String returns a human-readable representation of this tree.
 func (f *RandomForest) String() string {
 	return fmt.Sprintf("RandomForest(ForestSize: %d, Features:%d, %s\n)", f.ForestSize, f.Features, f.Model)
@@ -1,6 +1,7 @@
 package ensemble
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/sjwhitworth/golearn/base"
@@ -53,3 +54,65 @@ func TestRandomForest(t *testing.T) {
 		})
 	})
 }
+
+func TestRandomForestClassWeights(t *testing.T) {
+	Convey("Given an imbalanced dataset", t, func() {
+		rand.Seed(42)
+		// When x is p, 8 rows are neg and 2 are pos; when it's q,
+		// all 10 are neg
+		inst := base.NewDenseInstances()
+		attrs := make([]*base.CategoricalAttribute, 3)
+		specs := make([]base.AttributeSpec, 3)
+		for i, name := range []string{"x", "y", "class"} {
+			attrs[i] = base.NewCategoricalAttribute()
+			attrs[i].SetName(name)
+			specs[i] = inst.AddAttribute(attrs[i])
+		}
+		inst.AddClassAttribute(attrs[2])
+		inst.Extend(20)
+		for i := 0; i < 20; i++ {
+			values := []string{"q", "a", "neg"}
+			if i < 10 {
+				values[0] = "p"
+			}
+			if i < 2 {
+				values[2] = "pos"
+			}
+			for j, v := range values {
+				inst.Set(specs[j], i, attrs[j].GetSysValFromString(v))
+			}
+		}
+
+		Convey("An unweighted forest should predict the majority class", func() {
+			rf := NewRandomForest(10, 2)
+			So(rf.Fit(inst), ShouldBeNil)
+			predictions, err := rf.Predict(inst)
+			So(err, ShouldBeNil)
+			So(base.GetClass(predictions, 0), ShouldEqual, "neg")
+		})
+
+		Convey("A weighted forest should favour the rare class", func() {
+			rf := NewRandomForest(10, 2)
+			rf.ClassWeights = map[string]float64{"pos": 100}
+			So(rf.Fit(inst), ShouldBeNil)
+			predictions, err := rf.Predict(inst)
+			So(err, ShouldBeNil)
+			So(base.GetClass(predictions, 0), ShouldEqual, "pos")
+			So(base.GetClass(predictions, 19), ShouldEqual, "neg")
+		})
+
+		Convey("Feature importances should favour the informative attribute", func() {
+			rf := NewRandomForest(10, 2)
+			So(rf.Fit(inst), ShouldBeNil)
+			importances := rf.GetFeatureImportances()
+			So(importances[attrs[0]], ShouldAlmostEqual, 1, 1e-9)
+			So(importances[attrs[1]], ShouldAlmostEqual, 0, 1e-9)
+		})
+
+		Convey("Invalid weights should be rejected", func() {
+			rf := NewRandomForest(10, 2)
+			rf.ClassWeights = map[string]float64{"pos": 0}
+			So(rf.Fit(inst), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,140 @@
+package evaluation
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// This file contains measures of how well predicted probabilities are
+// calibrated. They accept the reference (`ref') Instances and a grid of
+// probabilities (`probs') with a FloatAttribute named after each class
+// value, like those returned by PredictProba, and look at the
+// probabilities of a single class.
+
+// ReliabilityBin summarises the predictions whose probability for a
+// class fell in one bin of a reliability curve.
+type ReliabilityBin struct {
+	// Range of probabilities covered by the bin
+	Lower, Upper float64
+	// Mean predicted probability of the rows in the bin
+	MeanPredicted float64
+	// Fraction of the rows in the bin which belong to the class
+	FractionPositive float64
+	// Number of rows in the bin
+	Count int
+}
+
+// getClassProbabilities returns the probability of class for each row
+// of probs, and whether each row of ref belongs to it.
+func getClassProbabilities(ref, probs base.FixedDataGrid, class string) ([]float64, []bool, error) {
+	_, refRows := ref.Size()
+	_, probRows := probs.Size()
+	if refRows != probRows {
+		return nil, nil, fmt.Errorf("Row count mismatch: ref has %d rows, probs has %d rows", refRows, probRows)
+	}
+	var spec base.AttributeSpec
+	found := false
+	for _, a := range probs.AllAttributes() {
+		if _, ok := a.(*base.FloatAttribute); ok && a.GetName() == class {
+			s, err := probs.GetAttribute(a)
+			if err != nil {
+				return nil, nil, err
+			}
+			spec, found = s, true
+			break
+		}
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("probs has no FloatAttribute named %s", class)
+	}
+	p := make([]float64, refRows)
+	positive := make([]bool, refRows)
+	for i := range p {
+		p[i] = base.UnpackBytesToFloat(probs.Get(spec, i))
+		positive[i] = base.GetClass(ref, i) == class
+	}
+	return p, positive, nil
+}
+
+// GetReliabilityCurve divides the probabilities predicted for a class
+// into the given number of equal-width bins between 0 and 1, and
+// returns the mean predicted probability and the observed fraction of
+// the class in each. For well-calibrated probabilities the two are
+// close. Empty bins are left out.
+func GetReliabilityCurve(ref, probs base.FixedDataGrid, class string, bins int) ([]ReliabilityBin, error) {
+	if bins < 1 {
+		return nil, fmt.Errorf("Need at least one bin")
+	}
+	p, positive, err := getClassProbabilities(ref, probs, class)
+	if err != nil {
+		return nil, err
+	}
+	sums := make([]float64, bins)
+	positives := make([]int, bins)
+	counts := make([]int, bins)
+	for i, v := range p {
+		b := int(math.Floor(v * float64(bins)))
+		if b < 0 {
+			b = 0
+		} else if b >= bins {
+			b = bins - 1
+		}
+		sums[b] += v
+		counts[b]++
+		if positive[i] {
+			positives[b]++
+		}
+	}
+	ret := make([]ReliabilityBin, 0, bins)
+	for b := range counts {
+		if counts[b] == 0 {
+			continue
+		}
+		ret = append(ret, ReliabilityBin{
+			Lower:            float64(b) / float64(bins),
+			Upper:            float64(b+1) / float64(bins),
+			MeanPredicted:    sums[b] / float64(counts[b]),
+			FractionPositive: float64(positives[b]) / float64(counts[b]),
+			Count:            counts[b],
+		})
+	}
+	return ret, nil
+}
+
+// GetExpectedCalibrationError returns the mean absolute difference
+// between the predicted probability and the observed fraction over the
+// bins of a reliability curve, weighted by the number of rows in each.
+func GetExpectedCalibrationError(curve []ReliabilityBin) float64 {
+	total, count := 0.0, 0
+	for _, b := range curve {
+		total += float64(b.Count) * math.Abs(b.MeanPredicted-b.FractionPositive)
+		count += b.Count
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// GetBrierScore returns the mean squared difference between the
+// probability predicted for a class and whether each row belongs to
+// it. Lower is better.
+func GetBrierScore(ref, probs base.FixedDataGrid, class string) (float64, error) {
+	p, positive, err := getClassProbabilities(ref, probs, class)
+	if err != nil {
+		return 0, err
+	}
+	if len(p) == 0 {
+		return 0, fmt.Errorf("No rows to compare")
+	}
+	total := 0.0
+	for i, v := range p {
+		target := 0.0
+		if positive[i] {
+			target = 1
+		}
+		total += (v - target) * (v - target)
+	}
+	return total / float64(len(p)), nil
+}
@@ -0,0 +1,84 @@
+package evaluation
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+// buildProbabilities creates a DenseInstances with a FloatAttribute for
+// each class, holding the given probabilities.
+func buildProbabilities(classes []string, probs [][]float64) *base.DenseInstances {
+	inst := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, len(classes))
+	for k, c := range classes {
+		specs[k] = inst.AddAttribute(base.NewFloatAttribute(c))
+	}
+	inst.Extend(len(probs))
+	for i, row := range probs {
+		for k, p := range row {
+			inst.Set(specs[k], i, base.PackFloatToBytes(p))
+		}
+	}
+	return inst
+}
+
+func TestCalibrationMetrics(t *testing.T) {
+	Convey("Given some predicted probabilities", t, func() {
+		ref := buildLabels([]string{"A", "A", "B", "B", "A"})
+		probs := buildProbabilities([]string{"A", "B"}, [][]float64{
+			{0.9, 0.1}, {0.7, 0.3}, {0.2, 0.8}, {0.4, 0.6}, {0.95, 0.05},
+		})
+
+		Convey("The reliability curve should summarise each bin", func() {
+			curve, err := GetReliabilityCurve(ref, probs, "A", 2)
+			So(err, ShouldBeNil)
+			So(len(curve), ShouldEqual, 2)
+			So(curve[0].Count, ShouldEqual, 2)
+			So(curve[0].MeanPredicted, ShouldAlmostEqual, 0.3)
+			So(curve[0].FractionPositive, ShouldEqual, 0)
+			So(curve[1].Lower, ShouldEqual, 0.5)
+			So(curve[1].Count, ShouldEqual, 3)
+			So(curve[1].MeanPredicted, ShouldAlmostEqual, 0.85)
+			So(curve[1].FractionPositive, ShouldEqual, 1)
+
+			So(GetExpectedCalibrationError(curve), ShouldAlmostEqual, 0.21)
+		})
+
+		Convey("Empty bins should be left out", func() {
+			curve, err := GetReliabilityCurve(ref, probs, "A", 10)
+			So(err, ShouldBeNil)
+			So(len(curve), ShouldEqual, 4)
+			total := 0
+			for _, b := range curve {
+				total += b.Count
+			}
+			So(total, ShouldEqual, 5)
+		})
+
+		Convey("The Brier score should be the mean squared error", func() {
+			score, err := GetBrierScore(ref, probs, "A")
+			So(err, ShouldBeNil)
+			So(score, ShouldAlmostEqual, 0.0605)
+		})
+
+		Convey("Unknown classes and mismatched grids should be rejected", func() {
+			_, err := GetReliabilityCurve(ref, probs, "C", 2)
+			So(err, ShouldNotBeNil)
+			_, err = GetReliabilityCurve(ref, probs, "A", 0)
+			So(err, ShouldNotBeNil)
+			_, err = GetBrierScore(buildLabels([]string{"A"}), probs, "A")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("A probability of one should land in the last bin", t, func() {
+		ref := buildLabels([]string{"A", "B"})
+		probs := buildProbabilities([]string{"A"}, [][]float64{{1}, {0}})
+		curve, err := GetReliabilityCurve(ref, probs, "A", 5)
+		So(err, ShouldBeNil)
+		So(len(curve), ShouldEqual, 2)
+		So(curve[1].Upper, ShouldEqual, 1)
+		So(curve[1].FractionPositive, ShouldEqual, 1)
+	})
+}
@@ -0,0 +1,456 @@
+package evaluation
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// This file contains measures for evaluating clusterings.
+//
+// Internal measures (silhouette, Calinski-Harabasz and Davies-Bouldin)
+// judge a set of cluster labels using only the non-class FloatAttributes
+// of the data which was clustered.
+//
+// External measures compare the class Attribute of a grid of
+// predicted cluster labels against the class Attribute of a reference
+// grid. Cluster labels don't need to match the reference class values.
+
+// getClusterRows retrieves the non-class FloatAttribute values of X
+// and the class label of each corresponding row of labels.
+func getClusterRows(X, labels base.FixedDataGrid) ([][]float64, []string, error) {
+	_, rows := X.Size()
+	_, labelRows := labels.Size()
+	if rows != labelRows {
+		return nil, nil, fmt.Errorf("Row count mismatch: data has %d rows, labels has %d rows", rows, labelRows)
+	}
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return nil, nil, fmt.Errorf("No FloatAttributes to evaluate")
+	}
+	values, err := base.CopyFloatRows(X, attrs)
+	if err != nil {
+		return nil, nil, err
+	}
+	points := make([][]float64, rows)
+	for i := range points {
+		points[i] = values[i*len(attrs) : (i+1)*len(attrs)]
+	}
+
+	assignments := make([]string, rows)
+	for i := range assignments {
+		assignments[i] = base.GetClass(labels, i)
+	}
+	return points, assignments, nil
+}
+
+// groupClusterRows returns the row indices belonging to each cluster.
+func groupClusterRows(assignments []string) map[string][]int {
+	ret := make(map[string][]int)
+	for i, a := range assignments {
+		ret[a] = append(ret[a], i)
+	}
+	return ret
+}
+
+func euclideanDistance(a, b []float64) float64 {
+	ret := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		ret += d * d
+	}
+	return math.Sqrt(ret)
+}
+
+func centroid(points [][]float64, members []int) []float64 {
+	ret := make([]float64, len(points[members[0]]))
+	for _, m := range members {
+		for j, v := range points[m] {
+			ret[j] += v
+		}
+	}
+	for j := range ret {
+		ret[j] /= float64(len(members))
+	}
+	return ret
+}
+
+// GetSilhouetteScore returns the mean silhouette coefficient across
+// every row of X, clustered according to the class Attribute of labels.
+//
+// For each row, a is the mean distance to the other members of its
+// cluster and b the mean distance to the members of the nearest other
+// cluster; the coefficient is (b - a) / max(a, b). Rows in singleton
+// clusters score 0. Results lie between -1 (wrong) and 1 (dense,
+// well-separated clusters).
+func GetSilhouetteScore(X, labels base.FixedDataGrid) (float64, error) {
+	points, assignments, err := getClusterRows(X, labels)
+	if err != nil {
+		return 0, err
+	}
+	clusters := groupClusterRows(assignments)
+	if len(clusters) < 2 || len(clusters) >= len(points) {
+		return 0, fmt.Errorf("Silhouette needs between 2 and %d clusters, got %d", len(points)-1, len(clusters))
+	}
+
+	total := 0.0
+	for i, p := range points {
+		own := clusters[assignments[i]]
+		if len(own) == 1 {
+			continue
+		}
+		a := 0.0
+		for _, j := range own {
+			a += euclideanDistance(p, points[j])
+		}
+		a /= float64(len(own) - 1)
+
+		b := math.Inf(1)
+		for c, members := range clusters {
+			if c == assignments[i] {
+				continue
+			}
+			d := 0.0
+			for _, j := range members {
+				d += euclideanDistance(p, points[j])
+			}
+			d /= float64(len(members))
+			if d < b {
+				b = d
+			}
+		}
+		total += (b - a) / math.Max(a, b)
+	}
+	return total / float64(len(points)), nil
+}
+
+// GetCalinskiHarabaszScore returns the ratio of between-cluster
+// dispersion to within-cluster dispersion (the Variance Ratio
+// Criterion), each normalised by its degrees of freedom. Higher is
+// better.
+func GetCalinskiHarabaszScore(X, labels base.FixedDataGrid) (float64, error) {
+	points, assignments, err := getClusterRows(X, labels)
+	if err != nil {
+		return 0, err
+	}
+	clusters := groupClusterRows(assignments)
+	n, k := len(points), len(clusters)
+	if k < 2 || k >= n {
+		return 0, fmt.Errorf("Calinski-Harabasz needs between 2 and %d clusters, got %d", n-1, k)
+	}
+
+	all := make([]int, n)
+	for i := range all {
+		all[i] = i
+	}
+	overall := centroid(points, all)
+
+	between, within := 0.0, 0.0
+	for _, members := range clusters {
+		c := centroid(points, members)
+		d := euclideanDistance(c, overall)
+		between += float64(len(members)) * d * d
+		for _, m := range members {
+			d := euclideanDistance(points[m], c)
+			within += d * d
+		}
+	}
+	if within == 0 {
+		return 1, nil
+	}
+	return between * float64(n-k) / (within * float64(k-1)), nil
+}
+
+// GetDaviesBouldinScore returns the average similarity between each
+// cluster and its most similar neighbour, where similarity is the ratio
+// of the clusters' summed scatter (mean distance to centroid) to the
+// distance between their centroids. Lower is better, with 0 the minimum.
+func GetDaviesBouldinScore(X, labels base.FixedDataGrid) (float64, error) {
+	points, assignments, err := getClusterRows(X, labels)
+	if err != nil {
+		return 0, err
+	}
+	clusters := groupClusterRows(assignments)
+	if len(clusters) < 2 {
+		return 0, fmt.Errorf("Davies-Bouldin needs at least 2 clusters, got %d", len(clusters))
+	}
+
+	centroids := make([][]float64, 0, len(clusters))
+	scatter := make([]float64, 0, len(clusters))
+	for _, members := range clusters {
+		c := centroid(points, members)
+		s := 0.0
+		for _, m := range members {
+			s += euclideanDistance(points[m], c)
+		}
+		centroids = append(centroids, c)
+		scatter = append(scatter, s/float64(len(members)))
+	}
+
+	total := 0.0
+	for i := range centroids {
+		worst := 0.0
+		for j := range centroids {
+			if i == j {
+				continue
+			}
+			d := euclideanDistance(centroids[i], centroids[j])
+			if d == 0 {
+				continue
+			}
+			if r := (scatter[i] + scatter[j]) / d; r > worst {
+				worst = r
+			}
+		}
+		total += worst
+	}
+	return total / float64(len(centroids)), nil
+}
+
+// contingencyMarginals returns the row (reference class) sums, the
+// column (cluster) sums and the total of a ConfusionMatrix.
+func contingencyMarginals(c ConfusionMatrix) (map[string]int, map[string]int, int) {
+	classSums := make(map[string]int)
+	clusterSums := make(map[string]int)
+	total := 0
+	for ref := range c {
+		for gen, n := range c[ref] {
+			classSums[ref] += n
+			clusterSums[gen] += n
+			total += n
+		}
+	}
+	return classSums, clusterSums, total
+}
+
+func choose2(n int) float64 {
+	return float64(n) * float64(n-1) / 2
+}
+
+func entropyOfCounts(counts map[string]int, total int) float64 {
+	ret := 0.0
+	for _, n := range counts {
+		if n == 0 {
+			continue
+		}
+		p := float64(n) / float64(total)
+		ret -= p * math.Log(p)
+	}
+	return ret
+}
+
+func mutualInformation(c ConfusionMatrix, classSums, clusterSums map[string]int, total int) float64 {
+	ret := 0.0
+	for ref := range c {
+		for gen, n := range c[ref] {
+			if n == 0 {
+				continue
+			}
+			nij := float64(n)
+			ret += nij / float64(total) * math.Log(nij*float64(total)/float64(classSums[ref]*clusterSums[gen]))
+		}
+	}
+	return ret
+}
+
+func adjustedRandIndex(c ConfusionMatrix) float64 {
+	classSums, clusterSums, total := contingencyMarginals(c)
+	index := 0.0
+	for ref := range c {
+		for _, n := range c[ref] {
+			index += choose2(n)
+		}
+	}
+	sumClasses, sumClusters := 0.0, 0.0
+	for _, n := range classSums {
+		sumClasses += choose2(n)
+	}
+	for _, n := range clusterSums {
+		sumClusters += choose2(n)
+	}
+	expected := sumClasses * sumClusters / choose2(total)
+	maximum := (sumClasses + sumClusters) / 2
+	if maximum == expected {
+		// Both partitions are trivial (one cluster, or all singletons)
+		return 1.0
+	}
+	return (index - expected) / (maximum - expected)
+}
+
+func mutualInformationOf(c ConfusionMatrix) float64 {
+	classSums, clusterSums, total := contingencyMarginals(c)
+	return mutualInformation(c, classSums, clusterSums, total)
+}
+
+func normalisedMutualInformation(c ConfusionMatrix) float64 {
+	classSums, clusterSums, total := contingencyMarginals(c)
+	hClass := entropyOfCounts(classSums, total)
+	hCluster := entropyOfCounts(clusterSums, total)
+	if hClass == 0 && hCluster == 0 {
+		return 1.0
+	}
+	mi := mutualInformation(c, classSums, clusterSums, total)
+	return mi / ((hClass + hCluster) / 2)
+}
+
+// expectedMutualInformation returns the mutual information expected
+// between two random partitions with the given marginals, following
+// Vinh, Epps and Bailey (2010).
+func expectedMutualInformation(classSums, clusterSums map[string]int, total int) float64 {
+	n := float64(total)
+	lgN, _ := math.Lgamma(n + 1)
+	ret := 0.0
+	for _, a := range classSums {
+		lgA, _ := math.Lgamma(float64(a) + 1)
+		lgNA, _ := math.Lgamma(n - float64(a) + 1)
+		for _, b := range clusterSums {
+			lgB, _ := math.Lgamma(float64(b) + 1)
+			lgNB, _ := math.Lgamma(n - float64(b) + 1)
+			start := a + b - total
+			if start < 1 {
+				start = 1
+			}
+			end := a
+			if b < end {
+				end = b
+			}
+			for nij := start; nij <= end; nij++ {
+				fn := float64(nij)
+				term := fn / n * math.Log(n*fn/(float64(a)*float64(b)))
+				lgNij, _ := math.Lgamma(fn + 1)
+				lgAN, _ := math.Lgamma(float64(a-nij) + 1)
+				lgBN, _ := math.Lgamma(float64(b-nij) + 1)
+				lgRest, _ := math.Lgamma(n - float64(a) - float64(b) + fn + 1)
+				logProb := lgA + lgB + lgNA + lgNB - lgN - lgNij - lgAN - lgBN - lgRest
+				ret += term * math.Exp(logProb)
+			}
+		}
+	}
+	return ret
+}
+
+func adjustedMutualInformation(c ConfusionMatrix) float64 {
+	classSums, clusterSums, total := contingencyMarginals(c)
+	if len(classSums) == len(clusterSums) && (len(classSums) == 1 || len(classSums) == total) {
+		return 1.0
+	}
+	hClass := entropyOfCounts(classSums, total)
+	hCluster := entropyOfCounts(clusterSums, total)
+	mi := mutualInformation(c, classSums, clusterSums, total)
+	emi := expectedMutualInformation(classSums, clusterSums, total)
+	denominator := (hClass+hCluster)/2 - emi
+	if denominator == 0 {
+		return 1.0
+	}
+	return (mi - emi) / denominator
+}
+
+func homogeneity(c ConfusionMatrix) float64 {
+	classSums, clusterSums, total := contingencyMarginals(c)
+	hClass := entropyOfCounts(classSums, total)
+	if hClass == 0 {
+		return 1.0
+	}
+	return mutualInformation(c, classSums, clusterSums, total) / hClass
+}
+
+func completeness(c ConfusionMatrix) float64 {
+	classSums, clusterSums, total := contingencyMarginals(c)
+	hCluster := entropyOfCounts(clusterSums, total)
+	if hCluster == 0 {
+		return 1.0
+	}
+	return mutualInformation(c, classSums, clusterSums, total) / hCluster
+}
+
+func vMeasure(c ConfusionMatrix) float64 {
+	h := homogeneity(c)
+	comp := completeness(c)
+	if h+comp == 0 {
+		return 0
+	}
+	return 2 * h * comp / (h + comp)
+}
+
+// contingencyTable returns the ConfusionMatrix of reference against
+// predicted, which doubles as a contingency table: the predicted
+// labels don't need to match the reference class values.
+func contingencyTable(reference, predicted base.FixedDataGrid) (ConfusionMatrix, error) {
+	if _, rows := reference.Size(); rows == 0 {
+		return nil, fmt.Errorf("No rows to compare")
+	}
+	return GetConfusionMatrix(reference, predicted)
+}
+
+// GetAdjustedRandIndex returns the Rand index of predicted against
+// reference (the fraction of row pairs on which the reference and the clustering
+// agree) adjusted for chance, so that random labellings score close to
+// 0 and identical partitions score 1.
+func GetAdjustedRandIndex(reference, predicted base.FixedDataGrid) (float64, error) {
+	c, err := contingencyTable(reference, predicted)
+	if err != nil {
+		return 0, err
+	}
+	return adjustedRandIndex(c), nil
+}
+
+// GetMutualInformation returns the mutual information (in nats)
+// between the reference classes and the clusters.
+func GetMutualInformation(reference, predicted base.FixedDataGrid) (float64, error) {
+	c, err := contingencyTable(reference, predicted)
+	if err != nil {
+		return 0, err
+	}
+	return mutualInformationOf(c), nil
+}
+
+// GetNormalisedMutualInformation returns the mutual information
+// divided by the arithmetic mean of the class and cluster entropies,
+// giving a value between 0 (independent) and 1 (identical partitions).
+func GetNormalisedMutualInformation(reference, predicted base.FixedDataGrid) (float64, error) {
+	c, err := contingencyTable(reference, predicted)
+	if err != nil {
+		return 0, err
+	}
+	return normalisedMutualInformation(c), nil
+}
+
+// GetAdjustedMutualInformation returns the mutual information adjusted
+// for chance, normalised by the arithmetic mean of the entropies.
+// Random labellings score close to 0 and identical partitions score 1.
+func GetAdjustedMutualInformation(reference, predicted base.FixedDataGrid) (float64, error) {
+	c, err := contingencyTable(reference, predicted)
+	if err != nil {
+		return 0, err
+	}
+	return adjustedMutualInformation(c), nil
+}
+
+// GetHomogeneity returns 1 - H(class|cluster) / H(class): it is 1 when
+// every cluster contains only members of a single reference class.
+func GetHomogeneity(reference, predicted base.FixedDataGrid) (float64, error) {
+	c, err := contingencyTable(reference, predicted)
+	if err != nil {
+		return 0, err
+	}
+	return homogeneity(c), nil
+}
+
+// GetCompleteness returns 1 - H(cluster|class) / H(cluster): it is 1
+// when every member of a given reference class is in the same cluster.
+func GetCompleteness(reference, predicted base.FixedDataGrid) (float64, error) {
+	c, err := contingencyTable(reference, predicted)
+	if err != nil {
+		return 0, err
+	}
+	return completeness(c), nil
+}
+
+// GetVMeasure computes the harmonic mean of homogeneity and completeness.
+func GetVMeasure(reference, predicted base.FixedDataGrid) (float64, error) {
+	c, err := contingencyTable(reference, predicted)
+	if err != nil {
+		return 0, err
+	}
+	return vMeasure(c), nil
+}
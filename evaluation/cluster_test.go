@@ -0,0 +1,114 @@
+package evaluation
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+// buildLabels creates a DenseInstances with a single categorical
+// class Attribute holding the given values.
+func buildLabels(vals []string) *base.DenseInstances {
+	inst := base.NewDenseInstances()
+	attr := base.NewCategoricalAttribute()
+	attr.SetName("Label")
+	spec := inst.AddAttribute(attr)
+	inst.AddClassAttribute(attr)
+	inst.Extend(len(vals))
+	for i, v := range vals {
+		inst.Set(spec, i, attr.GetSysValFromString(v))
+	}
+	return inst
+}
+
+// score returns the value of an external measure, which must succeed.
+func score(measure func(base.FixedDataGrid, base.FixedDataGrid) (float64, error), ref, gen base.FixedDataGrid) float64 {
+	ret, err := measure(ref, gen)
+	So(err, ShouldBeNil)
+	return ret
+}
+
+func TestClusterMetrics(t *testing.T) {
+	Convey("Given two clusters of 2-d points", t, func() {
+		points := [][]float64{{0, 0}, {0, 1}, {1, 0}, {5, 5}, {5, 6}, {6, 5}, {2, 2}}
+		data := base.NewDenseInstances()
+		specs := []base.AttributeSpec{
+			data.AddAttribute(base.NewFloatAttribute("x")),
+			data.AddAttribute(base.NewFloatAttribute("y")),
+		}
+		data.Extend(len(points))
+		for i, p := range points {
+			for j, v := range p {
+				data.Set(specs[j], i, base.PackFloatToBytes(v))
+			}
+		}
+		labels := buildLabels([]string{"A", "A", "A", "B", "B", "B", "A"})
+
+		Convey("Silhouette", func() {
+			s, err := GetSilhouetteScore(data, labels)
+			So(err, ShouldBeNil)
+			So(s, ShouldAlmostEqual, 0.7575327, 1e-6)
+		})
+
+		Convey("Calinski-Harabasz", func() {
+			s, err := GetCalinskiHarabaszScore(data, labels)
+			So(err, ShouldBeNil)
+			So(s, ShouldAlmostEqual, 52.7003484, 1e-6)
+		})
+
+		Convey("Davies-Bouldin", func() {
+			s, err := GetDaviesBouldinScore(data, labels)
+			So(err, ShouldBeNil)
+			So(s, ShouldAlmostEqual, 0.2709783, 1e-6)
+		})
+
+		Convey("Mismatched row counts should be an error", func() {
+			_, err := GetSilhouetteScore(data, buildLabels([]string{"A", "B"}))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("A single cluster should be an error", func() {
+			_, err := GetSilhouetteScore(data, buildLabels([]string{"A", "A", "A", "A", "A", "A", "A"}))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Compared against a reference class", func() {
+			ref := buildLabels([]string{"x", "x", "x", "y", "y", "y", "y"})
+			So(score(GetAdjustedRandIndex, ref, labels), ShouldAlmostEqual, 0.4166667, 1e-6)
+			So(score(GetMutualInformation, ref, labels), ShouldAlmostEqual, 0.3615737, 1e-6)
+			So(score(GetNormalisedMutualInformation, ref, labels), ShouldAlmostEqual, 0.5294618, 1e-6)
+			So(score(GetAdjustedMutualInformation, ref, labels), ShouldAlmostEqual, 0.4501631, 1e-6)
+		})
+
+		Convey("External measures should reject mismatched or empty grids", func() {
+			_, err := GetAdjustedRandIndex(labels, buildLabels([]string{"A", "B"}))
+			So(err, ShouldNotBeNil)
+			_, err = GetVMeasure(buildLabels(nil), buildLabels(nil))
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a clustering which splits one class", t, func() {
+		ref := buildLabels([]string{"0", "0", "1", "1"})
+		gen := buildLabels([]string{"0", "0", "1", "2"})
+
+		Convey("It should be homogeneous but incomplete", func() {
+			So(score(GetHomogeneity, ref, gen), ShouldAlmostEqual, 1.0, 1e-9)
+			So(score(GetCompleteness, ref, gen), ShouldAlmostEqual, 2.0/3.0, 1e-9)
+			So(score(GetVMeasure, ref, gen), ShouldAlmostEqual, 0.8, 1e-9)
+			So(score(GetAdjustedRandIndex, ref, gen), ShouldAlmostEqual, 0.5714286, 1e-6)
+		})
+	})
+
+	Convey("Given a relabelled but identical clustering", t, func() {
+		ref := buildLabels([]string{"a", "a", "b", "b", "c", "c"})
+		gen := buildLabels([]string{"2", "2", "0", "0", "1", "1"})
+
+		Convey("Every external measure should be 1", func() {
+			So(score(GetAdjustedRandIndex, ref, gen), ShouldAlmostEqual, 1.0, 1e-9)
+			So(score(GetNormalisedMutualInformation, ref, gen), ShouldAlmostEqual, 1.0, 1e-9)
+			So(score(GetAdjustedMutualInformation, ref, gen), ShouldAlmostEqual, 1.0, 1e-9)
+			So(score(GetVMeasure, ref, gen), ShouldAlmostEqual, 1.0, 1e-9)
+		})
+	})
+}
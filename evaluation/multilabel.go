@@ -0,0 +1,181 @@
+package evaluation
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"strconv"
+)
+
+// This file contains measures for evaluating predictions of several
+// class Attributes at once (such as multi-label classification, where
+// each class Attribute says whether a label applies).
+//
+// Every class Attribute of the reference (`ref') Instances is compared
+// with the same Attribute in the generated (`gen') ones. Values are
+// compared as strings, except that FloatAttributes are compared as
+// numbers (so a label of 1 matches whatever the precision).
+
+// labelValue returns the value of a class Attribute as a string.
+func labelValue(a base.Attribute, v []byte) string {
+	if _, ok := a.(*base.FloatAttribute); ok {
+		return strconv.FormatFloat(base.UnpackBytesToFloat(v), 'g', -1, 64)
+	}
+	return a.GetStringFromSysVal(v)
+}
+
+// getLabelValues returns the value of each class Attribute of ref on
+// each row of ref and gen, along with the Attributes' names.
+func getLabelValues(ref, gen base.FixedDataGrid) ([]string, [][]string, [][]string, error) {
+	_, refRows := ref.Size()
+	_, genRows := gen.Size()
+	if refRows != genRows {
+		return nil, nil, nil, fmt.Errorf("Row count mismatch: ref has %d rows, gen has %d rows", refRows, genRows)
+	}
+	classAttrs := ref.AllClassAttributes()
+	if len(classAttrs) == 0 {
+		return nil, nil, nil, fmt.Errorf("ref has no class Attributes")
+	}
+	names := make([]string, len(classAttrs))
+	refValues := make([][]string, refRows)
+	genValues := make([][]string, refRows)
+	for i := range refValues {
+		refValues[i] = make([]string, len(classAttrs))
+		genValues[i] = make([]string, len(classAttrs))
+	}
+	for j, a := range classAttrs {
+		names[j] = a.GetName()
+		refSpec, err := ref.GetAttribute(a)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		genSpec, err := gen.GetAttribute(a)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("gen is missing class Attribute %s: %s", a.GetName(), err)
+		}
+		for i := 0; i < refRows; i++ {
+			refValues[i][j] = labelValue(a, ref.Get(refSpec, i))
+			genValues[i][j] = labelValue(a, gen.Get(genSpec, i))
+		}
+	}
+	return names, refValues, genValues, nil
+}
+
+// GetHammingLoss returns the fraction of class Attribute values which
+// were predicted incorrectly, over every row and class Attribute.
+func GetHammingLoss(ref, gen base.FixedDataGrid) (float64, error) {
+	names, refValues, genValues, err := getLabelValues(ref, gen)
+	if err != nil {
+		return 0, err
+	}
+	if len(refValues) == 0 {
+		return 0, fmt.Errorf("No rows to compare")
+	}
+	wrong := 0
+	for i, row := range refValues {
+		for j, v := range row {
+			if genValues[i][j] != v {
+				wrong++
+			}
+		}
+	}
+	return float64(wrong) / float64(len(refValues)*len(names)), nil
+}
+
+// GetSubsetAccuracy returns the fraction of rows for which every class
+// Attribute was predicted correctly.
+func GetSubsetAccuracy(ref, gen base.FixedDataGrid) (float64, error) {
+	_, refValues, genValues, err := getLabelValues(ref, gen)
+	if err != nil {
+		return 0, err
+	}
+	if len(refValues) == 0 {
+		return 0, fmt.Errorf("No rows to compare")
+	}
+	correct := 0
+	for i, row := range refValues {
+		matched := true
+		for j, v := range row {
+			if genValues[i][j] != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(refValues)), nil
+}
+
+// GetLabelConfusionMatrices builds a ConfusionMatrix for each class
+// Attribute, keyed by its name.
+func GetLabelConfusionMatrices(ref, gen base.FixedDataGrid) (map[string]ConfusionMatrix, error) {
+	names, refValues, genValues, err := getLabelValues(ref, gen)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]ConfusionMatrix)
+	for j, name := range names {
+		c := make(ConfusionMatrix)
+		for i, row := range refValues {
+			if _, ok := c[row[j]]; !ok {
+				c[row[j]] = make(map[string]int)
+			}
+			c[row[j]][genValues[i][j]]++
+		}
+		ret[name] = c
+	}
+	return ret, nil
+}
+
+// labelCounts returns the true positives, false positives and false
+// negatives for the given positive value.
+func labelCounts(positive string, c ConfusionMatrix) (float64, float64, float64) {
+	return GetTruePositives(positive, c), GetFalsePositives(positive, c), GetFalseNegatives(positive, c)
+}
+
+// f1 returns the F1 score given the true positives, false positives
+// and false negatives, or 0 if there are no positives at all.
+func f1(tp, fp, fn float64) float64 {
+	if tp+fp+fn == 0 {
+		return 0
+	}
+	return 2 * tp / (2*tp + fp + fn)
+}
+
+// GetLabelwiseF1Scores returns the F1 score of each class Attribute in
+// a set of matrices from GetLabelConfusionMatrices, treating the given
+// value (such as "1") as positive. A label which is never positive in
+// either the reference or the predictions scores 0.
+func GetLabelwiseF1Scores(matrices map[string]ConfusionMatrix, positive string) map[string]float64 {
+	ret := make(map[string]float64)
+	for name, c := range matrices {
+		ret[name] = f1(labelCounts(positive, c))
+	}
+	return ret
+}
+
+// GetMacroLabelF1Score returns the mean of GetLabelwiseF1Scores, which
+// weights every class Attribute equally.
+func GetMacroLabelF1Score(matrices map[string]ConfusionMatrix, positive string) float64 {
+	if len(matrices) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, score := range GetLabelwiseF1Scores(matrices, positive) {
+		total += score
+	}
+	return total / float64(len(matrices))
+}
+
+// GetMicroLabelF1Score returns the F1 score of the true positives,
+// false positives and false negatives summed over every class
+// Attribute, which weights every positive value equally.
+func GetMicroLabelF1Score(matrices map[string]ConfusionMatrix, positive string) float64 {
+	tp, fp, fn := 0.0, 0.0, 0.0
+	for _, c := range matrices {
+		t, p, n := labelCounts(positive, c)
+		tp, fp, fn = tp+t, fp+p, fn+n
+	}
+	return f1(tp, fp, fn)
+}
@@ -0,0 +1,103 @@
+package evaluation
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+// buildMultiLabels creates a DenseInstances with the given class
+// Attributes, one column of values each.
+func buildMultiLabels(attrs []base.Attribute, rows [][]string) *base.DenseInstances {
+	inst := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, len(attrs))
+	for j, a := range attrs {
+		specs[j] = inst.AddAttribute(a)
+		inst.AddClassAttribute(a)
+	}
+	inst.Extend(len(rows))
+	for i, row := range rows {
+		for j, v := range row {
+			inst.Set(specs[j], i, attrs[j].GetSysValFromString(v))
+		}
+	}
+	return inst
+}
+
+func TestMultiLabelMetrics(t *testing.T) {
+	Convey("Given three labels", t, func() {
+		attrs := make([]base.Attribute, 3)
+		for j, name := range []string{"a", "b", "c"} {
+			a := base.NewCategoricalAttribute()
+			a.SetName(name)
+			attrs[j] = a
+		}
+		ref := buildMultiLabels(attrs, [][]string{
+			{"1", "0", "1"},
+			{"0", "1", "0"},
+			{"1", "1", "0"},
+			{"0", "0", "1"},
+		})
+		gen := buildMultiLabels(attrs, [][]string{
+			{"1", "0", "1"},
+			{"0", "0", "0"},
+			{"1", "1", "1"},
+			{"1", "0", "1"},
+		})
+
+		Convey("The Hamming loss should be the fraction of wrong values", func() {
+			loss, err := GetHammingLoss(ref, gen)
+			So(err, ShouldBeNil)
+			So(loss, ShouldAlmostEqual, 0.25)
+
+			loss, err = GetHammingLoss(ref, ref)
+			So(err, ShouldBeNil)
+			So(loss, ShouldEqual, 0)
+		})
+
+		Convey("The subset accuracy should be the fraction of perfect rows", func() {
+			acc, err := GetSubsetAccuracy(ref, gen)
+			So(err, ShouldBeNil)
+			So(acc, ShouldAlmostEqual, 0.25)
+		})
+
+		Convey("The F1 scores should treat each label separately", func() {
+			matrices, err := GetLabelConfusionMatrices(ref, gen)
+			So(err, ShouldBeNil)
+			So(len(matrices), ShouldEqual, 3)
+			So(matrices["b"]["1"]["0"], ShouldEqual, 1)
+
+			scores := GetLabelwiseF1Scores(matrices, "1")
+			So(scores["a"], ShouldAlmostEqual, 0.8)
+			So(scores["b"], ShouldAlmostEqual, 2.0/3)
+			So(scores["c"], ShouldAlmostEqual, 0.8)
+			So(GetMacroLabelF1Score(matrices, "1"), ShouldAlmostEqual, (0.8+2.0/3+0.8)/3)
+			So(GetMicroLabelF1Score(matrices, "1"), ShouldAlmostEqual, 10.0/13)
+		})
+
+		Convey("A label which is never positive should score 0", func() {
+			matrices, err := GetLabelConfusionMatrices(ref, gen)
+			So(err, ShouldBeNil)
+			So(GetLabelwiseF1Scores(matrices, "missing")["a"], ShouldEqual, 0)
+		})
+
+		Convey("Mismatched grids should be rejected", func() {
+			short := buildMultiLabels(attrs, [][]string{{"1", "0", "1"}})
+			_, err := GetHammingLoss(ref, short)
+			So(err, ShouldNotBeNil)
+
+			other := buildMultiLabels(attrs[:2], [][]string{{"1", "0"}, {"0", "1"}, {"1", "1"}, {"0", "0"}})
+			_, err = GetSubsetAccuracy(ref, other)
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("FloatAttribute labels should be compared as numbers", t, func() {
+		a := base.NewFloatAttribute("a")
+		ref := buildMultiLabels([]base.Attribute{a}, [][]string{{"1"}, {"0"}, {"1"}})
+		gen := buildMultiLabels([]base.Attribute{a}, [][]string{{"1.0"}, {"0"}, {"0"}})
+		matrices, err := GetLabelConfusionMatrices(ref, gen)
+		So(err, ShouldBeNil)
+		So(GetLabelwiseF1Scores(matrices, "1")["a"], ShouldAlmostEqual, 2.0/3)
+	})
+}
@@ -0,0 +1,119 @@
+// Package feature_selection chooses which of the non-class Attributes
+// of a dataset are worth keeping.
+//
+// SelectKBest, SelectPercentile:
+//
+//	Score each Attribute against the class using a ScoreFunc (Chi2,
+//	ANOVAF or MutualInformation) and keep the highest-scoring ones.
+//
+// VarianceThreshold:
+//
+//	Removes FloatAttributes whose values barely vary, and constant
+//	Attributes of other types.
+//
+// RFE:
+//
+//	Recursive feature elimination: repeatedly fits a model and
+//	removes the Attributes with the smallest coefficients or
+//	importances.
+//
+// Each implements Selector. Transform returns an InstancesView of
+// the selected Attributes and the class Attributes, so no data is
+// copied.
+package feature_selection
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"sort"
+)
+
+// Selector implementations choose a subset of the non-class Attributes
+// of the data passed to Fit, and keep only those (and the class
+// Attributes) in Transform.
+type Selector interface {
+	Fit(base.FixedDataGrid) error
+	Transform(base.FixedDataGrid) (base.FixedDataGrid, error)
+	FitTransform(base.FixedDataGrid) (base.FixedDataGrid, error)
+	GetSelectedAttributes() []base.Attribute
+}
+
+// featureAttributes returns the non-class Attributes of X in the
+// order of AllAttributes.
+func featureAttributes(X base.FixedDataGrid) []base.Attribute {
+	isClass := make(map[base.Attribute]bool)
+	for _, a := range X.AllClassAttributes() {
+		isClass[a] = true
+	}
+	ret := make([]base.Attribute, 0)
+	for _, a := range X.AllAttributes() {
+		if !isClass[a] {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// project returns a view of the selected Attributes and the class
+// Attributes of X.
+func project(X base.FixedDataGrid, selected []base.Attribute) (base.FixedDataGrid, error) {
+	if selected == nil {
+		return nil, base.NoTrainingDataError
+	}
+	attrs := make([]base.Attribute, 0, len(selected))
+	for _, a := range selected {
+		if _, err := X.GetAttribute(a); err != nil {
+			return nil, fmt.Errorf("Selected Attribute %s is missing: %s", a.GetName(), err)
+		}
+		attrs = append(attrs, a)
+	}
+	attrs = append(attrs, X.AllClassAttributes()...)
+	return base.NewInstancesViewFromAttrs(X, attrs), nil
+}
+
+// keepInOrder returns the members of attrs for which keep is true,
+// in their original order.
+func keepInOrder(attrs []base.Attribute, keep map[base.Attribute]bool) []base.Attribute {
+	ret := make([]base.Attribute, 0, len(keep))
+	for _, a := range attrs {
+		if keep[a] {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// attributeScores sorts Attributes by descending score, keeping the
+// original order of equal scores.
+type attributeScores struct {
+	attrs  []base.Attribute
+	scores []float64
+}
+
+func (a attributeScores) Len() int { return len(a.attrs) }
+func (a attributeScores) Swap(i, j int) {
+	a.attrs[i], a.attrs[j] = a.attrs[j], a.attrs[i]
+	a.scores[i], a.scores[j] = a.scores[j], a.scores[i]
+}
+func (a attributeScores) Less(i, j int) bool {
+	// NaN scores sort last
+	if a.scores[j] != a.scores[j] {
+		return a.scores[i] == a.scores[i]
+	}
+	return a.scores[i] > a.scores[j]
+}
+
+// best returns the k highest-scoring Attributes, in their original
+// order.
+func best(attrs []base.Attribute, scores []float64, k int) []base.Attribute {
+	sorted := attributeScores{
+		append([]base.Attribute(nil), attrs...),
+		append([]float64(nil), scores...),
+	}
+	sort.Stable(sorted)
+	keep := make(map[base.Attribute]bool)
+	for _, a := range sorted.attrs[:k] {
+		keep[a] = true
+	}
+	return keepInOrder(attrs, keep)
+}
@@ -0,0 +1,202 @@
+package feature_selection
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// ImportanceFunc fits a model to X and returns how important each of
+// its non-class Attributes was to the model. Attributes which are left
+// out are treated as having no importance.
+type ImportanceFunc func(X base.FixedDataGrid) (map[base.Attribute]float64, error)
+
+// CoefficientModel is a model with a single coefficient per feature,
+// such as linear_models.Ridge.
+type CoefficientModel interface {
+	Fit(base.FixedDataGrid) error
+	GetCoefficients() map[base.Attribute]float64
+}
+
+// MultiCoefficientModel is a model with a row of coefficients for each
+// class, such as softmax.SoftmaxRegression.
+type MultiCoefficientModel interface {
+	Fit(base.FixedDataGrid) error
+	GetAttributes() []base.Attribute
+	GetCoefficients() [][]float64
+}
+
+// ImportanceModel is a model which measures the importance of each
+// feature itself, such as ensemble.RandomForest.
+type ImportanceModel interface {
+	Fit(base.FixedDataGrid) error
+	GetFeatureImportances() map[base.Attribute]float64
+}
+
+// CoefficientImportance ranks Attributes by the absolute value of
+// their coefficients in m. The features should be on similar scales
+// for the coefficients to be comparable.
+func CoefficientImportance(m CoefficientModel) ImportanceFunc {
+	return func(X base.FixedDataGrid) (map[base.Attribute]float64, error) {
+		if err := m.Fit(X); err != nil {
+			return nil, err
+		}
+		ret := make(map[base.Attribute]float64)
+		for a, w := range m.GetCoefficients() {
+			ret[a] = math.Abs(w)
+		}
+		return ret, nil
+	}
+}
+
+// MultiCoefficientImportance ranks Attributes by the sum of the
+// absolute values of their coefficients in m over every class.
+func MultiCoefficientImportance(m MultiCoefficientModel) ImportanceFunc {
+	return func(X base.FixedDataGrid) (map[base.Attribute]float64, error) {
+		if err := m.Fit(X); err != nil {
+			return nil, err
+		}
+		attrs := m.GetAttributes()
+		ret := make(map[base.Attribute]float64)
+		for _, row := range m.GetCoefficients() {
+			if len(row) != len(attrs) {
+				return nil, fmt.Errorf("Expected %d coefficients, got %d", len(attrs), len(row))
+			}
+			for j, w := range row {
+				ret[attrs[j]] += math.Abs(w)
+			}
+		}
+		return ret, nil
+	}
+}
+
+// FeatureImportance ranks Attributes by the importances reported by m.
+func FeatureImportance(m ImportanceModel) ImportanceFunc {
+	return func(X base.FixedDataGrid) (map[base.Attribute]float64, error) {
+		if err := m.Fit(X); err != nil {
+			return nil, err
+		}
+		return m.GetFeatureImportances(), nil
+	}
+}
+
+// RFE performs recursive feature elimination: starting from every
+// non-class Attribute, it repeatedly fits a model to the remaining
+// Attributes and removes the Step least important ones, until Features
+// are left.
+type RFE struct {
+	// Fits the model and reports the importance of each Attribute
+	Importance ImportanceFunc
+	// Number of Attributes to keep, or 0 to keep half of them
+	Features int
+	// Number of Attributes removed in each round (at least 1)
+	Step     int
+	ranking  map[base.Attribute]int
+	selected []base.Attribute
+}
+
+// NewRFE returns an RFE which keeps the given number of Attributes,
+// removing one in each round.
+func NewRFE(importance ImportanceFunc, features int) *RFE {
+	return &RFE{Importance: importance, Features: features, Step: 1}
+}
+
+// String returns a human-readable summary.
+func (r *RFE) String() string {
+	return fmt.Sprintf("RFE(%d feature(s), step: %d)", r.Features, r.Step)
+}
+
+// Fit chooses which of the non-class Attributes of X to keep.
+func (r *RFE) Fit(X base.FixedDataGrid) error {
+	if r.Importance == nil {
+		return fmt.Errorf("No ImportanceFunc to rank Attributes with")
+	}
+	if r.Step < 1 {
+		return fmt.Errorf("Step must be at least 1")
+	}
+	remaining := featureAttributes(X)
+	if len(remaining) == 0 {
+		return fmt.Errorf("No Attributes to select from")
+	}
+	features := r.Features
+	if features == 0 {
+		features = (len(remaining) + 1) / 2
+	}
+	if features < 1 || features > len(remaining) {
+		return fmt.Errorf("Can't keep %d of %d Attribute(s)", features, len(remaining))
+	}
+
+	// Attributes removed in the same round share a rank, and those
+	// removed first have the highest
+	removed := make([][]base.Attribute, 0)
+	for len(remaining) > features {
+		view, err := project(X, remaining)
+		if err != nil {
+			return err
+		}
+		importances, err := r.Importance(view)
+		if err != nil {
+			return err
+		}
+		scores := make([]float64, len(remaining))
+		for j, a := range remaining {
+			scores[j] = importances[a]
+		}
+		keep := len(remaining) - r.Step
+		if keep < features {
+			keep = features
+		}
+		kept := best(remaining, scores, keep)
+		isKept := make(map[base.Attribute]bool)
+		for _, a := range kept {
+			isKept[a] = true
+		}
+		round := make([]base.Attribute, 0, len(remaining)-keep)
+		for _, a := range remaining {
+			if !isKept[a] {
+				round = append(round, a)
+			}
+		}
+		removed = append(removed, round)
+		remaining = kept
+	}
+
+	r.ranking = make(map[base.Attribute]int)
+	for _, a := range remaining {
+		r.ranking[a] = 1
+	}
+	for i, round := range removed {
+		for _, a := range round {
+			r.ranking[a] = len(removed) - i + 1
+		}
+	}
+	r.selected = remaining
+	return nil
+}
+
+// Transform keeps the selected Attributes of X and its class
+// Attributes.
+func (r *RFE) Transform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return project(X, r.selected)
+}
+
+// FitTransform fits to X and returns the selected Attributes of X.
+func (r *RFE) FitTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if err := r.Fit(X); err != nil {
+		return nil, err
+	}
+	return r.Transform(X)
+}
+
+// GetSelectedAttributes returns the Attributes kept by Transform, in
+// the order they appeared in the training data.
+func (r *RFE) GetSelectedAttributes() []base.Attribute {
+	return r.selected
+}
+
+// GetRanking returns the rank of each non-class Attribute of the
+// training data: 1 for those which were selected, 2 for those removed
+// in the last round, and so on.
+func (r *RFE) GetRanking() map[base.Attribute]int {
+	return r.ranking
+}
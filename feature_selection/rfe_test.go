@@ -0,0 +1,112 @@
+package feature_selection
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/linear_models"
+	"github.com/sjwhitworth/golearn/softmax"
+	"github.com/sjwhitworth/golearn/trees"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+// regressionData returns rows with FloatAttributes a, b, c and d,
+// drawn from a standard normal distribution, and a FloatAttribute y
+// equal to 3a + b + 0.5c plus a little noise.
+func regressionData(rows int) (*base.DenseInstances, []base.Attribute) {
+	inst := base.NewDenseInstances()
+	attrs := make([]base.Attribute, 4)
+	specs := make([]base.AttributeSpec, 4)
+	for j, name := range []string{"a", "b", "c", "d"} {
+		attrs[j] = base.NewFloatAttribute(name)
+		specs[j] = inst.AddAttribute(attrs[j])
+	}
+	y := base.NewFloatAttribute("y")
+	ySpec := inst.AddAttribute(y)
+	inst.AddClassAttribute(y)
+	inst.Extend(rows)
+	for i := 0; i < rows; i++ {
+		target := rand.NormFloat64() * 0.1
+		for j, w := range []float64{3, 1, 0.5, 0} {
+			v := rand.NormFloat64()
+			target += w * v
+			inst.Set(specs[j], i, base.PackFloatToBytes(v))
+		}
+		inst.Set(ySpec, i, base.PackFloatToBytes(target))
+	}
+	return inst, attrs
+}
+
+func TestRFE(t *testing.T) {
+	Convey("Given data where some features matter more than others", t, func() {
+		rand.Seed(42)
+		inst, attrs := regressionData(200)
+
+		Convey("RFE driven by Ridge coefficients should keep the largest", func() {
+			r := NewRFE(CoefficientImportance(linear_models.NewRidge(0.1)), 2)
+			out, err := r.FitTransform(inst)
+			So(err, ShouldBeNil)
+			So(r.GetSelectedAttributes(), ShouldResemble, attrs[:2])
+			ranking := r.GetRanking()
+			So(ranking[attrs[0]], ShouldEqual, 1)
+			So(ranking[attrs[1]], ShouldEqual, 1)
+			So(ranking[attrs[2]], ShouldEqual, 2)
+			So(ranking[attrs[3]], ShouldEqual, 3)
+			cols, rows := out.Size()
+			So(cols, ShouldEqual, 3)
+			So(rows, ShouldEqual, 200)
+
+			Convey("Removing several at once should share their rank", func() {
+				r.Step = 2
+				So(r.Fit(inst), ShouldBeNil)
+				So(r.GetSelectedAttributes(), ShouldResemble, attrs[:2])
+				So(r.GetRanking()[attrs[2]], ShouldEqual, 2)
+				So(r.GetRanking()[attrs[3]], ShouldEqual, 2)
+			})
+		})
+
+		Convey("By default half of the features should be kept", func() {
+			r := NewRFE(CoefficientImportance(linear_models.NewRidge(0.1)), 0)
+			So(r.Fit(inst), ShouldBeNil)
+			So(len(r.GetSelectedAttributes()), ShouldEqual, 2)
+		})
+
+		Convey("Invalid settings should fail", func() {
+			So(NewRFE(CoefficientImportance(linear_models.NewRidge(0.1)), 5).Fit(inst), ShouldNotBeNil)
+			So(NewRFE(nil, 2).Fit(inst), ShouldNotBeNil)
+			r := NewRFE(CoefficientImportance(linear_models.NewRidge(0.1)), 2)
+			r.Step = 0
+			So(r.Fit(inst), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given the iris dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("RFE should work with a coefficient for each class", func() {
+			s, err := softmax.NewSoftmaxRegression("l2", 1.0, 1e-6)
+			So(err, ShouldBeNil)
+			r := NewRFE(MultiCoefficientImportance(s), 2)
+			out, err := r.FitTransform(inst)
+			So(err, ShouldBeNil)
+			So(len(r.GetSelectedAttributes()), ShouldEqual, 2)
+			cols, _ := out.Size()
+			So(cols, ShouldEqual, 3)
+		})
+	})
+
+	Convey("Given the tennis dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/tennis.csv", true)
+		So(err, ShouldBeNil)
+		attrs := featureAttributes(inst)
+
+		Convey("RFE driven by tree importances should keep the deepest splits", func() {
+			r := NewRFE(FeatureImportance(trees.NewID3DecisionTree(0)), 2)
+			So(r.Fit(inst), ShouldBeNil)
+			So(r.GetSelectedAttributes(), ShouldResemble, attrs[2:])
+			So(r.GetRanking()[attrs[0]], ShouldEqual, 2)
+			So(r.GetRanking()[attrs[1]], ShouldEqual, 3)
+		})
+	})
+}
@@ -0,0 +1,254 @@
+package feature_selection
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// ScoreFunc scores how strongly each of the given Attributes of X is
+// related to its class Attribute, returning one score per Attribute.
+// Higher scores are better.
+type ScoreFunc func(X base.FixedDataGrid, attrs []base.Attribute) ([]float64, error)
+
+// mutualInformationBins is the number of equal-width bins FloatAttributes
+// are divided into by MutualInformation.
+const mutualInformationBins = 10
+
+// classLabels returns the class value of each row of X, which must
+// have exactly one class Attribute.
+func classLabels(X base.FixedDataGrid) ([]string, error) {
+	if len(X.AllClassAttributes()) != 1 {
+		return nil, fmt.Errorf("Exactly one class Attribute is needed")
+	}
+	_, rows := X.Size()
+	if rows == 0 {
+		return nil, fmt.Errorf("No rows to score")
+	}
+	ret := make([]string, rows)
+	for i := range ret {
+		ret[i] = base.GetClass(X, i)
+	}
+	return ret, nil
+}
+
+// floatValues returns the value of a FloatAttribute on each row of X.
+func floatValues(X base.FixedDataGrid, a base.Attribute) ([]float64, error) {
+	spec, err := X.GetAttribute(a)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := X.Size()
+	ret := make([]float64, rows)
+	for i := range ret {
+		ret[i] = base.UnpackBytesToFloat(X.Get(spec, i))
+	}
+	return ret, nil
+}
+
+// discreteValues returns the value of an Attribute on each row of X
+// as a string.
+func discreteValues(X base.FixedDataGrid, a base.Attribute) ([]string, error) {
+	spec, err := X.GetAttribute(a)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := X.Size()
+	ret := make([]string, rows)
+	for i := range ret {
+		ret[i] = a.GetStringFromSysVal(X.Get(spec, i))
+	}
+	return ret, nil
+}
+
+// binValues divides values into the given number of equal-width bins
+// between their minimum and maximum, and returns the bin of each.
+func binValues(values []float64, bins int) []string {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	ret := make([]string, len(values))
+	for i, v := range values {
+		b := 0
+		if max > min {
+			b = int(float64(bins) * (v - min) / (max - min))
+			if b >= bins {
+				b = bins - 1
+			}
+		}
+		ret[i] = fmt.Sprintf("%d", b)
+	}
+	return ret
+}
+
+// contingencyChi2 returns the chi-squared statistic of the table of
+// how often each value occurs with each class.
+func contingencyChi2(values, classes []string) float64 {
+	table := make(map[string]map[string]float64)
+	valueTotals := make(map[string]float64)
+	classTotals := make(map[string]float64)
+	for i, v := range values {
+		if table[v] == nil {
+			table[v] = make(map[string]float64)
+		}
+		table[v][classes[i]]++
+		valueTotals[v]++
+		classTotals[classes[i]]++
+	}
+	n := float64(len(values))
+	ret := 0.0
+	for v, vt := range valueTotals {
+		for c, ct := range classTotals {
+			expected := vt * ct / n
+			d := table[v][c] - expected
+			ret += d * d / expected
+		}
+	}
+	return ret
+}
+
+// Chi2 scores each Attribute using the chi-squared statistic, which
+// measures how far it is from being independent of the class.
+//
+// FloatAttributes must be non-negative (such as counts or
+// frequencies): the total of each one within each class is compared
+// with the total expected if it were spread across the classes in
+// proportion to their size. Other Attributes are scored from the table
+// of how often each value occurs with each class.
+func Chi2(X base.FixedDataGrid, attrs []base.Attribute) ([]float64, error) {
+	classes, err := classLabels(X)
+	if err != nil {
+		return nil, err
+	}
+	classCounts := make(map[string]float64)
+	for _, c := range classes {
+		classCounts[c]++
+	}
+	n := float64(len(classes))
+	ret := make([]float64, len(attrs))
+	for j, a := range attrs {
+		if _, ok := a.(*base.FloatAttribute); !ok {
+			values, err := discreteValues(X, a)
+			if err != nil {
+				return nil, err
+			}
+			ret[j] = contingencyChi2(values, classes)
+			continue
+		}
+		values, err := floatValues(X, a)
+		if err != nil {
+			return nil, err
+		}
+		observed := make(map[string]float64)
+		total := 0.0
+		for i, v := range values {
+			if v < 0 {
+				return nil, fmt.Errorf("Chi2 needs non-negative values, but %s has %f", a.GetName(), v)
+			}
+			observed[classes[i]] += v
+			total += v
+		}
+		for c, count := range classCounts {
+			expected := total * count / n
+			if expected == 0 {
+				continue
+			}
+			d := observed[c] - expected
+			ret[j] += d * d / expected
+		}
+	}
+	return ret, nil
+}
+
+// ANOVAF scores each FloatAttribute using the ANOVA F-statistic: the
+// ratio of the variance of its class means to the variance within each
+// class. Attributes which are perfectly separated by class (with no
+// variance within any class) score +Inf.
+func ANOVAF(X base.FixedDataGrid, attrs []base.Attribute) ([]float64, error) {
+	classes, err := classLabels(X)
+	if err != nil {
+		return nil, err
+	}
+	classCounts := make(map[string]float64)
+	for _, c := range classes {
+		classCounts[c]++
+	}
+	k, n := len(classCounts), len(classes)
+	if k < 2 || n <= k {
+		return nil, fmt.Errorf("ANOVAF needs at least two classes and more rows than classes")
+	}
+	ret := make([]float64, len(attrs))
+	for j, a := range attrs {
+		if _, ok := a.(*base.FloatAttribute); !ok {
+			return nil, fmt.Errorf("ANOVAF can only score FloatAttributes, not %s", a.GetName())
+		}
+		values, err := floatValues(X, a)
+		if err != nil {
+			return nil, err
+		}
+		mean := 0.0
+		sums := make(map[string]float64)
+		for i, v := range values {
+			mean += v
+			sums[classes[i]] += v
+		}
+		mean /= float64(n)
+		between, within := 0.0, 0.0
+		for c, count := range classCounts {
+			d := sums[c]/count - mean
+			between += count * d * d
+		}
+		for i, v := range values {
+			d := v - sums[classes[i]]/classCounts[classes[i]]
+			within += d * d
+		}
+		switch {
+		case within > 0:
+			ret[j] = (between / float64(k-1)) / (within / float64(n-k))
+		case between > 0:
+			ret[j] = math.Inf(1)
+		}
+	}
+	return ret, nil
+}
+
+// MutualInformation scores each Attribute by its mutual information
+// with the class (in nats), which measures how much knowing its value
+// reduces uncertainty about the class. Unlike ANOVAF, this picks up
+// relationships which aren't reflected in the class means.
+//
+// FloatAttributes are first divided into 10 equal-width bins.
+func MutualInformation(X base.FixedDataGrid, attrs []base.Attribute) ([]float64, error) {
+	classes, err := classLabels(X)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]float64, len(attrs))
+	for j, a := range attrs {
+		var values []string
+		if _, ok := a.(*base.FloatAttribute); ok {
+			floats, err := floatValues(X, a)
+			if err != nil {
+				return nil, err
+			}
+			values = binValues(floats, mutualInformationBins)
+		} else if values, err = discreteValues(X, a); err != nil {
+			return nil, err
+		}
+		joint := make(map[[2]string]float64)
+		valueCounts := make(map[string]float64)
+		classCounts := make(map[string]float64)
+		for i, v := range values {
+			joint[[2]string{v, classes[i]}]++
+			valueCounts[v]++
+			classCounts[classes[i]]++
+		}
+		n := float64(len(values))
+		for key, count := range joint {
+			ret[j] += count / n * math.Log(count*n/(valueCounts[key[0]]*classCounts[key[1]]))
+		}
+	}
+	return ret, nil
+}
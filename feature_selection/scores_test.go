@@ -0,0 +1,85 @@
+package feature_selection
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestScores(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		attrs := featureAttributes(inst)
+
+		Convey("Chi2 should match the reference values", func() {
+			scores, err := Chi2(inst, attrs)
+			So(err, ShouldBeNil)
+			So(scores[0], ShouldAlmostEqual, 10.81782088, 1e-6)
+			So(scores[1], ShouldAlmostEqual, 3.59449902, 1e-6)
+			So(scores[2], ShouldAlmostEqual, 116.16984746, 1e-6)
+			So(scores[3], ShouldAlmostEqual, 67.24482759, 1e-6)
+		})
+
+		Convey("ANOVAF should match the reference values", func() {
+			scores, err := ANOVAF(inst, attrs)
+			So(err, ShouldBeNil)
+			So(scores[0], ShouldAlmostEqual, 119.26450218, 1e-6)
+			So(scores[1], ShouldAlmostEqual, 47.36446140, 1e-6)
+			So(scores[2], ShouldAlmostEqual, 1179.03432770, 1e-6)
+			So(scores[3], ShouldAlmostEqual, 959.32440573, 1e-6)
+		})
+
+		Convey("MutualInformation should prefer the petal measurements", func() {
+			scores, err := MutualInformation(inst, attrs)
+			So(err, ShouldBeNil)
+			for _, s := range scores {
+				So(s, ShouldBeGreaterThanOrEqualTo, 0)
+				So(s, ShouldBeLessThanOrEqualTo, math.Log(3)+1e-9)
+			}
+			So(scores[2], ShouldBeGreaterThan, scores[0])
+			So(scores[3], ShouldBeGreaterThan, scores[1])
+		})
+	})
+
+	Convey("Given the tennis dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/tennis.csv", true)
+		So(err, ShouldBeNil)
+		outlook := featureAttributes(inst)[:1]
+
+		Convey("Chi2 should score categorical Attributes from their contingency table", func() {
+			scores, err := Chi2(inst, outlook)
+			So(err, ShouldBeNil)
+			So(scores[0], ShouldAlmostEqual, 3.54666667, 1e-6)
+		})
+
+		Convey("MutualInformation should score categorical Attributes directly", func() {
+			scores, err := MutualInformation(inst, outlook)
+			So(err, ShouldBeNil)
+			So(scores[0], ShouldAlmostEqual, 0.17103394, 1e-6)
+		})
+
+		Convey("ANOVAF should refuse categorical Attributes", func() {
+			_, err := ANOVAF(inst, outlook)
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Chi2 should refuse negative values", t, func() {
+		inst := base.NewDenseInstances()
+		x := base.NewFloatAttribute("x")
+		xSpec := inst.AddAttribute(x)
+		class := base.NewCategoricalAttribute()
+		class.SetName("class")
+		classSpec := inst.AddAttribute(class)
+		inst.AddClassAttribute(class)
+		inst.Extend(2)
+		inst.Set(xSpec, 0, base.PackFloatToBytes(-1))
+		inst.Set(xSpec, 1, base.PackFloatToBytes(1))
+		inst.Set(classSpec, 0, class.GetSysValFromString("a"))
+		inst.Set(classSpec, 1, class.GetSysValFromString("b"))
+		_, err := Chi2(inst, []base.Attribute{x})
+		So(err, ShouldNotBeNil)
+	})
+}
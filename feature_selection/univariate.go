@@ -0,0 +1,136 @@
+package feature_selection
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// univariateSelector stores the parts common to the selectors which
+// score each Attribute on its own.
+type univariateSelector struct {
+	// Scores each non-class Attribute against the class
+	Score    ScoreFunc
+	scores   map[base.Attribute]float64
+	selected []base.Attribute
+}
+
+// fit scores the non-class Attributes of X and keeps the best k,
+// where k is chosen from the number of Attributes.
+func (u *univariateSelector) fit(X base.FixedDataGrid, count func(int) (int, error)) error {
+	if u.Score == nil {
+		return fmt.Errorf("No ScoreFunc to rank Attributes with")
+	}
+	attrs := featureAttributes(X)
+	if len(attrs) == 0 {
+		return fmt.Errorf("No Attributes to select from")
+	}
+	k, err := count(len(attrs))
+	if err != nil {
+		return err
+	}
+	scores, err := u.Score(X, attrs)
+	if err != nil {
+		return err
+	}
+	if len(scores) != len(attrs) {
+		return fmt.Errorf("Expected %d scores, got %d", len(attrs), len(scores))
+	}
+	u.scores = make(map[base.Attribute]float64)
+	for j, a := range attrs {
+		u.scores[a] = scores[j]
+	}
+	u.selected = best(attrs, scores, k)
+	return nil
+}
+
+// Transform keeps the selected Attributes of X and its class
+// Attributes.
+func (u *univariateSelector) Transform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return project(X, u.selected)
+}
+
+// GetSelectedAttributes returns the Attributes kept by Transform, in
+// the order they appeared in the training data.
+func (u *univariateSelector) GetSelectedAttributes() []base.Attribute {
+	return u.selected
+}
+
+// GetScores returns the score of each non-class Attribute of the
+// training data.
+func (u *univariateSelector) GetScores() map[base.Attribute]float64 {
+	return u.scores
+}
+
+// SelectKBest keeps the K highest-scoring non-class Attributes.
+type SelectKBest struct {
+	univariateSelector
+	// Number of Attributes to keep
+	K int
+}
+
+// NewSelectKBest returns a SelectKBest which keeps the k Attributes
+// with the highest scores.
+func NewSelectKBest(score ScoreFunc, k int) *SelectKBest {
+	return &SelectKBest{univariateSelector{Score: score}, k}
+}
+
+// String returns a human-readable summary.
+func (s *SelectKBest) String() string {
+	return fmt.Sprintf("SelectKBest(%d)", s.K)
+}
+
+// Fit scores the non-class Attributes of X and chooses which to keep.
+func (s *SelectKBest) Fit(X base.FixedDataGrid) error {
+	return s.fit(X, func(n int) (int, error) {
+		if s.K < 1 || s.K > n {
+			return 0, fmt.Errorf("Can't keep %d of %d Attribute(s)", s.K, n)
+		}
+		return s.K, nil
+	})
+}
+
+// FitTransform fits to X and returns the selected Attributes of X.
+func (s *SelectKBest) FitTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if err := s.Fit(X); err != nil {
+		return nil, err
+	}
+	return s.Transform(X)
+}
+
+// SelectPercentile keeps the highest-scoring Percentile percent of the
+// non-class Attributes (rounding up, so at least one is kept).
+type SelectPercentile struct {
+	univariateSelector
+	// Percentage of Attributes to keep, greater than 0 and at most 100
+	Percentile float64
+}
+
+// NewSelectPercentile returns a SelectPercentile which keeps the given
+// percentage of the Attributes.
+func NewSelectPercentile(score ScoreFunc, percentile float64) *SelectPercentile {
+	return &SelectPercentile{univariateSelector{Score: score}, percentile}
+}
+
+// String returns a human-readable summary.
+func (s *SelectPercentile) String() string {
+	return fmt.Sprintf("SelectPercentile(%g%%)", s.Percentile)
+}
+
+// Fit scores the non-class Attributes of X and chooses which to keep.
+func (s *SelectPercentile) Fit(X base.FixedDataGrid) error {
+	return s.fit(X, func(n int) (int, error) {
+		if !(s.Percentile > 0 && s.Percentile <= 100) {
+			return 0, fmt.Errorf("Percentile must be greater than 0 and at most 100")
+		}
+		return int(math.Ceil(s.Percentile * float64(n) / 100)), nil
+	})
+}
+
+// FitTransform fits to X and returns the selected Attributes of X.
+func (s *SelectPercentile) FitTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if err := s.Fit(X); err != nil {
+		return nil, err
+	}
+	return s.Transform(X)
+}
@@ -0,0 +1,63 @@
+package feature_selection
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestUnivariateSelectors(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		attrs := featureAttributes(inst)
+
+		Convey("An unfitted selector should return an error", func() {
+			_, err := NewSelectKBest(Chi2, 2).Transform(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Asking for too many Attributes should fail", func() {
+			So(NewSelectKBest(Chi2, 5).Fit(inst), ShouldNotBeNil)
+			So(NewSelectKBest(Chi2, 0).Fit(inst), ShouldNotBeNil)
+			So(NewSelectPercentile(Chi2, 150).Fit(inst), ShouldNotBeNil)
+		})
+
+		Convey("SelectKBest should keep the petal measurements", func() {
+			s := NewSelectKBest(ANOVAF, 2)
+			out, err := s.FitTransform(inst)
+			So(err, ShouldBeNil)
+			So(s.GetSelectedAttributes(), ShouldResemble, attrs[2:])
+			So(s.GetScores()[attrs[2]], ShouldAlmostEqual, 1179.03432770, 1e-6)
+
+			Convey("And return a view with the class Attribute", func() {
+				cols, rows := out.Size()
+				So(cols, ShouldEqual, 3)
+				So(rows, ShouldEqual, 150)
+				So(out.AllClassAttributes(), ShouldResemble, inst.AllClassAttributes())
+				So(base.GetClass(out, 100), ShouldEqual, base.GetClass(inst, 100))
+				spec, err := out.GetAttribute(attrs[3])
+				So(err, ShouldBeNil)
+				orig, err := inst.GetAttribute(attrs[3])
+				So(err, ShouldBeNil)
+				So(out.Get(spec, 7), ShouldResemble, inst.Get(orig, 7))
+				_, err = out.GetAttribute(attrs[0])
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("SelectPercentile should round up", func() {
+			s := NewSelectPercentile(Chi2, 50)
+			So(s.Fit(inst), ShouldBeNil)
+			So(s.GetSelectedAttributes(), ShouldResemble, attrs[2:])
+
+			s.Percentile = 30
+			So(s.Fit(inst), ShouldBeNil)
+			So(s.GetSelectedAttributes(), ShouldResemble, attrs[2:])
+
+			s.Percentile = 1
+			So(s.Fit(inst), ShouldBeNil)
+			So(s.GetSelectedAttributes(), ShouldResemble, attrs[2:3])
+		})
+	})
+}
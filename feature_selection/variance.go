@@ -0,0 +1,105 @@
+package feature_selection
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// VarianceThreshold removes the non-class FloatAttributes whose
+// variance in the training data is at most Threshold, without looking
+// at the class. Attributes of other types are removed only if they
+// take a single value. With the default Threshold of 0, only constant
+// Attributes are removed.
+type VarianceThreshold struct {
+	Threshold float64
+	variances map[base.Attribute]float64
+	selected  []base.Attribute
+}
+
+// NewVarianceThreshold returns a VarianceThreshold which removes
+// FloatAttributes with at most the given variance.
+func NewVarianceThreshold(threshold float64) *VarianceThreshold {
+	return &VarianceThreshold{Threshold: threshold}
+}
+
+// String returns a human-readable summary.
+func (v *VarianceThreshold) String() string {
+	return fmt.Sprintf("VarianceThreshold(%g)", v.Threshold)
+}
+
+// Fit measures the non-class Attributes of X and chooses which to
+// keep.
+func (v *VarianceThreshold) Fit(X base.FixedDataGrid) error {
+	if v.Threshold < 0 {
+		return fmt.Errorf("Threshold can't be negative")
+	}
+	attrs := featureAttributes(X)
+	if len(attrs) == 0 {
+		return fmt.Errorf("No Attributes to select from")
+	}
+	_, rows := X.Size()
+	if rows == 0 {
+		return fmt.Errorf("No rows to measure")
+	}
+	variances := make(map[base.Attribute]float64)
+	keep := make(map[base.Attribute]bool)
+	for _, a := range attrs {
+		if _, ok := a.(*base.FloatAttribute); !ok {
+			values, err := discreteValues(X, a)
+			if err != nil {
+				return err
+			}
+			for _, s := range values {
+				if s != values[0] {
+					keep[a] = true
+					break
+				}
+			}
+			continue
+		}
+		values, err := floatValues(X, a)
+		if err != nil {
+			return err
+		}
+		mean := 0.0
+		for _, x := range values {
+			mean += x
+		}
+		mean /= float64(rows)
+		variance := 0.0
+		for _, x := range values {
+			variance += (x - mean) * (x - mean)
+		}
+		variances[a] = variance / float64(rows)
+		keep[a] = variances[a] > v.Threshold
+	}
+	v.variances = variances
+	v.selected = keepInOrder(attrs, keep)
+	return nil
+}
+
+// Transform keeps the selected Attributes of X and its class
+// Attributes.
+func (v *VarianceThreshold) Transform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return project(X, v.selected)
+}
+
+// FitTransform fits to X and returns the selected Attributes of X.
+func (v *VarianceThreshold) FitTransform(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if err := v.Fit(X); err != nil {
+		return nil, err
+	}
+	return v.Transform(X)
+}
+
+// GetSelectedAttributes returns the Attributes kept by Transform, in
+// the order they appeared in the training data.
+func (v *VarianceThreshold) GetSelectedAttributes() []base.Attribute {
+	return v.selected
+}
+
+// GetVariances returns the (population) variance of each non-class
+// FloatAttribute of the training data.
+func (v *VarianceThreshold) GetVariances() map[base.Attribute]float64 {
+	return v.variances
+}
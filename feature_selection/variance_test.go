@@ -0,0 +1,61 @@
+package feature_selection
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestVarianceThreshold(t *testing.T) {
+	Convey("Given Attributes with different amounts of variation", t, func() {
+		inst := base.NewDenseInstances()
+		constant := base.NewFloatAttribute("constant")
+		small := base.NewFloatAttribute("small")
+		large := base.NewFloatAttribute("large")
+		same := base.NewCategoricalAttribute()
+		same.SetName("same")
+		varied := base.NewCategoricalAttribute()
+		varied.SetName("varied")
+		class := base.NewCategoricalAttribute()
+		class.SetName("class")
+		specs := []base.AttributeSpec{
+			inst.AddAttribute(constant),
+			inst.AddAttribute(small),
+			inst.AddAttribute(large),
+			inst.AddAttribute(same),
+			inst.AddAttribute(varied),
+		}
+		classSpec := inst.AddAttribute(class)
+		inst.AddClassAttribute(class)
+		inst.Extend(4)
+		for i := 0; i < 4; i++ {
+			inst.Set(specs[0], i, base.PackFloatToBytes(3))
+			inst.Set(specs[1], i, base.PackFloatToBytes(0.1*float64(i%2)))
+			inst.Set(specs[2], i, base.PackFloatToBytes(2*float64(i)))
+			inst.Set(specs[3], i, same.GetSysValFromString("x"))
+			inst.Set(specs[4], i, varied.GetSysValFromString([]string{"x", "y"}[i%2]))
+			inst.Set(classSpec, i, class.GetSysValFromString([]string{"a", "b"}[i/2]))
+		}
+
+		Convey("The default threshold should only remove constant Attributes", func() {
+			v := NewVarianceThreshold(0)
+			out, err := v.FitTransform(inst)
+			So(err, ShouldBeNil)
+			So(v.GetSelectedAttributes(), ShouldResemble, []base.Attribute{small, large, varied})
+			So(v.GetVariances()[small], ShouldAlmostEqual, 0.0025, 1e-9)
+			So(v.GetVariances()[large], ShouldAlmostEqual, 5, 1e-9)
+			cols, _ := out.Size()
+			So(cols, ShouldEqual, 4)
+		})
+
+		Convey("A higher threshold should remove small variances", func() {
+			v := NewVarianceThreshold(0.01)
+			So(v.Fit(inst), ShouldBeNil)
+			So(v.GetSelectedAttributes(), ShouldResemble, []base.Attribute{large, varied})
+		})
+
+		Convey("A negative threshold should fail", func() {
+			So(NewVarianceThreshold(-1).Fit(inst), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,132 @@
+package filters
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// PolynomialFeatures generates every product of its FloatAttributes
+// of total degree between 2 and Degree, so that linear models can fit
+// non-linear relationships. With Attributes a and b and Degree 2 it
+// generates a^2, a*b and b^2. If InteractionOnly is set, no Attribute
+// appears more than once in a product (so just a*b).
+//
+// PolynomialFeatures is a base.Deriver: add Attributes to it, Train()
+// it and then wrap a FixedDataGrid with base.NewLazilyDerivedInstances
+// to add the products, which are computed as they're read.
+type PolynomialFeatures struct {
+	Degree          int
+	InteractionOnly bool
+	attrs           []base.Attribute
+	// Each product, as indices into attrs (in ascending order)
+	terms   [][]int
+	derived []base.Attribute
+	index   map[base.Attribute]int
+}
+
+// NewPolynomialFeatures creates a blank PolynomialFeatures filter.
+func NewPolynomialFeatures(degree int, interactionOnly bool) *PolynomialFeatures {
+	return &PolynomialFeatures{
+		Degree:          degree,
+		InteractionOnly: interactionOnly,
+		attrs:           make([]base.Attribute, 0),
+		index:           make(map[base.Attribute]int),
+	}
+}
+
+// AddAttribute adds a FloatAttribute to this filter.
+func (p *PolynomialFeatures) AddAttribute(a base.Attribute) error {
+	if _, ok := a.(*base.FloatAttribute); !ok {
+		return fmt.Errorf("%s is not a FloatAttribute", a)
+	}
+	p.attrs = append(p.attrs, a)
+	return nil
+}
+
+// String gets a human-readable string
+func (p *PolynomialFeatures) String() string {
+	return fmt.Sprintf("PolynomialFeatures(degree: %d, interaction only: %t, %d Attribute(s))", p.Degree, p.InteractionOnly, len(p.attrs))
+}
+
+// Train generates the products and a FloatAttribute for each of them.
+// Products are ordered by degree, then by the order the Attributes were
+// added.
+func (p *PolynomialFeatures) Train() error {
+	if p.Degree < 1 {
+		return fmt.Errorf("Degree must be at least 1")
+	}
+	p.terms = make([][]int, 0)
+	p.derived = make([]base.Attribute, 0)
+	p.index = make(map[base.Attribute]int)
+
+	var generate func(term []int, start, remaining int)
+	generate = func(term []int, start, remaining int) {
+		if remaining == 0 {
+			p.terms = append(p.terms, append([]int(nil), term...))
+			return
+		}
+		for j := start; j < len(p.attrs); j++ {
+			next := j
+			if p.InteractionOnly {
+				next = j + 1
+			}
+			generate(append(term, j), next, remaining-1)
+		}
+	}
+	for degree := 2; degree <= p.Degree; degree++ {
+		generate(make([]int, 0, degree), 0, degree)
+	}
+
+	for k, term := range p.terms {
+		a := base.NewFloatAttribute(p.termName(term))
+		p.derived = append(p.derived, a)
+		p.index[a] = k
+	}
+	return nil
+}
+
+// termName returns a readable name for a product, such as "a^2*b".
+func (p *PolynomialFeatures) termName(term []int) string {
+	var buffer bytes.Buffer
+	for i := 0; i < len(term); {
+		power := 1
+		for i+power < len(term) && term[i+power] == term[i] {
+			power++
+		}
+		if i > 0 {
+			buffer.WriteString("*")
+		}
+		buffer.WriteString(p.attrs[term[i]].GetName())
+		if power > 1 {
+			buffer.WriteString(fmt.Sprintf("^%d", power))
+		}
+		i += power
+	}
+	return buffer.String()
+}
+
+// GetInputAttributes returns the Attributes added to the filter.
+func (p *PolynomialFeatures) GetInputAttributes() []base.Attribute {
+	return p.attrs
+}
+
+// GetDerivedAttributes returns the FloatAttributes created by Train(),
+// one per product.
+func (p *PolynomialFeatures) GetDerivedAttributes() []base.Attribute {
+	return p.derived
+}
+
+// Derive multiplies together the values of the Attributes making up
+// the product represented by a.
+func (p *PolynomialFeatures) Derive(a base.Attribute, inputs [][]byte) []byte {
+	k, ok := p.index[a]
+	if !ok {
+		panic(fmt.Sprintf("Not a recognised Attribute %v", a))
+	}
+	product := 1.0
+	for _, j := range p.terms[k] {
+		product *= base.UnpackBytesToFloat(inputs[j])
+	}
+	return base.PackFloatToBytes(product)
+}
@@ -0,0 +1,97 @@
+package filters
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestPolynomialFeatures(t *testing.T) {
+
+	Convey("Given the iris dataset...", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		attrs := base.NonClassFloatAttributes(inst)
+		So(len(attrs), ShouldEqual, 4)
+
+		derive := func(degree int, interactionOnly bool) (*PolynomialFeatures, *base.LazilyDerivedInstances) {
+			p := NewPolynomialFeatures(degree, interactionOnly)
+			for _, a := range attrs {
+				So(p.AddAttribute(a), ShouldBeNil)
+			}
+			So(p.Train(), ShouldBeNil)
+			ret, err := base.NewLazilyDerivedInstances(inst, p)
+			So(err, ShouldBeNil)
+			return p, ret
+		}
+
+		Convey("Degree 2 should add every square and pairwise product...", func() {
+			p, instP := derive(2, false)
+			So(len(p.GetDerivedAttributes()), ShouldEqual, 10)
+			cols, rows := instP.Size()
+			So(cols, ShouldEqual, 15)
+			So(rows, ShouldEqual, 150)
+
+			Convey("With readable names...", func() {
+				names := p.GetDerivedAttributes()
+				So(names[0].GetName(), ShouldEqual, attrs[0].GetName()+"^2")
+				So(names[1].GetName(), ShouldEqual, attrs[0].GetName()+"*"+attrs[1].GetName())
+				So(names[9].GetName(), ShouldEqual, attrs[3].GetName()+"^2")
+			})
+
+			Convey("The class Attribute should have survived...", func() {
+				So(len(instP.AllClassAttributes()), ShouldEqual, 1)
+				So(base.GetClass(instP, 0), ShouldEqual, "Iris-setosa")
+			})
+
+			Convey("The products should be computed correctly...", func() {
+				// The first row is 5.1, 3.5, 1.4, 0.2
+				specs := base.ResolveAttributes(instP, p.GetDerivedAttributes())
+				So(base.UnpackBytesToFloat(instP.Get(specs[0], 0)), ShouldAlmostEqual, 5.1*5.1)
+				So(base.UnpackBytesToFloat(instP.Get(specs[1], 0)), ShouldAlmostEqual, 5.1*3.5)
+				So(base.UnpackBytesToFloat(instP.Get(specs[9], 0)), ShouldAlmostEqual, 0.2*0.2)
+			})
+
+			Convey("MapOverRows should agree with Get...", func() {
+				specs := base.ResolveAllAttributes(instP)
+				instP.MapOverRows(specs, func(row [][]byte, i int) (bool, error) {
+					for j, s := range specs {
+						So(row[j], ShouldResemble, instP.Get(s, i))
+					}
+					return i < 10, nil
+				})
+			})
+		})
+
+		Convey("Interaction-only products shouldn't repeat Attributes...", func() {
+			p, _ := derive(3, true)
+			// 6 pairs and 4 triples
+			So(len(p.GetDerivedAttributes()), ShouldEqual, 10)
+			for _, a := range p.GetDerivedAttributes() {
+				So(a.GetName(), ShouldNotContainSubstring, "^")
+			}
+		})
+
+		Convey("Degree 3 should add every product up to three Attributes...", func() {
+			p, _ := derive(3, false)
+			So(len(p.GetDerivedAttributes()), ShouldEqual, 30)
+			So(p.GetDerivedAttributes()[10].GetName(), ShouldEqual, attrs[0].GetName()+"^3")
+		})
+
+		Convey("Only FloatAttributes can be added...", func() {
+			p := NewPolynomialFeatures(2, false)
+			So(p.AddAttribute(inst.AllClassAttributes()[0]), ShouldNotBeNil)
+		})
+
+		Convey("Derived names can't clash with existing Attributes...", func() {
+			_, instP := derive(2, false)
+			p := NewPolynomialFeatures(2, false)
+			for _, a := range attrs {
+				So(p.AddAttribute(a), ShouldBeNil)
+			}
+			So(p.Train(), ShouldBeNil)
+			_, err := base.NewLazilyDerivedInstances(instP, p)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
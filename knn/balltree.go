@@ -0,0 +1,131 @@
+package knn
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	"math"
+)
+
+// BallTree is a NeighbourIndex which recursively splits the rows in
+// two, and bounds each half by a ball around its centroid. A ball is
+// skipped when it can't contain anything closer than the k-th nearest
+// row found so far. Unlike KDTree it works with any of the distances
+// in pairwise which obey the triangle inequality (such as Mahalanobis
+// or Hamming), and copes better with more dimensions.
+type BallTree struct {
+	rows     [][]float64
+	distance vectorDistance
+	// Row numbers, permuted so each node covers a contiguous range
+	order []int
+	nodes []ballNode
+}
+
+type ballNode struct {
+	start, end int
+	// Leaves have no children
+	left, right int
+	centre      []float64
+	radius      float64
+}
+
+// NewBallTree builds a BallTree over rows. Nodes with leafSize rows or
+// fewer aren't split any further.
+func NewBallTree(rows [][]float64, distance pairwise.PairwiseDistanceFunc, leafSize int) (*BallTree, error) {
+	// Pruning is only safe if the distance obeys the triangle inequality
+	switch d := distance.(type) {
+	case *pairwise.Euclidean, *pairwise.Manhattan, *pairwise.Chebyshev, *pairwise.Cranberra,
+		*pairwise.WeightedEuclidean, *pairwise.Mahalanobis, *pairwise.Hamming, *pairwise.Jaccard,
+		*pairwise.Gower, *pairwise.HEOM:
+	case *pairwise.Minkowski:
+		if d.GetP() < 1 {
+			return nil, fmt.Errorf("BallTree needs a Minkowski distance with p of at least 1")
+		}
+	default:
+		return nil, fmt.Errorf("BallTree doesn't support distance %T", distance)
+	}
+	if leafSize < 1 {
+		return nil, fmt.Errorf("Leaf size must be positive")
+	}
+	t := &BallTree{
+		rows:     rows,
+		distance: newVectorDistance(distance),
+		order:    make([]int, len(rows)),
+		nodes:    make([]ballNode, 0),
+	}
+	for i := range t.order {
+		t.order[i] = i
+	}
+	if len(rows) > 0 {
+		t.build(0, len(rows), leafSize)
+	}
+	return t, nil
+}
+
+// build creates the node covering order[start:end], and returns its
+// position.
+func (t *BallTree) build(start, end, leafSize int) int {
+	indices := t.order[start:end]
+	centre := make([]float64, len(t.rows[indices[0]]))
+	for _, i := range indices {
+		for d, v := range t.rows[i] {
+			centre[d] += v / float64(len(indices))
+		}
+	}
+	radius := 0.0
+	for _, i := range indices {
+		radius = math.Max(radius, t.distance(centre, t.rows[i]))
+	}
+
+	pos := len(t.nodes)
+	t.nodes = append(t.nodes, ballNode{start, end, -1, -1, centre, radius})
+	if end-start <= leafSize || radius == 0 {
+		return pos
+	}
+	axis, _ := widestDimension(t.rows, indices)
+	mid := medianSplit(t.rows, indices, axis) + start
+	left := t.build(start, mid, leafSize)
+	right := t.build(mid, end, leafSize)
+	t.nodes[pos].left = left
+	t.nodes[pos].right = right
+	return pos
+}
+
+// Query returns the k rows closest to x, nearest first.
+func (t *BallTree) Query(x []float64, k int) ([]int, []float64) {
+	set := newNeighbourSet(k)
+	if len(t.nodes) > 0 {
+		t.search(0, x, t.lowerBound(0, x), set)
+	}
+	return set.results()
+}
+
+// lowerBound returns the smallest possible distance from x to a row in
+// the given node. It's reduced slightly to allow for rounding error,
+// so that rows tied with the k-th nearest aren't skipped.
+func (t *BallTree) lowerBound(pos int, x []float64) float64 {
+	node := t.nodes[pos]
+	d := t.distance(x, node.centre)
+	return math.Max(0, d-node.radius-1e-9*(d+node.radius))
+}
+
+func (t *BallTree) search(pos int, x []float64, bound float64, set *neighbourSet) {
+	if bound > set.bound() {
+		return
+	}
+	node := t.nodes[pos]
+	if node.left < 0 {
+		for _, i := range t.order[node.start:node.end] {
+			set.offer(i, t.distance(x, t.rows[i]))
+		}
+		return
+	}
+	// Visit the nearer ball first
+	near, far := node.left, node.right
+	nearBound, farBound := t.lowerBound(near, x), t.lowerBound(far, x)
+	if farBound < nearBound {
+		near, far = far, near
+		nearBound, farBound = farBound, nearBound
+	}
+	t.search(near, x, nearBound, set)
+	t.search(far, x, farBound, set)
+}
@@ -0,0 +1,123 @@
+package knn
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// distanceBlockSize is the number of rows compared at a time: a block
+// of query rows and a block of training rows both fit in cache.
+const distanceBlockSize = 64
+
+// readAttributeRows returns the values of attrs for every row of grid,
+// one row after another. FloatAttributes are read with
+// base.CopyFloatRows.
+func readAttributeRows(grid base.FixedDataGrid, attrs []base.Attribute) ([]float64, error) {
+	if floats, err := base.CopyFloatRows(grid, attrs); err == nil {
+		return floats, nil
+	}
+	specs := make([]base.AttributeSpec, len(attrs))
+	for i, a := range attrs {
+		spec, err := grid.GetAttribute(a)
+		if err != nil {
+			return nil, fmt.Errorf("Attribute %s is missing: %s", a.GetName(), err)
+		}
+		specs[i] = spec
+	}
+	_, rows := grid.Size()
+	ret := make([]float64, rows*len(attrs))
+	err := grid.MapOverRows(specs, func(row [][]byte, rowNo int) (bool, error) {
+		for i, a := range attrs {
+			ret[rowNo*len(attrs)+i] = attributeValue(a, row[i])
+		}
+		return true, nil
+	})
+	return ret, err
+}
+
+// blockedDistances computes the distance from every query row to every
+// training row (both stored one row of cols values after another),
+// working through them in cache-sized blocks on every CPU. collect is
+// called once per query with its distance to each training row, and
+// may be called concurrently, but never twice for the same query; the
+// slice is reused afterwards.
+func blockedDistances(queries, train []float64, cols int, distance pairwise.PairwiseDistanceFunc, collect func(query int, distances []float64)) {
+	numQueries, numTrain := 0, 0
+	if cols > 0 {
+		numQueries, numTrain = len(queries)/cols, len(train)/cols
+	}
+
+	// Euclidean distances are square rooted at the end
+	var accumulate func(x, y []float64) float64
+	finish := func(d float64) float64 { return d }
+	switch distance.(type) {
+	case *pairwise.Euclidean:
+		accumulate = func(x, y []float64) float64 {
+			sum := 0.0
+			for i, v := range x {
+				diff := v - y[i]
+				sum += diff * diff
+			}
+			return sum
+		}
+		finish = math.Sqrt
+	default:
+		accumulate = newVectorDistance(distance)
+	}
+
+	workers := runtime.NumCPU()
+	pipe := make(chan int, workers)
+	var wait sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			out := make([]float64, distanceBlockSize*numTrain)
+			for start := range pipe {
+				end := start + distanceBlockSize
+				if end > numQueries {
+					end = numQueries
+				}
+				for tStart := 0; tStart < numTrain; tStart += distanceBlockSize {
+					tEnd := tStart + distanceBlockSize
+					if tEnd > numTrain {
+						tEnd = numTrain
+					}
+					for q := start; q < end; q++ {
+						x := queries[q*cols : (q+1)*cols]
+						row := out[(q-start)*numTrain:]
+						for t := tStart; t < tEnd; t++ {
+							row[t] = accumulate(x, train[t*cols:(t+1)*cols])
+						}
+					}
+				}
+				for q := start; q < end; q++ {
+					row := out[(q-start)*numTrain : (q-start+1)*numTrain]
+					for t, d := range row {
+						row[t] = finish(d)
+					}
+					collect(q, row)
+				}
+			}
+		}()
+	}
+	for start := 0; start < numQueries; start += distanceBlockSize {
+		pipe <- start
+	}
+	close(pipe)
+	wait.Wait()
+}
+
+// nearest returns the k smallest distances (and their positions),
+// nearest first.
+func nearest(distances []float64, k int) ([]int, []float64) {
+	set := newNeighbourSet(k)
+	for i, d := range distances {
+		set.offer(i, d)
+	}
+	return set.results()
+}
@@ -0,0 +1,81 @@
+package knn
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+func TestBlockedDistances(t *testing.T) {
+	rand.Seed(42)
+
+	Convey("Given more rows than fit in one block", t, func() {
+		flatten := func(rows [][]float64) []float64 {
+			ret := make([]float64, 0)
+			for _, r := range rows {
+				ret = append(ret, r...)
+			}
+			return ret
+		}
+		train := randomRows(150, 5, false)
+		queries := randomRows(130, 5, false)
+
+		for _, name := range []string{"euclidean", "manhattan", "cosine"} {
+			Convey("Every "+name+" distance should be computed exactly once", func() {
+				distance, _ := pairwise.NewDistance(name)
+				reference := newVectorDistance(distance)
+				calls := make([]int, len(queries))
+				mismatches := 0
+				blockedDistances(flatten(queries), flatten(train), 5, distance, func(q int, d []float64) {
+					calls[q]++
+					for t := range train {
+						if diff := d[t] - reference(queries[q], train[t]); diff > 1e-9 || diff < -1e-9 {
+							mismatches++
+						}
+					}
+				})
+				So(mismatches, ShouldEqual, 0)
+				for _, c := range calls {
+					So(c, ShouldEqual, 1)
+				}
+			})
+		}
+	})
+
+	Convey("Given the iris dataset split into InstancesViews", t, func() {
+		iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		train, test := base.InstancesTrainTestSplit(iris, 0.4)
+
+		Convey("Blocked predictions should match those from a KDTree", func() {
+			blocked := NewKnnClassifier("euclidean", 1)
+			blocked.Fit(train)
+			indexed := NewKnnClassifier("euclidean", 1)
+			indexed.Algorithm = "kd_tree"
+			indexed.Fit(train)
+
+			expected := indexed.Predict(test)
+			predictions := blocked.Predict(test)
+			_, rows := test.Size()
+			for i := 0; i < rows; i++ {
+				So(base.GetClass(predictions, i), ShouldEqual, base.GetClass(expected, i))
+			}
+		})
+	})
+}
+
+func BenchmarkBlockedPredict(b *testing.B) {
+	inst, err := base.ParseCSVToInstances("../examples/datasets/mnist_test.csv", true)
+	if err != nil {
+		panic(err)
+	}
+	train, test := base.InstancesTrainTestSplit(inst, 0.2)
+	cls := NewKnnClassifier("euclidean", 1)
+	cls.Fit(train)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cls.Predict(test)
+	}
+}
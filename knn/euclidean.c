@@ -1,3 +1,5 @@
+// +build cgo
+
 // #cgo CFLAGS: -Og -march=native -ffast-math
 
 #include <stdio.h>
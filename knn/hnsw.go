@@ -0,0 +1,233 @@
+package knn
+
+import (
+	"container/heap"
+	"fmt"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	"math"
+	"math/rand"
+)
+
+// HNSWParams configure an HNSW index.
+type HNSWParams struct {
+	// Number of links made by each new row. More links improve recall
+	// at the cost of memory and build time.
+	M int
+	// Number of candidates considered when linking a new row.
+	EfConstruction int
+	// Number of candidates considered by each query (at least k).
+	// Raising it improves recall and slows queries down.
+	EfSearch int
+}
+
+// DefaultHNSWParams returns M = 16, EfConstruction = 200 and
+// EfSearch = 50.
+func DefaultHNSWParams() HNSWParams {
+	return HNSWParams{M: 16, EfConstruction: 200, EfSearch: 50}
+}
+
+// HNSW is an approximate NeighbourIndex, which links each row to some
+// of its neighbours in a hierarchy of graphs (a hierarchical navigable
+// small world). Queries walk greedily through the graphs, so they take
+// roughly logarithmic time even in many dimensions, but may miss some
+// of the true nearest rows. Recall can be measured with Recall.
+//
+// Rows are assigned to levels at random using math/rand.
+//
+// For more information:
+//
+// Y. A. Malkov and D. A. Yashunin (2016). Efficient and robust
+// approximate nearest neighbor search using Hierarchical Navigable Small
+// World graphs. arXiv:1603.09320.
+type HNSW struct {
+	HNSWParams
+	rows     [][]float64
+	distance vectorDistance
+	// links[i][l] are the neighbours of row i at level l
+	links    [][][]int
+	entry    int
+	maxLevel int
+}
+
+// NewHNSW builds an HNSW index over rows.
+func NewHNSW(rows [][]float64, distance pairwise.PairwiseDistanceFunc, params HNSWParams) (*HNSW, error) {
+	if params.M < 2 {
+		return nil, fmt.Errorf("M must be at least 2")
+	}
+	if params.EfConstruction < 1 || params.EfSearch < 1 {
+		return nil, fmt.Errorf("EfConstruction and EfSearch must be positive")
+	}
+	h := &HNSW{
+		HNSWParams: params,
+		rows:       rows,
+		distance:   newVectorDistance(distance),
+		links:      make([][][]int, len(rows)),
+		entry:      -1,
+	}
+	levelScale := 1 / math.Log(float64(params.M))
+	for i := range rows {
+		level := int(-math.Log(1-rand.Float64()) * levelScale)
+		h.insert(i, level)
+	}
+	return h, nil
+}
+
+// insert links row q into every level up to the given one.
+func (h *HNSW) insert(q, level int) {
+	h.links[q] = make([][]int, level+1)
+	if h.entry < 0 {
+		h.entry, h.maxLevel = q, level
+		return
+	}
+
+	x := h.rows[q]
+	entry := []neighbour{{h.entry, h.distance(x, h.rows[h.entry])}}
+	for l := h.maxLevel; l > level; l-- {
+		entry = h.searchLevel(x, entry, 1, l)
+	}
+	for l := minInt(level, h.maxLevel); l >= 0; l-- {
+		candidates := h.searchLevel(x, entry, h.EfConstruction, l)
+		selected := candidates
+		if len(selected) > h.M {
+			selected = selected[:h.M]
+		}
+		for _, n := range selected {
+			h.links[q][l] = append(h.links[q][l], n.index)
+			h.links[n.index][l] = append(h.links[n.index][l], q)
+			h.prune(n.index, l)
+		}
+		entry = candidates
+	}
+	if level > h.maxLevel {
+		h.entry, h.maxLevel = q, level
+	}
+}
+
+// prune keeps only the closest links of row i at level l, if it has
+// more than the maximum (2M at level 0 and M above it).
+func (h *HNSW) prune(i, l int) {
+	max := h.M
+	if l == 0 {
+		max = 2 * h.M
+	}
+	if len(h.links[i][l]) <= max {
+		return
+	}
+	set := newNeighbourSet(max)
+	for _, j := range h.links[i][l] {
+		set.offer(j, h.distance(h.rows[i], h.rows[j]))
+	}
+	kept, _ := set.results()
+	h.links[i][l] = kept
+}
+
+// candidateHeap pops the nearest candidate first.
+type candidateHeap []neighbour
+
+func (c candidateHeap) Len() int            { return len(c) }
+func (c candidateHeap) Less(i, j int) bool  { return closer(c[i], c[j]) }
+func (c candidateHeap) Swap(i, j int)       { c[i], c[j] = c[j], c[i] }
+func (c *candidateHeap) Push(x interface{}) { *c = append(*c, x.(neighbour)) }
+func (c *candidateHeap) Pop() interface{} {
+	old := *c
+	ret := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return ret
+}
+
+// searchLevel returns (up to) the ef rows nearest to x found by a best
+// first search of level l starting from entry, nearest first.
+func (h *HNSW) searchLevel(x []float64, entry []neighbour, ef, l int) []neighbour {
+	visited := make(map[int]bool)
+	candidates := make(candidateHeap, 0)
+	found := newNeighbourSet(ef)
+	for _, e := range entry {
+		visited[e.index] = true
+		heap.Push(&candidates, e)
+		found.offer(e.index, e.distance)
+	}
+	for candidates.Len() > 0 {
+		c := heap.Pop(&candidates).(neighbour)
+		if c.distance > found.bound() {
+			break
+		}
+		for _, j := range h.links[c.index][l] {
+			if visited[j] {
+				continue
+			}
+			visited[j] = true
+			d := h.distance(x, h.rows[j])
+			if d < found.bound() || len(found.heap) < ef {
+				heap.Push(&candidates, neighbour{j, d})
+				found.offer(j, d)
+			}
+		}
+	}
+	indices, distances := found.results()
+	ret := make([]neighbour, len(indices))
+	for i := range indices {
+		ret[i] = neighbour{indices[i], distances[i]}
+	}
+	return ret
+}
+
+// Query returns (approximately) the k rows closest to x, nearest
+// first.
+func (h *HNSW) Query(x []float64, k int) ([]int, []float64) {
+	if h.entry < 0 || k < 1 {
+		return []int{}, []float64{}
+	}
+	entry := []neighbour{{h.entry, h.distance(x, h.rows[h.entry])}}
+	for l := h.maxLevel; l > 0; l-- {
+		entry = h.searchLevel(x, entry, 1, l)
+	}
+	found := h.searchLevel(x, entry, maxInt(h.EfSearch, k), 0)
+	if len(found) > k {
+		found = found[:k]
+	}
+	indices := make([]int, len(found))
+	distances := make([]float64, len(found))
+	for i, n := range found {
+		indices[i], distances[i] = n.index, n.distance
+	}
+	return indices, distances
+}
+
+// Recall returns the proportion of the k nearest neighbours of each
+// query (according to exact, such as a BruteForceIndex) which are also
+// returned by approximate.
+func Recall(approximate, exact NeighbourIndex, queries [][]float64, k int) float64 {
+	found, total := 0, 0
+	for _, q := range queries {
+		expected, _ := exact.Query(q, k)
+		actual, _ := approximate.Query(q, k)
+		matched := make(map[int]bool)
+		for _, i := range actual {
+			matched[i] = true
+		}
+		for _, i := range expected {
+			if matched[i] {
+				found++
+			}
+		}
+		total += len(expected)
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(found) / float64(total)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,107 @@
+package knn
+
+import (
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+func TestHNSW(t *testing.T) {
+	rand.Seed(42)
+
+	Convey("Given some random rows in 32 dimensions", t, func() {
+		rows := randomRows(2000, 32, false)
+		queries := randomRows(50, 32, false)
+		distance := pairwise.NewEuclidean()
+		exact := NewBruteForceIndex(rows, distance)
+
+		Convey("An HNSW index should find most of the nearest neighbours", func() {
+			index, err := NewHNSW(rows, distance, DefaultHNSWParams())
+			So(err, ShouldBeNil)
+			So(Recall(index, exact, queries, 10), ShouldBeGreaterThan, 0.9)
+
+			Convey("And returned distances should be correct and sorted", func() {
+				indices, distances := index.Query(queries[0], 10)
+				So(len(indices), ShouldEqual, 10)
+				for i := range indices {
+					So(distances[i], ShouldAlmostEqual, newVectorDistance(distance)(queries[0], rows[indices[i]]))
+					if i > 0 {
+						So(distances[i], ShouldBeGreaterThanOrEqualTo, distances[i-1])
+					}
+				}
+			})
+
+			Convey("Raising EfSearch should improve recall", func() {
+				index.EfSearch = 10
+				low := Recall(index, exact, queries, 10)
+				index.EfSearch = 200
+				high := Recall(index, exact, queries, 10)
+				So(high, ShouldBeGreaterThanOrEqualTo, low)
+				So(high, ShouldBeGreaterThan, 0.98)
+			})
+		})
+
+		Convey("Invalid settings should be rejected", func() {
+			_, err := NewHNSW(rows, distance, HNSWParams{M: 1, EfConstruction: 10, EfSearch: 10})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("An empty index should return nothing", func() {
+			index, err := NewHNSW([][]float64{}, distance, DefaultHNSWParams())
+			So(err, ShouldBeNil)
+			indices, _ := index.Query(queries[0], 5)
+			So(len(indices), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given the MNIST sample", t, func() {
+		rows := mnistRows()
+		train, queries := rows[:400], rows[400:]
+
+		Convey("An HNSW index should agree closely with brute force", func() {
+			index, err := NewHNSW(train, pairwise.NewEuclidean(), DefaultHNSWParams())
+			So(err, ShouldBeNil)
+			So(Recall(index, NewBruteForceIndex(train, pairwise.NewEuclidean()), queries, 5), ShouldBeGreaterThan, 0.95)
+		})
+	})
+}
+
+func TestKnnWithHNSW(t *testing.T) {
+	rand.Seed(42)
+
+	Convey("Given the iris dataset", t, func() {
+		iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		train, test := base.InstancesTrainTestSplit(iris, 0.5)
+
+		Convey("An approximate classifier should be as accurate as an exact one", func() {
+			accuracy := func(cls *KNNClassifier) float64 {
+				cls.Fit(train)
+				cf, err := evaluation.GetConfusionMatrix(test, cls.Predict(test))
+				So(err, ShouldBeNil)
+				return evaluation.GetAccuracy(cf)
+			}
+			exact := NewKnnClassifier("euclidean", 3)
+			exact.AllowOptimisations = false
+			approximate := NewKnnClassifier("euclidean", 3)
+			approximate.Algorithm = "hnsw"
+			So(accuracy(approximate), ShouldBeGreaterThanOrEqualTo, accuracy(exact)-0.02)
+		})
+	})
+
+	Convey("Given some regression data", t, func() {
+		data := []float64{0, 0, 1, 1, 2, 2, 3, 3, 10, 10}
+		values := []float64{0, 1, 2, 3, 4}
+
+		Convey("An approximate regressor should find the neighbours of a small dataset exactly", func() {
+			knn := NewKnnRegressor("euclidean")
+			knn.Algorithm = "hnsw"
+			knn.Fit(values, data, 5, 2)
+			So(knn.Predict(mat64.NewDense(1, 2, []float64{1.2, 1.2}), 2), ShouldAlmostEqual, 1.5)
+		})
+	})
+}
@@ -0,0 +1,174 @@
+package knn
+
+import (
+	"container/heap"
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	"math"
+)
+
+// NeighbourIndex finds the training rows closest to a query.
+type NeighbourIndex interface {
+	// Query returns the indices of the k rows closest to x, and their
+	// distances from it, nearest first. Ties are broken by index.
+	Query(x []float64, k int) ([]int, []float64)
+}
+
+// vectorDistance measures the distance between two rows.
+type vectorDistance func(x, y []float64) float64
+
+// newVectorDistance returns a vectorDistance equivalent to d. The
+// Minkowski family is computed directly, and anything else through
+// d.Distance on row vectors.
+func newVectorDistance(d pairwise.PairwiseDistanceFunc) vectorDistance {
+	switch d.(type) {
+	case *pairwise.Euclidean:
+		return func(x, y []float64) float64 {
+			sum := 0.0
+			for i := range x {
+				diff := x[i] - y[i]
+				sum += diff * diff
+			}
+			return math.Sqrt(sum)
+		}
+	case *pairwise.Manhattan:
+		return func(x, y []float64) float64 {
+			sum := 0.0
+			for i := range x {
+				sum += math.Abs(x[i] - y[i])
+			}
+			return sum
+		}
+	case *pairwise.Chebyshev:
+		return func(x, y []float64) float64 {
+			max := 0.0
+			for i := range x {
+				max = math.Max(max, math.Abs(x[i]-y[i]))
+			}
+			return max
+		}
+	}
+	return func(x, y []float64) float64 {
+		return d.Distance(mat64.NewDense(1, len(x), x), mat64.NewDense(1, len(y), y))
+	}
+}
+
+// neighbour is a candidate result of a query.
+type neighbour struct {
+	index    int
+	distance float64
+}
+
+// neighbourHeap keeps the k best candidates seen so far, with the
+// furthest at the top so it can be replaced.
+type neighbourHeap []neighbour
+
+func (h neighbourHeap) Len() int { return len(h) }
+func (h neighbourHeap) Less(i, j int) bool {
+	if h[i].distance != h[j].distance {
+		return h[i].distance > h[j].distance
+	}
+	return h[i].index > h[j].index
+}
+func (h neighbourHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighbourHeap) Push(x interface{}) { *h = append(*h, x.(neighbour)) }
+func (h *neighbourHeap) Pop() interface{} {
+	old := *h
+	ret := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return ret
+}
+
+// neighbourSet collects the k nearest candidates offered to it.
+type neighbourSet struct {
+	k    int
+	heap neighbourHeap
+}
+
+func newNeighbourSet(k int) *neighbourSet {
+	return &neighbourSet{k, make(neighbourHeap, 0, k+1)}
+}
+
+// offer considers row index at the given distance.
+func (s *neighbourSet) offer(index int, distance float64) {
+	n := neighbour{index, distance}
+	if len(s.heap) < s.k {
+		heap.Push(&s.heap, n)
+	} else if s.k > 0 && closer(n, s.heap[0]) {
+		s.heap[0] = n
+		heap.Fix(&s.heap, 0)
+	}
+}
+
+// bound returns the distance a candidate must beat to be accepted.
+func (s *neighbourSet) bound() float64 {
+	if len(s.heap) < s.k {
+		return math.Inf(1)
+	}
+	return s.heap[0].distance
+}
+
+// results returns the candidates, nearest first.
+func (s *neighbourSet) results() ([]int, []float64) {
+	n := len(s.heap)
+	indices := make([]int, n)
+	distances := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		next := heap.Pop(&s.heap).(neighbour)
+		indices[i] = next.index
+		distances[i] = next.distance
+	}
+	return indices, distances
+}
+
+// closer returns true if a should be ranked before b.
+func closer(a, b neighbour) bool {
+	if a.distance != b.distance {
+		return a.distance < b.distance
+	}
+	return a.index < b.index
+}
+
+// BruteForceIndex answers queries by measuring the distance to every
+// row.
+type BruteForceIndex struct {
+	rows     [][]float64
+	distance vectorDistance
+}
+
+// NewBruteForceIndex returns a BruteForceIndex over rows.
+func NewBruteForceIndex(rows [][]float64, distance pairwise.PairwiseDistanceFunc) *BruteForceIndex {
+	return &BruteForceIndex{rows, newVectorDistance(distance)}
+}
+
+// Query returns the k rows closest to x, nearest first.
+func (b *BruteForceIndex) Query(x []float64, k int) ([]int, []float64) {
+	set := newNeighbourSet(k)
+	for i, row := range b.rows {
+		set.offer(i, b.distance(x, row))
+	}
+	return set.results()
+}
+
+// NewNeighbourIndex builds the index named by algorithm ("brute",
+// "kd_tree", "ball_tree" or "hnsw") over rows. leafSize only applies to
+// the trees, and "hnsw" uses DefaultHNSWParams.
+func NewNeighbourIndex(algorithm string, rows [][]float64, distance pairwise.PairwiseDistanceFunc, leafSize int) (NeighbourIndex, error) {
+	return buildIndex(algorithm, rows, distance, leafSize, DefaultHNSWParams())
+}
+
+// buildIndex is NewNeighbourIndex, with HNSWParams for "hnsw".
+func buildIndex(algorithm string, rows [][]float64, distance pairwise.PairwiseDistanceFunc, leafSize int, params HNSWParams) (NeighbourIndex, error) {
+	switch algorithm {
+	case "hnsw":
+		return NewHNSW(rows, distance, params)
+	case "brute":
+		return NewBruteForceIndex(rows, distance), nil
+	case "kd_tree":
+		return NewKDTree(rows, distance, leafSize)
+	case "ball_tree":
+		return NewBallTree(rows, distance, leafSize)
+	}
+	return nil, fmt.Errorf("Unsupported neighbour search algorithm %q", algorithm)
+}
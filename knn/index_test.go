@@ -0,0 +1,210 @@
+package knn
+
+import (
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+func randomRows(n, d int, integer bool) [][]float64 {
+	ret := make([][]float64, n)
+	for i := range ret {
+		ret[i] = make([]float64, d)
+		for j := range ret[i] {
+			if integer {
+				ret[i][j] = float64(rand.Intn(3))
+			} else {
+				ret[i][j] = rand.NormFloat64()
+			}
+		}
+	}
+	return ret
+}
+
+func TestNeighbourIndexes(t *testing.T) {
+	rand.Seed(42)
+
+	// Every index should give exactly the same answers as brute force
+	compare := func(index NeighbourIndex, rows, queries [][]float64, distance pairwise.PairwiseDistanceFunc) {
+		brute := NewBruteForceIndex(rows, distance)
+		for _, q := range queries {
+			for _, k := range []int{1, 5, 20} {
+				expectedIndices, expectedDistances := brute.Query(q, k)
+				indices, distances := index.Query(q, k)
+				So(indices, ShouldResemble, expectedIndices)
+				for i := range distances {
+					So(distances[i], ShouldAlmostEqual, expectedDistances[i])
+				}
+			}
+		}
+	}
+
+	Convey("Given some random rows", t, func() {
+		rows := randomRows(500, 4, false)
+		queries := randomRows(30, 4, false)
+
+		for _, name := range []string{"euclidean", "manhattan", "chebyshev"} {
+			distance, err := pairwise.NewDistance(name)
+			So(err, ShouldBeNil)
+
+			Convey("A KDTree should find the nearest rows by "+name+" distance", func() {
+				tree, err := NewKDTree(rows, distance, 10)
+				So(err, ShouldBeNil)
+				compare(tree, rows, queries, distance)
+			})
+
+			Convey("A BallTree should find the nearest rows by "+name+" distance", func() {
+				tree, err := NewBallTree(rows, distance, 10)
+				So(err, ShouldBeNil)
+				compare(tree, rows, queries, distance)
+			})
+		}
+
+		Convey("A BallTree should work with a Mahalanobis distance", func() {
+			distance, err := pairwise.NewMahalanobis(mat64.NewDense(4, 4, []float64{
+				2, 1, 0, 0,
+				1, 2, 0, 0,
+				0, 0, 1, 0,
+				0, 0, 0, 4,
+			}))
+			So(err, ShouldBeNil)
+			tree, err := NewBallTree(rows, distance, 10)
+			So(err, ShouldBeNil)
+			compare(tree, rows, queries, distance)
+		})
+
+		Convey("A KDTree should reject a distance it can't prune with", func() {
+			_, err := NewKDTree(rows, pairwise.NewCosineKernel(), 10)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("A BallTree should reject distances which aren't metrics", func() {
+			_, err := NewBallTree(rows, pairwise.NewCosineKernel(), 10)
+			So(err, ShouldNotBeNil)
+			_, err = NewBallTree(rows, pairwise.NewMinkowski(0.5), 10)
+			So(err, ShouldNotBeNil)
+			tree, err := NewBallTree(rows, pairwise.NewMinkowski(3), 10)
+			So(err, ShouldBeNil)
+			compare(tree, rows, queries, pairwise.NewMinkowski(3))
+		})
+
+		Convey("Asking for more neighbours than rows should return every row", func() {
+			tree, err := NewKDTree(rows[:3], pairwise.NewEuclidean(), 1)
+			So(err, ShouldBeNil)
+			indices, _ := tree.Query(queries[0], 5)
+			So(len(indices), ShouldEqual, 3)
+		})
+	})
+
+	Convey("Given rows with many duplicates", t, func() {
+		rows := randomRows(300, 3, true)
+		queries := randomRows(20, 3, true)
+
+		Convey("Ties should be broken by row number", func() {
+			for _, name := range []string{"euclidean", "manhattan"} {
+				distance, _ := pairwise.NewDistance(name)
+				kd, err := NewKDTree(rows, distance, 5)
+				So(err, ShouldBeNil)
+				compare(kd, rows, queries, distance)
+			}
+			ball, err := NewBallTree(rows, pairwise.NewHamming(), 5)
+			So(err, ShouldBeNil)
+			compare(ball, rows, queries, pairwise.NewHamming())
+		})
+	})
+}
+
+func TestKnnClassifierWithIndexes(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		train, test := base.InstancesTrainTestSplit(iris, 0.5)
+
+		// One neighbour, so that there are no tied votes
+		brute := NewKnnClassifier("euclidean", 1)
+		brute.AllowOptimisations = false
+		brute.Fit(train)
+		expected := brute.Predict(test)
+
+		for _, algorithm := range []string{"kd_tree", "ball_tree"} {
+			Convey("Predictions using a "+algorithm+" should match brute force", func() {
+				cls := NewKnnClassifier("euclidean", 1)
+				cls.Algorithm = algorithm
+				cls.LeafSize = 5
+				cls.Fit(train)
+				predictions := cls.Predict(test)
+				_, rows := test.Size()
+				for i := 0; i < rows; i++ {
+					So(base.GetClass(predictions, i), ShouldEqual, base.GetClass(expected, i))
+				}
+			})
+		}
+
+		Convey("An unknown algorithm should panic at Fit time", func() {
+			cls := NewKnnClassifier("euclidean", 3)
+			cls.Algorithm = "nonexistent"
+			So(func() { cls.Fit(train) }, ShouldPanic)
+		})
+	})
+}
+
+func TestKnnRegressorWithIndexes(t *testing.T) {
+	Convey("Given some regression data", t, func() {
+		data := []float64{0, 0, 1, 1, 2, 2, 3, 3, 10, 10}
+		values := []float64{0, 1, 2, 3, 4}
+		query := mat64.NewDense(1, 2, []float64{1.2, 1.2})
+
+		brute := NewKnnRegressor("euclidean")
+		brute.Fit(values, data, 5, 2)
+
+		for _, algorithm := range []string{"kd_tree", "ball_tree"} {
+			Convey("Predictions using a "+algorithm+" should match brute force", func() {
+				knn := NewKnnRegressor("euclidean")
+				knn.Algorithm = algorithm
+				knn.LeafSize = 1
+				knn.Fit(values, data, 5, 2)
+				So(knn.Predict(query, 2), ShouldAlmostEqual, brute.Predict(query, 2))
+				So(knn.Predict(query, 2), ShouldAlmostEqual, 1.5)
+			})
+		}
+	})
+}
+
+func mnistRows() [][]float64 {
+	inst, err := base.ParseCSVToInstances("../examples/datasets/mnist_test.csv", true)
+	if err != nil {
+		panic(err)
+	}
+	attrs := base.NonClassFloatAttributes(inst)
+	_, n := inst.Size()
+	rows := make([][]float64, n)
+	inst.MapOverRows(base.ResolveAttributes(inst, attrs), func(row [][]byte, i int) (bool, error) {
+		rows[i] = make([]float64, len(row))
+		for j, v := range row {
+			rows[i][j] = base.UnpackBytesToFloat(v)
+		}
+		return true, nil
+	})
+	return rows
+}
+
+func benchmarkIndex(b *testing.B, algorithm string) {
+	rows := mnistRows()
+	train, queries := rows[:400], rows[400:]
+	index, err := NewNeighbourIndex(algorithm, train, pairwise.NewEuclidean(), 30)
+	if err != nil {
+		panic(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.Query(queries[i%len(queries)], 5)
+	}
+}
+
+func BenchmarkBruteForceIndex(b *testing.B) { benchmarkIndex(b, "brute") }
+func BenchmarkKDTree(b *testing.B)          { benchmarkIndex(b, "kd_tree") }
+func BenchmarkBallTree(b *testing.B)        { benchmarkIndex(b, "ball_tree") }
+func BenchmarkHNSW(b *testing.B)            { benchmarkIndex(b, "hnsw") }
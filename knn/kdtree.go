@@ -0,0 +1,146 @@
+package knn
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	"math"
+	"sort"
+)
+
+// KDTree is a NeighbourIndex which recursively splits the rows in two
+// at the median of the dimension with the largest spread. A subtree is
+// skipped when the query is further from the splitting plane than the
+// k-th nearest row found so far, so it only works with distances from
+// the Minkowski family (Euclidean, Manhattan, Chebyshev and Minkowski
+// with p >= 1). It works best with fewer than about 20 dimensions.
+type KDTree struct {
+	rows     [][]float64
+	distance vectorDistance
+	// Row numbers, permuted so each node covers a contiguous range
+	order []int
+	nodes []kdNode
+}
+
+type kdNode struct {
+	start, end int
+	// Leaves have no children
+	left, right int
+	axis        int
+	split       float64
+}
+
+// NewKDTree builds a KDTree over rows. Nodes with leafSize rows or
+// fewer aren't split any further.
+func NewKDTree(rows [][]float64, distance pairwise.PairwiseDistanceFunc, leafSize int) (*KDTree, error) {
+	switch distance.(type) {
+	case *pairwise.Euclidean, *pairwise.Manhattan, *pairwise.Chebyshev, *pairwise.Minkowski:
+	default:
+		return nil, fmt.Errorf("KDTree doesn't support distance %T", distance)
+	}
+	if leafSize < 1 {
+		return nil, fmt.Errorf("Leaf size must be positive")
+	}
+	t := &KDTree{
+		rows:     rows,
+		distance: newVectorDistance(distance),
+		order:    make([]int, len(rows)),
+		nodes:    make([]kdNode, 0),
+	}
+	for i := range t.order {
+		t.order[i] = i
+	}
+	if len(rows) > 0 {
+		t.build(0, len(rows), leafSize)
+	}
+	return t, nil
+}
+
+// build creates the node covering order[start:end], and returns its
+// position.
+func (t *KDTree) build(start, end, leafSize int) int {
+	pos := len(t.nodes)
+	t.nodes = append(t.nodes, kdNode{start: start, end: end, left: -1, right: -1})
+	if end-start <= leafSize {
+		return pos
+	}
+	axis, spread := widestDimension(t.rows, t.order[start:end])
+	if spread == 0 {
+		// Every row is identical
+		return pos
+	}
+	mid := medianSplit(t.rows, t.order[start:end], axis) + start
+	t.nodes[pos].axis = axis
+	t.nodes[pos].split = t.rows[t.order[mid]][axis]
+	left := t.build(start, mid, leafSize)
+	right := t.build(mid, end, leafSize)
+	t.nodes[pos].left = left
+	t.nodes[pos].right = right
+	return pos
+}
+
+// Query returns the k rows closest to x, nearest first.
+func (t *KDTree) Query(x []float64, k int) ([]int, []float64) {
+	set := newNeighbourSet(k)
+	if len(t.nodes) > 0 {
+		t.search(0, x, set)
+	}
+	return set.results()
+}
+
+func (t *KDTree) search(pos int, x []float64, set *neighbourSet) {
+	node := t.nodes[pos]
+	if node.left < 0 {
+		for _, i := range t.order[node.start:node.end] {
+			set.offer(i, t.distance(x, t.rows[i]))
+		}
+		return
+	}
+	diff := x[node.axis] - node.split
+	near, far := node.left, node.right
+	if diff >= 0 {
+		near, far = far, near
+	}
+	t.search(near, x, set)
+	// Every row on the far side is at least |diff| away
+	if math.Abs(diff) <= set.bound() {
+		t.search(far, x, set)
+	}
+}
+
+// widestDimension returns the dimension in which the given rows are
+// most spread out, and the size of that spread.
+func widestDimension(rows [][]float64, indices []int) (int, float64) {
+	best, bestSpread := 0, -1.0
+	for d := range rows[indices[0]] {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, i := range indices {
+			lo = math.Min(lo, rows[i][d])
+			hi = math.Max(hi, rows[i][d])
+		}
+		if hi-lo > bestSpread {
+			best, bestSpread = d, hi-lo
+		}
+	}
+	return best, bestSpread
+}
+
+// byDimension sorts row numbers by one of their values.
+type byDimension struct {
+	rows    [][]float64
+	indices []int
+	axis    int
+}
+
+func (b byDimension) Len() int      { return len(b.indices) }
+func (b byDimension) Swap(i, j int) { b.indices[i], b.indices[j] = b.indices[j], b.indices[i] }
+func (b byDimension) Less(i, j int) bool {
+	return b.rows[b.indices[i]][b.axis] < b.rows[b.indices[j]][b.axis]
+}
+
+// medianSplit sorts indices by the given dimension and returns the
+// position of the median, which is never 0 so both halves are
+// non-empty.
+func medianSplit(rows [][]float64, indices []int, axis int) int {
+	sort.Sort(byDimension{rows, indices, axis})
+	return len(indices) / 2
+}
@@ -12,15 +12,37 @@ import (
 )
 
 // A KNNClassifier consists of a data matrix, associated labels in the same order as the matrix, and a distance function.
-// The accepted distance functions at this time are 'euclidean' and 'manhattan'.
+// The distance function can be any of those registered with pairwise.RegisterDistance, such as 'euclidean',
+// 'manhattan', 'cosine' or 'hamming'. Distances which are pairwise.CategoricalDistanceFuncs (like 'hamming' and
+// 'jaccard') compare the CategoricalAttributes and BinaryAttributes, 'gower' and 'heom' compare Attributes of
+// every type, and all others compare the FloatAttributes. Distances which need fitting (like 'mahalanobis')
+// are fitted to the training data.
 // Optimisations only occur when things are identically group into identical
-// AttributeGroups, which don't include the class variable, in the same order.
+// AttributeGroups, which don't include the class variable, in the same order;
+// otherwise distances are computed in blocks in pure Go.
+// Weights controls how much each neighbour's vote counts: 'uniform' (the default), 'distance' (inverse
+// distance), or one of the kernels 'gaussian', 'epanechnikov' or 'tricube' with the given Bandwidth (if
+// Bandwidth isn't positive, the distance to the furthest of the neighbours is used).
+// Setting Algorithm to 'kd_tree' or 'ball_tree' builds a NeighbourIndex at Fit time, so that
+// each prediction doesn't need to measure the distance to every training row. 'hnsw' builds an
+// approximate index (configured by HNSW), which is much faster on wide data but may miss neighbours.
 type KNNClassifier struct {
 	base.BaseEstimator
 	TrainingData       base.FixedDataGrid
 	DistanceFunc       string
 	NearestNeighbours  int
 	AllowOptimisations bool
+	Weights            string
+	Bandwidth          float64
+	// One of 'brute' (the default), 'kd_tree', 'ball_tree' or 'hnsw'
+	Algorithm string
+	// Maximum number of rows in each leaf of a tree
+	LeafSize int
+	// Settings for the 'hnsw' Algorithm
+	HNSW       HNSWParams
+	distance   pairwise.PairwiseDistanceFunc
+	index      NeighbourIndex
+	indexAttrs []base.Attribute
 }
 
 // NewKnnClassifier returns a new classifier
@@ -29,12 +51,81 @@ func NewKnnClassifier(distfunc string, neighbours int) *KNNClassifier {
 	KNN.DistanceFunc = distfunc
 	KNN.NearestNeighbours = neighbours
 	KNN.AllowOptimisations = true
+	KNN.Weights = "uniform"
+	KNN.Algorithm = "brute"
+	KNN.LeafSize = 30
+	KNN.HNSW = DefaultHNSWParams()
 	return &KNN
 }
 
-// Fit stores the training data for later
+// Fit stores the training data for later, fits the distance to it, and
+// builds an index over it if Algorithm isn't 'brute'.
 func (KNN *KNNClassifier) Fit(trainingData base.FixedDataGrid) {
+	distanceFunc, err := newDistance(KNN.DistanceFunc, trainingData)
+	if err != nil {
+		panic(err)
+	}
 	KNN.TrainingData = trainingData
+	KNN.distance = distanceFunc
+	KNN.index = nil
+	if KNN.Algorithm == "" || KNN.Algorithm == "brute" {
+		return
+	}
+	attrs := KNN.distanceAttributes(distanceFunc, trainingData.AllAttributes())
+	_, trainRows := trainingData.Size()
+	rows := make([][]float64, trainRows)
+	trainingData.MapOverRows(base.ResolveAttributes(trainingData, attrs), func(row [][]byte, rowNo int) (bool, error) {
+		rows[rowNo] = make([]float64, len(attrs))
+		for i, a := range attrs {
+			rows[rowNo][i] = attributeValue(a, row[i])
+		}
+		return true, nil
+	})
+	index, err := buildIndex(KNN.Algorithm, rows, distanceFunc, KNN.LeafSize, KNN.HNSW)
+	if err != nil {
+		panic(err)
+	}
+	KNN.index = index
+	KNN.indexAttrs = attrs
+}
+
+// newDistance returns the distance registered as name, fitted to
+// trainingData if it's a pairwise.TrainableDistanceFunc.
+func newDistance(name string, trainingData base.FixedDataGrid) (pairwise.PairwiseDistanceFunc, error) {
+	distanceFunc, err := pairwise.NewDistance(name)
+	if err != nil {
+		return nil, err
+	}
+	if trainable, ok := distanceFunc.(pairwise.TrainableDistanceFunc); ok {
+		if err := trainable.Fit(trainingData); err != nil {
+			return nil, err
+		}
+	}
+	return distanceFunc, nil
+}
+
+// distanceAttributes returns the non-class Attributes which distanceFunc
+// can compare (or the ones it chooses, if it's a
+// pairwise.AttributeDistanceFunc).
+func (KNN *KNNClassifier) distanceAttributes(distanceFunc pairwise.PairwiseDistanceFunc, attrs []base.Attribute) []base.Attribute {
+	if chooser, ok := distanceFunc.(pairwise.AttributeDistanceFunc); ok {
+		return chooser.GetAttributes()
+	}
+	ret := make([]base.Attribute, 0)
+	_, categorical := distanceFunc.(pairwise.CategoricalDistanceFunc)
+	for _, a := range base.AttributeDifference(attrs, KNN.TrainingData.AllClassAttributes()) {
+		switch a.(type) {
+		case *base.CategoricalAttribute, *base.BinaryAttribute:
+			if categorical {
+				ret = append(ret, a)
+			}
+		case *base.FloatAttribute:
+			if !categorical {
+				ret = append(ret, a)
+			}
+		}
+	}
+	return ret
 }
 
 func (KNN *KNNClassifier) canUseOptimisations(what base.FixedDataGrid) bool {
@@ -88,16 +179,12 @@ func (KNN *KNNClassifier) canUseOptimisations(what base.FixedDataGrid) bool {
 }
 
 // Predict returns a classification for the vector, based on a vector input, using the KNN algorithm.
+//
+// IMPORTANT: Predict panics if Fit hasn't been called.
 func (KNN *KNNClassifier) Predict(what base.FixedDataGrid) base.FixedDataGrid {
-	// Check what distance function we are using
-	var distanceFunc pairwise.PairwiseDistanceFunc
-	switch KNN.DistanceFunc {
-	case "euclidean":
-		distanceFunc = pairwise.NewEuclidean()
-	case "manhattan":
-		distanceFunc = pairwise.NewManhattan()
-	default:
-		panic("unsupported distance function")
+	distanceFunc := KNN.distance
+	if distanceFunc == nil {
+		panic("Fit() must be called first")
 	}
 	// Check Compatibility
 	allAttrs := base.CheckCompatible(what, KNN.TrainingData)
@@ -106,6 +193,11 @@ func (KNN *KNNClassifier) Predict(what base.FixedDataGrid) base.FixedDataGrid {
 		return nil
 	}
 
+	// Use the index if there is one
+	if KNN.index != nil {
+		return KNN.indexedPredict(what)
+	}
+
 	// Use optimised version if permitted
 	if KNN.AllowOptimisations {
 		if KNN.DistanceFunc == "euclidean" {
@@ -114,116 +206,120 @@ func (KNN *KNNClassifier) Predict(what base.FixedDataGrid) base.FixedDataGrid {
 			}
 		}
 	}
-	fmt.Println("Optimisations are switched off")
 
-	// Remove the Attributes which aren't numeric
-	allNumericAttrs := make([]base.Attribute, 0)
-	for _, a := range allAttrs {
-		if fAttr, ok := a.(*base.FloatAttribute); ok {
-			allNumericAttrs = append(allNumericAttrs, fAttr)
-		}
+	// Otherwise compare every row in blocks
+	return KNN.blockedPredict(what, distanceFunc, KNN.distanceAttributes(distanceFunc, allAttrs))
+}
+
+// blockedPredict classifies each row of what by a vote between its
+// nearest neighbours, found by measuring the distance to every training
+// row with blockedDistances.
+func (KNN *KNNClassifier) blockedPredict(what base.FixedDataGrid, distanceFunc pairwise.PairwiseDistanceFunc, attrs []base.Attribute) base.FixedDataGrid {
+	train, err := readAttributeRows(KNN.TrainingData, attrs)
+	if err != nil {
+		panic(err)
+	}
+	queries, err := readAttributeRows(what, attrs)
+	if err != nil {
+		panic(err)
 	}
+	_, rows := what.Size()
+	neighbours := make([][]int, rows)
+	distances := make([][]float64, rows)
+	blockedDistances(queries, train, len(attrs), distanceFunc, func(q int, d []float64) {
+		neighbours[q], distances[q] = nearest(d, KNN.NearestNeighbours)
+	})
 
-	// Generate return vector
 	ret := base.GeneratePredictionVector(what)
+	maxmap := make(map[string]float64)
+	for i := 0; i < rows; i++ {
+		base.SetClass(ret, i, KNN.vote(maxmap, neighbours[i], distances[i]))
+	}
+	return ret
+}
 
-	// Resolve Attribute specifications for both
-	whatAttrSpecs := base.ResolveAttributes(what, allNumericAttrs)
-	trainAttrSpecs := base.ResolveAttributes(KNN.TrainingData, allNumericAttrs)
-
-	// Reserve storage for most the most similar items
-	distances := make(map[int]float64)
-
-	// Reserve storage for voting map
-	maxmap := make(map[string]int)
-
-	// Reserve storage for row computations
-	trainRowBuf := make([]float64, len(allNumericAttrs))
-	predRowBuf := make([]float64, len(allNumericAttrs))
-
-	_, maxRow := what.Size()
-	curRow := 0
-
-	// Iterate over all outer rows
-	what.MapOverRows(whatAttrSpecs, func(predRow [][]byte, predRowNo int) (bool, error) {
-
-		if (curRow%1) == 0 && curRow > 0 {
-			fmt.Printf("KNN: %.2f %% done\n", float64(curRow)*100.0/float64(maxRow))
-		}
-		curRow++
-
-		// Read the float values out
-		for i, _ := range allNumericAttrs {
-			predRowBuf[i] = base.UnpackBytesToFloat(predRow[i])
+// indexedPredict classifies each row of what by a vote between its
+// nearest neighbours, found using the index built by Fit.
+func (KNN *KNNClassifier) indexedPredict(what base.FixedDataGrid) base.FixedDataGrid {
+	ret := base.GeneratePredictionVector(what)
+	maxmap := make(map[string]float64)
+	query := make([]float64, len(KNN.indexAttrs))
+	what.MapOverRows(base.ResolveAttributes(what, KNN.indexAttrs), func(row [][]byte, rowNo int) (bool, error) {
+		for i, a := range KNN.indexAttrs {
+			query[i] = attributeValue(a, row[i])
 		}
-
-		predMat := utilities.FloatsToMatrix(predRowBuf)
-
-		// Find the closest match in the training data
-		KNN.TrainingData.MapOverRows(trainAttrSpecs, func(trainRow [][]byte, srcRowNo int) (bool, error) {
-			// Read the float values out
-			for i, _ := range allNumericAttrs {
-				trainRowBuf[i] = base.UnpackBytesToFloat(trainRow[i])
-			}
-
-			// Compute the distance
-			trainMat := utilities.FloatsToMatrix(trainRowBuf)
-			distances[srcRowNo] = distanceFunc.Distance(predMat, trainMat)
-			return true, nil
-		})
-
-		sorted := utilities.SortIntMap(distances)
-		values := sorted[:KNN.NearestNeighbours]
-
-		maxClass := KNN.vote(maxmap, values)
-
-		base.SetClass(ret, predRowNo, maxClass)
+		neighbours, distances := KNN.index.Query(query, KNN.NearestNeighbours)
+		base.SetClass(ret, rowNo, KNN.vote(maxmap, neighbours, distances))
 		return true, nil
-
 	})
-
 	return ret
 }
 
-func (KNN *KNNClassifier) vote(maxmap map[string]int, values []int) string {
+// attributeValue converts a value of a FloatAttribute, a
+// CategoricalAttribute or a BinaryAttribute to a float64.
+func attributeValue(a base.Attribute, val []byte) float64 {
+	switch a.(type) {
+	case *base.CategoricalAttribute:
+		return float64(base.UnpackBytesToU64(val))
+	case *base.BinaryAttribute:
+		return float64(val[0])
+	}
+	return base.UnpackBytesToFloat(val)
+}
+
+// vote returns the class with the greatest total weight among the
+// neighbours (given nearest first). Ties go to the class of the nearest
+// neighbour.
+func (KNN *KNNClassifier) vote(maxmap map[string]float64, values []int, distances []float64) string {
 	// Reset maxMap
 	for a := range maxmap {
 		maxmap[a] = 0
 	}
 
 	// Refresh maxMap
-	for _, elem := range values {
-		label := base.GetClass(KNN.TrainingData, elem)
-		if _, ok := maxmap[label]; ok {
-			maxmap[label]++
-		} else {
-			maxmap[label] = 1
-		}
+	weights := neighbourWeights(KNN.Weights, KNN.Bandwidth, distances)
+	for i, elem := range values {
+		maxmap[base.GetClass(KNN.TrainingData, elem)] += weights[i]
 	}
 
-	// Sort the maxMap
+	// Find the heaviest class, visiting them in order of distance
 	var maxClass string
-	maxVal := -1
-	for a := range maxmap {
-		if maxmap[a] > maxVal {
-			maxVal = maxmap[a]
-			maxClass = a
+	maxVal := -1.0
+	for _, elem := range values {
+		label := base.GetClass(KNN.TrainingData, elem)
+		if maxmap[label] > maxVal {
+			maxVal = maxmap[label]
+			maxClass = label
 		}
 	}
 	return maxClass
 }
 
 // A KNNRegressor consists of a data matrix, associated result variables in the same order as the matrix, and a name.
+// Distances which need fitting (like 'mahalanobis') are fitted to the data matrix, whose columns are all treated
+// as FloatAttributes.
+// Like KNNClassifier, setting Algorithm to 'kd_tree', 'ball_tree' or 'hnsw' builds a NeighbourIndex at Fit time.
 type KNNRegressor struct {
 	base.BaseEstimator
 	Values       []float64
 	DistanceFunc string
+	// One of 'brute' (the default), 'kd_tree', 'ball_tree' or 'hnsw'
+	Algorithm string
+	// Maximum number of rows in each leaf of a tree
+	LeafSize int
+	// Settings for the 'hnsw' Algorithm
+	HNSW     HNSWParams
+	index    NeighbourIndex
+	distance pairwise.PairwiseDistanceFunc
 }
 
 // NewKnnRegressor mints a new classifier.
 func NewKnnRegressor(distfunc string) *KNNRegressor {
 	KNN := KNNRegressor{}
 	KNN.DistanceFunc = distfunc
+	KNN.Algorithm = "brute"
+	KNN.LeafSize = 30
+	KNN.HNSW = DefaultHNSWParams()
 	return &KNN
 }
 
@@ -234,29 +330,59 @@ func (KNN *KNNRegressor) Fit(values []float64, numbers []float64, rows int, cols
 
 	KNN.Data = mat64.NewDense(rows, cols, numbers)
 	KNN.Values = values
+	KNN.index = nil
+	distanceFunc, err := pairwise.NewDistance(KNN.DistanceFunc)
+	if err != nil {
+		panic(err)
+	}
+	if trainable, ok := distanceFunc.(pairwise.TrainableDistanceFunc); ok {
+		if err := trainable.Fit(matrixInstances(numbers, rows, cols)); err != nil {
+			panic(err)
+		}
+	}
+	KNN.distance = distanceFunc
+	if KNN.Algorithm == "" || KNN.Algorithm == "brute" {
+		return
+	}
+
+	vectors := make([][]float64, rows)
+	for i := range vectors {
+		vectors[i] = numbers[i*cols : (i+1)*cols]
+	}
+	index, err := buildIndex(KNN.Algorithm, vectors, distanceFunc, KNN.LeafSize, KNN.HNSW)
+	if err != nil {
+		panic(err)
+	}
+	KNN.index = index
 }
 
 func (KNN *KNNRegressor) Predict(vector *mat64.Dense, K int) float64 {
+	// Use the index if there is one
+	if KNN.index != nil {
+		r, c := vector.Dims()
+		query := make([]float64, 0, r*c)
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				query = append(query, vector.At(i, j))
+			}
+		}
+		neighbours, _ := KNN.index.Query(query, K)
+		sum := 0.0
+		for _, elem := range neighbours {
+			sum += KNN.Values[elem]
+		}
+		return sum / float64(len(neighbours))
+	}
+
 	// Get the number of rows
 	rows, _ := KNN.Data.Dims()
 	rownumbers := make(map[int]float64)
 	labels := make([]float64, 0)
 
-	// Check what distance function we are using
-	var distanceFunc pairwise.PairwiseDistanceFunc
-	switch KNN.DistanceFunc {
-	case "euclidean":
-		distanceFunc = pairwise.NewEuclidean()
-	case "manhattan":
-		distanceFunc = pairwise.NewManhattan()
-	default:
-		panic("unsupported distance function")
-	}
-
 	for i := 0; i < rows; i++ {
 		row := KNN.Data.RowView(i)
 		rowMat := utilities.FloatsToMatrix(row)
-		distance := distanceFunc.Distance(rowMat, vector)
+		distance := KNN.distance.Distance(rowMat, vector)
 		rownumbers[i] = distance
 	}
 
@@ -273,3 +399,21 @@ func (KNN *KNNRegressor) Predict(vector *mat64.Dense, K int) float64 {
 	average := sum / float64(K)
 	return average
 }
+
+// matrixInstances returns rows of numbers (in row-major order) as
+// DenseInstances with a FloatAttribute per column, so that distances
+// can be fitted to them.
+func matrixInstances(numbers []float64, rows int, cols int) *base.DenseInstances {
+	ret := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, cols)
+	for j := range specs {
+		specs[j] = ret.AddAttribute(base.NewFloatAttribute(fmt.Sprintf("%d", j)))
+	}
+	ret.Extend(rows)
+	for i := 0; i < rows; i++ {
+		for j, s := range specs {
+			ret.Set(s, i, base.PackFloatToBytes(numbers[i*cols+j]))
+		}
+	}
+	return ret
+}
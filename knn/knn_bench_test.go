@@ -8,10 +8,10 @@ import (
 )
 
 func readMnist() (*base.DenseInstances, *base.DenseInstances) {
-	// Create the class Attribute
-	classAttrs := make(map[int]base.Attribute)
-	classAttrs[0] = base.NewCategoricalAttribute()
-	classAttrs[0].SetName("label")
+	// Read the class Attribute as categorical
+	schema := base.CSVSchema{
+		"label": {Attribute: base.NewCategoricalAttribute()},
+	}
 	// Setup the class Attribute to be in its own group
 	classAttrGroups := make(map[string]string)
 	classAttrGroups["label"] = "ClassGroup"
@@ -22,7 +22,7 @@ func readMnist() (*base.DenseInstances, *base.DenseInstances) {
 		"../examples/datasets/mnist_train.csv",
 		attrGroups,
 		classAttrGroups,
-		classAttrs,
+		schema,
 		true,
 	)
 	if err != nil {
@@ -1,3 +1,5 @@
+// +build cgo
+
 package knn
 
 // #include "knn.h"
@@ -5,6 +7,7 @@ import "C"
 
 import (
 	"github.com/sjwhitworth/golearn/base"
+	"math"
 	"sort"
 	"unsafe"
 )
@@ -56,7 +59,8 @@ func (KNN *KNNClassifier) optimisedEuclideanPredict(d *base.DenseInstances) base
 	distanceVec := distanceRecs(make([]_Ctype_struct_dist, trainRows))
 	// Additional datastructures
 	voteVec := make([]int, KNN.NearestNeighbours)
-	maxMap := make(map[string]int)
+	voteDistances := make([]float64, KNN.NearestNeighbours)
+	maxMap := make(map[string]float64)
 
 	for row := 0; row < predRows; row++ {
 		for i := 0; i < trainRows; i++ {
@@ -78,8 +82,9 @@ func (KNN *KNNClassifier) optimisedEuclideanPredict(d *base.DenseInstances) base
 		votes := distanceVec[:KNN.NearestNeighbours]
 		for i, v := range votes {
 			voteVec[i] = int(v.p)
+			voteDistances[i] = math.Sqrt(float64(v.dist))
 		}
-		maxClass := KNN.vote(maxMap, voteVec)
+		maxClass := KNN.vote(maxMap, voteVec, voteDistances)
 		base.SetClass(ret, row, maxClass)
 	}
 	return ret
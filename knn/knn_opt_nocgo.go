@@ -0,0 +1,15 @@
+// +build !cgo
+
+package knn
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+)
+
+// optimisedEuclideanPredict needs cgo, so without it the pure Go
+// blocked computation is used instead.
+func (KNN *KNNClassifier) optimisedEuclideanPredict(d *base.DenseInstances) base.FixedDataGrid {
+	euclidean := pairwise.NewEuclidean()
+	return KNN.blockedPredict(d, euclidean, KNN.distanceAttributes(euclidean, d.AllAttributes()))
+}
@@ -1,6 +1,7 @@
 package knn
 
 import (
+	"github.com/gonum/matrix/mat64"
 	"github.com/sjwhitworth/golearn/base"
 	. "github.com/smartystreets/goconvey/convey"
 	"testing"
@@ -65,3 +66,130 @@ func TestKnnClassifierWithOptimisations(t *testing.T) {
 		})
 	})
 }
+
+func TestKnnClassifierWithCategoricalDistance(t *testing.T) {
+	Convey("Given categorical training data", t, func() {
+		data := base.NewDenseInstances()
+		names := []string{"colour", "shape", "size", "label"}
+		attrs := make([]*base.CategoricalAttribute, len(names))
+		specs := make([]base.AttributeSpec, len(names))
+		for i, name := range names {
+			attrs[i] = base.NewCategoricalAttribute()
+			attrs[i].SetName(name)
+			specs[i] = data.AddAttribute(attrs[i])
+		}
+		data.AddClassAttribute(attrs[3])
+		rows := [][]string{
+			{"red", "round", "small", "cherry"},
+			{"red", "round", "small", "cherry"},
+			{"yellow", "long", "large", "banana"},
+			{"yellow", "long", "medium", "banana"},
+		}
+		data.Extend(len(rows))
+		for i, row := range rows {
+			for j, v := range row {
+				data.Set(specs[j], i, attrs[j].GetSysValFromString(v))
+			}
+		}
+
+		cls := NewKnnClassifier("hamming", 1)
+		cls.Fit(data)
+
+		Convey("Rows should be matched by category", func() {
+			query := base.NewDenseInstances()
+			querySpecs := make([]base.AttributeSpec, len(names))
+			for i := range names {
+				querySpecs[i] = query.AddAttribute(attrs[i])
+			}
+			query.AddClassAttribute(attrs[3])
+			query.Extend(2)
+			for i, row := range [][]string{{"yellow", "long", "small", "cherry"}, {"red", "round", "large", "cherry"}} {
+				for j, v := range row {
+					query.Set(querySpecs[j], i, attrs[j].GetSysValFromString(v))
+				}
+			}
+
+			predictions := cls.Predict(query)
+			So(base.GetClass(predictions, 0), ShouldEqual, "banana")
+			So(base.GetClass(predictions, 1), ShouldEqual, "cherry")
+		})
+	})
+}
+
+func TestKnnClassifierWithMixedAttributes(t *testing.T) {
+	// accuracy returns the proportion of the training data predicted
+	// correctly when it's also the test data, which is 1 if every row
+	// is its own nearest neighbour
+	accuracy := func(data base.FixedDataGrid, distance string) float64 {
+		cls := NewKnnClassifier(distance, 1)
+		cls.Fit(data)
+		predictions := cls.Predict(data)
+		_, rows := data.Size()
+		correct := 0
+		for i := 0; i < rows; i++ {
+			if base.GetClass(predictions, i) == base.GetClass(data, i) {
+				correct++
+			}
+		}
+		return float64(correct) / float64(rows)
+	}
+
+	Convey("Given a dataset with only CategoricalAttributes", t, func() {
+		tennis, err := base.ParseCSVToInstances("../examples/datasets/tennis.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("Gower and HEOM distance should use every Attribute", func() {
+			So(accuracy(tennis, "gower"), ShouldEqual, 1)
+			So(accuracy(tennis, "heom"), ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given a dataset with both kinds of Attribute", t, func() {
+		weather, err := base.ParseDenseARFFToInstances("../examples/datasets/weather.arff")
+		So(err, ShouldBeNil)
+
+		Convey("Gower and HEOM distance should use every Attribute", func() {
+			So(accuracy(weather, "gower"), ShouldEqual, 1)
+			So(accuracy(weather, "heom"), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestKnnRegressorWithTrainableDistances(t *testing.T) {
+	Convey("Given some regression data", t, func() {
+		data := []float64{0, 0, 1, 2, 2, 1, 3, 3, 10, 9}
+		values := []float64{0, 1, 2, 3, 4}
+
+		for _, distance := range []string{"mahalanobis", "gower", "heom"} {
+			Convey("The "+distance+" distance should be fitted to the data", func() {
+				knn := NewKnnRegressor(distance)
+				knn.Fit(values, data, 5, 2)
+				So(knn.Predict(mat64.NewDense(1, 2, []float64{2, 1}), 1), ShouldEqual, 2)
+				So(knn.Predict(mat64.NewDense(1, 2, []float64{9, 9}), 1), ShouldEqual, 4)
+			})
+		}
+	})
+}
+
+func TestKnnClassifierFitsDistanceOnce(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		train, test := base.InstancesTrainTestSplit(iris, 0.5)
+
+		Convey("Predicting before Fit should panic", func() {
+			cls := NewKnnClassifier("mahalanobis", 3)
+			So(func() { cls.Predict(test) }, ShouldPanicWith, "Fit() must be called first")
+		})
+
+		Convey("Predict should use the distance fitted by Fit", func() {
+			cls := NewKnnClassifier("mahalanobis", 3)
+			cls.Fit(train)
+			distance := cls.distance
+			So(distance, ShouldNotBeNil)
+			cls.Predict(test)
+			cls.Predict(test)
+			So(cls.distance, ShouldEqual, distance)
+		})
+	})
+}
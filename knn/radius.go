@@ -0,0 +1,106 @@
+package knn
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+)
+
+// RadiusNeighborsClassifier classifies each row by a vote between every
+// training row within a fixed distance (Radius) of it, rather than a
+// fixed number of them. DistanceFunc, Weights and Bandwidth work as for
+// KNNClassifier.
+type RadiusNeighborsClassifier struct {
+	base.BaseEstimator
+	TrainingData base.FixedDataGrid
+	DistanceFunc string
+	Radius       float64
+	Weights      string
+	Bandwidth    float64
+	// The class predicted for rows without any training rows within
+	// Radius. If it's empty, Predict returns an error instead.
+	OutlierClass string
+	attrs        []base.Attribute
+	train        []float64
+	distanceFunc pairwise.PairwiseDistanceFunc
+}
+
+// NewRadiusNeighborsClassifier returns a new classifier.
+func NewRadiusNeighborsClassifier(distfunc string, radius float64) *RadiusNeighborsClassifier {
+	return &RadiusNeighborsClassifier{
+		DistanceFunc: distfunc,
+		Radius:       radius,
+		Weights:      "uniform",
+	}
+}
+
+// String returns a human-readable summary.
+func (r *RadiusNeighborsClassifier) String() string {
+	return fmt.Sprintf("RadiusNeighborsClassifier(%s, radius: %g, weights: %s)", r.DistanceFunc, r.Radius, r.Weights)
+}
+
+// Fit stores the training data for later.
+func (r *RadiusNeighborsClassifier) Fit(trainingData base.FixedDataGrid) error {
+	if r.Radius <= 0 {
+		return fmt.Errorf("Radius must be positive")
+	}
+	distanceFunc, err := newDistance(r.DistanceFunc, trainingData)
+	if err != nil {
+		return err
+	}
+	// Use the same Attributes as KNNClassifier would
+	knn := &KNNClassifier{TrainingData: trainingData}
+	attrs := knn.distanceAttributes(distanceFunc, trainingData.AllAttributes())
+	if len(attrs) == 0 {
+		return fmt.Errorf("No Attributes which %s can compare", r.DistanceFunc)
+	}
+	train, err := readAttributeRows(trainingData, attrs)
+	if err != nil {
+		return err
+	}
+	r.TrainingData = trainingData
+	r.attrs = attrs
+	r.train = train
+	r.distanceFunc = distanceFunc
+	return nil
+}
+
+// Predict returns the class of each row of what which has the greatest
+// total weight among the training rows within Radius.
+func (r *RadiusNeighborsClassifier) Predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if r.TrainingData == nil {
+		return nil, fmt.Errorf("You need to Fit() before you can Predict()")
+	}
+	queries, err := readAttributeRows(what, r.attrs)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := what.Size()
+	neighbours := make([][]int, rows)
+	distances := make([][]float64, rows)
+	blockedDistances(queries, r.train, len(r.attrs), r.distanceFunc, func(q int, d []float64) {
+		for t, dist := range d {
+			if dist <= r.Radius {
+				neighbours[q] = append(neighbours[q], t)
+				distances[q] = append(distances[q], dist)
+			}
+		}
+	})
+
+	// Voting is the same as KNNClassifier's, except that neighbours
+	// aren't sorted, so ties go to the class of the first training row
+	voter := &KNNClassifier{TrainingData: r.TrainingData, Weights: r.Weights, Bandwidth: r.Bandwidth}
+	ret := base.GeneratePredictionVector(what)
+	maxmap := make(map[string]float64)
+	for i := 0; i < rows; i++ {
+		if len(neighbours[i]) == 0 {
+			if r.OutlierClass == "" {
+				return nil, fmt.Errorf("Row %d has no training rows within %g", i, r.Radius)
+			}
+			base.SetClass(ret, i, r.OutlierClass)
+			continue
+		}
+		base.SetClass(ret, i, voter.vote(maxmap, neighbours[i], distances[i]))
+	}
+	return ret, nil
+}
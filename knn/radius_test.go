@@ -0,0 +1,59 @@
+package knn
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestRadiusNeighborsClassifier(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		train, test := base.InstancesTrainTestSplit(iris, 0.4)
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewRadiusNeighborsClassifier("euclidean", 1).Predict(test)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Votes within a radius should classify most rows correctly", func() {
+			cls := NewRadiusNeighborsClassifier("euclidean", 1)
+			cls.Weights = "distance"
+			cls.OutlierClass = "Iris-setosa"
+			So(cls.Fit(train), ShouldBeNil)
+			predictions, err := cls.Predict(test)
+			So(err, ShouldBeNil)
+			cf, err := evaluation.GetConfusionMatrix(test, predictions)
+			So(err, ShouldBeNil)
+			So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.85)
+		})
+
+		Convey("Rows without neighbours should be reported", func() {
+			cls := NewRadiusNeighborsClassifier("euclidean", 1e-9)
+			So(cls.Fit(train), ShouldBeNil)
+			_, err := cls.Predict(test)
+			So(err, ShouldNotBeNil)
+
+			Convey("Unless there's an OutlierClass", func() {
+				cls.OutlierClass = "unknown"
+				predictions, err := cls.Predict(test)
+				So(err, ShouldBeNil)
+				// Only rows repeated in the training data have neighbours
+				_, rows := predictions.Size()
+				unknown := 0
+				for i := 0; i < rows; i++ {
+					if base.GetClass(predictions, i) == "unknown" {
+						unknown++
+					}
+				}
+				So(unknown, ShouldBeGreaterThan, rows*9/10)
+			})
+		})
+
+		Convey("A non-positive radius should be rejected", func() {
+			So(NewRadiusNeighborsClassifier("euclidean", 0).Fit(train), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package knn
+
+import (
+	"fmt"
+	"math"
+)
+
+// neighbourWeights returns how much each neighbour counts towards a
+// prediction, given its distance. The schemes are
+//
+//	"uniform" (or ""): every neighbour counts equally.
+//	"distance": neighbours count 1/distance. If any are at distance 0,
+//	only those count.
+//	"gaussian", "epanechnikov", "tricube": neighbours count K(d/h) for
+//	the named kernel K, where h is bandwidth, or the distance to the
+//	furthest neighbour if bandwidth isn't positive.
+//
+// If every weight would be zero, neighbours count equally.
+func neighbourWeights(scheme string, bandwidth float64, distances []float64) []float64 {
+	ret := make([]float64, len(distances))
+	switch scheme {
+	case "", "uniform":
+	case "distance":
+		exact := false
+		for _, d := range distances {
+			if d == 0 {
+				exact = true
+			}
+		}
+		for i, d := range distances {
+			if exact {
+				if d == 0 {
+					ret[i] = 1
+				}
+			} else {
+				ret[i] = 1 / d
+			}
+		}
+	case "gaussian", "epanechnikov", "tricube":
+		h := bandwidth
+		if h <= 0 {
+			for _, d := range distances {
+				h = math.Max(h, d)
+			}
+		}
+		if h == 0 {
+			break
+		}
+		for i, d := range distances {
+			u := d / h
+			switch scheme {
+			case "gaussian":
+				ret[i] = math.Exp(-0.5 * u * u)
+			case "epanechnikov":
+				ret[i] = math.Max(0, 1-u*u)
+			case "tricube":
+				if u < 1 {
+					ret[i] = math.Pow(1-u*u*u, 3)
+				}
+			}
+		}
+	default:
+		panic(fmt.Sprintf("unsupported weighting %q", scheme))
+	}
+
+	total := 0.0
+	for _, w := range ret {
+		total += w
+	}
+	if total == 0 {
+		for i := range ret {
+			ret[i] = 1
+		}
+	}
+	return ret
+}
@@ -0,0 +1,90 @@
+package knn
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestNeighbourWeights(t *testing.T) {
+	Convey("Given some neighbour distances", t, func() {
+		distances := []float64{0.5, 1, 2}
+
+		Convey("Uniform weights should all be 1", func() {
+			So(neighbourWeights("uniform", 0, distances), ShouldResemble, []float64{1, 1, 1})
+		})
+
+		Convey("Distance weights should be inverse distances", func() {
+			So(neighbourWeights("distance", 0, distances), ShouldResemble, []float64{2, 1, 0.5})
+		})
+
+		Convey("Exact matches should take all of the weight", func() {
+			So(neighbourWeights("distance", 0, []float64{0, 1}), ShouldResemble, []float64{1, 0})
+		})
+
+		Convey("Kernel weights should use the given bandwidth", func() {
+			w := neighbourWeights("gaussian", 1, distances)
+			So(w[1], ShouldAlmostEqual, math.Exp(-0.5))
+			w = neighbourWeights("epanechnikov", 4, distances)
+			So(w[2], ShouldAlmostEqual, 0.75)
+		})
+
+		Convey("Without a bandwidth, the furthest neighbour should set it", func() {
+			w := neighbourWeights("tricube", 0, distances)
+			So(w[2], ShouldEqual, 0)
+			So(w[1], ShouldAlmostEqual, math.Pow(1-0.125, 3))
+		})
+
+		Convey("If every weight is zero, neighbours should count equally", func() {
+			So(neighbourWeights("epanechnikov", 0, []float64{1}), ShouldResemble, []float64{1})
+		})
+
+		Convey("Unknown schemes should panic", func() {
+			So(func() { neighbourWeights("nonexistent", 0, distances) }, ShouldPanic)
+		})
+	})
+}
+
+func TestKnnClassifierWithWeights(t *testing.T) {
+	Convey("Given one close neighbour of one class and two distant ones of another", t, func() {
+		data := base.NewDenseInstances()
+		x := base.NewFloatAttribute("x")
+		xSpec := data.AddAttribute(x)
+		cls := base.NewCategoricalAttribute()
+		cls.SetName("class")
+		clsSpec := data.AddAttribute(cls)
+		data.AddClassAttribute(cls)
+		data.Extend(4)
+		for i, row := range []struct {
+			x     float64
+			class string
+		}{{0.1, "near"}, {2.0, "far"}, {2.1, "far"}, {0, "query"}} {
+			data.Set(xSpec, i, base.PackFloatToBytes(row.x))
+			data.Set(clsSpec, i, cls.GetSysValFromString(row.class))
+		}
+		train := base.NewInstancesViewFromVisible(data, []int{0, 1, 2}, data.AllAttributes())
+		query := base.NewInstancesViewFromVisible(data, []int{3}, data.AllAttributes())
+
+		Convey("Uniform voting should pick the majority", func() {
+			knn := NewKnnClassifier("euclidean", 3)
+			knn.Fit(train)
+			So(base.GetClass(knn.Predict(query), 0), ShouldEqual, "far")
+		})
+
+		Convey("Inverse distance voting should pick the closest", func() {
+			knn := NewKnnClassifier("euclidean", 3)
+			knn.Weights = "distance"
+			knn.Fit(train)
+			So(base.GetClass(knn.Predict(query), 0), ShouldEqual, "near")
+		})
+
+		Convey("Gaussian voting with a narrow bandwidth should pick the closest", func() {
+			knn := NewKnnClassifier("euclidean", 3)
+			knn.Weights = "gaussian"
+			knn.Bandwidth = 0.5
+			knn.Fit(train)
+			So(base.GetClass(knn.Predict(query), 0), ShouldEqual, "near")
+		})
+	})
+}
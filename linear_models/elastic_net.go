@@ -0,0 +1,235 @@
+package linear_models
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+	"sort"
+)
+
+// ElasticNet is least squares linear regression with a mixture of L1
+// and L2 penalties on the coefficients, minimising
+//
+// \frac{1}{2n} ||y - Xw||^2 + \alpha \rho ||w||_1 + \frac{\alpha (1 - \rho)}{2} ||w||^2
+//
+// where \rho is L1Ratio. With L1Ratio = 1 this is the Lasso, which
+// sets the coefficients of uninformative features to exactly zero.
+// The coefficients are found by cyclic coordinate descent.
+//
+// For more information:
+//
+// J. Friedman, T. Hastie and R. Tibshirani (2010). Regularization paths
+// for generalized linear models via coordinate descent. Journal of
+// Statistical Software, 33(1), pp. 1-22.
+type ElasticNet struct {
+	regularisedModel
+	// Overall strength of the penalty.
+	Alpha float64
+	// Fraction of the penalty which is L1.
+	L1Ratio float64
+	// Maximum number of passes over the features.
+	MaxIterations int
+	// Coordinate descent stops once no coefficient changed by more
+	// than Tolerance times the largest coefficient in a pass.
+	Tolerance float64
+	// Start from the coefficients of the previous Fit (if it had the
+	// same features) rather than from zero.
+	WarmStart bool
+	// Coefficients on the prepared (centred and scaled) data, kept
+	// for warm starts
+	w []float64
+	// Number of passes made by the last Fit
+	iterations int
+}
+
+// PathStep is one point on a regularisation path.
+type PathStep struct {
+	Alpha        float64
+	Coefficients []float64
+	Intercept    float64
+	// Number of coordinate descent passes needed
+	Iterations int
+}
+
+// NewElasticNet returns an ElasticNet with the given penalty, which fits
+// an intercept.
+func NewElasticNet(alpha, l1Ratio float64) *ElasticNet {
+	return &ElasticNet{
+		regularisedModel: regularisedModel{FitIntercept: true},
+		Alpha:            alpha,
+		L1Ratio:          l1Ratio,
+		MaxIterations:    1000,
+		Tolerance:        1e-4,
+	}
+}
+
+// NewLasso returns an ElasticNet with a pure L1 penalty.
+func NewLasso(alpha float64) *ElasticNet {
+	return NewElasticNet(alpha, 1.0)
+}
+
+// String returns a human-readable summary.
+func (e *ElasticNet) String() string {
+	if e.L1Ratio == 1 {
+		return fmt.Sprintf("Lasso(alpha: %g)", e.Alpha)
+	}
+	return fmt.Sprintf("ElasticNet(alpha: %g, l1 ratio: %g)", e.Alpha, e.L1Ratio)
+}
+
+// validate checks the settings.
+func (e *ElasticNet) validate() error {
+	if e.L1Ratio < 0 || e.L1Ratio > 1 {
+		return fmt.Errorf("L1Ratio must be between 0 and 1")
+	}
+	if e.MaxIterations < 1 {
+		return fmt.Errorf("MaxIterations must be positive")
+	}
+	return nil
+}
+
+// Fit estimates the coefficients from X, whose class Attribute must be
+// a FloatAttribute.
+func (e *ElasticNet) Fit(X base.FixedDataGrid) error {
+	if err := e.validate(); err != nil {
+		return err
+	}
+	if e.Alpha < 0 {
+		return fmt.Errorf("Alpha must not be negative")
+	}
+	d, err := e.prepare(X)
+	if err != nil {
+		return err
+	}
+	w := make([]float64, len(d.cols))
+	if e.WarmStart && e.fitted && len(e.w) == len(w) {
+		copy(w, e.w)
+	}
+	e.iterations = e.coordinateDescent(d, e.Alpha, w)
+	e.w = w
+	e.finish(d, w)
+	return nil
+}
+
+// Path fits the model for each of the given penalties in decreasing
+// order, starting each fit from the coefficients of the previous one.
+// If alphas is nil, 100 penalties are spaced evenly on a log scale from
+// the smallest which gives all-zero coefficients down to a thousandth
+// of that. The model itself is left unchanged.
+func (e *ElasticNet) Path(X base.FixedDataGrid, alphas []float64) ([]PathStep, error) {
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	fitted, attrs, cls := e.fitted, e.attrs, e.cls
+	d, err := e.prepare(X)
+	e.fitted, e.attrs, e.cls = fitted, attrs, cls
+	if err != nil {
+		return nil, err
+	}
+
+	if alphas == nil {
+		alphas, err = e.defaultAlphas(d, 100, 1e-3)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		alphas = append([]float64(nil), alphas...)
+		sort.Sort(sort.Reverse(sort.Float64Slice(alphas)))
+	}
+
+	ret := make([]PathStep, len(alphas))
+	w := make([]float64, len(d.cols))
+	for k, alpha := range alphas {
+		if alpha < 0 {
+			return nil, fmt.Errorf("Alpha must not be negative")
+		}
+		iterations := e.coordinateDescent(d, alpha, w)
+		coefficients, intercept := d.unscale(w)
+		ret[k] = PathStep{alpha, coefficients, intercept, iterations}
+	}
+	return ret, nil
+}
+
+// defaultAlphas returns count penalties spaced evenly on a log scale,
+// from the smallest which zeroes every coefficient down to ratio times
+// that.
+func (e *ElasticNet) defaultAlphas(d *design, count int, ratio float64) ([]float64, error) {
+	if e.L1Ratio == 0 {
+		return nil, fmt.Errorf("Penalties must be given when L1Ratio is 0")
+	}
+	largest := 0.0
+	for _, col := range d.cols {
+		largest = math.Max(largest, math.Abs(dot(col, d.y)))
+	}
+	largest /= float64(d.rows) * e.L1Ratio
+	if largest == 0 {
+		return nil, fmt.Errorf("The features don't explain the class at all")
+	}
+	ret := make([]float64, count)
+	for k := range ret {
+		ret[k] = largest * math.Pow(ratio, float64(k)/float64(count-1))
+	}
+	return ret, nil
+}
+
+// coordinateDescent minimises the objective on d for the given penalty,
+// starting from (and updating) w. It returns the number of passes made.
+func (e *ElasticNet) coordinateDescent(d *design, alpha float64, w []float64) int {
+	n := float64(d.rows)
+	l1 := alpha * e.L1Ratio * n
+	l2 := alpha * (1 - e.L1Ratio) * n
+
+	norms := make([]float64, len(d.cols))
+	for j, col := range d.cols {
+		norms[j] = dot(col, col)
+	}
+	residual := append([]float64(nil), d.y...)
+	for j, col := range d.cols {
+		if w[j] != 0 {
+			for i, x := range col {
+				residual[i] -= x * w[j]
+			}
+		}
+	}
+
+	iteration := 0
+	for iteration < e.MaxIterations {
+		iteration++
+		largestChange, largestWeight := 0.0, 0.0
+		for j, col := range d.cols {
+			if norms[j] == 0 {
+				continue
+			}
+			old := w[j]
+			rho := dot(col, residual) + old*norms[j]
+			w[j] = softThreshold(rho, l1) / (norms[j] + l2)
+			if change := w[j] - old; change != 0 {
+				for i, x := range col {
+					residual[i] -= x * change
+				}
+				largestChange = math.Max(largestChange, math.Abs(change))
+			}
+			largestWeight = math.Max(largestWeight, math.Abs(w[j]))
+		}
+		if largestWeight == 0 || largestChange/largestWeight < e.Tolerance {
+			break
+		}
+	}
+	return iteration
+}
+
+// softThreshold shrinks v towards zero by threshold.
+func softThreshold(v, threshold float64) float64 {
+	if v > threshold {
+		return v - threshold
+	}
+	if v < -threshold {
+		return v + threshold
+	}
+	return 0
+}
+
+// GetIterations returns the number of coordinate descent passes made
+// by the last Fit.
+func (e *ElasticNet) GetIterations() int {
+	return e.iterations
+}
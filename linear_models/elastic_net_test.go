@@ -0,0 +1,106 @@
+package linear_models
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestElasticNet(t *testing.T) {
+	Convey("Given the exams dataset", t, func() {
+		exams, err := base.ParseCSVToInstances("../examples/datasets/exams.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewLasso(1.0).Predict(exams)
+			So(err, ShouldEqual, NoTrainingDataError)
+		})
+
+		Convey("The Lasso solution should satisfy the optimality conditions", func() {
+			e := NewLasso(0.5)
+			e.Standardise = true
+			e.Tolerance = 1e-10
+			So(e.Fit(exams), ShouldBeNil)
+			d, err := e.prepare(exams)
+			So(err, ShouldBeNil)
+			residual := append([]float64(nil), d.y...)
+			for j, col := range d.cols {
+				for i, x := range col {
+					residual[i] -= x * e.w[j]
+				}
+			}
+			for j, col := range d.cols {
+				gradient := dot(col, residual) / float64(d.rows)
+				if e.w[j] == 0 {
+					So(math.Abs(gradient), ShouldBeLessThanOrEqualTo, e.Alpha+1e-6)
+				} else {
+					So(gradient, ShouldAlmostEqual, e.Alpha*math.Copysign(1, e.w[j]), 1e-6)
+				}
+			}
+		})
+
+		Convey("A large enough penalty should zero every coefficient", func() {
+			e := NewLasso(1000)
+			So(e.Fit(exams), ShouldBeNil)
+			for _, c := range e.GetCoefficientVector() {
+				So(c, ShouldEqual, 0)
+			}
+		})
+
+		Convey("With no L1 penalty, it should match Ridge", func() {
+			e := NewElasticNet(0.1, 0)
+			e.Tolerance = 1e-12
+			So(e.Fit(exams), ShouldBeNil)
+			_, rows := exams.Size()
+			r := NewRidge(0.1 * float64(rows))
+			So(r.Fit(exams), ShouldBeNil)
+			for j, c := range e.GetCoefficientVector() {
+				So(c, ShouldAlmostEqual, r.GetCoefficientVector()[j], 1e-6)
+			}
+			So(e.GetIntercept(), ShouldAlmostEqual, r.GetIntercept(), 1e-4)
+		})
+
+		Convey("A warm start should need fewer passes", func() {
+			e := NewElasticNet(0.1, 0.5)
+			So(e.Fit(exams), ShouldBeNil)
+			cold := e.GetIterations()
+			e.WarmStart = true
+			So(e.Fit(exams), ShouldBeNil)
+			So(e.GetIterations(), ShouldBeLessThan, cold)
+		})
+
+		Convey("The regularisation path should start with every coefficient at zero", func() {
+			e := NewLasso(1)
+			e.Standardise = true
+			path, err := e.Path(exams, nil)
+			So(err, ShouldBeNil)
+			So(len(path), ShouldEqual, 100)
+			for _, c := range path[0].Coefficients {
+				So(c, ShouldEqual, 0)
+			}
+			for k := 1; k < len(path); k++ {
+				So(path[k].Alpha, ShouldBeLessThan, path[k-1].Alpha)
+			}
+
+			Convey("And end close to least squares", func() {
+				lr := NewLinearRegression()
+				So(lr.Fit(exams), ShouldBeNil)
+				last := path[len(path)-1]
+				predictions, err := lr.Predict(exams)
+				So(err, ShouldBeNil)
+				_, rows := exams.Size()
+				specs := base.ResolveAttributes(exams, base.NonClassFloatAttributes(exams))
+				for i := 0; i < rows; i++ {
+					expected := base.UnpackBytesToFloat(predictions.Get(base.ResolveAttributes(predictions, predictions.AllClassAttributes())[0], i))
+					actual := last.Intercept
+					for j, s := range specs {
+						actual += last.Coefficients[j] * base.UnpackBytesToFloat(exams.Get(s, i))
+					}
+					So(actual, ShouldAlmostEqual, expected, 1)
+				}
+				So(e.fitted, ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -13,7 +13,7 @@ import (
 
 var (
 	NotEnoughDataError  = errors.New("not enough rows to support this many variables.")
-	NoTrainingDataError = errors.New("you need to Fit() before you can Predict()")
+	NoTrainingDataError = base.NoTrainingDataError
 )
 
 type LinearRegression struct {
@@ -22,6 +22,11 @@ type LinearRegression struct {
 	regressionCoefficients []float64
 	attrs                  []base.Attribute
 	cls                    base.Attribute
+	// Observed values and residuals of the training data
+	observed  []float64
+	residuals []float64
+	// Diagonal of (X'X)^-1, where X includes a column of ones
+	unscaledVariances []float64
 }
 
 func init() {
@@ -107,9 +112,36 @@ func (lr *LinearRegression) Fit(inst base.FixedDataGrid) error {
 	lr.fitted = true
 	lr.attrs = attrs
 	lr.cls = classAttrs[0]
+
+	// Keep what's needed for Summary()
+	lr.observed = make([]float64, rows)
+	lr.residuals = make([]float64, rows)
+	for i := 0; i < rows; i++ {
+		lr.observed[i] = observed.At(i, 0)
+		lr.residuals[i] = lr.observed[i]
+		for j := 0; j < n; j++ {
+			lr.residuals[i] -= explVariables.At(i, j) * regressionCoefficients[j]
+		}
+	}
+	lr.unscaledVariances = unscaledVariances(reg, n)
 	return nil
 }
 
+// GetCoefficients returns the coefficient of each FloatAttribute used
+// in the fit.
+func (lr *LinearRegression) GetCoefficients() map[base.Attribute]float64 {
+	ret := make(map[base.Attribute]float64)
+	for j, a := range lr.attrs {
+		ret[a] = lr.regressionCoefficients[j]
+	}
+	return ret
+}
+
+// GetIntercept returns the fitted intercept.
+func (lr *LinearRegression) GetIntercept() float64 {
+	return lr.disturbance
+}
+
 func (lr *LinearRegression) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
 	if !lr.fitted {
 		return nil, NoTrainingDataError
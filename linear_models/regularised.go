@@ -0,0 +1,183 @@
+package linear_models
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// regularisedModel holds the parts shared by the penalised least
+// squares models (Ridge and ElasticNet): reading the training data,
+// centring and scaling it, and mapping the fitted coefficients back to
+// the original features.
+type regularisedModel struct {
+	// Fit an (unpenalised) intercept by centring the data first.
+	FitIntercept bool
+	// Scale each feature to unit variance before fitting, so that the
+	// penalty treats them equally. The coefficients are still reported
+	// on the original scale.
+	Standardise bool
+
+	attrs []base.Attribute
+	cls   base.Attribute
+	// Coefficients on the original scale
+	coefficients []float64
+	intercept    float64
+	fitted       bool
+}
+
+// design is training data ready for fitting. The features are stored
+// by column, so cols[j][i] is feature j of row i.
+type design struct {
+	cols   [][]float64
+	y      []float64
+	xMean  []float64
+	xScale []float64
+	yMean  float64
+	rows   int
+}
+
+// prepare reads the non-class FloatAttributes and the (Float) class of
+// X, centring and scaling them as configured.
+func (m *regularisedModel) prepare(X base.FixedDataGrid) (*design, error) {
+	classAttrs := X.AllClassAttributes()
+	if len(classAttrs) != 1 {
+		return nil, fmt.Errorf("Only 1 class variable is permitted")
+	}
+	if _, ok := classAttrs[0].(*base.FloatAttribute); !ok {
+		return nil, fmt.Errorf("%s: class Attribute must be a FloatAttribute", classAttrs[0])
+	}
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return nil, fmt.Errorf("No FloatAttributes to fit")
+	}
+	_, rows := X.Size()
+	if rows == 0 {
+		return nil, NoTrainingDataError
+	}
+
+	d := &design{
+		cols:   make([][]float64, len(attrs)),
+		y:      make([]float64, rows),
+		xMean:  make([]float64, len(attrs)),
+		xScale: make([]float64, len(attrs)),
+		rows:   rows,
+	}
+	for j := range d.cols {
+		d.cols[j] = make([]float64, rows)
+	}
+	X.MapOverRows(base.ResolveAttributes(X, attrs), func(row [][]byte, i int) (bool, error) {
+		for j, v := range row {
+			d.cols[j][i] = base.UnpackBytesToFloat(v)
+		}
+		return true, nil
+	})
+	X.MapOverRows(base.ResolveAttributes(X, classAttrs), func(row [][]byte, i int) (bool, error) {
+		d.y[i] = base.UnpackBytesToFloat(row[0])
+		return true, nil
+	})
+
+	for j, col := range d.cols {
+		mean := 0.0
+		for _, v := range col {
+			mean += v
+		}
+		mean /= float64(rows)
+		d.xScale[j] = 1.0
+		if m.Standardise {
+			variance := 0.0
+			for _, v := range col {
+				variance += (v - mean) * (v - mean)
+			}
+			if sd := math.Sqrt(variance / float64(rows)); sd > 0 {
+				d.xScale[j] = sd
+			}
+		}
+		if m.FitIntercept {
+			d.xMean[j] = mean
+		}
+		for i := range col {
+			col[i] = (col[i] - d.xMean[j]) / d.xScale[j]
+		}
+	}
+	if m.FitIntercept {
+		for _, v := range d.y {
+			d.yMean += v
+		}
+		d.yMean /= float64(rows)
+		for i := range d.y {
+			d.y[i] -= d.yMean
+		}
+	}
+
+	m.attrs = attrs
+	m.cls = classAttrs[0]
+	return d, nil
+}
+
+// unscale converts coefficients fitted on the prepared data back to the
+// original scale, returning them along with the intercept.
+func (d *design) unscale(w []float64) ([]float64, float64) {
+	coefficients := make([]float64, len(w))
+	intercept := d.yMean
+	for j, v := range w {
+		coefficients[j] = v / d.xScale[j]
+		intercept -= d.xMean[j] * coefficients[j]
+	}
+	return coefficients, intercept
+}
+
+// finish records the coefficients fitted on d.
+func (m *regularisedModel) finish(d *design, w []float64) {
+	m.coefficients, m.intercept = d.unscale(w)
+	m.fitted = true
+}
+
+// Predict returns the predicted value of each row of X.
+func (m *regularisedModel) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !m.fitted {
+		return nil, NoTrainingDataError
+	}
+	ret := base.GeneratePredictionVector(X)
+	attrSpecs := base.ResolveAttributes(X, m.attrs)
+	clsSpec, err := ret.GetAttribute(m.cls)
+	if err != nil {
+		return nil, err
+	}
+	X.MapOverRows(attrSpecs, func(row [][]byte, i int) (bool, error) {
+		prediction := m.intercept
+		for j, r := range row {
+			prediction += base.UnpackBytesToFloat(r) * m.coefficients[j]
+		}
+		ret.Set(clsSpec, i, base.PackFloatToBytes(prediction))
+		return true, nil
+	})
+	return ret, nil
+}
+
+// GetAttributes returns the features, in the order used by
+// GetCoefficientVector.
+func (m *regularisedModel) GetAttributes() []base.Attribute {
+	return m.attrs
+}
+
+// GetCoefficients returns the coefficient of each feature.
+func (m *regularisedModel) GetCoefficients() map[base.Attribute]float64 {
+	ret := make(map[base.Attribute]float64)
+	for j, a := range m.attrs {
+		ret[a] = m.coefficients[j]
+	}
+	return ret
+}
+
+// GetCoefficientVector returns the coefficients in the order of
+// GetAttributes.
+func (m *regularisedModel) GetCoefficientVector() []float64 {
+	return m.coefficients
+}
+
+// GetIntercept returns the intercept, which is zero unless
+// FitIntercept is set.
+func (m *regularisedModel) GetIntercept() float64 {
+	return m.intercept
+}
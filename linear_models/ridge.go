@@ -0,0 +1,248 @@
+package linear_models
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// RidgeSolver selects how Ridge solves its normal equations.
+type RidgeSolver int
+
+const (
+	// ClosedFormSolver factorises the (regularised) Gram matrix
+	// directly. It uses the smaller of X'X and XX', so it copes with
+	// more features than rows.
+	ClosedFormSolver RidgeSolver = iota
+	// ConjugateGradientSolver iterates towards the solution without
+	// forming the Gram matrix, which suits many features.
+	ConjugateGradientSolver
+)
+
+// String returns a human-readable name for the RidgeSolver.
+func (s RidgeSolver) String() string {
+	switch s {
+	case ClosedFormSolver:
+		return "closed form"
+	case ConjugateGradientSolver:
+		return "conjugate gradient"
+	}
+	return fmt.Sprintf("RidgeSolver(%d)", int(s))
+}
+
+// Ridge is least squares linear regression with an L2 penalty on the
+// coefficients, minimising
+//
+// ||y - Xw||^2 + \alpha ||w||^2
+//
+// Unlike LinearRegression, it works when there are fewer rows than
+// features provided Alpha is positive.
+type Ridge struct {
+	regularisedModel
+	// Strength of the penalty.
+	Alpha float64
+	// How to solve for the coefficients.
+	Solver RidgeSolver
+	// Maximum number of iterations of the conjugate gradient solver.
+	MaxIterations int
+	// The conjugate gradient solver stops once the norm of the
+	// residual falls below Tolerance times the norm of X'y.
+	Tolerance float64
+}
+
+// NewRidge returns a Ridge model with the given penalty, which fits an
+// intercept using the closed form solver.
+func NewRidge(alpha float64) *Ridge {
+	return &Ridge{
+		regularisedModel: regularisedModel{FitIntercept: true},
+		Alpha:            alpha,
+		Solver:           ClosedFormSolver,
+		MaxIterations:    1000,
+		Tolerance:        1e-10,
+	}
+}
+
+// String returns a human-readable summary.
+func (r *Ridge) String() string {
+	return fmt.Sprintf("Ridge(alpha: %g, solver: %s)", r.Alpha, r.Solver)
+}
+
+// Fit estimates the coefficients from X, whose class Attribute must be
+// a FloatAttribute.
+func (r *Ridge) Fit(X base.FixedDataGrid) error {
+	if r.Alpha < 0 {
+		return fmt.Errorf("Alpha must not be negative")
+	}
+	d, err := r.prepare(X)
+	if err != nil {
+		return err
+	}
+	var w []float64
+	switch r.Solver {
+	case ClosedFormSolver:
+		w, err = r.solveClosedForm(d)
+	case ConjugateGradientSolver:
+		w, err = r.solveConjugateGradient(d)
+	default:
+		err = fmt.Errorf("Unsupported solver: %s", r.Solver)
+	}
+	if err != nil {
+		return err
+	}
+	r.finish(d, w)
+	return nil
+}
+
+// solveClosedForm solves (X'X + aI)w = X'y, or equivalently
+// w = X'(XX' + aI)^-1 y when there are more features than rows.
+func (r *Ridge) solveClosedForm(d *design) ([]float64, error) {
+	features := len(d.cols)
+	if features <= d.rows {
+		gram := make([][]float64, features)
+		rhs := make([]float64, features)
+		for j := range gram {
+			gram[j] = make([]float64, features)
+			for k := 0; k <= j; k++ {
+				gram[j][k] = dot(d.cols[j], d.cols[k])
+				gram[k][j] = gram[j][k]
+			}
+			gram[j][j] += r.Alpha
+			rhs[j] = dot(d.cols[j], d.y)
+		}
+		return solveSymmetric(gram, rhs)
+	}
+
+	gram := make([][]float64, d.rows)
+	for i := range gram {
+		gram[i] = make([]float64, d.rows)
+	}
+	for _, col := range d.cols {
+		for i := range gram {
+			for k := 0; k <= i; k++ {
+				gram[i][k] += col[i] * col[k]
+			}
+		}
+	}
+	for i := range gram {
+		for k := 0; k < i; k++ {
+			gram[k][i] = gram[i][k]
+		}
+		gram[i][i] += r.Alpha
+	}
+	dual, err := solveSymmetric(gram, d.y)
+	if err != nil {
+		return nil, err
+	}
+	w := make([]float64, features)
+	for j, col := range d.cols {
+		w[j] = dot(col, dual)
+	}
+	return w, nil
+}
+
+// solveConjugateGradient solves (X'X + aI)w = X'y by conjugate
+// gradients, only ever multiplying by X and X'.
+func (r *Ridge) solveConjugateGradient(d *design) ([]float64, error) {
+	features := len(d.cols)
+	// apply returns (X'X + aI)v
+	apply := func(v []float64) []float64 {
+		xv := make([]float64, d.rows)
+		for j, col := range d.cols {
+			for i, x := range col {
+				xv[i] += x * v[j]
+			}
+		}
+		ret := make([]float64, features)
+		for j, col := range d.cols {
+			ret[j] = dot(col, xv) + r.Alpha*v[j]
+		}
+		return ret
+	}
+
+	w := make([]float64, features)
+	residual := make([]float64, features)
+	for j, col := range d.cols {
+		residual[j] = dot(col, d.y)
+	}
+	target := r.Tolerance * math.Sqrt(dot(residual, residual))
+	direction := append([]float64(nil), residual...)
+	rr := dot(residual, residual)
+	for iteration := 0; iteration < r.MaxIterations; iteration++ {
+		if math.Sqrt(rr) <= target {
+			return w, nil
+		}
+		ad := apply(direction)
+		curvature := dot(direction, ad)
+		if curvature <= 0 {
+			return nil, fmt.Errorf("The system is singular; try a positive Alpha")
+		}
+		step := rr / curvature
+		for j := range w {
+			w[j] += step * direction[j]
+			residual[j] -= step * ad[j]
+		}
+		next := dot(residual, residual)
+		for j := range direction {
+			direction[j] = residual[j] + next/rr*direction[j]
+		}
+		rr = next
+	}
+	if math.Sqrt(rr) > target {
+		return nil, fmt.Errorf("The conjugate gradient solver didn't converge in %d iterations", r.MaxIterations)
+	}
+	return w, nil
+}
+
+// dot returns the dot product of a and b.
+func dot(a, b []float64) float64 {
+	ret := 0.0
+	for i, v := range a {
+		ret += v * b[i]
+	}
+	return ret
+}
+
+// solveSymmetric solves a x = b for a symmetric positive definite a
+// using its Cholesky decomposition. a is overwritten.
+func solveSymmetric(a [][]float64, b []float64) ([]float64, error) {
+	n := len(a)
+	largest := 0.0
+	for i := range a {
+		largest = math.Max(largest, math.Abs(a[i][i]))
+	}
+	// Replace the lower triangle of a with L, where a = LL'
+	for j := 0; j < n; j++ {
+		diag := a[j][j]
+		for k := 0; k < j; k++ {
+			diag -= a[j][k] * a[j][k]
+		}
+		if diag <= 1e-12*largest {
+			return nil, fmt.Errorf("The system is singular; try a positive Alpha")
+		}
+		a[j][j] = math.Sqrt(diag)
+		for i := j + 1; i < n; i++ {
+			v := a[i][j]
+			for k := 0; k < j; k++ {
+				v -= a[i][k] * a[j][k]
+			}
+			a[i][j] = v / a[j][j]
+		}
+	}
+	// Forward substitution (Ly = b), then back substitution (L'x = y)
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		v := b[i]
+		for k := 0; k < i; k++ {
+			v -= a[i][k] * x[k]
+		}
+		x[i] = v / a[i][i]
+	}
+	for i := n - 1; i >= 0; i-- {
+		v := x[i]
+		for k := i + 1; k < n; k++ {
+			v -= a[k][i] * x[k]
+		}
+		x[i] = v / a[i][i]
+	}
+	return x, nil
+}
@@ -0,0 +1,108 @@
+package linear_models
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestRidge(t *testing.T) {
+	Convey("Given the exams dataset", t, func() {
+		exams, err := base.ParseCSVToInstances("../examples/datasets/exams.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewRidge(1.0).Predict(exams)
+			So(err, ShouldEqual, NoTrainingDataError)
+		})
+
+		Convey("Fitting no rows should return a NoTrainingDataError", func() {
+			empty := base.NewInstancesViewFromVisible(exams, []int{}, exams.AllAttributes())
+			So(NewRidge(1.0).Fit(empty), ShouldEqual, NoTrainingDataError)
+		})
+
+		Convey("With no penalty, it should match LinearRegression", func() {
+			lr := NewLinearRegression()
+			So(lr.Fit(exams), ShouldBeNil)
+			r := NewRidge(0)
+			So(r.Fit(exams), ShouldBeNil)
+			expected := lr.GetCoefficients()
+			for a, c := range r.GetCoefficients() {
+				So(c, ShouldAlmostEqual, expected[a], 1e-6)
+			}
+			So(r.GetIntercept(), ShouldAlmostEqual, lr.disturbance, 1e-6)
+
+			Convey("Whether or not the features are standardised", func() {
+				s := NewRidge(0)
+				s.Standardise = true
+				So(s.Fit(exams), ShouldBeNil)
+				for a, c := range s.GetCoefficients() {
+					So(c, ShouldAlmostEqual, expected[a], 1e-6)
+				}
+				So(s.GetIntercept(), ShouldAlmostEqual, lr.disturbance, 1e-6)
+			})
+		})
+
+		Convey("The conjugate gradient solver should match the closed form", func() {
+			r := NewRidge(10)
+			So(r.Fit(exams), ShouldBeNil)
+			cg := NewRidge(10)
+			cg.Solver = ConjugateGradientSolver
+			So(cg.Fit(exams), ShouldBeNil)
+			for j, c := range cg.GetCoefficientVector() {
+				So(c, ShouldAlmostEqual, r.GetCoefficientVector()[j], 1e-6)
+			}
+			So(cg.GetIntercept(), ShouldAlmostEqual, r.GetIntercept(), 1e-6)
+
+			Convey("And the coefficients should be keyed by Attribute", func() {
+				coefficients := cg.GetCoefficients()
+				So(len(coefficients), ShouldEqual, 3)
+				for j, a := range cg.GetAttributes() {
+					So(coefficients[a], ShouldEqual, cg.GetCoefficientVector()[j])
+				}
+			})
+		})
+
+		Convey("A larger penalty should shrink the coefficients", func() {
+			small := NewRidge(1)
+			So(small.Fit(exams), ShouldBeNil)
+			large := NewRidge(1000)
+			So(large.Fit(exams), ShouldBeNil)
+			So(dot(large.GetCoefficientVector(), large.GetCoefficientVector()), ShouldBeLessThan, dot(small.GetCoefficientVector(), small.GetCoefficientVector()))
+		})
+	})
+
+	Convey("Given fewer rows than features", t, func() {
+		exams, err := base.ParseCSVToInstances("../examples/datasets/exams.csv", true)
+		So(err, ShouldBeNil)
+		rows := []int{0, 1, 2}
+		few := base.NewInstancesViewFromVisible(exams, rows, exams.AllAttributes())
+
+		Convey("Both solvers should still fit and agree", func() {
+			r := NewRidge(1)
+			r.FitIntercept = false
+			So(r.Fit(few), ShouldBeNil)
+			cg := NewRidge(1)
+			cg.FitIntercept = false
+			cg.Solver = ConjugateGradientSolver
+			So(cg.Fit(few), ShouldBeNil)
+			for j, c := range cg.GetCoefficientVector() {
+				So(c, ShouldAlmostEqual, r.GetCoefficientVector()[j], 1e-6)
+			}
+
+			predictions, err := r.Predict(few)
+			So(err, ShouldBeNil)
+			_, n := few.Size()
+			for i := 0; i < n; i++ {
+				actual := base.UnpackBytesToFloat(predictions.Get(base.ResolveAttributes(predictions, predictions.AllClassAttributes())[0], i))
+				expected := base.UnpackBytesToFloat(few.Get(base.ResolveAttributes(few, few.AllClassAttributes())[0], i))
+				So(actual, ShouldAlmostEqual, expected, 1)
+			}
+		})
+
+		Convey("Without a penalty the system should be singular", func() {
+			r := NewRidge(0)
+			So(r.Fit(few), ShouldNotBeNil)
+		})
+	})
+}
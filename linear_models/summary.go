@@ -0,0 +1,254 @@
+package linear_models
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// CoefficientSummary describes the estimate of a single coefficient.
+type CoefficientSummary struct {
+	Estimate      float64
+	StandardError float64
+	// Estimate divided by its standard error
+	TStatistic float64
+	// Two-sided p-value of the hypothesis that the coefficient is zero
+	PValue float64
+	// Confidence interval, at the level given to Summary
+	Lower float64
+	Upper float64
+}
+
+// RegressionSummary describes a LinearRegression fit, assuming the
+// errors are independent and normally distributed with a constant
+// variance.
+type RegressionSummary struct {
+	// Features, in the order they were fitted
+	Attributes   []base.Attribute
+	Coefficients map[base.Attribute]CoefficientSummary
+	Intercept    CoefficientSummary
+	// Confidence level of the coefficient intervals
+	ConfidenceLevel float64
+	// Observed minus fitted value of each training row
+	Residuals []float64
+	// Estimate of the standard deviation of the errors
+	ResidualStandardError float64
+	// Residual degrees of freedom (rows minus coefficients)
+	DegreesOfFreedom int
+	// Fraction of the variance explained by the model
+	RSquared         float64
+	AdjustedRSquared float64
+	// Test of the hypothesis that every coefficient (bar the
+	// intercept) is zero
+	FStatistic float64
+	FPValue    float64
+}
+
+// Summary returns the standard errors, t-statistics, p-values and
+// confidence intervals (at the given level, e.g. 0.95) of the fitted
+// coefficients, along with R², adjusted R² and the F-statistic.
+func (lr *LinearRegression) Summary(confidenceLevel float64) (*RegressionSummary, error) {
+	if !lr.fitted {
+		return nil, NoTrainingDataError
+	}
+	if confidenceLevel <= 0 || confidenceLevel >= 1 {
+		return nil, fmt.Errorf("Confidence level must be between 0 and 1")
+	}
+	n := len(lr.observed)
+	p := len(lr.regressionCoefficients) + 1
+	df := n - p
+	if df < 1 {
+		return nil, fmt.Errorf("Need more rows than coefficients to estimate the error variance")
+	}
+
+	mean := 0.0
+	for _, y := range lr.observed {
+		mean += y
+	}
+	mean /= float64(n)
+	rss, tss := 0.0, 0.0
+	for i, y := range lr.observed {
+		rss += lr.residuals[i] * lr.residuals[i]
+		tss += (y - mean) * (y - mean)
+	}
+	variance := rss / float64(df)
+
+	ret := &RegressionSummary{
+		Attributes:            lr.attrs,
+		Coefficients:          make(map[base.Attribute]CoefficientSummary),
+		ConfidenceLevel:       confidenceLevel,
+		Residuals:             append([]float64(nil), lr.residuals...),
+		ResidualStandardError: math.Sqrt(variance),
+		DegreesOfFreedom:      df,
+	}
+	critical := studentTQuantile(1-(1-confidenceLevel)/2, float64(df))
+	describe := func(estimate float64, j int) CoefficientSummary {
+		se := math.Sqrt(variance * lr.unscaledVariances[j])
+		t := estimate / se
+		return CoefficientSummary{
+			Estimate:      estimate,
+			StandardError: se,
+			TStatistic:    t,
+			PValue:        studentTTwoSidedPValue(t, float64(df)),
+			Lower:         estimate - critical*se,
+			Upper:         estimate + critical*se,
+		}
+	}
+	ret.Intercept = describe(lr.disturbance, 0)
+	for j, a := range lr.attrs {
+		ret.Coefficients[a] = describe(lr.regressionCoefficients[j], j+1)
+	}
+
+	if tss > 0 {
+		ret.RSquared = 1 - rss/tss
+		ret.AdjustedRSquared = 1 - (1-ret.RSquared)*float64(n-1)/float64(df)
+	}
+	if p > 1 && rss > 0 {
+		ret.FStatistic = ((tss - rss) / float64(p-1)) / variance
+		ret.FPValue = fUpperTail(ret.FStatistic, float64(p-1), float64(df))
+	}
+	return ret, nil
+}
+
+// String returns a table of the coefficients and the overall fit.
+func (s *RegressionSummary) String() string {
+	var buffer bytes.Buffer
+	lower := fmt.Sprintf("%.1f%%", 50*(1-s.ConfidenceLevel))
+	upper := fmt.Sprintf("%.1f%%", 50*(1+s.ConfidenceLevel))
+	buffer.WriteString(fmt.Sprintf("%-20s %12s %12s %8s %10s %12s %12s\n", "", "Estimate", "Std. Error", "t", "P>|t|", lower, upper))
+	row := func(name string, c CoefficientSummary) {
+		buffer.WriteString(fmt.Sprintf("%-20s %12.6g %12.6g %8.3f %10.4g %12.6g %12.6g\n", name, c.Estimate, c.StandardError, c.TStatistic, c.PValue, c.Lower, c.Upper))
+	}
+	row("(Intercept)", s.Intercept)
+	for _, a := range s.Attributes {
+		row(a.GetName(), s.Coefficients[a])
+	}
+	buffer.WriteString(fmt.Sprintf("\nResidual standard error: %.6g on %d degrees of freedom\n", s.ResidualStandardError, s.DegreesOfFreedom))
+	buffer.WriteString(fmt.Sprintf("R-squared: %.4f, Adjusted R-squared: %.4f\n", s.RSquared, s.AdjustedRSquared))
+	buffer.WriteString(fmt.Sprintf("F-statistic: %.6g on %d and %d DF, p-value: %.4g", s.FStatistic, len(s.Attributes), s.DegreesOfFreedom, s.FPValue))
+	return buffer.String()
+}
+
+// unscaledVariances returns the diagonal of (R'R)^-1 = R^-1 R^-T for
+// the upper triangular n x n matrix r.
+func unscaledVariances(r *mat64.Dense, n int) []float64 {
+	// Invert R column by column with back substitution
+	inverse := make([][]float64, n)
+	for i := range inverse {
+		inverse[i] = make([]float64, n)
+	}
+	for c := 0; c < n; c++ {
+		for i := c; i >= 0; i-- {
+			v := 0.0
+			if i == c {
+				v = 1.0
+			}
+			for j := i + 1; j <= c; j++ {
+				v -= r.At(i, j) * inverse[j][c]
+			}
+			inverse[i][c] = v / r.At(i, i)
+		}
+	}
+	ret := make([]float64, n)
+	for i, row := range inverse {
+		for _, v := range row {
+			ret[i] += v * v
+		}
+	}
+	return ret
+}
+
+// studentTTwoSidedPValue returns P(|T| > |t|) for T following Student's
+// t-distribution with df degrees of freedom.
+func studentTTwoSidedPValue(t, df float64) float64 {
+	if math.IsInf(t, 0) {
+		return 0
+	}
+	return regularisedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// studentTQuantile returns the value below which a fraction q (> 0.5)
+// of Student's t-distribution with df degrees of freedom lies.
+func studentTQuantile(q, df float64) float64 {
+	target := 2 * (1 - q)
+	lo, hi := 0.0, 1.0
+	for studentTTwoSidedPValue(hi, df) > target {
+		hi *= 2
+	}
+	for i := 0; i < 200 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2
+		if studentTTwoSidedPValue(mid, df) > target {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// fUpperTail returns P(F > f) for F following the F-distribution with
+// d1 and d2 degrees of freedom.
+func fUpperTail(f, d1, d2 float64) float64 {
+	if f <= 0 {
+		return 1
+	}
+	return regularisedIncompleteBeta(d2/(d2+d1*f), d2/2, d1/2)
+}
+
+// regularisedIncompleteBeta returns I_x(a, b), evaluated with the
+// continued fraction in Numerical Recipes (Press et al., 2007, 6.4).
+func regularisedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgA, _ := math.Lgamma(a)
+	lgB, _ := math.Lgamma(b)
+	lgAB, _ := math.Lgamma(a + b)
+	front := math.Exp(lgAB - lgA - lgB + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly for x < (a+1)/(a+b+2);
+	// otherwise use the symmetry I_x(a, b) = 1 - I_{1-x}(b, a)
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction for the
+// incomplete beta function with the modified Lentz method.
+func betaContinuedFraction(x, a, b float64) float64 {
+	const tiny = 1e-300
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		for _, numerator := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + numerator*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + numerator/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
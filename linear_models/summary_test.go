@@ -0,0 +1,77 @@
+package linear_models
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLinearRegressionSummary(t *testing.T) {
+	Convey("Given a LinearRegression", t, func() {
+		lr := NewLinearRegression()
+		exams, err := base.ParseCSVToInstances("../examples/datasets/exams.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("Summarising before Fit should return an error", func() {
+			_, err := lr.Summary(0.95)
+			So(err, ShouldEqual, NoTrainingDataError)
+		})
+
+		Convey("Fitted to the exams dataset", func() {
+			So(lr.Fit(exams), ShouldBeNil)
+			summary, err := lr.Summary(0.95)
+			So(err, ShouldBeNil)
+
+			Convey("The coefficients should be keyed by Attribute", func() {
+				coefficients := lr.GetCoefficients()
+				So(len(coefficients), ShouldEqual, 3)
+				for j, a := range summary.Attributes {
+					So(coefficients[a], ShouldEqual, lr.regressionCoefficients[j])
+					So(summary.Coefficients[a].Estimate, ShouldEqual, coefficients[a])
+				}
+				So(lr.GetIntercept(), ShouldAlmostEqual, -4.336102401240243, 1e-6)
+			})
+
+			Convey("The standard errors, t-statistics and p-values should be right", func() {
+				standardErrors := map[string]float64{"EXAM1": 0.12138888777558525, "EXAM2": 0.10084945908176944, "EXAM3": 0.10301405539513114}
+				tStatistics := map[string]float64{"EXAM1": 2.932214185204929, "EXAM2": 5.379491001323335, "EXAM3": 11.332863382615637}
+				pValues := map[string]float64{"EXAM1": 0.007961213699111272, "EXAM2": 2.4603558299912626e-05}
+				for _, a := range summary.Attributes {
+					c := summary.Coefficients[a]
+					So(c.StandardError, ShouldAlmostEqual, standardErrors[a.GetName()], 1e-8)
+					So(c.TStatistic, ShouldAlmostEqual, tStatistics[a.GetName()], 1e-6)
+					if p, ok := pValues[a.GetName()]; ok {
+						So(c.PValue, ShouldAlmostEqual, p, p*1e-4)
+					}
+				}
+				So(summary.Intercept.StandardError, ShouldAlmostEqual, 3.7642260681137483, 1e-6)
+				So(summary.Intercept.PValue, ShouldAlmostEqual, 0.26229818456429727, 1e-6)
+				So(summary.DegreesOfFreedom, ShouldEqual, 21)
+			})
+
+			Convey("The confidence intervals should use the t-distribution", func() {
+				for _, a := range summary.Attributes {
+					c := summary.Coefficients[a]
+					So(c.Upper-c.Estimate, ShouldAlmostEqual, 2.079613846919917*c.StandardError, 1e-6)
+					So(c.Estimate-c.Lower, ShouldAlmostEqual, 2.079613846919917*c.StandardError, 1e-6)
+				}
+			})
+
+			Convey("The overall fit statistics should be right", func() {
+				So(summary.RSquared, ShouldAlmostEqual, 0.9896615789448399, 1e-9)
+				So(summary.AdjustedRSquared, ShouldAlmostEqual, 0.9881846616512456, 1e-9)
+				So(summary.FStatistic, ShouldAlmostEqual, 670.0859846636024, 1e-6)
+				So(summary.FPValue, ShouldBeLessThan, 1e-15)
+				So(len(summary.Residuals), ShouldEqual, 25)
+				So(summary.Residuals[0], ShouldAlmostEqual, -0.6072043917355429, 1e-6)
+				So(summary.String(), ShouldContainSubstring, "EXAM3")
+			})
+		})
+	})
+
+	Convey("The distribution functions should match tabulated values", t, func() {
+		So(studentTTwoSidedPValue(2.0, 10), ShouldAlmostEqual, 0.07338803477074964, 1e-8)
+		So(studentTQuantile(0.975, 10), ShouldAlmostEqual, 2.228139, 1e-6)
+		So(fUpperTail(3.072467, 3, 21), ShouldAlmostEqual, 0.05, 1e-6)
+	})
+}
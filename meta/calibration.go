@@ -0,0 +1,494 @@
+package meta
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// CalibrationMethod selects how CalibratedClassifier maps scores to
+// probabilities.
+type CalibrationMethod int
+
+const (
+	// PlattScaling fits a sigmoid 1 / (1 + exp(As + B)) to the scores.
+	// It suits scores which are distorted in a sigmoid shape (like SVM
+	// margins), and needs little data.
+	PlattScaling CalibrationMethod = iota
+	// IsotonicCalibration fits any non-decreasing function of the
+	// scores. It's more flexible than PlattScaling, but can overfit
+	// with fewer than about a thousand rows.
+	IsotonicCalibration
+)
+
+// String returns a human-readable name for the CalibrationMethod.
+func (m CalibrationMethod) String() string {
+	switch m {
+	case PlattScaling:
+		return "sigmoid"
+	case IsotonicCalibration:
+		return "isotonic"
+	}
+	return fmt.Sprintf("CalibrationMethod(%d)", int(m))
+}
+
+// CalibratedClassifier turns the scores of a wrapped classifier into
+// calibrated probabilities: of the rows given a probability of 0.8 for
+// some class, about 80% should belong to it.
+//
+// Fit divides the training data into Folds folds. For each fold, a
+// classifier is trained on the others and scores the rows it held out;
+// a calibration function is then fitted for each class (one versus the
+// rest) which maps these held-out scores to probabilities. Finally a
+// classifier is trained on all of the data, and its scores are
+// calibrated and normalised to sum to one when predicting. Folds are
+// assigned using math/rand.
+//
+// The score of each class is taken from the wrapped classifier's
+// PredictProba method if it has one. Otherwise, if it has a
+// DecisionFunction with a column per pair of classes named "a vs b"
+// (like svm.SVC), the score of each class is the sum of the decision
+// values in its favour. Otherwise the score is 1 for the predicted
+// class and 0 for the others.
+//
+// For more information:
+//
+// J. Platt (1999). Probabilistic outputs for support vector machines
+// and comparisons to regularized likelihood methods. Advances in Large
+// Margin Classifiers, pp. 61-74.
+//
+// B. Zadrozny and C. Elkan (2002). Transforming classifier scores into
+// accurate multiclass probability estimates. KDD, pp. 694-699.
+type CalibratedClassifier struct {
+	// Returns a classifier ready for training.
+	NewClassifierFunction func() base.Classifier
+	Method                CalibrationMethod
+	// Number of folds used to produce held-out scores (at least 2).
+	Folds int
+
+	classifier  base.Classifier
+	classes     []string
+	calibrators []calibrator
+}
+
+// NewCalibratedClassifier creates a new CalibratedClassifier with the
+// given method and three folds. The first argument must be a function
+// which returns a base.Classifier ready for training.
+func NewCalibratedClassifier(f func() base.Classifier, method CalibrationMethod) *CalibratedClassifier {
+	return &CalibratedClassifier{NewClassifierFunction: f, Method: method, Folds: 3}
+}
+
+// String returns a human-readable summary.
+func (c *CalibratedClassifier) String() string {
+	return fmt.Sprintf("CalibratedClassifier(method: %s, folds: %d)", c.Method, c.Folds)
+}
+
+// calibrator maps the score of one class to a probability.
+type calibrator interface {
+	calibrate(score float64) float64
+}
+
+// Fit trains the wrapped classifier and the calibration functions.
+func (c *CalibratedClassifier) Fit(using base.FixedDataGrid) error {
+	if c.Method != PlattScaling && c.Method != IsotonicCalibration {
+		return fmt.Errorf("Unsupported calibration method: %s", c.Method)
+	}
+	if c.Folds < 2 {
+		return fmt.Errorf("Folds must be at least 2")
+	}
+	classAttr, err := categoricalClassAttribute(using)
+	if err != nil {
+		return err
+	}
+	classes, _ := classRows(using, classAttr)
+	if len(classes) < 2 {
+		return fmt.Errorf("Must have more than one class")
+	}
+	_, rows := using.Size()
+	if rows < c.Folds {
+		return fmt.Errorf("Need at least as many rows as folds")
+	}
+
+	// Score each row with a classifier which didn't see it
+	attrs := using.AllAttributes()
+	order := rand.Perm(rows)
+	scores := make([][]float64, rows)
+	for f := 0; f < c.Folds; f++ {
+		heldOut := make([]int, 0)
+		training := make([]int, 0)
+		for i, row := range order {
+			if i%c.Folds == f {
+				heldOut = append(heldOut, row)
+			} else {
+				training = append(training, row)
+			}
+		}
+		cls := c.NewClassifierFunction()
+		if err := cls.Fit(base.NewInstancesViewFromVisible(using, training, attrs)); err != nil {
+			return err
+		}
+		foldScores, err := classScores(cls, base.NewInstancesViewFromVisible(using, heldOut, attrs), classes)
+		if err != nil {
+			return err
+		}
+		for i, row := range heldOut {
+			scores[row] = foldScores[i]
+		}
+	}
+
+	// Calibrate each class against the rest
+	calibrators := make([]calibrator, len(classes))
+	for k, class := range classes {
+		x := make([]float64, rows)
+		y := make([]bool, rows)
+		for i := range x {
+			x[i] = scores[i][k]
+			y[i] = base.GetClass(using, i) == class
+		}
+		if c.Method == PlattScaling {
+			calibrators[k] = fitPlatt(x, y)
+		} else {
+			calibrators[k] = fitIsotonic(x, y)
+		}
+	}
+
+	cls := c.NewClassifierFunction()
+	if err := cls.Fit(using); err != nil {
+		return err
+	}
+	c.classifier = cls
+	c.classes = classes
+	c.calibrators = calibrators
+	return nil
+}
+
+// classScores returns the score of each class for each row of X,
+// according to cls.
+func classScores(cls base.Classifier, X base.FixedDataGrid, classes []string) ([][]float64, error) {
+	_, rows := X.Size()
+	ret := make([][]float64, rows)
+	for i := range ret {
+		ret[i] = make([]float64, len(classes))
+	}
+	index := make(map[string]int)
+	for k, class := range classes {
+		index[class] = k
+	}
+
+	type probabilistic interface {
+		PredictProba(base.FixedDataGrid) (base.FixedDataGrid, error)
+	}
+	type decisionFunction interface {
+		DecisionFunction(base.FixedDataGrid) (base.FixedDataGrid, error)
+	}
+	if p, ok := cls.(probabilistic); ok {
+		probs, err := p.PredictProba(X)
+		if err != nil {
+			return nil, err
+		}
+		// Classes missing from the training data score 0
+		for _, a := range probs.AllAttributes() {
+			k, ok := index[a.GetName()]
+			if !ok {
+				continue
+			}
+			spec, err := probs.GetAttribute(a)
+			if err != nil {
+				return nil, err
+			}
+			for i := range ret {
+				ret[i][k] = base.UnpackBytesToFloat(probs.Get(spec, i))
+			}
+		}
+		return ret, nil
+	}
+	if d, ok := cls.(decisionFunction); ok {
+		decisions, err := d.DecisionFunction(X)
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range decisions.AllAttributes() {
+			pair := strings.SplitN(attr.GetName(), " vs ", 2)
+			if len(pair) != 2 {
+				return nil, fmt.Errorf("Can't interpret decision function %s", attr.GetName())
+			}
+			a, aOk := index[pair[0]]
+			b, bOk := index[pair[1]]
+			spec, err := decisions.GetAttribute(attr)
+			if err != nil {
+				return nil, err
+			}
+			for i := range ret {
+				v := base.UnpackBytesToFloat(decisions.Get(spec, i))
+				if aOk {
+					ret[i][a] += v
+				}
+				if bOk {
+					ret[i][b] -= v
+				}
+			}
+		}
+		return ret, nil
+	}
+	predictions, err := cls.Predict(X)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ret {
+		if k, ok := index[base.GetClass(predictions, i)]; ok {
+			ret[i][k] = 1
+		}
+	}
+	return ret, nil
+}
+
+// PredictProba returns the calibrated probability of each class, as
+// one FloatAttribute per class value.
+func (c *CalibratedClassifier) PredictProba(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	probs, err := c.probabilities(what)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, len(c.classes))
+	for k, class := range c.classes {
+		specs[k] = ret.AddAttribute(base.NewFloatAttribute(class))
+	}
+	ret.Extend(len(probs))
+	for i, row := range probs {
+		for k, p := range row {
+			ret.Set(specs[k], i, base.PackFloatToBytes(p))
+		}
+	}
+	return ret, nil
+}
+
+// Predict returns the most probable class of each row of what. Ties go
+// to the class which comes first in the CategoricalAttribute.
+func (c *CalibratedClassifier) Predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	probs, err := c.probabilities(what)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.GeneratePredictionVector(what)
+	for i, row := range probs {
+		base.SetClass(ret, i, c.classes[argMax(row)])
+	}
+	return ret, nil
+}
+
+// probabilities returns the normalised, calibrated probability of each
+// class for each row of what.
+func (c *CalibratedClassifier) probabilities(what base.FixedDataGrid) ([][]float64, error) {
+	if c.classifier == nil {
+		return nil, base.NoTrainingDataError
+	}
+	scores, err := classScores(c.classifier, what, c.classes)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range scores {
+		total := 0.0
+		for k, s := range row {
+			row[k] = c.calibrators[k].calibrate(s)
+			total += row[k]
+		}
+		for k := range row {
+			if total > 0 {
+				row[k] /= total
+			} else {
+				row[k] = 1 / float64(len(row))
+			}
+		}
+	}
+	return scores, nil
+}
+
+// GetClasses returns the class values seen during training, in the
+// order of the class Attribute.
+func (c *CalibratedClassifier) GetClasses() []string {
+	return c.classes
+}
+
+// plattCalibrator is the sigmoid 1 / (1 + exp(a s + b)).
+type plattCalibrator struct {
+	a, b float64
+}
+
+func (p *plattCalibrator) calibrate(score float64) float64 {
+	z := p.a*score + p.b
+	if z >= 0 {
+		return math.Exp(-z) / (1 + math.Exp(-z))
+	}
+	return 1 / (1 + math.Exp(z))
+}
+
+// fitPlatt fits a sigmoid to the scores by Newton's method with a
+// backtracking line search, using Platt's smoothed targets to avoid
+// overfitting. This follows the pseudo-code of H.-T. Lin, C.-J. Lin and
+// R. C. Weng (2007). A note on Platt's probabilistic outputs for support
+// vector machines. Machine Learning, 68(3), pp. 267-276.
+func fitPlatt(scores []float64, positive []bool) *plattCalibrator {
+	prior1, prior0 := 0.0, 0.0
+	for _, p := range positive {
+		if p {
+			prior1++
+		} else {
+			prior0++
+		}
+	}
+	hiTarget := (prior1 + 1) / (prior1 + 2)
+	loTarget := 1 / (prior0 + 2)
+	t := make([]float64, len(scores))
+	for i, p := range positive {
+		if p {
+			t[i] = hiTarget
+		} else {
+			t[i] = loTarget
+		}
+	}
+
+	const sigma = 1e-12
+	a, b := 0.0, math.Log((prior0+1)/(prior1+1))
+	objective := func(a, b float64) float64 {
+		ret := 0.0
+		for i, s := range scores {
+			f := s*a + b
+			if f >= 0 {
+				ret += t[i]*f + math.Log(1+math.Exp(-f))
+			} else {
+				ret += (t[i]-1)*f + math.Log(1+math.Exp(f))
+			}
+		}
+		return ret
+	}
+	fval := objective(a, b)
+	for iteration := 0; iteration < 100; iteration++ {
+		// Gradient and Hessian, with a small ridge for stability
+		h11, h22, h21, g1, g2 := sigma, sigma, 0.0, 0.0, 0.0
+		for i, s := range scores {
+			f := s*a + b
+			var p, q float64
+			if f >= 0 {
+				p = math.Exp(-f) / (1 + math.Exp(-f))
+				q = 1 / (1 + math.Exp(-f))
+			} else {
+				p = 1 / (1 + math.Exp(f))
+				q = math.Exp(f) / (1 + math.Exp(f))
+			}
+			d2 := p * q
+			h11 += s * s * d2
+			h22 += d2
+			h21 += s * d2
+			d1 := t[i] - p
+			g1 += s * d1
+			g2 += d1
+		}
+		if math.Abs(g1) < 1e-5 && math.Abs(g2) < 1e-5 {
+			break
+		}
+		det := h11*h22 - h21*h21
+		dA := -(h22*g1 - h21*g2) / det
+		dB := -(-h21*g1 + h11*g2) / det
+		gd := g1*dA + g2*dB
+		step := 1.0
+		for step >= 1e-10 {
+			newA, newB := a+step*dA, b+step*dB
+			newF := objective(newA, newB)
+			if newF < fval+1e-4*step*gd {
+				a, b, fval = newA, newB, newF
+				break
+			}
+			step /= 2
+		}
+		if step < 1e-10 {
+			break
+		}
+	}
+	return &plattCalibrator{a, b}
+}
+
+// isotonicCalibrator is a non-decreasing piecewise linear function
+// through the points (x[i], y[i]), constant beyond its ends.
+type isotonicCalibrator struct {
+	x, y []float64
+}
+
+func (c *isotonicCalibrator) calibrate(score float64) float64 {
+	n := len(c.x)
+	if score <= c.x[0] {
+		return c.y[0]
+	}
+	if score >= c.x[n-1] {
+		return c.y[n-1]
+	}
+	j := sort.SearchFloat64s(c.x, score)
+	if c.x[j] == score {
+		return c.y[j]
+	}
+	frac := (score - c.x[j-1]) / (c.x[j] - c.x[j-1])
+	return c.y[j-1] + frac*(c.y[j]-c.y[j-1])
+}
+
+// byScore sorts row indices by their scores.
+type byScore struct {
+	order  []int
+	scores []float64
+}
+
+func (b byScore) Len() int           { return len(b.order) }
+func (b byScore) Less(i, j int) bool { return b.scores[b.order[i]] < b.scores[b.order[j]] }
+func (b byScore) Swap(i, j int)      { b.order[i], b.order[j] = b.order[j], b.order[i] }
+
+// fitIsotonic fits the non-decreasing function of the scores closest
+// (in squared error) to the labels, using the pool adjacent violators
+// algorithm.
+func fitIsotonic(scores []float64, positive []bool) *isotonicCalibrator {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Sort(byScore{order, scores})
+
+	// Each block has a mean value, a total weight and a range of scores;
+	// rows with the same score start in the same block.
+	type block struct {
+		sum, weight float64
+		lo, hi      float64
+	}
+	blocks := make([]block, 0)
+	for _, i := range order {
+		y := 0.0
+		if positive[i] {
+			y = 1
+		}
+		if n := len(blocks); n > 0 && blocks[n-1].hi == scores[i] {
+			blocks[n-1].sum += y
+			blocks[n-1].weight++
+		} else {
+			blocks = append(blocks, block{y, 1, scores[i], scores[i]})
+		}
+		// Merge with the previous block while they're out of order
+		for n := len(blocks); n > 1 && blocks[n-2].sum/blocks[n-2].weight >= blocks[n-1].sum/blocks[n-1].weight; n = len(blocks) {
+			prev, last := blocks[n-2], blocks[n-1]
+			blocks[n-2] = block{prev.sum + last.sum, prev.weight + last.weight, prev.lo, last.hi}
+			blocks = blocks[:n-1]
+		}
+	}
+
+	// Each block contributes its mean at both ends of its range
+	ret := &isotonicCalibrator{}
+	for _, b := range blocks {
+		mean := b.sum / b.weight
+		ret.x = append(ret.x, b.lo)
+		ret.y = append(ret.y, mean)
+		if b.hi > b.lo {
+			ret.x = append(ret.x, b.hi)
+			ret.y = append(ret.y, mean)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,190 @@
+package meta
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	"github.com/sjwhitworth/golearn/softmax"
+	"github.com/sjwhitworth/golearn/svm"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// calibrationData returns rows with a FloatAttribute x, which belong to
+// the class "pos" with probability 1 / (1 + exp(-4x)) and "neg"
+// otherwise.
+func calibrationData(rows int) *base.DenseInstances {
+	inst := base.NewDenseInstances()
+	xSpec := inst.AddAttribute(base.NewFloatAttribute("x"))
+	class := base.NewCategoricalAttribute()
+	class.SetName("class")
+	class.GetSysValFromString("neg")
+	class.GetSysValFromString("pos")
+	classSpec := inst.AddAttribute(class)
+	inst.AddClassAttribute(class)
+	inst.Extend(rows)
+	for i := 0; i < rows; i++ {
+		x := rand.Float64()*3 - 1.5
+		inst.Set(xSpec, i, base.PackFloatToBytes(x))
+		label := "neg"
+		if rand.Float64() < 1/(1+math.Exp(-4*x)) {
+			label = "pos"
+		}
+		inst.Set(classSpec, i, class.GetSysValFromString(label))
+	}
+	return inst
+}
+
+// overconfident exaggerates the probabilities of a SoftmaxRegression.
+type overconfident struct {
+	*softmax.SoftmaxRegression
+}
+
+func (o overconfident) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	probs, err := o.SoftmaxRegression.PredictProba(X)
+	if err != nil {
+		return nil, err
+	}
+	ret := probs.(*base.DenseInstances)
+	specs := base.ResolveAttributes(ret, ret.AllAttributes())
+	_, rows := ret.Size()
+	for i := 0; i < rows; i++ {
+		total := 0.0
+		values := make([]float64, len(specs))
+		for k, spec := range specs {
+			values[k] = math.Pow(base.UnpackBytesToFloat(ret.Get(spec, i)), 6)
+			total += values[k]
+		}
+		for k, spec := range specs {
+			ret.Set(spec, i, base.PackFloatToBytes(values[k]/total))
+		}
+	}
+	return ret, nil
+}
+
+func newOverconfident() base.Classifier {
+	s, err := softmax.NewSoftmaxRegression("l2", 100.0, 1e-6)
+	if err != nil {
+		panic(err)
+	}
+	return overconfident{s}
+}
+
+// hardOnly hides everything but Predict.
+type hardOnly struct {
+	base.Classifier
+}
+
+func TestCalibratedClassifier(t *testing.T) {
+	Convey("Given an overconfident classifier", t, func() {
+		rand.Seed(42)
+		train := calibrationData(1000)
+		test := calibrationData(1000)
+
+		raw := newOverconfident()
+		So(raw.Fit(train), ShouldBeNil)
+		rawProbs, err := raw.(overconfident).PredictProba(test)
+		So(err, ShouldBeNil)
+		rawCurve, err := evaluation.GetReliabilityCurve(test, rawProbs, "pos", 10)
+		So(err, ShouldBeNil)
+		rawError := evaluation.GetExpectedCalibrationError(rawCurve)
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewCalibratedClassifier(newOverconfident, PlattScaling).Predict(test)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Invalid settings should be rejected", func() {
+			c := NewCalibratedClassifier(newOverconfident, PlattScaling)
+			c.Folds = 1
+			So(c.Fit(train), ShouldNotBeNil)
+			c = NewCalibratedClassifier(newOverconfident, CalibrationMethod(7))
+			So(c.Fit(train), ShouldNotBeNil)
+		})
+
+		for _, method := range []CalibrationMethod{PlattScaling, IsotonicCalibration} {
+			method := method
+			Convey("Calibrating with "+method.String()+" should help", func() {
+				c := NewCalibratedClassifier(newOverconfident, method)
+				So(c.Fit(train), ShouldBeNil)
+				probs, err := c.PredictProba(test)
+				So(err, ShouldBeNil)
+				curve, err := evaluation.GetReliabilityCurve(test, probs, "pos", 10)
+				So(err, ShouldBeNil)
+				So(evaluation.GetExpectedCalibrationError(curve), ShouldBeLessThan, rawError)
+
+				rawBrier, err := evaluation.GetBrierScore(test, rawProbs, "pos")
+				So(err, ShouldBeNil)
+				brier, err := evaluation.GetBrierScore(test, probs, "pos")
+				So(err, ShouldBeNil)
+				So(brier, ShouldBeLessThan, rawBrier)
+
+				predictions, err := c.Predict(test)
+				So(err, ShouldBeNil)
+				cf, err := evaluation.GetConfusionMatrix(test, predictions)
+				So(err, ShouldBeNil)
+				So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.7)
+			})
+		}
+
+		Convey("SVM margins should be calibrated", func() {
+			c := NewCalibratedClassifier(func() base.Classifier {
+				return svm.NewSVC(pairwise.NewLinearKernel())
+			}, PlattScaling)
+			small := calibrationData(200)
+			So(c.Fit(small), ShouldBeNil)
+			probs, err := c.PredictProba(test)
+			So(err, ShouldBeNil)
+			brier, err := evaluation.GetBrierScore(test, probs, "pos")
+			So(err, ShouldBeNil)
+			So(brier, ShouldBeLessThan, 0.2)
+		})
+
+		Convey("Classifiers without scores should still work", func() {
+			c := NewCalibratedClassifier(func() base.Classifier {
+				return hardOnly{newOverconfident()}
+			}, IsotonicCalibration)
+			So(c.Fit(train), ShouldBeNil)
+			probs, err := c.PredictProba(test)
+			So(err, ShouldBeNil)
+			// Only two distinct scores, so only two distinct probabilities
+			curve, err := evaluation.GetReliabilityCurve(test, probs, "pos", 100)
+			So(err, ShouldBeNil)
+			So(len(curve), ShouldEqual, 2)
+		})
+	})
+}
+
+func TestCalibrationFunctions(t *testing.T) {
+	Convey("Isotonic regression should pool adjacent violators", t, func() {
+		c := fitIsotonic([]float64{1, 2, 3, 4, 5}, []bool{false, true, false, true, true})
+		So(c.calibrate(0), ShouldEqual, 0)
+		So(c.calibrate(1), ShouldEqual, 0)
+		So(c.calibrate(2.5), ShouldEqual, 0.5)
+		So(c.calibrate(3.5), ShouldAlmostEqual, 0.75)
+		So(c.calibrate(5), ShouldEqual, 1)
+		So(c.calibrate(10), ShouldEqual, 1)
+	})
+
+	Convey("Tied scores should share a value", t, func() {
+		c := fitIsotonic([]float64{1, 1, 2, 2}, []bool{false, true, true, true})
+		So(c.calibrate(1), ShouldEqual, 0.5)
+		So(c.calibrate(2), ShouldEqual, 1)
+	})
+
+	Convey("Platt scaling should recover a sigmoid", t, func() {
+		rand.Seed(42)
+		scores := make([]float64, 5000)
+		positive := make([]bool, len(scores))
+		for i := range scores {
+			scores[i] = rand.NormFloat64()
+			positive[i] = rand.Float64() < 1/(1+math.Exp(-(2*scores[i]+0.5)))
+		}
+		p := fitPlatt(scores, positive)
+		So(p.a, ShouldAlmostEqual, -2, 0.2)
+		So(p.b, ShouldAlmostEqual, -0.5, 0.15)
+		So(p.calibrate(0), ShouldAlmostEqual, 1/(1+math.Exp(p.b)), 1e-12)
+	})
+}
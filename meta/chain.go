@@ -0,0 +1,259 @@
+package meta
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// ClassifierChain predicts several class Attributes one after another,
+// giving each classifier in the chain the non-class Attributes plus the
+// values of every class Attribute before it. This lets it take
+// relationships between the class Attributes (such as labels which
+// usually appear together) into account, which MultiOutputClassifier
+// can't. During training the earlier classifiers' true values are
+// used, and during prediction their predictions.
+//
+// Earlier class Attributes are presented to later classifiers as
+// FloatAttributes named "<name> (chain)": a FloatAttribute keeps its
+// value, a BinaryAttribute becomes 0 or 1, and a CategoricalAttribute
+// becomes the index of its value (so 0 or 1 for a two-valued label).
+//
+// For more information:
+//
+// J. Read, B. Pfahringer, G. Holmes and E. Frank (2011). Classifier
+// chains for multi-label classification. Machine Learning, 85(3),
+// pp. 333-359.
+type ClassifierChain struct {
+	// Returns a classifier ready to be trained to predict the given
+	// class Attribute.
+	NewClassifierFunction func(base.Attribute) base.Classifier
+	// Order optionally lists the names of the class Attributes in the
+	// order they should be predicted. By default they're predicted in
+	// the order returned by AllAttributes.
+	Order []string
+
+	features []base.Attribute
+	// Class Attributes in chain order
+	labels []base.Attribute
+	// FloatAttribute version of each class Attribute
+	chained     []base.Attribute
+	classifiers []base.Classifier
+}
+
+// NewClassifierChain creates a new ClassifierChain. The argument must
+// be a function which returns a base.Classifier ready for training.
+func NewClassifierChain(f func(base.Attribute) base.Classifier) *ClassifierChain {
+	return &ClassifierChain{NewClassifierFunction: f}
+}
+
+// String returns a human-readable summary.
+func (c *ClassifierChain) String() string {
+	return fmt.Sprintf("ClassifierChain(%d classifiers)", len(c.classifiers))
+}
+
+// orderedClassAttributes returns the class Attributes of X in the order
+// of AllAttributes.
+func orderedClassAttributes(X base.FixedDataGrid) []base.Attribute {
+	classAttrs := X.AllClassAttributes()
+	ret := make([]base.Attribute, 0, len(classAttrs))
+	for _, a := range X.AllAttributes() {
+		for _, b := range classAttrs {
+			if a.Equals(b) {
+				ret = append(ret, a)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// chainOrder returns the class Attributes of X in the order given by
+// names, or the default order if names is nil.
+func chainOrder(X base.FixedDataGrid, names []string) ([]base.Attribute, error) {
+	labels := orderedClassAttributes(X)
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("No class Attributes")
+	}
+	if names == nil {
+		return labels, nil
+	}
+	if len(names) != len(labels) {
+		return nil, fmt.Errorf("Order has %d names, but there are %d class Attributes", len(names), len(labels))
+	}
+	ret := make([]base.Attribute, len(names))
+	used := make(map[int]bool)
+	for i, name := range names {
+		found := false
+		for j, a := range labels {
+			if a.GetName() == name && !used[j] {
+				ret[i] = a
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Order names %s, which isn't a class Attribute (or is repeated)", name)
+		}
+	}
+	return ret, nil
+}
+
+// Fit trains a classifier for each class Attribute of using, in chain
+// order.
+func (c *ClassifierChain) Fit(using base.FixedDataGrid) error {
+	labels, err := chainOrder(using, c.Order)
+	if err != nil {
+		return err
+	}
+	features := base.NonClassAttributes(using)
+	chained := make([]base.Attribute, len(labels))
+	for j, a := range labels {
+		chained[j] = base.NewFloatAttribute(fmt.Sprintf("%s (chain)", a.GetName()))
+	}
+	c.features, c.labels, c.chained = features, labels, chained
+
+	work, err := c.workingCopy(using, true)
+	if err != nil {
+		return err
+	}
+	classifiers := make([]base.Classifier, len(labels))
+	for j, a := range labels {
+		classifiers[j] = c.NewClassifierFunction(a)
+		if err := classifiers[j].Fit(c.stepView(work, j)); err != nil {
+			return err
+		}
+	}
+	c.classifiers = classifiers
+	return nil
+}
+
+// workingCopy returns a copy of the features of from, along with the
+// class Attributes and their chained versions. If withLabels is true,
+// the class Attributes are copied too; otherwise they're left empty to
+// be filled in with predictions.
+func (c *ClassifierChain) workingCopy(from base.FixedDataGrid, withLabels bool) (*base.DenseInstances, error) {
+	if err := requireAttributes(from, c.features); err != nil {
+		return nil, err
+	}
+	ret := base.NewDenseInstances()
+	featureSpecs := make([]base.AttributeSpec, len(c.features))
+	for i, a := range c.features {
+		featureSpecs[i] = ret.AddAttribute(a)
+	}
+	for _, a := range c.chained {
+		ret.AddAttribute(a)
+	}
+	for _, a := range c.labels {
+		ret.AddAttribute(a)
+		ret.AddClassAttribute(a)
+	}
+	_, rows := from.Size()
+	ret.Extend(rows)
+
+	copyRows := func(attrs []base.Attribute, specs []base.AttributeSpec) error {
+		fromSpecs := base.ResolveAttributes(from, attrs)
+		return from.MapOverRows(fromSpecs, func(row [][]byte, i int) (bool, error) {
+			for j, v := range row {
+				ret.Set(specs[j], i, v)
+			}
+			return true, nil
+		})
+	}
+	if err := copyRows(c.features, featureSpecs); err != nil {
+		return nil, err
+	}
+	if withLabels {
+		if err := requireAttributes(from, c.labels); err != nil {
+			return nil, err
+		}
+		for j := range c.labels {
+			if err := c.setLabel(ret, from, j, rows); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ret, nil
+}
+
+// setLabel copies class Attribute j (and its chained version) from one
+// grid into the working copy.
+func (c *ClassifierChain) setLabel(work *base.DenseInstances, from base.FixedDataGrid, j, rows int) error {
+	a := c.labels[j]
+	fromSpec, err := from.GetAttribute(a)
+	if err != nil {
+		return err
+	}
+	labelSpec, err := work.GetAttribute(a)
+	if err != nil {
+		return err
+	}
+	chainedSpec, err := work.GetAttribute(c.chained[j])
+	if err != nil {
+		return err
+	}
+	for i := 0; i < rows; i++ {
+		v := from.Get(fromSpec, i)
+		work.Set(labelSpec, i, v)
+		work.Set(chainedSpec, i, base.PackFloatToBytes(chainValue(a, v)))
+	}
+	return nil
+}
+
+// chainValue converts a value of a class Attribute to a float.
+func chainValue(a base.Attribute, v []byte) float64 {
+	switch a.(type) {
+	case *base.FloatAttribute:
+		return base.UnpackBytesToFloat(v)
+	case *base.BinaryAttribute:
+		if v[0] > 0 {
+			return 1
+		}
+		return 0
+	}
+	return float64(base.UnpackBytesToU64(v))
+}
+
+// stepView returns the view of the working copy seen by classifier j.
+func (c *ClassifierChain) stepView(work base.FixedDataGrid, j int) base.FixedDataGrid {
+	attrs := make([]base.Attribute, 0, len(c.features)+j+1)
+	attrs = append(attrs, c.features...)
+	attrs = append(attrs, c.chained[:j]...)
+	attrs = append(attrs, c.labels[j])
+	return base.NewInstancesViewFromAttrs(work, attrs)
+}
+
+// Predict returns the predicted value of every class Attribute for
+// each row of what, which only needs the non-class Attributes.
+func (c *ClassifierChain) Predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if c.classifiers == nil {
+		return nil, base.NoTrainingDataError
+	}
+	work, err := c.workingCopy(what, false)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := what.Size()
+	for j, cls := range c.classifiers {
+		predictions, err := cls.Predict(c.stepView(work, j))
+		if err != nil {
+			return nil, err
+		}
+		if err := c.setLabel(work, predictions, j, rows); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := base.NewDenseInstances()
+	for _, a := range c.labels {
+		ret.AddAttribute(a)
+		ret.AddClassAttribute(a)
+	}
+	ret.Extend(rows)
+	for _, a := range c.labels {
+		if err := copyColumn(work, ret, a, rows); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
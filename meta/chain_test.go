@@ -0,0 +1,74 @@
+package meta
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+func TestClassifierChain(t *testing.T) {
+	Convey("Given data where one label depends on the others", t, func() {
+		rand.Seed(42)
+		data := multiLabelData(300, false)
+		c := NewClassifierChain(func(a base.Attribute) base.Classifier {
+			return newSoftmax()
+		})
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := c.Predict(data)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("An invalid order should be rejected", func() {
+			c.Order = []string{"a", "b", "d"}
+			So(c.Fit(data), ShouldNotBeNil)
+			c.Order = []string{"a", "a", "b"}
+			So(c.Fit(data), ShouldNotBeNil)
+			c.Order = []string{"a", "b"}
+			So(c.Fit(data), ShouldNotBeNil)
+		})
+
+		Convey("After fitting", func() {
+			So(c.Fit(data), ShouldBeNil)
+			predictions, err := c.Predict(data)
+			So(err, ShouldBeNil)
+			acc := labelAccuracies(data, predictions)
+
+			Convey("Later classifiers should use the earlier labels", func() {
+				m := NewMultiOutputClassifier(func(a base.Attribute) base.Classifier {
+					return newSoftmax()
+				})
+				So(m.Fit(data), ShouldBeNil)
+				independent, err := m.Predict(data)
+				So(err, ShouldBeNil)
+				So(acc["c"], ShouldBeGreaterThan, 0.95)
+				So(acc["c"], ShouldBeGreaterThan, labelAccuracies(data, independent)["c"])
+			})
+
+			Convey("Most rows should be entirely right", func() {
+				subset, err := evaluation.GetSubsetAccuracy(data, predictions)
+				So(err, ShouldBeNil)
+				So(subset, ShouldBeGreaterThan, 0.9)
+			})
+
+			Convey("Only the features should be needed to predict", func() {
+				features := base.NonClassAttributes(data)
+				unlabelled, err := c.Predict(base.NewInstancesViewFromAttrs(data, features))
+				So(err, ShouldBeNil)
+				loss, err := evaluation.GetHammingLoss(predictions, unlabelled)
+				So(err, ShouldBeNil)
+				So(loss, ShouldEqual, 0)
+			})
+		})
+
+		Convey("The labels can be predicted in any order", func() {
+			c.Order = []string{"c", "b", "a"}
+			So(c.Fit(data), ShouldBeNil)
+			predictions, err := c.Predict(data)
+			So(err, ShouldBeNil)
+			So(labelAccuracies(data, predictions)["c"], ShouldBeGreaterThan, 0.8)
+		})
+	})
+}
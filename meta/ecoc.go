@@ -0,0 +1,312 @@
+package meta
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+	"math/rand"
+)
+
+// ECOCModel combines binary classifiers using error-correcting output
+// codes. Each class value is assigned a code word of +1s and -1s, one
+// per classifier, and each classifier is trained to predict its digit
+// of the code. A row is assigned the class whose code word is closest
+// (in Hamming distance) to the digits predicted for it, so with long
+// enough codes a few of the classifiers can be wrong without changing
+// the result. Ties go to the class which comes first in the
+// CategoricalAttribute.
+//
+// The binary classifiers are trained on a CategoricalAttribute class
+// with the values "0" (for -1) and "1" (for +1), named after the
+// original. Only one CategoricalAttribute class variable is supported.
+//
+// For more information:
+//
+// T. G. Dietterich and G. Bakiri (1995). Solving multiclass learning
+// problems via error-correcting output codes. Journal of Artificial
+// Intelligence Research, 2, pp. 263-286.
+type ECOCModel struct {
+	// Returns a classifier ready to be trained on one digit of the
+	// code.
+	NewClassifierFunction func() base.Classifier
+	// If Code is nil, a random code is generated with CodeSize times
+	// as many classifiers as there are classes, using math/rand.
+	CodeSize float64
+	// Code optionally gives the code word of each class which appears
+	// in the training data, in the order of the class Attribute. Every
+	// entry must be +1 or -1.
+	Code [][]int
+
+	classes     []string
+	code        [][]int
+	filters     []*ecocFilter
+	classifiers []base.Classifier
+}
+
+// NewECOCModel creates a new ECOCModel with a random code of the given
+// relative size. The first argument must be a function which returns
+// a base.Classifier ready for training.
+func NewECOCModel(f func() base.Classifier, codeSize float64) *ECOCModel {
+	return &ECOCModel{NewClassifierFunction: f, CodeSize: codeSize}
+}
+
+// String returns a human-readable summary.
+func (m *ECOCModel) String() string {
+	return fmt.Sprintf("ECOCModel(%d classifiers)", len(m.classifiers))
+}
+
+// Fit chooses a code (if one wasn't given) and trains a classifier for
+// each of its columns.
+func (m *ECOCModel) Fit(using base.FixedDataGrid) error {
+	classAttr, err := categoricalClassAttribute(using)
+	if err != nil {
+		return err
+	}
+	classes, _ := classRows(using, classAttr)
+	if len(classes) < 2 {
+		return fmt.Errorf("Must have more than one class")
+	}
+
+	code := m.Code
+	if code == nil {
+		if m.CodeSize <= 0 {
+			return fmt.Errorf("CodeSize must be positive")
+		}
+		columns := int(math.Ceil(m.CodeSize * float64(len(classes))))
+		if code, err = randomCode(len(classes), columns); err != nil {
+			return err
+		}
+	}
+	if err := validateCode(code, len(classes)); err != nil {
+		return err
+	}
+
+	// Map the stored value of each class to its row of the code
+	index := make(map[uint64]int)
+	for k, c := range classes {
+		index[base.UnpackBytesToU64(classAttr.GetSysValFromString(c))] = k
+	}
+
+	filters := make([]*ecocFilter, len(code[0]))
+	classifiers := make([]base.Classifier, len(code[0]))
+	for l := range filters {
+		digits := make(map[uint64]uint64)
+		for v, k := range index {
+			if code[k][l] > 0 {
+				digits[v] = 1
+			} else {
+				digits[v] = 0
+			}
+		}
+		filters[l] = newECOCFilter(using, classAttr, digits)
+		classifiers[l] = m.NewClassifierFunction()
+		if err := classifiers[l].Fit(base.NewLazilyFilteredInstances(using, filters[l])); err != nil {
+			return err
+		}
+	}
+
+	m.classes = classes
+	m.code = code
+	m.filters = filters
+	m.classifiers = classifiers
+	return nil
+}
+
+// randomCode returns a code with the given number of rows (classes)
+// and columns (classifiers). Every column contains both digits, and
+// every row is different, so each classifier has two classes to tell
+// apart and every class can be recovered. Columns are kept distinct
+// where possible.
+func randomCode(rows, columns int) ([][]int, error) {
+	for attempt := 0; attempt < 100; attempt++ {
+		code := make([][]int, rows)
+		for k := range code {
+			code[k] = make([]int, columns)
+		}
+		seen := make(map[string]bool)
+		for l := 0; l < columns; l++ {
+			for try := 0; ; try++ {
+				key := make([]byte, rows)
+				positive, negative := false, false
+				for k := range code {
+					if rand.Intn(2) == 0 {
+						code[k][l] = -1
+						key[k] = '0'
+						negative = true
+					} else {
+						code[k][l] = 1
+						key[k] = '1'
+						positive = true
+					}
+				}
+				if !positive || !negative {
+					continue
+				}
+				if !seen[string(key)] || try >= 10 {
+					seen[string(key)] = true
+					break
+				}
+			}
+		}
+		if validateCode(code, rows) == nil {
+			return code, nil
+		}
+	}
+	return nil, fmt.Errorf("Couldn't generate a code which separates every class: try a larger CodeSize")
+}
+
+// validateCode checks that code has a row for each class and is made
+// up of +1s and -1s, that every column has both, and that no two rows
+// are the same.
+func validateCode(code [][]int, classes int) error {
+	if len(code) != classes {
+		return fmt.Errorf("Code has %d rows, but there are %d classes", len(code), classes)
+	}
+	columns := len(code[0])
+	if columns == 0 {
+		return fmt.Errorf("Code has no columns")
+	}
+	for _, row := range code {
+		if len(row) != columns {
+			return fmt.Errorf("Every row of the code must have the same length")
+		}
+		for _, d := range row {
+			if d != 1 && d != -1 {
+				return fmt.Errorf("Code entries must be +1 or -1, not %d", d)
+			}
+		}
+	}
+	for l := 0; l < columns; l++ {
+		positive, negative := false, false
+		for _, row := range code {
+			positive = positive || row[l] > 0
+			negative = negative || row[l] < 0
+		}
+		if !positive || !negative {
+			return fmt.Errorf("Column %d of the code doesn't separate any classes", l)
+		}
+	}
+	for a := range code {
+		for b := a + 1; b < len(code); b++ {
+			if hammingDistance(code[a], code[b]) == 0 {
+				return fmt.Errorf("Classes %d and %d have the same code word", a, b)
+			}
+		}
+	}
+	return nil
+}
+
+// hammingDistance returns the number of positions at which a and b
+// differ.
+func hammingDistance(a, b []int) int {
+	ret := 0
+	for i := range a {
+		if a[i] != b[i] {
+			ret++
+		}
+	}
+	return ret
+}
+
+// Predict returns the class of each row of what whose code word is
+// closest to the digits predicted for it.
+func (m *ECOCModel) Predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if m.classifiers == nil {
+		return nil, base.NoTrainingDataError
+	}
+	_, rows := what.Size()
+	words := make([][]int, rows)
+	for i := range words {
+		words[i] = make([]int, len(m.classifiers))
+	}
+	for l, c := range m.classifiers {
+		predictions, err := c.Predict(base.NewLazilyFilteredInstances(what, m.filters[l]))
+		if err != nil {
+			return nil, err
+		}
+		for i := range words {
+			switch base.GetClass(predictions, i) {
+			case "0":
+				words[i][l] = -1
+			case "1":
+				words[i][l] = 1
+			default:
+				return nil, fmt.Errorf("Classifier %d predicted %s, rather than 0 or 1", l, base.GetClass(predictions, i))
+			}
+		}
+	}
+
+	ret := base.GeneratePredictionVector(what)
+	closeness := make([]float64, len(m.classes))
+	for i, word := range words {
+		for k := range m.classes {
+			closeness[k] = -float64(hammingDistance(word, m.code[k]))
+		}
+		base.SetClass(ret, i, m.classes[argMax(closeness)])
+	}
+	return ret, nil
+}
+
+// GetClasses returns the class values seen during training, in the
+// order of the class Attribute.
+func (m *ECOCModel) GetClasses() []string {
+	return m.classes
+}
+
+// GetCode returns the code word of each class, in the order of
+// GetClasses.
+func (m *ECOCModel) GetCode() [][]int {
+	return m.code
+}
+
+// ecocFilter replaces the class Attribute with one digit of the code.
+type ecocFilter struct {
+	attrs     map[base.Attribute]base.Attribute
+	classAttr base.Attribute
+	// Digit of the code for each stored class value
+	digits map[uint64]uint64
+}
+
+// newECOCFilter returns a filter which replaces the class Attribute of
+// from with a binary CategoricalAttribute holding each class's digit.
+func newECOCFilter(from base.FixedDataGrid, classAttr base.Attribute, digits map[uint64]uint64) *ecocFilter {
+	binary := base.NewCategoricalAttribute()
+	binary.SetName(classAttr.GetName())
+	binary.GetSysValFromString("0")
+	binary.GetSysValFromString("1")
+	attrs := make(map[base.Attribute]base.Attribute)
+	for _, a := range from.AllAttributes() {
+		attrs[a] = a
+		if a.Equals(classAttr) {
+			attrs[a] = binary
+		}
+	}
+	return &ecocFilter{attrs, classAttr, digits}
+}
+
+func (f *ecocFilter) AddAttribute(a base.Attribute) error {
+	return fmt.Errorf("Not supported")
+}
+
+func (f *ecocFilter) GetAttributesAfterFiltering() []base.FilteredAttribute {
+	ret := make([]base.FilteredAttribute, 0, len(f.attrs))
+	for a := range f.attrs {
+		ret = append(ret, base.FilteredAttribute{Old: a, New: f.attrs[a]})
+	}
+	return ret
+}
+
+func (f *ecocFilter) String() string {
+	return "ecocFilter"
+}
+
+func (f *ecocFilter) Transform(old, to base.Attribute, seq []byte) []byte {
+	if !old.Equals(f.classAttr) {
+		return seq
+	}
+	return base.PackU64ToBytes(f.digits[base.UnpackBytesToU64(seq)])
+}
+
+func (f *ecocFilter) Train() error {
+	return fmt.Errorf("Unsupported")
+}
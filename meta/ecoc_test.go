@@ -0,0 +1,82 @@
+package meta
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	"github.com/sjwhitworth/golearn/svm"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+// Most columns of a random code group the iris classes in ways which
+// aren't linearly separable, so use a kernel SVM.
+func newRBFSVC() base.Classifier {
+	return svm.NewSVC(pairwise.NewRBFKernel(0.5))
+}
+
+func TestECOCModel(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		rand.Seed(42)
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewECOCModel(newRBFSVC, 2).Predict(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Invalid codes should be rejected", func() {
+			m := NewECOCModel(newRBFSVC, 2)
+			m.Code = [][]int{{1, -1}, {-1, 1}}
+			So(m.Fit(inst), ShouldNotBeNil)
+			m.Code = [][]int{{1, -1}, {-1, 1}, {1, -1}}
+			So(m.Fit(inst), ShouldNotBeNil)
+			m.Code = [][]int{{1, 1}, {-1, 1}, {1, 0}}
+			So(m.Fit(inst), ShouldNotBeNil)
+			m.Code = [][]int{{1, 1}, {-1, 1}, {1, 1}}
+			So(m.Fit(inst), ShouldNotBeNil)
+		})
+
+		Convey("With a random code", func() {
+			m := NewECOCModel(newRBFSVC, 2)
+			So(m.Fit(inst), ShouldBeNil)
+
+			Convey("The code should have a distinct word for each class", func() {
+				code := m.GetCode()
+				So(len(code), ShouldEqual, 3)
+				So(len(code[0]), ShouldEqual, 6)
+				So(validateCode(code, 3), ShouldBeNil)
+			})
+
+			Convey("It should fit the training data well", func() {
+				predictions, err := m.Predict(inst)
+				So(err, ShouldBeNil)
+				cf, err := evaluation.GetConfusionMatrix(inst, predictions)
+				So(err, ShouldBeNil)
+				So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.9)
+			})
+		})
+
+		Convey("A one-versus-rest code should work", func() {
+			m := NewECOCModel(newRBFSVC, 0)
+			m.Code = [][]int{{1, -1, -1}, {-1, 1, -1}, {-1, -1, 1}}
+			So(m.Fit(inst), ShouldBeNil)
+			predictions, err := m.Predict(inst)
+			So(err, ShouldBeNil)
+			cf, err := evaluation.GetConfusionMatrix(inst, predictions)
+			So(err, ShouldBeNil)
+			So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.9)
+		})
+	})
+
+	Convey("Random codes should separate every class", t, func() {
+		rand.Seed(42)
+		for classes := 2; classes < 10; classes++ {
+			code, err := randomCode(classes, classes*2)
+			So(err, ShouldBeNil)
+			So(validateCode(code, classes), ShouldBeNil)
+		}
+	})
+}
@@ -8,6 +8,23 @@
 		with a number of selected attributes, and uses
 		that to train an ensemble of models. Predictions
 		are generated via majority voting.
+
+	Multiclass strategies:
+		OneVsAllModel, OneVsOneModel and ECOCModel build
+		a multiclass classifier out of binary ones, by
+		training one per class, one per pair of classes,
+		or one per digit of an error-correcting code.
+
+	Multiple outputs:
+		MultiOutputClassifier and MultiOutputRegressor
+		train a model for each class Attribute, and
+		ClassifierChain trains them in sequence so that
+		later models can use the earlier predictions.
+
+	Calibration:
+		CalibratedClassifier turns a classifier's scores
+		into probabilities using Platt scaling or
+		isotonic regression fitted on held-out folds.
 */
 
 package meta
@@ -0,0 +1,185 @@
+package meta
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// Regressor is implemented by models which are trained on, and predict,
+// a FloatAttribute class (such as linear_models.LinearRegression).
+// Every base.Classifier is also a Regressor.
+type Regressor interface {
+	Fit(base.FixedDataGrid) error
+	Predict(base.FixedDataGrid) (base.FixedDataGrid, error)
+}
+
+// multiOutput trains an independent model for each class Attribute.
+type multiOutput struct {
+	features []base.Attribute
+	labels   []base.Attribute
+	models   []Regressor
+}
+
+// fit trains a model for each class Attribute of using, on a view with
+// only that class Attribute. If float is true, every class Attribute
+// must be a FloatAttribute.
+func (m *multiOutput) fit(using base.FixedDataGrid, newModel func(base.Attribute) Regressor, float bool) error {
+	labels := orderedClassAttributes(using)
+	if len(labels) == 0 {
+		return fmt.Errorf("No class Attributes")
+	}
+	if float {
+		for _, a := range labels {
+			if _, ok := a.(*base.FloatAttribute); !ok {
+				return fmt.Errorf("%s: class Attribute must be a FloatAttribute", a)
+			}
+		}
+	}
+	features := base.NonClassAttributes(using)
+	models := make([]Regressor, len(labels))
+	for j, a := range labels {
+		models[j] = newModel(a)
+		if err := models[j].Fit(labelView(using, features, a)); err != nil {
+			return err
+		}
+	}
+	m.features = features
+	m.labels = labels
+	m.models = models
+	return nil
+}
+
+// labelView returns a view of from with the given features, and label
+// as its only class Attribute.
+func labelView(from base.FixedDataGrid, features []base.Attribute, label base.Attribute) base.FixedDataGrid {
+	attrs := make([]base.Attribute, len(features)+1)
+	copy(attrs, features)
+	attrs[len(features)] = label
+	return base.NewInstancesViewFromAttrs(from, attrs)
+}
+
+// predict returns the prediction of every model, each in the column of
+// its class Attribute. what must have all of the class Attributes.
+func (m *multiOutput) predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if m.models == nil {
+		return nil, base.NoTrainingDataError
+	}
+	if err := requireAttributes(what, m.features); err != nil {
+		return nil, err
+	}
+	if err := requireAttributes(what, m.labels); err != nil {
+		return nil, err
+	}
+	ret := base.GeneratePredictionVector(what)
+	_, rows := what.Size()
+	for j, a := range m.labels {
+		predictions, err := m.models[j].Predict(labelView(what, m.features, a))
+		if err != nil {
+			return nil, err
+		}
+		if err := copyColumn(predictions, ret, a, rows); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// requireAttributes returns an error if X is missing any of attrs.
+func requireAttributes(X base.FixedDataGrid, attrs []base.Attribute) error {
+	for _, a := range attrs {
+		if _, err := X.GetAttribute(a); err != nil {
+			return fmt.Errorf("Attribute %s is missing: %s", a.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// copyColumn copies the values of Attribute a from one grid to the
+// other.
+func copyColumn(from base.FixedDataGrid, to base.UpdatableDataGrid, a base.Attribute, rows int) error {
+	fromSpec, err := from.GetAttribute(a)
+	if err != nil {
+		return fmt.Errorf("Predictions are missing %s: %s", a.GetName(), err)
+	}
+	toSpec, err := to.GetAttribute(a)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < rows; i++ {
+		to.Set(toSpec, i, from.Get(fromSpec, i))
+	}
+	return nil
+}
+
+// MultiOutputClassifier trains a separate classifier for each class
+// Attribute, so that models which only look at one (like most of
+// golearn's) can predict several. Each classifier sees the non-class
+// Attributes and its own class Attribute. The classifiers are
+// independent: use ClassifierChain to take the relationships between
+// the class Attributes into account.
+type MultiOutputClassifier struct {
+	// Returns a classifier ready to be trained to predict the given
+	// class Attribute.
+	NewClassifierFunction func(base.Attribute) base.Classifier
+	multiOutput
+}
+
+// NewMultiOutputClassifier creates a new MultiOutputClassifier. The
+// argument must be a function which returns a base.Classifier ready
+// for training.
+func NewMultiOutputClassifier(f func(base.Attribute) base.Classifier) *MultiOutputClassifier {
+	return &MultiOutputClassifier{NewClassifierFunction: f}
+}
+
+// String returns a human-readable summary.
+func (m *MultiOutputClassifier) String() string {
+	return fmt.Sprintf("MultiOutputClassifier(%d classifiers)", len(m.models))
+}
+
+// Fit trains a classifier for each class Attribute of using.
+func (m *MultiOutputClassifier) Fit(using base.FixedDataGrid) error {
+	return m.fit(using, func(a base.Attribute) Regressor {
+		return m.NewClassifierFunction(a)
+	}, false)
+}
+
+// Predict returns the predicted value of every class Attribute for
+// each row of what, which must have the same class Attributes as the
+// training data.
+func (m *MultiOutputClassifier) Predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return m.predict(what)
+}
+
+// MultiOutputRegressor trains a separate regressor for each class
+// Attribute, which must all be FloatAttributes. Each regressor sees
+// the non-class Attributes and its own class Attribute.
+type MultiOutputRegressor struct {
+	// Returns a regressor ready to be trained to predict the given
+	// class Attribute.
+	NewRegressorFunction func(base.Attribute) Regressor
+	multiOutput
+}
+
+// NewMultiOutputRegressor creates a new MultiOutputRegressor. The
+// argument must be a function which returns a Regressor ready for
+// training.
+func NewMultiOutputRegressor(f func(base.Attribute) Regressor) *MultiOutputRegressor {
+	return &MultiOutputRegressor{NewRegressorFunction: f}
+}
+
+// String returns a human-readable summary.
+func (m *MultiOutputRegressor) String() string {
+	return fmt.Sprintf("MultiOutputRegressor(%d regressors)", len(m.models))
+}
+
+// Fit trains a regressor for each class Attribute of using.
+func (m *MultiOutputRegressor) Fit(using base.FixedDataGrid) error {
+	return m.fit(using, m.NewRegressorFunction, true)
+}
+
+// Predict returns the predicted value of every class Attribute for
+// each row of what, which must have the same class Attributes as the
+// training data.
+func (m *MultiOutputRegressor) Predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return m.predict(what)
+}
@@ -0,0 +1,166 @@
+package meta
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	"github.com/sjwhitworth/golearn/linear_models"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// multiLabelData returns rows with the FloatAttributes x and y, and
+// the class Attributes a (x > 0), b (y > 0) and c (both), along with
+// the FloatAttribute class Attributes p = 2x + 1 and q = 3 - y if
+// regression is true.
+func multiLabelData(rows int, regression bool) *base.DenseInstances {
+	inst := base.NewDenseInstances()
+	features := []base.AttributeSpec{
+		inst.AddAttribute(base.NewFloatAttribute("x")),
+		inst.AddAttribute(base.NewFloatAttribute("y")),
+	}
+	labels := make([]*base.CategoricalAttribute, 3)
+	labelSpecs := make([]base.AttributeSpec, 3)
+	targets := make([]base.AttributeSpec, 0)
+	if regression {
+		for _, name := range []string{"p", "q"} {
+			a := base.NewFloatAttribute(name)
+			targets = append(targets, inst.AddAttribute(a))
+			inst.AddClassAttribute(a)
+		}
+	} else {
+		for j, name := range []string{"a", "b", "c"} {
+			labels[j] = base.NewCategoricalAttribute()
+			labels[j].SetName(name)
+			labels[j].GetSysValFromString("0")
+			labels[j].GetSysValFromString("1")
+			labelSpecs[j] = inst.AddAttribute(labels[j])
+			inst.AddClassAttribute(labels[j])
+		}
+	}
+	inst.Extend(rows)
+
+	// Keep the points away from the axes so a and b are easy
+	sample := func() float64 {
+		for {
+			if v := rand.Float64()*2 - 1; math.Abs(v) > 0.1 {
+				return v
+			}
+		}
+	}
+	for i := 0; i < rows; i++ {
+		x, y := sample(), sample()
+		inst.Set(features[0], i, base.PackFloatToBytes(x))
+		inst.Set(features[1], i, base.PackFloatToBytes(y))
+		if regression {
+			inst.Set(targets[0], i, base.PackFloatToBytes(2*x+1))
+			inst.Set(targets[1], i, base.PackFloatToBytes(3-y))
+			continue
+		}
+		values := []bool{x > 0, y > 0, x > 0 && y > 0}
+		for j, v := range values {
+			s := "0"
+			if v {
+				s = "1"
+			}
+			inst.Set(labelSpecs[j], i, labels[j].GetSysValFromString(s))
+		}
+	}
+	return inst
+}
+
+// labelAccuracies returns the accuracy of each class Attribute.
+func labelAccuracies(ref, gen base.FixedDataGrid) map[string]float64 {
+	matrices, err := evaluation.GetLabelConfusionMatrices(ref, gen)
+	So(err, ShouldBeNil)
+	ret := make(map[string]float64)
+	for name, c := range matrices {
+		ret[name] = evaluation.GetAccuracy(c)
+	}
+	return ret
+}
+
+func TestMultiOutputClassifier(t *testing.T) {
+	Convey("Given data with three labels", t, func() {
+		rand.Seed(42)
+		data := multiLabelData(300, false)
+		labels := make([]string, 0)
+		m := NewMultiOutputClassifier(func(a base.Attribute) base.Classifier {
+			labels = append(labels, a.GetName())
+			return newSoftmax()
+		})
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := m.Predict(data)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("After fitting", func() {
+			So(m.Fit(data), ShouldBeNil)
+
+			Convey("There should be a classifier for each label, in order", func() {
+				So(labels, ShouldResemble, []string{"a", "b", "c"})
+			})
+
+			Convey("Every label should be predicted", func() {
+				predictions, err := m.Predict(data)
+				So(err, ShouldBeNil)
+				So(len(predictions.AllClassAttributes()), ShouldEqual, 3)
+				acc := labelAccuracies(data, predictions)
+				So(acc["a"], ShouldBeGreaterThan, 0.95)
+				So(acc["b"], ShouldBeGreaterThan, 0.95)
+
+				loss, err := evaluation.GetHammingLoss(data, predictions)
+				So(err, ShouldBeNil)
+				So(loss, ShouldBeLessThan, 0.1)
+			})
+
+			Convey("Data without the labels should be rejected", func() {
+				x := data.AllAttributes()[0]
+				_, err := m.Predict(base.NewInstancesViewFromAttrs(data, []base.Attribute{x}))
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestMultiOutputRegressor(t *testing.T) {
+	Convey("Given data with two targets", t, func() {
+		rand.Seed(42)
+		data := multiLabelData(100, true)
+		regressors := make([]*linear_models.Ridge, 0)
+		m := NewMultiOutputRegressor(func(a base.Attribute) Regressor {
+			r := linear_models.NewRidge(0)
+			regressors = append(regressors, r)
+			return r
+		})
+		So(m.Fit(data), ShouldBeNil)
+
+		Convey("Each regressor should only see the features", func() {
+			So(len(regressors), ShouldEqual, 2)
+			for _, r := range regressors {
+				So(len(r.GetCoefficientVector()), ShouldEqual, 2)
+			}
+		})
+
+		Convey("Both targets should be predicted exactly", func() {
+			predictions, err := m.Predict(data)
+			So(err, ShouldBeNil)
+			_, rows := data.Size()
+			for _, a := range data.AllClassAttributes() {
+				refSpec, err := data.GetAttribute(a)
+				So(err, ShouldBeNil)
+				genSpec, err := predictions.GetAttribute(a)
+				So(err, ShouldBeNil)
+				for i := 0; i < rows; i++ {
+					So(base.UnpackBytesToFloat(predictions.Get(genSpec, i)), ShouldAlmostEqual, base.UnpackBytesToFloat(data.Get(refSpec, i)), 1e-6)
+				}
+			}
+		})
+
+		Convey("Categorical targets should be rejected", func() {
+			So(m.Fit(multiLabelData(10, false)), ShouldNotBeNil)
+		})
+	})
+}
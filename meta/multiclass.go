@@ -0,0 +1,52 @@
+package meta
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// categoricalClassAttribute returns the class Attribute of X, which
+// must be the only one and a CategoricalAttribute.
+func categoricalClassAttribute(X base.FixedDataGrid) (*base.CategoricalAttribute, error) {
+	classAttrs := X.AllClassAttributes()
+	if len(classAttrs) != 1 {
+		return nil, fmt.Errorf("Only 1 class Attribute is supported")
+	}
+	c, ok := classAttrs[0].(*base.CategoricalAttribute)
+	if !ok {
+		return nil, fmt.Errorf("%s: class Attribute must be a CategoricalAttribute", classAttrs[0])
+	}
+	return c, nil
+}
+
+// classRows returns the class values which appear in X, in the order
+// of the class Attribute, and the rows belonging to each.
+func classRows(X base.FixedDataGrid, classAttr *base.CategoricalAttribute) ([]string, [][]int) {
+	_, rows := X.Size()
+	byClass := make(map[string][]int)
+	for i := 0; i < rows; i++ {
+		c := base.GetClass(X, i)
+		byClass[c] = append(byClass[c], i)
+	}
+	classes := make([]string, 0)
+	members := make([][]int, 0)
+	for _, c := range classAttr.GetValues() {
+		if r, ok := byClass[c]; ok {
+			classes = append(classes, c)
+			members = append(members, r)
+		}
+	}
+	return classes, members
+}
+
+// argMax returns the index of the largest value, preferring the first
+// of any ties.
+func argMax(values []float64) int {
+	best := 0
+	for i, v := range values {
+		if v > values[best] {
+			best = i
+		}
+	}
+	return best
+}
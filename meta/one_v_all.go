@@ -3,6 +3,7 @@ package meta
 import (
 	"fmt"
 	"github.com/sjwhitworth/golearn/base"
+	"math"
 )
 
 // OneVsAllModel replaces class Attributes with numeric versions
@@ -27,12 +28,14 @@ func NewOneVsAllModel(f func(string) base.Classifier) *OneVsAllModel {
 	}
 }
 
+// String returns a human-readable summary.
+func (m *OneVsAllModel) String() string {
+	return fmt.Sprintf("OneVsAllModel(%d classifiers)", len(m.classifiers))
+}
+
 func (m *OneVsAllModel) generateAttributes(from base.FixedDataGrid) map[base.Attribute]base.Attribute {
 	attrs := from.AllAttributes()
 	classAttrs := from.AllClassAttributes()
-	if len(classAttrs) != 1 {
-		panic("Only 1 class Attribute is supported!")
-	}
 	ret := make(map[base.Attribute]base.Attribute)
 	for _, a := range attrs {
 		ret[a] = a
@@ -49,16 +52,10 @@ func (m *OneVsAllModel) generateAttributes(from base.FixedDataGrid) map[base.Att
 // Fit creates n filtered datasets (where n is the number of values
 // a CategoricalAttribute can take) and uses them to train the
 // underlying classifiers.
-func (m *OneVsAllModel) Fit(using base.FixedDataGrid) {
-	var classAttr *base.CategoricalAttribute
-	// Do some validation
-	classAttrs := using.AllClassAttributes()
-	for _, a := range classAttrs {
-		if c, ok := a.(*base.CategoricalAttribute); !ok {
-			panic("Unsupported ClassAttribute type")
-		} else {
-			classAttr = c
-		}
+func (m *OneVsAllModel) Fit(using base.FixedDataGrid) error {
+	classAttr, err := categoricalClassAttribute(using)
+	if err != nil {
+		return err
 	}
 	attrs := m.generateAttributes(using)
 
@@ -72,7 +69,7 @@ func (m *OneVsAllModel) Fit(using base.FixedDataGrid) {
 		}
 	}
 	if val == 0 {
-		panic("Must have more than one class!")
+		return fmt.Errorf("Must have more than one class")
 	}
 	m.maxClassVal = val
 
@@ -87,19 +84,26 @@ func (m *OneVsAllModel) Fit(using base.FixedDataGrid) {
 		}
 		filters[i] = f
 		classifiers[i] = m.NewClassifierFunction(classVals[int(i)])
-		classifiers[i].Fit(base.NewLazilyFilteredInstances(using, f))
+		if err := classifiers[i].Fit(base.NewLazilyFilteredInstances(using, f)); err != nil {
+			return err
+		}
 	}
 
 	m.filters = filters
 	m.classifiers = classifiers
+	return nil
 }
 
 // Predict issues predictions. Each class-specific classifier is expected
 // to output a value between 0 (indicating that a given instance is not
 // a given class) and 1 (indicating that the given instance is definitely
 // that class). For each instance, the class with the highest value is chosen.
-// The result is undefined if several underlying models output the same value.
+// If several underlying models output the same value, the class which comes
+// first in the CategoricalAttribute is chosen.
 func (m *OneVsAllModel) Predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if m.classifiers == nil {
+		return nil, base.NoTrainingDataError
+	}
 	ret := base.GeneratePredictionVector(what)
 	vecs := make([]base.FixedDataGrid, m.maxClassVal+1)
 	specs := make([]base.AttributeSpec, m.maxClassVal+1)
@@ -117,7 +121,7 @@ func (m *OneVsAllModel) Predict(what base.FixedDataGrid) (base.FixedDataGrid, er
 	spec := base.ResolveAttributes(ret, ret.AllClassAttributes())[0]
 	for i := 0; i < rows; i++ {
 		class := uint64(0)
-		best := 0.0
+		best := math.Inf(-1)
 		for j := uint64(0); j <= m.maxClassVal; j++ {
 			val := base.UnpackBytesToFloat(vecs[j].Get(specs[j], i))
 			if val > best {
@@ -47,3 +47,24 @@ func TestOneVsAllModel(t *testing.T) {
 		})
 	})
 }
+
+func TestOneVsAllModelErrors(t *testing.T) {
+	Convey("Given a model", t, func() {
+		m := NewOneVsAllModel(func(c string) base.Classifier {
+			return newSoftmax()
+		})
+
+		Convey("Predicting before Fit should return an error", func() {
+			inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+			So(err, ShouldBeNil)
+			_, err = m.Predict(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("A FloatAttribute class should be rejected", func() {
+			exams, err := base.ParseCSVToInstances("../examples/datasets/exams.csv", true)
+			So(err, ShouldBeNil)
+			So(m.Fit(exams), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,125 @@
+package meta
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// OneVsOneModel trains a wrapped classifier for every pair of class
+// values, on just the rows belonging to those two classes. Each one
+// votes for one of its pair, and the class with the most votes is
+// chosen. Ties go to the class which comes first in the
+// CategoricalAttribute.
+//
+// With k classes this trains k(k-1)/2 classifiers, but each sees only
+// a fraction of the data, so it often trains faster than OneVsAllModel
+// for classifiers which scale badly with the number of rows (like
+// kernel SVMs). Only one CategoricalAttribute class variable is
+// supported.
+type OneVsOneModel struct {
+	// Returns a classifier ready to be trained to tell apart the
+	// two given class values.
+	NewClassifierFunction func(string, string) base.Classifier
+	classes               []string
+	// Indices into classes of the pair each classifier separates
+	pairs       [][2]int
+	classifiers []base.Classifier
+}
+
+// NewOneVsOneModel creates a new OneVsOneModel. The argument must be
+// a function which returns a base.Classifier ready for training.
+func NewOneVsOneModel(f func(string, string) base.Classifier) *OneVsOneModel {
+	return &OneVsOneModel{NewClassifierFunction: f}
+}
+
+// String returns a human-readable summary.
+func (m *OneVsOneModel) String() string {
+	return fmt.Sprintf("OneVsOneModel(%d classifiers)", len(m.classifiers))
+}
+
+// Fit trains a classifier for each pair of class values which appear
+// in using.
+func (m *OneVsOneModel) Fit(using base.FixedDataGrid) error {
+	classAttr, err := categoricalClassAttribute(using)
+	if err != nil {
+		return err
+	}
+	classes, members := classRows(using, classAttr)
+	if len(classes) < 2 {
+		return fmt.Errorf("Must have more than one class")
+	}
+
+	attrs := using.AllAttributes()
+	pairs := make([][2]int, 0)
+	classifiers := make([]base.Classifier, 0)
+	for i := range classes {
+		for j := i + 1; j < len(classes); j++ {
+			rows := make([]int, 0, len(members[i])+len(members[j]))
+			rows = append(rows, members[i]...)
+			rows = append(rows, members[j]...)
+			c := m.NewClassifierFunction(classes[i], classes[j])
+			if err := c.Fit(base.NewInstancesViewFromVisible(using, rows, attrs)); err != nil {
+				return err
+			}
+			pairs = append(pairs, [2]int{i, j})
+			classifiers = append(classifiers, c)
+		}
+	}
+
+	m.classes = classes
+	m.pairs = pairs
+	m.classifiers = classifiers
+	return nil
+}
+
+// Predict returns the class of each row of what with the most votes.
+// It returns an error if any classifier predicts a class outside its
+// pair.
+func (m *OneVsOneModel) Predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	votes, err := m.Votes(what)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.GeneratePredictionVector(what)
+	for i, v := range votes {
+		base.SetClass(ret, i, m.classes[argMax(v)])
+	}
+	return ret, nil
+}
+
+// Votes returns the number of votes each class received for each row
+// of what, with one column per class in the order of GetClasses.
+func (m *OneVsOneModel) Votes(what base.FixedDataGrid) ([][]float64, error) {
+	if m.classifiers == nil {
+		return nil, base.NoTrainingDataError
+	}
+	_, rows := what.Size()
+	votes := make([][]float64, rows)
+	for i := range votes {
+		votes[i] = make([]float64, len(m.classes))
+	}
+	for p, c := range m.classifiers {
+		predictions, err := c.Predict(what)
+		if err != nil {
+			return nil, err
+		}
+		a, b := m.pairs[p][0], m.pairs[p][1]
+		for i := range votes {
+			switch base.GetClass(predictions, i) {
+			case m.classes[a]:
+				votes[i][a]++
+			case m.classes[b]:
+				votes[i][b]++
+			default:
+				return nil, fmt.Errorf("Classifier for %s and %s predicted %s", m.classes[a], m.classes[b], base.GetClass(predictions, i))
+			}
+		}
+	}
+	return votes, nil
+}
+
+// GetClasses returns the class values seen during training, in the
+// order of the class Attribute.
+func (m *OneVsOneModel) GetClasses() []string {
+	return m.classes
+}
@@ -0,0 +1,70 @@
+package meta
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	"github.com/sjwhitworth/golearn/softmax"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func newSoftmax() base.Classifier {
+	m, err := softmax.NewSoftmaxRegression("l2", 10.0, 1e-4)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestOneVsOneModel(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		pairs := make([][2]string, 0)
+		m := NewOneVsOneModel(func(a, b string) base.Classifier {
+			pairs = append(pairs, [2]string{a, b})
+			return newSoftmax()
+		})
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := m.Predict(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("A FloatAttribute class should be rejected", func() {
+			exams, err := base.ParseCSVToInstances("../examples/datasets/exams.csv", true)
+			So(err, ShouldBeNil)
+			So(m.Fit(exams), ShouldNotBeNil)
+		})
+
+		Convey("After fitting", func() {
+			So(m.Fit(inst), ShouldBeNil)
+
+			Convey("There should be a classifier for each pair", func() {
+				So(len(m.classifiers), ShouldEqual, 3)
+				So(pairs, ShouldResemble, [][2]string{
+					{"Iris-setosa", "Iris-versicolor"},
+					{"Iris-setosa", "Iris-virginica"},
+					{"Iris-versicolor", "Iris-virginica"},
+				})
+			})
+
+			Convey("Each row should get one vote per classifier", func() {
+				votes, err := m.Votes(inst)
+				So(err, ShouldBeNil)
+				for _, v := range votes {
+					So(v[0]+v[1]+v[2], ShouldEqual, 3)
+				}
+			})
+
+			Convey("It should fit the training data well", func() {
+				predictions, err := m.Predict(inst)
+				So(err, ShouldBeNil)
+				cf, err := evaluation.GetConfusionMatrix(inst, predictions)
+				So(err, ShouldBeNil)
+				So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.95)
+			})
+		})
+	})
+}
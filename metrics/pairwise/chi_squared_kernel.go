@@ -0,0 +1,41 @@
+package pairwise
+
+import (
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+type ChiSquaredKernel struct {
+	gamma float64
+}
+
+// NewChiSquaredKernel returns an exponentiated chi-squared kernel,
+// which is often used with histograms. Values should be non-negative.
+func NewChiSquaredKernel(gamma float64) *ChiSquaredKernel {
+	return &ChiSquaredKernel{gamma: gamma}
+}
+
+// InnerProduct computes the inner product through a kernel trick
+// K(x, y) = exp(-gamma * sum_i (x_i - y_i)^2 / (x_i + y_i))
+// Elements where x_i + y_i is zero are skipped.
+func (c *ChiSquaredKernel) InnerProduct(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	r1, c1 := vectorX.Dims()
+	r2, c2 := vectorY.Dims()
+	if r1 != r2 || c1 != c2 {
+		panic(mat64.ErrShape)
+	}
+
+	result := .0
+
+	for i := 0; i < r1; i++ {
+		for j := 0; j < c1; j++ {
+			x, y := vectorX.At(i, j), vectorY.At(i, j)
+			if x+y == 0 {
+				continue
+			}
+			result += (x - y) * (x - y) / (x + y)
+		}
+	}
+	return math.Exp(-c.gamma * result)
+}
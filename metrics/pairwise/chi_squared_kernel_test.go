@@ -0,0 +1,35 @@
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChiSquaredKernel(t *testing.T) {
+	var vectorX, vectorY *mat64.Dense
+	kernel := NewChiSquaredKernel(0.1)
+
+	Convey("Given two vectors", t, func() {
+		vectorX = mat64.NewDense(3, 1, []float64{1, 2, 3})
+		vectorY = mat64.NewDense(3, 1, []float64{2, 4, 5})
+
+		Convey("When doing inner product", func() {
+			result := kernel.InnerProduct(vectorX, vectorY)
+
+			Convey("The result should almost equal 0.8607079764250578", func() {
+				So(result, ShouldAlmostEqual, 0.8607079764250578)
+			})
+		})
+
+		Convey("When both vectors have zeros in the same place", func() {
+			result := kernel.InnerProduct(mat64.NewDense(2, 1, []float64{0, 1}), mat64.NewDense(2, 1, []float64{0, 3}))
+
+			Convey("Those elements should be skipped", func() {
+				So(result, ShouldAlmostEqual, 0.9048374180359595)
+			})
+		})
+
+	})
+}
@@ -0,0 +1,31 @@
+package pairwise
+
+import (
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+type CosineKernel struct{}
+
+// NewCosineKernel returns a cosine similarity kernel
+func NewCosineKernel() *CosineKernel {
+	return &CosineKernel{}
+}
+
+// InnerProduct computes the cosine of the angle between two vectors
+// K(x, y) = x^T y / (||x|| ||y||)
+// If either vector is zero, the result is zero.
+func (c *CosineKernel) InnerProduct(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	norms := math.Sqrt(vectorX.Dot(vectorX) * vectorY.Dot(vectorY))
+	if norms == 0 {
+		return 0
+	}
+
+	return vectorX.Dot(vectorY) / norms
+}
+
+// Distance computes the cosine distance, 1 - K(x, y)
+func (c *CosineKernel) Distance(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	return 1 - c.InnerProduct(vectorX, vectorY)
+}
@@ -0,0 +1,43 @@
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCosineKernel(t *testing.T) {
+	var vectorX, vectorY *mat64.Dense
+	kernel := NewCosineKernel()
+
+	Convey("Given two vectors", t, func() {
+		vectorX = mat64.NewDense(3, 1, []float64{1, 2, 3})
+		vectorY = mat64.NewDense(3, 1, []float64{2, 4, 5})
+
+		Convey("When doing inner product", func() {
+			result := kernel.InnerProduct(vectorX, vectorY)
+
+			Convey("The result should almost equal 0.9960238411119947", func() {
+				So(result, ShouldAlmostEqual, 0.9960238411119947)
+			})
+		})
+
+		Convey("When calculating distance", func() {
+			result := kernel.Distance(vectorX, vectorY)
+
+			Convey("The result should almost equal 0.003976158888005288", func() {
+				So(result, ShouldAlmostEqual, 0.003976158888005288)
+			})
+		})
+
+		Convey("When one vector is zero", func() {
+			result := kernel.InnerProduct(vectorX, mat64.NewDense(3, 1, nil))
+
+			Convey("The result should be 0", func() {
+				So(result, ShouldEqual, 0)
+			})
+		})
+
+	})
+}
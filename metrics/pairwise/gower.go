@@ -0,0 +1,48 @@
+package pairwise
+
+import (
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+type Gower struct {
+	mixedAttributes
+}
+
+// NewGower returns a Gower distance, which compares FloatAttributes,
+// CategoricalAttributes and BinaryAttributes together. It must be
+// fitted to the training data before use.
+func NewGower() *Gower {
+	return &Gower{}
+}
+
+// Fit finds the Attributes to compare, and the range of each of the
+// FloatAttributes.
+func (g *Gower) Fit(X base.FixedDataGrid) error {
+	return g.fit(X)
+}
+
+// Distance computes the mean over Attributes of |x_i - y_i| / range_i
+// for FloatAttributes, and 0 (if equal) or 1 for the others. Missing
+// values (NaN) are left out of the mean.
+func (g *Gower) Distance(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	x, y := g.elements(vectorX), g.elements(vectorY)
+
+	sum, count := .0, 0
+
+	for i := range x {
+		d := g.difference(i, x[i], y[i])
+		if math.IsNaN(d) {
+			continue
+		}
+		sum += d
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
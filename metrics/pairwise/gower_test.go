@@ -0,0 +1,80 @@
+package pairwise
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// mixedRow returns the values of attrs in a row of X, encoded as an
+// AttributeDistanceFunc expects.
+func mixedRow(X base.FixedDataGrid, attrs []base.Attribute, row int) *mat64.Dense {
+	values := make([]float64, len(attrs))
+	for i, a := range attrs {
+		spec, err := X.GetAttribute(a)
+		if err != nil {
+			panic(err)
+		}
+		val := X.Get(spec, row)
+		switch a.(type) {
+		case *base.FloatAttribute:
+			values[i] = base.UnpackBytesToFloat(val)
+		case *base.CategoricalAttribute:
+			values[i] = float64(base.UnpackBytesToU64(val))
+		case *base.BinaryAttribute:
+			values[i] = float64(val[0])
+		}
+	}
+	return mat64.NewDense(len(values), 1, values)
+}
+
+// namedRow returns a vector holding the value given for each of attrs.
+func namedRow(attrs []base.Attribute, values map[string]float64) *mat64.Dense {
+	ret := make([]float64, len(attrs))
+	for i, a := range attrs {
+		ret[i] = values[a.GetName()]
+	}
+	return mat64.NewDense(len(ret), 1, ret)
+}
+
+func TestGower(t *testing.T) {
+	Convey("Given the weather dataset", t, func() {
+		weather, err := base.ParseDenseARFFToInstances("../../examples/datasets/weather.arff")
+		So(err, ShouldBeNil)
+		gower := NewGower()
+		So(gower.Fit(weather), ShouldBeNil)
+
+		Convey("Every non-class Attribute should be compared", func() {
+			names := make([]string, 0)
+			for _, a := range gower.GetAttributes() {
+				names = append(names, a.GetName())
+			}
+			So(len(names), ShouldEqual, 4)
+			for _, name := range []string{"outlook", "temperature", "humidity", "windy"} {
+				So(names, ShouldContain, name)
+			}
+		})
+
+		Convey("Differences should be averaged over Attributes", func() {
+			attrs := gower.GetAttributes()
+			x, y := mixedRow(weather, attrs, 0), mixedRow(weather, attrs, 1)
+			So(gower.Distance(x, y), ShouldAlmostEqual, (0+5.0/21+5.0/31+1)/4)
+			So(gower.Distance(x, x), ShouldEqual, 0)
+		})
+
+		Convey("Missing values should be left out", func() {
+			attrs := gower.GetAttributes()
+			x := namedRow(attrs, map[string]float64{"outlook": 0, "temperature": math.NaN(), "humidity": 70, "windy": 1})
+			y := namedRow(attrs, map[string]float64{"outlook": 1, "temperature": 80, "humidity": 70, "windy": 1})
+			So(gower.Distance(x, y), ShouldAlmostEqual, 1.0/3)
+		})
+	})
+
+	Convey("An unfitted Gower distance should panic", t, func() {
+		x := mat64.NewDense(1, 1, []float64{1})
+		So(func() { NewGower().Distance(x, x) }, ShouldPanic)
+	})
+}
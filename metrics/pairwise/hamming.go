@@ -0,0 +1,38 @@
+package pairwise
+
+import (
+	"github.com/gonum/matrix/mat64"
+)
+
+type Hamming struct{}
+
+// NewHamming returns the Hamming distance, which compares category
+// values (for example those of CategoricalAttributes or
+// BinaryAttributes) rather than magnitudes.
+func NewHamming() *Hamming {
+	return &Hamming{}
+}
+
+// Distance computes the proportion of elements which differ.
+func (h *Hamming) Distance(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	r1, c1 := vectorX.Dims()
+	r2, c2 := vectorY.Dims()
+	if r1 != r2 || c1 != c2 {
+		panic(mat64.ErrShape)
+	}
+
+	differ := 0
+
+	for i := 0; i < r1; i++ {
+		for j := 0; j < c1; j++ {
+			if vectorX.At(i, j) != vectorY.At(i, j) {
+				differ++
+			}
+		}
+	}
+
+	return float64(differ) / float64(r1*c1)
+}
+
+// Categorical marks Hamming as a CategoricalDistanceFunc.
+func (h *Hamming) Categorical() {}
@@ -0,0 +1,26 @@
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHamming(t *testing.T) {
+	var vectorX, vectorY *mat64.Dense
+	hamming := NewHamming()
+
+	Convey("Given two vectors of categories", t, func() {
+		vectorX = mat64.NewDense(3, 1, []float64{1, 2, 3})
+		vectorY = mat64.NewDense(3, 1, []float64{1, 4, 3})
+
+		Convey("When calculating distance", func() {
+			result := hamming.Distance(vectorX, vectorY)
+
+			Convey("The result should be the proportion which differ", func() {
+				So(result, ShouldAlmostEqual, 1.0/3)
+			})
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package pairwise
+
+import (
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+type HEOM struct {
+	mixedAttributes
+}
+
+// NewHEOM returns a Heterogeneous Euclidean-Overlap Metric, which
+// compares FloatAttributes by range-normalised difference and
+// CategoricalAttributes and BinaryAttributes by overlap. It must be
+// fitted to the training data before use.
+//
+// D. R. Wilson and T. R. Martinez (1997). Improved Heterogeneous
+// Distance Functions. Journal of Artificial Intelligence Research, 6,
+// pp. 1-34.
+func NewHEOM() *HEOM {
+	return &HEOM{}
+}
+
+// Fit finds the Attributes to compare, and the range of each of the
+// FloatAttributes.
+func (h *HEOM) Fit(X base.FixedDataGrid) error {
+	return h.fit(X)
+}
+
+// Distance computes sqrt(sum_i d_i^2), where d_i is |x_i - y_i| /
+// range_i for FloatAttributes, and 0 (if equal) or 1 for the others.
+// A missing value (NaN) counts as d_i = 1.
+func (h *HEOM) Distance(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	x, y := h.elements(vectorX), h.elements(vectorY)
+
+	sum := .0
+
+	for i := range x {
+		d := h.difference(i, x[i], y[i])
+		if math.IsNaN(d) {
+			d = 1
+		}
+		sum += d * d
+	}
+
+	return math.Sqrt(sum)
+}
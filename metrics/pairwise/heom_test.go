@@ -0,0 +1,37 @@
+package pairwise
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHEOM(t *testing.T) {
+	Convey("Given the weather dataset", t, func() {
+		weather, err := base.ParseDenseARFFToInstances("../../examples/datasets/weather.arff")
+		So(err, ShouldBeNil)
+		heom, err := NewDistance("heom")
+		So(err, ShouldBeNil)
+		So(heom.(TrainableDistanceFunc).Fit(weather), ShouldBeNil)
+		attrs := heom.(AttributeDistanceFunc).GetAttributes()
+
+		Convey("Differences should be combined like Euclidean distance", func() {
+			x, y := mixedRow(weather, attrs, 0), mixedRow(weather, attrs, 1)
+			So(heom.Distance(x, y), ShouldAlmostEqual, math.Sqrt(math.Pow(5.0/21, 2)+math.Pow(5.0/31, 2)+1))
+		})
+
+		Convey("Missing values should count as completely different", func() {
+			x := namedRow(attrs, map[string]float64{"outlook": 0, "temperature": math.NaN(), "humidity": 70, "windy": 1})
+			y := namedRow(attrs, map[string]float64{"outlook": 0, "temperature": 80, "humidity": 70, "windy": 1})
+			So(heom.Distance(x, y), ShouldEqual, 1)
+		})
+
+		Convey("Values outside the training range should differ by at most 1", func() {
+			x := namedRow(attrs, map[string]float64{"outlook": 0, "temperature": 0, "humidity": 70, "windy": 1})
+			y := namedRow(attrs, map[string]float64{"outlook": 0, "temperature": 1000, "humidity": 70, "windy": 1})
+			So(heom.Distance(x, y), ShouldEqual, 1)
+		})
+	})
+}
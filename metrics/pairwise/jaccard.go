@@ -0,0 +1,46 @@
+package pairwise
+
+import (
+	"github.com/gonum/matrix/mat64"
+)
+
+type Jaccard struct{}
+
+// NewJaccard returns the Jaccard distance between sets, represented as
+// vectors whose non-zero elements are members (for example the values
+// of BinaryAttributes).
+func NewJaccard() *Jaccard {
+	return &Jaccard{}
+}
+
+// Distance computes 1 - |X and Y| / |X or Y|, which is 0 if both sets
+// are empty.
+func (ja *Jaccard) Distance(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	r1, c1 := vectorX.Dims()
+	r2, c2 := vectorY.Dims()
+	if r1 != r2 || c1 != c2 {
+		panic(mat64.ErrShape)
+	}
+
+	both, either := 0, 0
+
+	for i := 0; i < r1; i++ {
+		for j := 0; j < c1; j++ {
+			x, y := vectorX.At(i, j) != 0, vectorY.At(i, j) != 0
+			if x && y {
+				both++
+			}
+			if x || y {
+				either++
+			}
+		}
+	}
+
+	if either == 0 {
+		return 0
+	}
+	return 1 - float64(both)/float64(either)
+}
+
+// Categorical marks Jaccard as a CategoricalDistanceFunc.
+func (ja *Jaccard) Categorical() {}
@@ -0,0 +1,35 @@
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJaccard(t *testing.T) {
+	var vectorX, vectorY *mat64.Dense
+	jaccard := NewJaccard()
+
+	Convey("Given two binary vectors", t, func() {
+		vectorX = mat64.NewDense(4, 1, []float64{1, 0, 1, 1})
+		vectorY = mat64.NewDense(4, 1, []float64{1, 1, 0, 1})
+
+		Convey("When calculating distance", func() {
+			result := jaccard.Distance(vectorX, vectorY)
+
+			Convey("The result should be 0.5", func() {
+				So(result, ShouldEqual, 0.5)
+			})
+		})
+
+		Convey("When both are empty", func() {
+			empty := mat64.NewDense(4, 1, nil)
+			result := jaccard.Distance(empty, empty)
+
+			Convey("The result should be 0", func() {
+				So(result, ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -0,0 +1,25 @@
+package pairwise
+
+import (
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+type LaplacianKernel struct {
+	gamma float64
+}
+
+// NewLaplacianKernel returns a Laplacian kernel, which is like the RBF
+// kernel but uses Manhattan rather than squared Euclidean distance
+func NewLaplacianKernel(gamma float64) *LaplacianKernel {
+	return &LaplacianKernel{gamma: gamma}
+}
+
+// InnerProduct computes the inner product through a kernel trick
+// K(x, y) = exp(-gamma * ||x - y||_1)
+func (l *LaplacianKernel) InnerProduct(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	distance := NewManhattan().Distance(vectorX, vectorY)
+
+	return math.Exp(-l.gamma * distance)
+}
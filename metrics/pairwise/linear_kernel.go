@@ -0,0 +1,18 @@
+package pairwise
+
+import (
+	"github.com/gonum/matrix/mat64"
+)
+
+type LinearKernel struct{}
+
+// NewLinearKernel returns a linear kernel
+func NewLinearKernel() *LinearKernel {
+	return &LinearKernel{}
+}
+
+// InnerProduct computes the ordinary inner product
+// K(x, y) = x^T y
+func (l *LinearKernel) InnerProduct(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	return vectorX.Dot(vectorY)
+}
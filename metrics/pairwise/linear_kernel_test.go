@@ -0,0 +1,27 @@
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLinearKernel(t *testing.T) {
+	var vectorX, vectorY *mat64.Dense
+	kernel := NewLinearKernel()
+
+	Convey("Given two vectors", t, func() {
+		vectorX = mat64.NewDense(3, 1, []float64{1, 2, 3})
+		vectorY = mat64.NewDense(3, 1, []float64{2, 4, 5})
+
+		Convey("When doing inner product", func() {
+			result := kernel.InnerProduct(vectorX, vectorY)
+
+			Convey("The result should almost equal 25", func() {
+				So(result, ShouldAlmostEqual, 25)
+			})
+		})
+
+	})
+}
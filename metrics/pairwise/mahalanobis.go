@@ -0,0 +1,154 @@
+package pairwise
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+type Mahalanobis struct {
+	inverse *mat64.Dense
+}
+
+// NewMahalanobis returns the Mahalanobis distance for data with the
+// given covariance matrix, which must be invertible.
+func NewMahalanobis(covariance *mat64.Dense) (*Mahalanobis, error) {
+	inverse, err := invert(covariance)
+	if err != nil {
+		return nil, err
+	}
+	return &Mahalanobis{inverse: inverse}, nil
+}
+
+// FitMahalanobis estimates the covariance of the non-class
+// FloatAttributes of X and returns the matching Mahalanobis distance.
+func FitMahalanobis(X base.FixedDataGrid) (*Mahalanobis, error) {
+	attrs := base.NonClassFloatAttributes(X)
+	rows, err := readRows(X, attrs)
+	if err != nil {
+		return nil, err
+	}
+	n, d := len(rows), len(attrs)
+	if n < 2 {
+		return nil, fmt.Errorf("Need at least two rows to estimate a covariance")
+	}
+
+	mean := make([]float64, d)
+	for _, r := range rows {
+		for j := 0; j < d; j++ {
+			mean[j] += r.At(j, 0) / float64(n)
+		}
+	}
+	covariance := mat64.NewDense(d, d, nil)
+	for _, r := range rows {
+		for j := 0; j < d; j++ {
+			for k := j; k < d; k++ {
+				v := covariance.At(j, k) + (r.At(j, 0)-mean[j])*(r.At(k, 0)-mean[k])/float64(n-1)
+				covariance.Set(j, k, v)
+				covariance.Set(k, j, v)
+			}
+		}
+	}
+	return NewMahalanobis(covariance)
+}
+
+// Fit estimates the covariance of the non-class FloatAttributes of X,
+// replacing the one the distance was created with.
+func (m *Mahalanobis) Fit(X base.FixedDataGrid) error {
+	fitted, err := FitMahalanobis(X)
+	if err != nil {
+		return err
+	}
+	m.inverse = fitted.inverse
+	return nil
+}
+
+// Distance computes sqrt((x - y)^T S^-1 (x - y)), where S is the
+// covariance matrix. Elements are taken in row-major order.
+func (m *Mahalanobis) Distance(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	if m.inverse == nil {
+		panic("Fit() must be called first")
+	}
+	r1, c1 := vectorX.Dims()
+	r2, c2 := vectorY.Dims()
+	d, _ := m.inverse.Dims()
+	if r1 != r2 || c1 != c2 || r1*c1 != d {
+		panic(mat64.ErrShape)
+	}
+
+	diff := make([]float64, d)
+	for i := 0; i < r1; i++ {
+		for j := 0; j < c1; j++ {
+			diff[i*c1+j] = vectorX.At(i, j) - vectorY.At(i, j)
+		}
+	}
+
+	sum := .0
+	for i := 0; i < d; i++ {
+		for j := 0; j < d; j++ {
+			sum += diff[i] * m.inverse.At(i, j) * diff[j]
+		}
+	}
+
+	// Guard against rounding error taking sum below zero
+	return math.Sqrt(math.Max(sum, 0))
+}
+
+// invert returns the inverse of a square matrix, using Gauss-Jordan
+// elimination with partial pivoting.
+func invert(a *mat64.Dense) (*mat64.Dense, error) {
+	n, c := a.Dims()
+	if n != c {
+		return nil, fmt.Errorf("Covariance matrix must be square")
+	}
+	work := mat64.NewDense(n, n, nil)
+	ret := mat64.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			work.Set(i, j, a.At(i, j))
+		}
+		ret.Set(i, i, 1)
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(work.At(r, col)) > math.Abs(work.At(pivot, col)) {
+				pivot = r
+			}
+		}
+		if math.Abs(work.At(pivot, col)) < 1e-12 {
+			return nil, fmt.Errorf("Covariance matrix is singular")
+		}
+		for j := 0; j < n; j++ {
+			a, b := work.At(col, j), work.At(pivot, j)
+			work.Set(col, j, b)
+			work.Set(pivot, j, a)
+			a, b = ret.At(col, j), ret.At(pivot, j)
+			ret.Set(col, j, b)
+			ret.Set(pivot, j, a)
+		}
+
+		scale := work.At(col, col)
+		for j := 0; j < n; j++ {
+			work.Set(col, j, work.At(col, j)/scale)
+			ret.Set(col, j, ret.At(col, j)/scale)
+		}
+		for r := 0; r < n; r++ {
+			if r == col {
+				continue
+			}
+			factor := work.At(r, col)
+			if factor == 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				work.Set(r, j, work.At(r, j)-factor*work.At(col, j))
+				ret.Set(r, j, ret.At(r, j)-factor*ret.At(col, j))
+			}
+		}
+	}
+	return ret, nil
+}
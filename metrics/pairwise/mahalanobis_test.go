@@ -0,0 +1,71 @@
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMahalanobis(t *testing.T) {
+	Convey("Given a covariance matrix", t, func() {
+		covariance := mat64.NewDense(2, 2, []float64{2, 1, 1, 3})
+		mahalanobis, err := NewMahalanobis(covariance)
+		So(err, ShouldBeNil)
+
+		Convey("When calculating distance", func() {
+			result := mahalanobis.Distance(mat64.NewDense(2, 1, []float64{1, 2}), mat64.NewDense(2, 1, []float64{0, 0}))
+
+			Convey("The result should almost equal 1.1832159566199232", func() {
+				So(result, ShouldAlmostEqual, 1.1832159566199232)
+			})
+		})
+	})
+
+	Convey("Given a distance which hasn't been fitted", t, func() {
+		x := mat64.NewDense(2, 1, []float64{1, 2})
+
+		Convey("Calculating a distance should panic", func() {
+			So(func() { (&Mahalanobis{}).Distance(x, x) }, ShouldPanicWith, "Fit() must be called first")
+		})
+	})
+
+	Convey("Given a singular covariance matrix", t, func() {
+		_, err := NewMahalanobis(mat64.NewDense(2, 2, []float64{1, 2, 2, 4}))
+
+		Convey("It should be rejected", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given a covariance fitted to the iris dataset", t, func() {
+		iris, err := base.ParseCSVToInstances("../../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		mahalanobis, err := FitMahalanobis(iris)
+		So(err, ShouldBeNil)
+		vectors, err := readRows(iris, base.NonClassFloatAttributes(iris))
+		So(err, ShouldBeNil)
+
+		Convey("Distances should be symmetric, and zero from a row to itself", func() {
+			So(mahalanobis.Distance(vectors[0], vectors[0]), ShouldEqual, 0)
+			So(mahalanobis.Distance(vectors[0], vectors[100]), ShouldAlmostEqual, mahalanobis.Distance(vectors[100], vectors[0]))
+		})
+
+		Convey("Multiplying the inverse by the covariance should give the identity", func() {
+			covariance, err := invert(mahalanobis.inverse)
+			So(err, ShouldBeNil)
+			product := mat64.NewDense(0, 0, nil)
+			product.Mul(covariance, mahalanobis.inverse)
+			for i := 0; i < 4; i++ {
+				for j := 0; j < 4; j++ {
+					expected := 0.0
+					if i == j {
+						expected = 1
+					}
+					So(product.At(i, j), ShouldAlmostEqual, expected)
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package pairwise
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// GramMatrix returns the matrix of kernel values K(x_i, y_j) between
+// every row i of X and every row j of Y, computed in parallel. Rows are
+// read from the non-class FloatAttributes of X, which Y must also have.
+// If Y is nil, the (symmetric) Gram matrix of X with itself is returned.
+func GramMatrix(kernel Kernel, X, Y base.FixedDataGrid) (*mat64.Dense, error) {
+	return pairwiseMatrix(kernel.InnerProduct, X, Y)
+}
+
+// DistanceMatrix returns the matrix of distances between every row i of
+// X and every row j of Y, computed in parallel. Rows are read from the
+// non-class FloatAttributes of X, which Y must also have. If Y is nil,
+// the distances between the rows of X are returned.
+func DistanceMatrix(distance PairwiseDistanceFunc, X, Y base.FixedDataGrid) (*mat64.Dense, error) {
+	return pairwiseMatrix(distance.Distance, X, Y)
+}
+
+// pairwiseMatrix evaluates f between the rows of X and Y. When Y is nil
+// f is assumed to be symmetric, so only half the matrix is evaluated.
+func pairwiseMatrix(f func(*mat64.Dense, *mat64.Dense) float64, X, Y base.FixedDataGrid) (*mat64.Dense, error) {
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return nil, fmt.Errorf("No FloatAttributes to compare")
+	}
+	rowsX, err := readRows(X, attrs)
+	if err != nil {
+		return nil, err
+	}
+	symmetric := Y == nil
+	rowsY := rowsX
+	if !symmetric {
+		if rowsY, err = readRows(Y, attrs); err != nil {
+			return nil, err
+		}
+	}
+
+	n, m := len(rowsX), len(rowsY)
+	if n == 0 || m == 0 {
+		return nil, fmt.Errorf("No rows to compare")
+	}
+	values := make([]float64, n*m)
+
+	// Each worker fills in whole rows (and, for a symmetric matrix,
+	// the matching columns), so no element is written twice
+	workers := runtime.NumCPU()
+	pipe := make(chan int, workers)
+	var wait sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for i := range pipe {
+				start := 0
+				if symmetric {
+					start = i
+				}
+				for j := start; j < m; j++ {
+					v := f(rowsX[i], rowsY[j])
+					values[i*m+j] = v
+					if symmetric {
+						values[j*m+i] = v
+					}
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		pipe <- i
+	}
+	close(pipe)
+	wait.Wait()
+
+	return mat64.NewDense(n, m, values), nil
+}
+
+// readRows returns the values of the given Attributes of each row of
+// X as a column vector.
+func readRows(X base.FixedDataGrid, attrs []base.Attribute) ([]*mat64.Dense, error) {
+	specs := make([]base.AttributeSpec, len(attrs))
+	for i, a := range attrs {
+		spec, err := X.GetAttribute(a)
+		if err != nil {
+			return nil, fmt.Errorf("Attribute %s is missing: %s", a.GetName(), err)
+		}
+		specs[i] = spec
+	}
+	_, rows := X.Size()
+	ret := make([]*mat64.Dense, rows)
+	err := X.MapOverRows(specs, func(row [][]byte, i int) (bool, error) {
+		values := make([]float64, len(row))
+		for j, v := range row {
+			values[j] = base.UnpackBytesToFloat(v)
+		}
+		ret[i] = mat64.NewDense(len(values), 1, values)
+		return true, nil
+	})
+	return ret, err
+}
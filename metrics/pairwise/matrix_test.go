@@ -0,0 +1,60 @@
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPairwiseMatrices(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		iris, err := base.ParseCSVToInstances("../../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		rows := base.NewInstancesViewFromVisible(iris, []int{0, 50, 100, 149}, iris.AllAttributes())
+		vectors, err := readRows(rows, base.NonClassFloatAttributes(rows))
+		So(err, ShouldBeNil)
+
+		Convey("The Gram matrix of a dataset with itself should be symmetric", func() {
+			kernel := NewRBFKernel(0.1)
+			gram, err := GramMatrix(kernel, rows, nil)
+			So(err, ShouldBeNil)
+			r, c := gram.Dims()
+			So(r, ShouldEqual, 4)
+			So(c, ShouldEqual, 4)
+			for i := 0; i < 4; i++ {
+				So(gram.At(i, i), ShouldAlmostEqual, 1.0)
+				for j := 0; j < 4; j++ {
+					So(gram.At(i, j), ShouldAlmostEqual, kernel.InnerProduct(vectors[i], vectors[j]))
+					So(gram.At(i, j), ShouldEqual, gram.At(j, i))
+				}
+			}
+		})
+
+		Convey("The distances between two datasets should be computed for every pair of rows", func() {
+			distance := NewManhattan()
+			other := base.NewInstancesViewFromVisible(iris, []int{1, 2}, iris.AllAttributes())
+			otherVectors, err := readRows(other, base.NonClassFloatAttributes(other))
+			So(err, ShouldBeNil)
+
+			distances, err := DistanceMatrix(distance, rows, other)
+			So(err, ShouldBeNil)
+			r, c := distances.Dims()
+			So(r, ShouldEqual, 4)
+			So(c, ShouldEqual, 2)
+			for i := 0; i < 4; i++ {
+				for j := 0; j < 2; j++ {
+					So(distances.At(i, j), ShouldAlmostEqual, distance.Distance(vectors[i], otherVectors[j]))
+				}
+			}
+		})
+
+		Convey("A dataset without the same Attributes should be rejected", func() {
+			other := base.NewDenseInstances()
+			other.AddAttribute(base.NewFloatAttribute("Sepal length"))
+			other.Extend(1)
+			_, err := GramMatrix(NewLinearKernel(), rows, other)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
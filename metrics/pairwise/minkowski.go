@@ -0,0 +1,42 @@
+package pairwise
+
+import (
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+type Minkowski struct {
+	p float64
+}
+
+// NewMinkowski returns the Minkowski distance of order p. p = 1 is
+// Manhattan distance and p = 2 is Euclidean. It's only a metric if p
+// is at least 1.
+func NewMinkowski(p float64) *Minkowski {
+	return &Minkowski{p: p}
+}
+
+// GetP returns the order of the distance.
+func (m *Minkowski) GetP() float64 {
+	return m.p
+}
+
+// Distance computes (sum_i |x_i - y_i|^p)^(1/p).
+func (m *Minkowski) Distance(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	r1, c1 := vectorX.Dims()
+	r2, c2 := vectorY.Dims()
+	if r1 != r2 || c1 != c2 {
+		panic(mat64.ErrShape)
+	}
+
+	sum := .0
+
+	for i := 0; i < r1; i++ {
+		for j := 0; j < c1; j++ {
+			sum += math.Pow(math.Abs(vectorX.At(i, j)-vectorY.At(i, j)), m.p)
+		}
+	}
+
+	return math.Pow(sum, 1/m.p)
+}
@@ -0,0 +1,32 @@
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMinkowski(t *testing.T) {
+	var vectorX, vectorY *mat64.Dense
+
+	Convey("Given two vectors", t, func() {
+		vectorX = mat64.NewDense(3, 1, []float64{1, 2, 3})
+		vectorY = mat64.NewDense(3, 1, []float64{2, 4, 5})
+
+		Convey("When calculating distance with p = 3", func() {
+			result := NewMinkowski(3).Distance(vectorX, vectorY)
+
+			Convey("The result should almost equal 2.571281590658235", func() {
+				So(result, ShouldAlmostEqual, 2.571281590658235)
+			})
+		})
+
+		Convey("When calculating distance with p = 1 or p = 2", func() {
+			Convey("The results should match Manhattan and Euclidean distance", func() {
+				So(NewMinkowski(1).Distance(vectorX, vectorY), ShouldAlmostEqual, NewManhattan().Distance(vectorX, vectorY))
+				So(NewMinkowski(2).Distance(vectorX, vectorY), ShouldAlmostEqual, NewEuclidean().Distance(vectorX, vectorY))
+			})
+		})
+	})
+}
@@ -0,0 +1,135 @@
+package pairwise
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// TrainableDistanceFunc is implemented by distances which need to learn
+// something from the training data (such as a covariance matrix, or the
+// range of each Attribute) before they can be used.
+type TrainableDistanceFunc interface {
+	PairwiseDistanceFunc
+	Fit(base.FixedDataGrid) error
+}
+
+// AttributeDistanceFunc is implemented by distances which choose the
+// Attributes they compare. Vectors passed to Distance must hold the
+// values of GetAttributes(), in order: FloatAttributes as themselves,
+// CategoricalAttributes as the index of their category and
+// BinaryAttributes as 0 or 1.
+type AttributeDistanceFunc interface {
+	PairwiseDistanceFunc
+	GetAttributes() []base.Attribute
+}
+
+// mixedAttributes describes each non-class Attribute compared by a
+// distance over mixed Attribute types.
+type mixedAttributes struct {
+	attrs []base.Attribute
+	// Whether each Attribute is compared by overlap (equal or not)
+	categorical []bool
+	// Range of each FloatAttribute in the training data
+	ranges []float64
+}
+
+// fit finds the non-class Attributes of X and the range of each of the
+// FloatAttributes.
+func (m *mixedAttributes) fit(X base.FixedDataGrid) error {
+	classAttrs := X.AllClassAttributes()
+	attrs := make([]base.Attribute, 0)
+	categorical := make([]bool, 0)
+	for _, a := range base.AttributeDifference(X.AllAttributes(), classAttrs) {
+		switch a.(type) {
+		case *base.FloatAttribute:
+			categorical = append(categorical, false)
+		case *base.CategoricalAttribute, *base.BinaryAttribute:
+			categorical = append(categorical, true)
+		default:
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	if len(attrs) == 0 {
+		return fmt.Errorf("No Attributes to compare")
+	}
+
+	specs := base.ResolveAttributes(X, attrs)
+	lo := make([]float64, len(attrs))
+	hi := make([]float64, len(attrs))
+	for i := range attrs {
+		lo[i], hi[i] = math.Inf(1), math.Inf(-1)
+	}
+	X.MapOverRows(specs, func(row [][]byte, rowNo int) (bool, error) {
+		for i := range attrs {
+			if categorical[i] {
+				continue
+			}
+			v := base.UnpackBytesToFloat(row[i])
+			if math.IsNaN(v) {
+				continue
+			}
+			lo[i] = math.Min(lo[i], v)
+			hi[i] = math.Max(hi[i], v)
+		}
+		return true, nil
+	})
+	ranges := make([]float64, len(attrs))
+	for i := range attrs {
+		if !categorical[i] && hi[i] > lo[i] {
+			ranges[i] = hi[i] - lo[i]
+		}
+	}
+
+	m.attrs = attrs
+	m.categorical = categorical
+	m.ranges = ranges
+	return nil
+}
+
+// GetAttributes returns the Attributes compared, which are the
+// non-class FloatAttributes, CategoricalAttributes and BinaryAttributes
+// of the training data.
+func (m *mixedAttributes) GetAttributes() []base.Attribute {
+	return m.attrs
+}
+
+// difference returns the distance between x and y in Attribute i,
+// between 0 and 1, or NaN if either is missing.
+func (m *mixedAttributes) difference(i int, x, y float64) float64 {
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return math.NaN()
+	}
+	if m.categorical[i] {
+		if x == y {
+			return 0
+		}
+		return 1
+	}
+	if m.ranges[i] == 0 {
+		return 0
+	}
+	return math.Min(math.Abs(x-y)/m.ranges[i], 1)
+}
+
+// elements returns the values of a vector in row-major order, after
+// checking there's one per Attribute.
+func (m *mixedAttributes) elements(vector *mat64.Dense) []float64 {
+	r, c := vector.Dims()
+	if m.attrs == nil {
+		panic("Fit() must be called first")
+	}
+	if r*c != len(m.attrs) {
+		panic(mat64.ErrShape)
+	}
+	ret := make([]float64, 0, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			ret = append(ret, vector.At(i, j))
+		}
+	}
+	return ret
+}
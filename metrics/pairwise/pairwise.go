@@ -8,3 +8,9 @@ import (
 type PairwiseDistanceFunc interface {
 	Distance(vectorX *mat64.Dense, vectorY *mat64.Dense) float64
 }
+
+// Kernel computes the inner product of two column vectors in some
+// (possibly implicit) feature space.
+type Kernel interface {
+	InnerProduct(vectorX *mat64.Dense, vectorY *mat64.Dense) float64
+}
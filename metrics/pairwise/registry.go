@@ -0,0 +1,73 @@
+package pairwise
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// CategoricalDistanceFunc is implemented by distances which compare
+// category values (such as those of CategoricalAttributes and
+// BinaryAttributes) rather than numbers. Hamming and Jaccard are both
+// CategoricalDistanceFuncs.
+type CategoricalDistanceFunc interface {
+	PairwiseDistanceFunc
+	// Categorical does nothing, and only marks the distance
+	Categorical()
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]func() PairwiseDistanceFunc{
+		"euclidean": func() PairwiseDistanceFunc { return NewEuclidean() },
+		"manhattan": func() PairwiseDistanceFunc { return NewManhattan() },
+		"chebyshev": func() PairwiseDistanceFunc { return NewChebyshev() },
+		"cranberra": func() PairwiseDistanceFunc { return NewCranberra() },
+		"cosine":    func() PairwiseDistanceFunc { return NewCosineKernel() },
+		"hamming":   func() PairwiseDistanceFunc { return NewHamming() },
+		"jaccard":   func() PairwiseDistanceFunc { return NewJaccard() },
+		// These need to be fitted before use
+		"mahalanobis": func() PairwiseDistanceFunc { return &Mahalanobis{} },
+		"gower":       func() PairwiseDistanceFunc { return NewGower() },
+		"heom":        func() PairwiseDistanceFunc { return NewHEOM() },
+	}
+)
+
+// RegisterDistance makes a distance available by name (for example to
+// knn.KNNClassifier), replacing any existing one with the same name.
+// Distances with parameters, such as Minkowski or Mahalanobis, can be
+// registered under a name of their own:
+//
+//	pairwise.RegisterDistance("minkowski3", func() pairwise.PairwiseDistanceFunc {
+//		return pairwise.NewMinkowski(3)
+//	})
+func RegisterDistance(name string, constructor func() PairwiseDistanceFunc) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[name] = constructor
+}
+
+// NewDistance returns the distance registered with a given name.
+// TrainableDistanceFuncs (such as "mahalanobis", "gower" and "heom")
+// must be fitted before they're used.
+func NewDistance(name string) (PairwiseDistanceFunc, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	constructor, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported distance function %q", name)
+	}
+	return constructor(), nil
+}
+
+// DistanceNames returns the names of every registered distance, sorted.
+func DistanceNames() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	ret := make([]string, 0, len(registry))
+	for name := range registry {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
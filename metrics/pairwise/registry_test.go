@@ -0,0 +1,39 @@
+package pairwise
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDistanceRegistry(t *testing.T) {
+	Convey("Given the built-in distances", t, func() {
+		Convey("Each should be available by name", func() {
+			for _, name := range []string{"euclidean", "manhattan", "chebyshev", "cranberra", "cosine", "hamming", "jaccard"} {
+				d, err := NewDistance(name)
+				So(err, ShouldBeNil)
+				So(d, ShouldNotBeNil)
+			}
+		})
+
+		Convey("Hamming and Jaccard should be categorical", func() {
+			d, _ := NewDistance("hamming")
+			So(d, ShouldImplement, (*CategoricalDistanceFunc)(nil))
+			d, _ = NewDistance("euclidean")
+			So(d, ShouldNotImplement, (*CategoricalDistanceFunc)(nil))
+		})
+
+		Convey("Unknown names should be rejected", func() {
+			_, err := NewDistance("nonexistent")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("New distances can be registered", func() {
+			RegisterDistance("minkowski3", func() PairwiseDistanceFunc { return NewMinkowski(3) })
+			d, err := NewDistance("minkowski3")
+			So(err, ShouldBeNil)
+			So(d, ShouldHaveSameTypeAs, &Minkowski{})
+			So(DistanceNames(), ShouldContain, "minkowski3")
+		})
+	})
+}
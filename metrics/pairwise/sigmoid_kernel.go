@@ -0,0 +1,24 @@
+package pairwise
+
+import (
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+type SigmoidKernel struct {
+	gamma float64
+	coef0 float64
+}
+
+// NewSigmoidKernel returns a sigmoid (hyperbolic tangent) kernel. It
+// isn't positive semi-definite for every gamma and coef0.
+func NewSigmoidKernel(gamma, coef0 float64) *SigmoidKernel {
+	return &SigmoidKernel{gamma: gamma, coef0: coef0}
+}
+
+// InnerProduct computes the inner product through a kernel trick
+// K(x, y) = tanh(gamma * x^T y + coef0)
+func (s *SigmoidKernel) InnerProduct(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	return math.Tanh(s.gamma*vectorX.Dot(vectorY) + s.coef0)
+}
@@ -0,0 +1,38 @@
+package pairwise
+
+import (
+	"math"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+type WeightedEuclidean struct {
+	weights []float64
+}
+
+// NewWeightedEuclidean returns a Euclidean distance in which the
+// squared difference of element i is multiplied by weights[i]. Weights
+// are matched to elements in row-major order.
+func NewWeightedEuclidean(weights []float64) *WeightedEuclidean {
+	return &WeightedEuclidean{weights: weights}
+}
+
+// Distance computes sqrt(sum_i w_i (x_i - y_i)^2).
+func (w *WeightedEuclidean) Distance(vectorX *mat64.Dense, vectorY *mat64.Dense) float64 {
+	r1, c1 := vectorX.Dims()
+	r2, c2 := vectorY.Dims()
+	if r1 != r2 || c1 != c2 || r1*c1 != len(w.weights) {
+		panic(mat64.ErrShape)
+	}
+
+	sum := .0
+
+	for i := 0; i < r1; i++ {
+		for j := 0; j < c1; j++ {
+			d := vectorX.At(i, j) - vectorY.At(i, j)
+			sum += w.weights[i*c1+j] * d * d
+		}
+	}
+
+	return math.Sqrt(sum)
+}
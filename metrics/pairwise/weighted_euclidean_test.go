@@ -0,0 +1,32 @@
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWeightedEuclidean(t *testing.T) {
+	var vectorX, vectorY *mat64.Dense
+	weightedEuclidean := NewWeightedEuclidean([]float64{1, 0.5, 0.25})
+
+	Convey("Given two vectors", t, func() {
+		vectorX = mat64.NewDense(3, 1, []float64{1, 2, 3})
+		vectorY = mat64.NewDense(3, 1, []float64{2, 4, 5})
+
+		Convey("When calculating distance", func() {
+			result := weightedEuclidean.Distance(vectorX, vectorY)
+
+			Convey("The result should be 2", func() {
+				So(result, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When the number of weights is wrong", func() {
+			Convey("It should panic", func() {
+				So(func() { NewWeightedEuclidean([]float64{1}).Distance(vectorX, vectorY) }, ShouldPanic)
+			})
+		})
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/sjwhitworth/golearn/base"
 	"math"
+	"sort"
 )
 
 // A Bernoulli Naive Bayes Classifier. Naive Bayes classifiers assumes
@@ -52,6 +53,15 @@ type BernoulliNBClassifier struct {
 	features int
 	// Attributes used to Train
 	attrs []base.Attribute
+	// Number of documents with each term, by class. Kept so that
+	// PartialFit can add to it.
+	docsContainingTerm map[string][]int
+	// Optional weight of each class when estimating the priors
+	// (classes which aren't mentioned are weighted 1).
+	ClassWeights map[string]float64
+	// If true and ClassWeights is nil, weight each class in inverse
+	// proportion to its frequency, which makes the priors uniform.
+	WeightClassesAutomatically bool
 }
 
 // Create a new Bernoulli Naive Bayes Classifier. The argument 'classes'
@@ -64,9 +74,40 @@ func NewBernoulliNBClassifier() *BernoulliNBClassifier {
 	return &nb
 }
 
+// String returns a human-readable summary.
+func (nb *BernoulliNBClassifier) String() string {
+	return "BernoulliNBClassifier"
+}
+
 // Fill data matrix with Bernoulli Naive Bayes model. All values
 // necessary for calculating prior probability and p(f_i)
-func (nb *BernoulliNBClassifier) Fit(X base.FixedDataGrid) {
+//
+// Fit returns an error if any of the non-class Attributes aren't
+// BinaryAttributes, or if there isn't exactly one class Attribute.
+func (nb *BernoulliNBClassifier) Fit(X base.FixedDataGrid) error {
+	// Forget anything learned so far
+	nb.condProb = make(map[string][]float64)
+	nb.features = 0
+	nb.trainingInstances = 0
+
+	classes := make([]string, 0)
+	if len(X.AllClassAttributes()) == 1 {
+		seen := make(map[string]bool)
+		_, rows := X.Size()
+		for i := 0; i < rows; i++ {
+			if c := base.GetClass(X, i); !seen[c] {
+				seen[c] = true
+				classes = append(classes, c)
+			}
+		}
+	}
+	return nb.PartialFit(X, classes)
+}
+
+// PartialFit updates the model with a batch of training data. The
+// classes argument lists every class which can appear across all
+// batches and is required on the first call.
+func (nb *BernoulliNBClassifier) PartialFit(X base.FixedDataGrid, classes []string) error {
 
 	// Check that all Attributes are binary
 	classAttrs := X.AllClassAttributes()
@@ -74,26 +115,47 @@ func (nb *BernoulliNBClassifier) Fit(X base.FixedDataGrid) {
 	featAttrs := base.AttributeDifference(allAttrs, classAttrs)
 	for i := range featAttrs {
 		if _, ok := featAttrs[i].(*base.BinaryAttribute); !ok {
-			panic(fmt.Sprintf("%v: Should be BinaryAttribute", featAttrs[i]))
+			return fmt.Errorf("%v: Should be BinaryAttribute", featAttrs[i])
 		}
 	}
-	featAttrSpecs := base.ResolveAttributes(X, featAttrs)
 
 	// Check that only one classAttribute is defined
 	if len(classAttrs) != 1 {
-		panic("Only one class Attribute can be used")
+		return fmt.Errorf("Only one class Attribute can be used")
+	}
+	if err := nb.priorOptions().validate(); err != nil {
+		return err
 	}
 
-	// Number of features and instances in this training set
-	_, nb.trainingInstances = X.Size()
-	nb.attrs = featAttrs
-	nb.features = len(featAttrs)
-
-	// Number of instances in class
-	nb.classInstances = make(map[string]int)
+	if nb.features == 0 {
+		// First batch: set up the model
+		if len(classes) == 0 {
+			return fmt.Errorf("The class values must be given on the first call to PartialFit")
+		}
+		nb.attrs = featAttrs
+		nb.features = len(featAttrs)
+		nb.trainingInstances = 0
+		// Number of instances in class
+		nb.classInstances = make(map[string]int)
+		// Number of documents with given term (by class)
+		nb.docsContainingTerm = make(map[string][]int)
+		for _, c := range classes {
+			nb.classInstances[c] = 0
+			nb.docsContainingTerm[c] = make([]int, nb.features)
+		}
+	} else if len(featAttrs) != nb.features {
+		return fmt.Errorf("Different dimensions in this batch and the previous ones")
+	}
+	featAttrSpecs := base.ResolveAttributes(X, nb.attrs)
 
-	// Number of documents with given term (by class)
-	docsContainingTerm := make(map[string][]int)
+	// Check every class is known before changing anything
+	_, rows := X.Size()
+	for r := 0; r < rows; r++ {
+		if _, ok := nb.classInstances[base.GetClass(X, r)]; !ok {
+			return fmt.Errorf("Class %s wasn't in the list given to PartialFit", base.GetClass(X, r))
+		}
+	}
+	nb.trainingInstances += rows
 
 	// This algorithm could be vectorized after binarizing the data
 	// matrix. Since mat64 doesn't have this function, a iterative
@@ -102,11 +164,7 @@ func (nb *BernoulliNBClassifier) Fit(X base.FixedDataGrid) {
 		class := base.GetClass(X, r)
 
 		// increment number of instances in class
-		t, ok := nb.classInstances[class]
-		if !ok {
-			t = 0
-		}
-		nb.classInstances[class] = t + 1
+		nb.classInstances[class]++
 
 		for feat := 0; feat < len(docVector); feat++ {
 			v := docVector[feat]
@@ -116,30 +174,25 @@ func (nb *BernoulliNBClassifier) Fit(X base.FixedDataGrid) {
 			if v[0] > 0 {
 				// Update number of times this feature appeared within
 				// given label.
-				t, ok := docsContainingTerm[class]
-				if !ok {
-					t = make([]int, nb.features)
-					docsContainingTerm[class] = t
-				}
-				t[feat] += 1
+				nb.docsContainingTerm[class][feat] += 1
 			}
 		}
 		return true, nil
 	})
 
 	// Pre-calculate conditional probabilities for each class
-	for c, _ := range nb.classInstances {
+	for c := range nb.classInstances {
 		nb.condProb[c] = make([]float64, nb.features)
 		for feat := 0; feat < nb.features; feat++ {
-			classTerms, _ := docsContainingTerm[c]
-			numDocs := classTerms[feat]
-			docsInClass, _ := nb.classInstances[c]
+			numDocs := nb.docsContainingTerm[c][feat]
+			docsInClass := nb.classInstances[c]
 
-			classCondProb, _ := nb.condProb[c]
+			classCondProb := nb.condProb[c]
 			// Calculate conditional probability with laplace smoothing
 			classCondProb[feat] = float64(numDocs+1) / float64(docsInClass+1)
 		}
 	}
+	return nil
 }
 
 // Use trained model to predict test vector's class. The following
@@ -161,12 +214,32 @@ func (nb *BernoulliNBClassifier) PredictOne(vector [][]byte) string {
 	}
 
 	// Currently only the predicted class is returned.
-	bestScore := -math.MaxFloat64
-	bestClass := ""
+	classes := make([]string, 0, len(nb.classInstances))
+	for class := range nb.classInstances {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	return argmaxClass(classes, nb.jointLogLikelihood(classes, vector))
+}
+
+// priorOptions returns the options which determine the class priors.
+func (nb *BernoulliNBClassifier) priorOptions() priorOptions {
+	return priorOptions{nil, nb.ClassWeights, nb.WeightClassesAutomatically}
+}
 
-	for class, classCount := range nb.classInstances {
+// jointLogLikelihood returns log(p(c)) + \sum_{f}{log(p(f|c))} for
+// each of the given classes.
+func (nb *BernoulliNBClassifier) jointLogLikelihood(classes []string, vector [][]byte) []float64 {
+	opts := nb.priorOptions()
+	total := 0.0
+	for class, count := range nb.classInstances {
+		total += opts.weight(class, float64(count)) * float64(count)
+	}
+	ret := make([]float64, len(classes))
+	for i, class := range classes {
+		classCount := float64(nb.classInstances[class])
 		// Init classScore with log(prior)
-		classScore := math.Log((float64(classCount)) / float64(nb.trainingInstances))
+		classScore := math.Log(opts.weight(class, classCount) * classCount / total)
 		for f := 0; f < nb.features; f++ {
 			if vector[f][0] > 0 {
 				// Test document has feature c
@@ -181,31 +254,36 @@ func (nb *BernoulliNBClassifier) PredictOne(vector [][]byte) string {
 				}
 			}
 		}
-
-		if classScore > bestScore {
-			bestScore = classScore
-			bestClass = class
-		}
+		ret[i] = classScore
 	}
-
-	return bestClass
+	return ret
 }
 
 // Predict is just a wrapper for the PredictOne function.
 //
-// IMPORTANT: Predict panics if Fit was not called or if the
-// document vector and train matrix have a different number of columns.
-func (nb *BernoulliNBClassifier) Predict(what base.FixedDataGrid) base.FixedDataGrid {
-	// Generate return vector
-	ret := base.GeneratePredictionVector(what)
+// Predict returns an error if Fit was not called or if any of the
+// Attributes used in training are missing.
+func (nb *BernoulliNBClassifier) Predict(what base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if nb.features == 0 {
+		return nil, base.NoTrainingDataError
+	}
 
 	// Get the features
-	featAttrSpecs := base.ResolveAttributes(what, nb.attrs)
+	featAttrSpecs := make([]base.AttributeSpec, len(nb.attrs))
+	for i, a := range nb.attrs {
+		spec, err := what.GetAttribute(a)
+		if err != nil {
+			return nil, fmt.Errorf("Attribute %s is missing: %s", a.GetName(), err)
+		}
+		featAttrSpecs[i] = spec
+	}
 
+	// Generate return vector
+	ret := base.GeneratePredictionVector(what)
 	what.MapOverRows(featAttrSpecs, func(row [][]byte, i int) (bool, error) {
 		base.SetClass(ret, i, nb.PredictOne(row))
 		return true, nil
 	})
 
-	return ret
+	return ret, nil
 }
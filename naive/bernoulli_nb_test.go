@@ -15,6 +15,19 @@ func TestNoFit(t *testing.T) {
 			testDoc := [][]byte{[]byte{0}, []byte{1}}
 			So(func() { nb.PredictOne(testDoc) }, ShouldPanic)
 		})
+
+		Convey("Predict should return an error if Fit was not called", func() {
+			testData, err := base.ParseCSVToInstances("test/simple_test.csv", false)
+			So(err, ShouldBeNil)
+			_, err = nb.Predict(convertToBinary(testData))
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Fit should return an error if the Attributes aren't binary", func() {
+			trainingData, err := base.ParseCSVToInstances("test/simple_train.csv", false)
+			So(err, ShouldBeNil)
+			So(nb.Fit(trainingData), ShouldNotBeNil)
+		})
 	})
 }
 
@@ -36,7 +49,7 @@ func TestSimple(t *testing.T) {
 		So(err, ShouldBeNil)
 
 		nb := NewBernoulliNBClassifier()
-		nb.Fit(convertToBinary(trainingData))
+		So(nb.Fit(convertToBinary(trainingData)), ShouldBeNil)
 
 		Convey("Check if Fit is working as expected", func() {
 			Convey("All data needed for prior should be correctly calculated", func() {
@@ -88,11 +101,23 @@ func TestSimple(t *testing.T) {
 			})
 		})
 
+		Convey("Class weights should shift the priors", func() {
+			testDoc := [][]byte{[]byte{0}, []byte{0}, []byte{0}}
+			nb.ClassWeights = map[string]float64{"red": 2}
+			So(nb.PredictOne(testDoc), ShouldEqual, "red")
+			nb.ClassWeights = map[string]float64{"blue": 2}
+			So(nb.PredictOne(testDoc), ShouldEqual, "blue")
+
+			nb.ClassWeights = map[string]float64{"blue": -2}
+			So(nb.PartialFit(convertToBinary(trainingData), nil), ShouldNotBeNil)
+		})
+
 		Convey("Predict should work as expected", func() {
 			testData, err := base.ParseCSVToInstances("test/simple_test.csv", false)
 			So(err, ShouldBeNil)
 
-			predictions := nb.Predict(convertToBinary(testData))
+			predictions, err := nb.Predict(convertToBinary(testData))
+			So(err, ShouldBeNil)
 
 			Convey("All simple predicitions should be correct", func() {
 				So(base.GetClass(predictions, 0), ShouldEqual, "blue")
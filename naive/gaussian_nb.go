@@ -0,0 +1,200 @@
+package naive
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// A Gaussian Naive Bayes Classifier. Each FloatAttribute is assumed to
+// be normally distributed within each class, so the likelihood of an
+// instance given a class C is
+//
+// p(F1, F2... Fn|C) = \prod_{i=1}^{n}{N(F_i; \mu_{C,i}, \sigma^2_{C,i})}
+//
+// where \mu_{C,i} and \sigma^2_{C,i} are the mean and variance of
+// feature i amongst the training instances of class C.
+type GaussianNBClassifier struct {
+	classModel
+	// Class probabilities. If nil, these are estimated from the
+	// training data.
+	Priors map[string]float64
+	// Optional weight of each class when estimating the priors
+	// (classes which aren't mentioned are weighted 1). Can't be used
+	// with Priors.
+	ClassWeights map[string]float64
+	// If true and ClassWeights is nil, weight each class in inverse
+	// proportion to its frequency, which makes the priors uniform.
+	WeightClassesAutomatically bool
+	// Fraction of the largest feature variance added to every
+	// variance, which keeps constant features from dividing by zero.
+	VarSmoothing float64
+	// Mean of each feature, by class: means[c][f]
+	means [][]float64
+	// Variance of each feature (before smoothing), by class
+	variances [][]float64
+	// Amount added to every variance
+	epsilon float64
+}
+
+// NewGaussianNBClassifier returns a GaussianNBClassifier which
+// estimates the class priors from the training data.
+func NewGaussianNBClassifier() *GaussianNBClassifier {
+	return &GaussianNBClassifier{VarSmoothing: 1e-9}
+}
+
+// String returns a human-readable summary.
+func (nb *GaussianNBClassifier) String() string {
+	return fmt.Sprintf("GaussianNBClassifier(var smoothing: %g)", nb.VarSmoothing)
+}
+
+// Fit estimates the mean and variance of every non-class FloatAttribute
+// within each class.
+func (nb *GaussianNBClassifier) Fit(X base.FixedDataGrid) error {
+	nb.fitted = false
+	return nb.PartialFit(X, classValues(X))
+}
+
+// PartialFit updates the mean and variance of every feature within
+// each class with a batch of training data, using the pairwise update
+// of Chan, Golub and LeVeque. The variance smoothing is computed from
+// the first batch.
+func (nb *GaussianNBClassifier) PartialFit(X base.FixedDataGrid, classes []string) error {
+	if nb.VarSmoothing < 0 {
+		return fmt.Errorf("VarSmoothing must not be negative")
+	}
+	first := !nb.fitted
+	classIndex, features, err := nb.partialFitClasses(X, classes, priorOptions{nb.Priors, nb.ClassWeights, nb.WeightClassesAutomatically}, nil)
+	if err != nil {
+		return err
+	}
+	if first {
+		nb.means = make([][]float64, len(nb.classes))
+		nb.variances = make([][]float64, len(nb.classes))
+		for k := range nb.classes {
+			nb.means[k] = make([]float64, len(nb.attrs))
+			nb.variances[k] = make([]float64, len(nb.attrs))
+		}
+		nb.updateEpsilon(features)
+	}
+
+	// Statistics of this batch
+	batchCounts := make([]float64, len(nb.classes))
+	batchMeans := make([][]float64, len(nb.classes))
+	batchVariances := make([][]float64, len(nb.classes))
+	for k := range nb.classes {
+		batchMeans[k] = make([]float64, len(nb.attrs))
+		batchVariances[k] = make([]float64, len(nb.attrs))
+	}
+	for i, row := range features {
+		k := classIndex[i]
+		batchCounts[k]++
+		for f, v := range row {
+			batchMeans[k][f] += v
+		}
+	}
+	for k, count := range batchCounts {
+		for f := range batchMeans[k] {
+			if count > 0 {
+				batchMeans[k][f] /= count
+			}
+		}
+	}
+	for i, row := range features {
+		k := classIndex[i]
+		for f, v := range row {
+			d := v - batchMeans[k][f]
+			batchVariances[k][f] += d * d
+		}
+	}
+
+	// Combine them with what's been seen before
+	for k, count := range batchCounts {
+		if count == 0 {
+			continue
+		}
+		total := nb.classCounts[k]
+		previous := total - count
+		for f := range nb.means[k] {
+			delta := batchMeans[k][f] - nb.means[k][f]
+			sq := nb.variances[k][f]*previous + batchVariances[k][f] + delta*delta*previous*count/total
+			nb.means[k][f] += delta * count / total
+			nb.variances[k][f] = sq / total
+		}
+	}
+
+	nb.fitted = true
+	return nil
+}
+
+// updateEpsilon sets the variance smoothing from the largest variance
+// of any feature across the given rows.
+func (nb *GaussianNBClassifier) updateEpsilon(features [][]float64) {
+	largest := 0.0
+	for f := range nb.attrs {
+		mean, sq := 0.0, 0.0
+		for _, row := range features {
+			mean += row[f]
+		}
+		mean /= float64(len(features))
+		for _, row := range features {
+			sq += (row[f] - mean) * (row[f] - mean)
+		}
+		largest = math.Max(largest, sq/float64(len(features)))
+	}
+	nb.epsilon = nb.VarSmoothing * largest
+	if nb.epsilon == 0 {
+		// Every feature is constant
+		nb.epsilon = nb.VarSmoothing
+	}
+}
+
+// jointLogLikelihood scores a row against every class.
+func (nb *GaussianNBClassifier) jointLogLikelihood(row []float64) []float64 {
+	ret := make([]float64, len(nb.classes))
+	for k := range nb.classes {
+		score := nb.logPriors[k]
+		for f, v := range row {
+			variance := nb.variances[k][f] + nb.epsilon
+			d := v - nb.means[k][f]
+			score -= 0.5 * (math.Log(2*math.Pi*variance) + d*d/variance)
+		}
+		ret[k] = score
+	}
+	return ret
+}
+
+// Predict returns the most probable class of each row of X.
+func (nb *GaussianNBClassifier) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return nb.predict(X, nb.jointLogLikelihood)
+}
+
+// PredictProba returns the posterior probability of each class,
+// as one FloatAttribute per class value.
+func (nb *GaussianNBClassifier) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return nb.predictProba(X, nb.jointLogLikelihood)
+}
+
+// GetClasses returns the class values, in the order used by
+// PredictProba, GetMeans and GetVariances.
+func (nb *GaussianNBClassifier) GetClasses() []string {
+	return nb.classes
+}
+
+// GetMeans returns the mean of each feature, by class.
+func (nb *GaussianNBClassifier) GetMeans() [][]float64 {
+	return nb.means
+}
+
+// GetVariances returns the variance of each feature, by class,
+// including smoothing.
+func (nb *GaussianNBClassifier) GetVariances() [][]float64 {
+	ret := make([][]float64, len(nb.variances))
+	for k, vals := range nb.variances {
+		ret[k] = make([]float64, len(vals))
+		for f, v := range vals {
+			ret[k][f] = v + nb.epsilon
+		}
+	}
+	return ret
+}
@@ -0,0 +1,109 @@
+package naive
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestGaussianNB(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewGaussianNBClassifier().Predict(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Fitting no rows should return a NoTrainingDataError", func() {
+			empty := base.NewInstancesViewFromVisible(inst, []int{}, inst.AllAttributes())
+			So(NewGaussianNBClassifier().Fit(empty), ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("After fitting", func() {
+			nb := NewGaussianNBClassifier()
+			So(nb.Fit(inst), ShouldBeNil)
+
+			Convey("The class means should be correct", func() {
+				So(nb.GetClasses()[0], ShouldEqual, "Iris-setosa")
+				So(nb.GetMeans()[0][0], ShouldAlmostEqual, 5.006, 1e-9)
+				So(nb.GetMeans()[0][2], ShouldAlmostEqual, 1.464, 1e-9)
+			})
+
+			Convey("The training data should be classified accurately", func() {
+				predictions, err := nb.Predict(inst)
+				So(err, ShouldBeNil)
+				cf, err := evaluation.GetConfusionMatrix(inst, predictions)
+				So(err, ShouldBeNil)
+				So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.94)
+			})
+
+			Convey("Each row's probabilities should sum to one", func() {
+				probs, err := nb.PredictProba(inst)
+				So(err, ShouldBeNil)
+				specs := base.ResolveAttributes(probs, probs.AllAttributes())
+				_, rows := probs.Size()
+				for i := 0; i < rows; i++ {
+					sum := 0.0
+					for _, s := range specs {
+						sum += base.UnpackBytesToFloat(probs.Get(s, i))
+					}
+					So(sum, ShouldAlmostEqual, 1.0, 1e-9)
+				}
+			})
+		})
+
+		Convey("Class priors can be supplied", func() {
+			nb := NewGaussianNBClassifier()
+			nb.Priors = map[string]float64{"Iris-setosa": 0.2, "Iris-versicolor": 0.2, "Iris-virginica": 0.6}
+			So(nb.Fit(inst), ShouldBeNil)
+
+			Convey("But must cover every class", func() {
+				delete(nb.Priors, "Iris-virginica")
+				So(nb.Fit(inst), ShouldNotBeNil)
+			})
+
+			Convey("And must sum to one", func() {
+				nb.Priors["Iris-virginica"] = 0.7
+				So(nb.Fit(inst), ShouldNotBeNil)
+			})
+
+			Convey("But not alongside class weights", func() {
+				nb.WeightClassesAutomatically = true
+				So(nb.Fit(inst), ShouldNotBeNil)
+			})
+		})
+
+		Convey("Given fewer rows of one class", func() {
+			rows := make([]int, 110)
+			for i := range rows {
+				rows[i] = i
+			}
+			imbalanced := base.NewInstancesViewFromVisible(inst, rows, inst.AllAttributes())
+			nb := NewGaussianNBClassifier()
+			So(nb.Fit(imbalanced), ShouldBeNil)
+			So(math.Exp(nb.logPriors[2]), ShouldAlmostEqual, 10.0/110.0)
+
+			Convey("Weighting the classes automatically should make the priors uniform", func() {
+				nb.WeightClassesAutomatically = true
+				So(nb.Fit(imbalanced), ShouldBeNil)
+				for _, p := range nb.logPriors {
+					So(math.Exp(p), ShouldAlmostEqual, 1.0/3.0)
+				}
+			})
+
+			Convey("Class weights should scale the priors", func() {
+				nb.ClassWeights = map[string]float64{"Iris-virginica": 10}
+				So(nb.Fit(imbalanced), ShouldBeNil)
+				So(math.Exp(nb.logPriors[0]), ShouldAlmostEqual, 50.0/200.0)
+				So(math.Exp(nb.logPriors[2]), ShouldAlmostEqual, 100.0/200.0)
+
+				nb.ClassWeights["Iris-setosa"] = 0
+				So(nb.Fit(imbalanced), ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,397 @@
+package naive
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/utilities"
+	"math"
+)
+
+// FloatLikelihood selects how MixedNBClassifier models FloatAttributes.
+type FloatLikelihood int
+
+const (
+	// GaussianLikelihood fits a normal distribution to each
+	// FloatAttribute within each class.
+	GaussianLikelihood FloatLikelihood = iota
+	// KernelDensityLikelihood places a Gaussian kernel on every
+	// training value, which copes with skewed or multi-modal features.
+	KernelDensityLikelihood
+)
+
+// A Mixed Naive Bayes Classifier, for tables which combine different
+// types of Attribute. Each non-class Attribute is modelled according
+// to its type:
+//
+//   - CategoricalAttribute: a smoothed frequency table,
+//     p(f_i = v|C) = (N_{C,i,v} + \alpha) / (N_C + \alpha V_i)
+//     where V_i is the number of values the Attribute takes.
+//   - BinaryAttribute: a smoothed Bernoulli distribution,
+//     p(f_i = 1|C) = (N_{C,i} + \alpha) / (N_C + 2\alpha)
+//   - FloatAttribute: either a Gaussian or a kernel density estimate,
+//     depending on FloatLikelihood.
+//
+// Values of a CategoricalAttribute which weren't seen during training
+// are ignored when predicting.
+type MixedNBClassifier struct {
+	classModel
+	// Class probabilities. If nil, these are estimated from the
+	// training data.
+	Priors map[string]float64
+	// Optional weight of each class when estimating the priors
+	// (classes which aren't mentioned are weighted 1). Can't be used
+	// with Priors.
+	ClassWeights map[string]float64
+	// If true and ClassWeights is nil, weight each class in inverse
+	// proportion to its frequency, which makes the priors uniform.
+	WeightClassesAutomatically bool
+	// Additive smoothing for Categorical and BinaryAttributes.
+	Alpha float64
+	// How FloatAttributes are modelled.
+	FloatLikelihood FloatLikelihood
+	// Fraction of each FloatAttribute's variance added to its
+	// per-class variance when using GaussianLikelihood.
+	VarSmoothing float64
+	// Kernel bandwidth when using KernelDensityLikelihood, or 0 to
+	// choose one per class and Attribute with Silverman's rule.
+	Bandwidth float64
+	// One model per Attribute in attrs
+	featureModels []featureModel
+}
+
+// featureModel is the per-class distribution of a single Attribute.
+type featureModel interface {
+	// update adds the values of a batch of training rows, whose
+	// classes are given by classIndex.
+	update(classIndex []int, values [][]byte, attr base.Attribute)
+	// logLikelihood returns log(p(f = val|C)) for the k-th class.
+	logLikelihood(k int, val []byte, attr base.Attribute) float64
+}
+
+// NewMixedNBClassifier returns a MixedNBClassifier with Laplace
+// smoothing which models FloatAttributes as Gaussians.
+func NewMixedNBClassifier() *MixedNBClassifier {
+	return &MixedNBClassifier{
+		Alpha:           1.0,
+		FloatLikelihood: GaussianLikelihood,
+		VarSmoothing:    1e-9,
+	}
+}
+
+// String returns a human-readable summary.
+func (nb *MixedNBClassifier) String() string {
+	float := "gaussian"
+	if nb.FloatLikelihood == KernelDensityLikelihood {
+		float = "kernel density"
+	}
+	return fmt.Sprintf("MixedNBClassifier(alpha: %g, float: %s)", nb.Alpha, float)
+}
+
+// Fit estimates the distribution of every non-class Attribute within
+// each class.
+func (nb *MixedNBClassifier) Fit(X base.FixedDataGrid) error {
+	nb.fitted = false
+	return nb.PartialFit(X, classValues(X))
+}
+
+// PartialFit updates the distribution of every feature within each
+// class with a batch of training data. Note that kernel density
+// estimates retain every training value.
+func (nb *MixedNBClassifier) PartialFit(X base.FixedDataGrid, classes []string) error {
+	if nb.Alpha < 0 {
+		return fmt.Errorf("Alpha must not be negative")
+	}
+	if nb.VarSmoothing < 0 || nb.Bandwidth < 0 {
+		return fmt.Errorf("VarSmoothing and Bandwidth must not be negative")
+	}
+	attrs := nb.attrs
+	if !nb.fitted {
+		attrs = base.NonClassAttributes(X)
+		if len(attrs) == 0 {
+			return fmt.Errorf("No Attributes to fit")
+		}
+		for _, a := range attrs {
+			switch a.(type) {
+			case *base.CategoricalAttribute, *base.BinaryAttribute, *base.FloatAttribute:
+			default:
+				return fmt.Errorf("%s: unsupported Attribute type", a)
+			}
+		}
+	}
+	first := !nb.fitted
+	classIndex, err := nb.partialFitLabels(X, attrs, classes, priorOptions{nb.Priors, nb.ClassWeights, nb.WeightClassesAutomatically})
+	if err != nil {
+		return err
+	}
+
+	if first {
+		nb.featureModels = make([]featureModel, len(attrs))
+		for j, a := range attrs {
+			switch a.(type) {
+			case *base.CategoricalAttribute:
+				nb.featureModels[j] = nb.newCategoricalFeature()
+			case *base.BinaryAttribute:
+				nb.featureModels[j] = nb.newBinaryFeature()
+			case *base.FloatAttribute:
+				if nb.FloatLikelihood == KernelDensityLikelihood {
+					nb.featureModels[j] = nb.newKernelDensityFeature()
+				} else {
+					nb.featureModels[j] = nb.newGaussianFeature()
+				}
+			}
+		}
+	}
+
+	// Collect the raw values of each Attribute
+	_, rows := X.Size()
+	values := make([][][]byte, len(attrs))
+	for j := range values {
+		values[j] = make([][]byte, rows)
+	}
+	specs := base.ResolveAttributes(X, attrs)
+	X.MapOverRows(specs, func(row [][]byte, i int) (bool, error) {
+		for j, v := range row {
+			values[j][i] = append([]byte(nil), v...)
+		}
+		return true, nil
+	})
+	for j, m := range nb.featureModels {
+		m.update(classIndex, values[j], specs[j].GetAttribute())
+	}
+	nb.fitted = true
+	return nil
+}
+
+// jointLogLikelihood scores a row against every class.
+func (nb *MixedNBClassifier) jointLogLikelihood(row [][]byte, specs []base.AttributeSpec) []float64 {
+	ret := make([]float64, len(nb.classes))
+	for k := range nb.classes {
+		ret[k] = nb.logPriors[k]
+		for j, v := range row {
+			ret[k] += nb.featureModels[j].logLikelihood(k, v, specs[j].GetAttribute())
+		}
+	}
+	return ret
+}
+
+// Predict returns the most probable class of each row of X.
+func (nb *MixedNBClassifier) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return nb.predictRows(X, nb.jointLogLikelihood)
+}
+
+// PredictProba returns the posterior probability of each class,
+// as one FloatAttribute per class value.
+func (nb *MixedNBClassifier) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return nb.predictProbaRows(X, nb.jointLogLikelihood)
+}
+
+// GetClasses returns the class values, in the order used by PredictProba.
+func (nb *MixedNBClassifier) GetClasses() []string {
+	return nb.classes
+}
+
+// categoricalFeature is a per-class frequency table.
+type categoricalFeature struct {
+	// Occurrences of each value, by class: counts[c][value]
+	counts []map[string]float64
+	// Values seen during training
+	seen  map[string]bool
+	alpha float64
+	// Number of training rows in each class
+	classCounts []float64
+}
+
+func (nb *MixedNBClassifier) newCategoricalFeature() *categoricalFeature {
+	ret := &categoricalFeature{
+		counts:      make([]map[string]float64, len(nb.classes)),
+		seen:        make(map[string]bool),
+		alpha:       nb.Alpha,
+		classCounts: nb.classCounts,
+	}
+	for k := range ret.counts {
+		ret.counts[k] = make(map[string]float64)
+	}
+	return ret
+}
+
+func (c *categoricalFeature) update(classIndex []int, values [][]byte, attr base.Attribute) {
+	for i, v := range values {
+		s := attr.GetStringFromSysVal(v)
+		c.counts[classIndex[i]][s]++
+		c.seen[s] = true
+	}
+}
+
+func (c *categoricalFeature) logLikelihood(k int, val []byte, attr base.Attribute) float64 {
+	s := attr.GetStringFromSysVal(val)
+	if !c.seen[s] {
+		return 0
+	}
+	return math.Log((c.counts[k][s] + c.alpha) / (c.classCounts[k] + c.alpha*float64(len(c.seen))))
+}
+
+// binaryFeature is a per-class Bernoulli distribution.
+type binaryFeature struct {
+	// Number of rows with the feature present, by class
+	present []float64
+	alpha   float64
+	// Number of training rows in each class
+	classCounts []float64
+}
+
+func (nb *MixedNBClassifier) newBinaryFeature() *binaryFeature {
+	return &binaryFeature{
+		present:     make([]float64, len(nb.classes)),
+		alpha:       nb.Alpha,
+		classCounts: nb.classCounts,
+	}
+}
+
+func (b *binaryFeature) update(classIndex []int, values [][]byte, attr base.Attribute) {
+	for i, v := range values {
+		if v[0] > 0 {
+			b.present[classIndex[i]]++
+		}
+	}
+}
+
+func (b *binaryFeature) logLikelihood(k int, val []byte, attr base.Attribute) float64 {
+	p := (b.present[k] + b.alpha) / (b.classCounts[k] + 2*b.alpha)
+	if val[0] > 0 {
+		return math.Log(p)
+	}
+	return math.Log(1 - p)
+}
+
+// gaussianFeature is a per-class normal distribution.
+type gaussianFeature struct {
+	counts    []float64
+	means     []float64
+	variances []float64
+	// Added to every variance, computed from the first batch
+	epsilon      float64
+	varSmoothing float64
+}
+
+func (nb *MixedNBClassifier) newGaussianFeature() *gaussianFeature {
+	return &gaussianFeature{
+		counts:       make([]float64, len(nb.classes)),
+		means:        make([]float64, len(nb.classes)),
+		variances:    make([]float64, len(nb.classes)),
+		epsilon:      -1,
+		varSmoothing: nb.VarSmoothing,
+	}
+}
+
+func (g *gaussianFeature) update(classIndex []int, values [][]byte, attr base.Attribute) {
+	byClass := make([][]float64, len(g.means))
+	all := make([]float64, len(values))
+	for i, v := range values {
+		all[i] = base.UnpackBytesToFloat(v)
+		byClass[classIndex[i]] = append(byClass[classIndex[i]], all[i])
+	}
+	if g.epsilon < 0 {
+		_, overall := meanVariance(all)
+		g.epsilon = g.varSmoothing * overall
+		if g.epsilon == 0 {
+			g.epsilon = g.varSmoothing
+		}
+	}
+	for k, vals := range byClass {
+		if len(vals) == 0 {
+			continue
+		}
+		mean, variance := meanVariance(vals)
+		count := float64(len(vals))
+		previous := g.counts[k]
+		total := previous + count
+		delta := mean - g.means[k]
+		sq := g.variances[k]*previous + variance*count + delta*delta*previous*count/total
+		g.means[k] += delta * count / total
+		g.variances[k] = sq / total
+		g.counts[k] = total
+	}
+}
+
+func (g *gaussianFeature) logLikelihood(k int, val []byte, attr base.Attribute) float64 {
+	variance := g.variances[k] + g.epsilon
+	d := base.UnpackBytesToFloat(val) - g.means[k]
+	return -0.5 * (math.Log(2*math.Pi*variance) + d*d/variance)
+}
+
+// kernelDensityFeature places a Gaussian kernel on every training value.
+type kernelDensityFeature struct {
+	// Training values, by class
+	samples [][]float64
+	// Kernel bandwidth, by class
+	bandwidths []float64
+	// Fixed bandwidth, or 0 to use Silverman's rule
+	bandwidth float64
+}
+
+func (nb *MixedNBClassifier) newKernelDensityFeature() *kernelDensityFeature {
+	return &kernelDensityFeature{
+		samples:    make([][]float64, len(nb.classes)),
+		bandwidths: make([]float64, len(nb.classes)),
+		bandwidth:  nb.Bandwidth,
+	}
+}
+
+func (d *kernelDensityFeature) update(classIndex []int, values [][]byte, attr base.Attribute) {
+	for i, v := range values {
+		k := classIndex[i]
+		d.samples[k] = append(d.samples[k], base.UnpackBytesToFloat(v))
+	}
+
+	all := make([]float64, 0)
+	for _, samples := range d.samples {
+		all = append(all, samples...)
+	}
+	_, overall := meanVariance(all)
+	for k, samples := range d.samples {
+		h := d.bandwidth
+		if h == 0 {
+			// Silverman's rule of thumb
+			_, variance := meanVariance(samples)
+			if variance == 0 {
+				variance = overall
+			}
+			h = 1.06 * math.Sqrt(variance) * math.Pow(float64(len(samples)), -0.2)
+			if h == 0 || math.IsNaN(h) || math.IsInf(h, 0) {
+				h = 1.0
+			}
+		}
+		d.bandwidths[k] = h
+	}
+}
+
+func (d *kernelDensityFeature) logLikelihood(k int, val []byte, attr base.Attribute) float64 {
+	if len(d.samples[k]) == 0 {
+		return math.Inf(-1)
+	}
+	x := base.UnpackBytesToFloat(val)
+	h := d.bandwidths[k]
+	terms := make([]float64, len(d.samples[k]))
+	for i, s := range d.samples[k] {
+		z := (x - s) / h
+		terms[i] = -0.5 * z * z
+	}
+	norm := math.Log(float64(len(terms)) * h * math.Sqrt(2*math.Pi))
+	return utilities.LogSumExp(terms) - norm
+}
+
+// meanVariance returns the mean and (population) variance of vals.
+func meanVariance(vals []float64) (float64, float64) {
+	if len(vals) == 0 {
+		return 0, 0
+	}
+	mean, sq := 0.0, 0.0
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(len(vals))
+	for _, v := range vals {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, sq / float64(len(vals))
+}
@@ -0,0 +1,77 @@
+package naive
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestMixedNB(t *testing.T) {
+	Convey("Given the tennis dataset, which is entirely categorical", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/tennis.csv", true)
+		So(err, ShouldBeNil)
+
+		nb := NewMixedNBClassifier()
+		So(nb.Fit(inst), ShouldBeNil)
+
+		Convey("The posterior of the first row should be correct", func() {
+			probs, err := nb.PredictProba(inst)
+			So(err, ShouldBeNil)
+			no := base.ResolveAttributes(probs, []base.Attribute{base.GetAttributeByName(probs, "no")})[0]
+			So(base.UnpackBytesToFloat(probs.Get(no, 0)), ShouldAlmostEqual, 0.6879691, 1e-6)
+		})
+	})
+
+	Convey("Given the weather dataset, which mixes categorical and real values", t, func() {
+		inst, err := base.ParseDenseARFFToInstances("../examples/datasets/weather.arff")
+		So(err, ShouldBeNil)
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewMixedNBClassifier().Predict(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("With Gaussian likelihoods", func() {
+			nb := NewMixedNBClassifier()
+			So(nb.Fit(inst), ShouldBeNil)
+
+			Convey("The posterior of the first row should be correct", func() {
+				probs, err := nb.PredictProba(inst)
+				So(err, ShouldBeNil)
+				no := base.ResolveAttributes(probs, []base.Attribute{base.GetAttributeByName(probs, "no")})[0]
+				So(base.UnpackBytesToFloat(probs.Get(no, 0)), ShouldAlmostEqual, 0.6986154, 1e-6)
+			})
+		})
+
+		Convey("With kernel density likelihoods", func() {
+			nb := NewMixedNBClassifier()
+			nb.FloatLikelihood = KernelDensityLikelihood
+			So(nb.Fit(inst), ShouldBeNil)
+			predictions, err := nb.Predict(inst)
+			So(err, ShouldBeNil)
+			cf, err := evaluation.GetConfusionMatrix(inst, predictions)
+			So(err, ShouldBeNil)
+			So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.8)
+		})
+	})
+
+	Convey("Given binary features", t, func() {
+		trainingData, err := base.ParseCSVToInstances("test/simple_train.csv", false)
+		So(err, ShouldBeNil)
+		testData, err := base.ParseCSVToInstances("test/simple_test.csv", false)
+		So(err, ShouldBeNil)
+
+		nb := NewMixedNBClassifier()
+		So(nb.Fit(convertToBinary(trainingData)), ShouldBeNil)
+
+		Convey("Predictions should match the Bernoulli model", func() {
+			predictions, err := nb.Predict(convertToBinary(testData))
+			So(err, ShouldBeNil)
+			So(base.GetClass(predictions, 0), ShouldEqual, "blue")
+			So(base.GetClass(predictions, 1), ShouldEqual, "red")
+			So(base.GetClass(predictions, 2), ShouldEqual, "blue")
+			So(base.GetClass(predictions, 3), ShouldEqual, "red")
+		})
+	})
+}
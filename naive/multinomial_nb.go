@@ -0,0 +1,288 @@
+package naive
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+)
+
+// A Multinomial Naive Bayes Classifier, suited to count features such
+// as word frequencies. The likelihood of an instance given a class C is
+//
+// p(F1, F2... Fn|C) \propto \prod_{i=1}^{n}{p(f_i|C)^{F_i}}
+//
+// where F_i is the count of feature i and p(f_i|C) is estimated with
+// additive smoothing:
+//
+// p(f_i|C) = \frac{N_{C,i} + \alpha}{N_C + \alpha n}
+//
+// For more information:
+//
+// C.D. Manning, P. Raghavan and H. Schuetze (2008). Introduction to
+// Information Retrieval. Cambridge University Press, pp. 234-265.
+// http://nlp.stanford.edu/IR-book/html/htmledition/naive-bayes-text-classification-1.html
+type MultinomialNBClassifier struct {
+	classModel
+	// Class probabilities. If nil, these are estimated from the
+	// training data.
+	Priors map[string]float64
+	// Optional weight of each class when estimating the priors
+	// (classes which aren't mentioned are weighted 1). Can't be used
+	// with Priors.
+	ClassWeights map[string]float64
+	// If true and ClassWeights is nil, weight each class in inverse
+	// proportion to its frequency, which makes the priors uniform.
+	WeightClassesAutomatically bool
+	// Additive (Laplace/Lidstone) smoothing parameter.
+	Alpha float64
+	// Total count of each feature, by class: featureCounts[c][f]
+	featureCounts [][]float64
+	// log(p(f|c)), accessed as featureLogProb[c][f]
+	featureLogProb [][]float64
+}
+
+// NewMultinomialNBClassifier returns a MultinomialNBClassifier with
+// Laplace smoothing (Alpha = 1).
+func NewMultinomialNBClassifier() *MultinomialNBClassifier {
+	return &MultinomialNBClassifier{Alpha: 1.0}
+}
+
+// String returns a human-readable summary.
+func (nb *MultinomialNBClassifier) String() string {
+	return fmt.Sprintf("MultinomialNBClassifier(alpha: %g)", nb.Alpha)
+}
+
+// Fit counts the occurrences of each feature within each class.
+// Every non-class FloatAttribute is treated as a (non-negative) count.
+func (nb *MultinomialNBClassifier) Fit(X base.FixedDataGrid) error {
+	nb.fitted = false
+	return nb.PartialFit(X, classValues(X))
+}
+
+// PartialFit adds the feature counts of a batch of training data to
+// those seen so far.
+func (nb *MultinomialNBClassifier) PartialFit(X base.FixedDataGrid, classes []string) error {
+	if nb.Alpha < 0 {
+		return fmt.Errorf("Alpha must not be negative")
+	}
+	counts, err := nb.partialFitCounts(X, classes, priorOptions{nb.Priors, nb.ClassWeights, nb.WeightClassesAutomatically}, &nb.featureCounts)
+	if err != nil {
+		return err
+	}
+
+	nb.featureLogProb = make([][]float64, len(nb.classes))
+	for k, counts := range counts {
+		total := 0.0
+		for _, c := range counts {
+			total += c + nb.Alpha
+		}
+		nb.featureLogProb[k] = make([]float64, len(counts))
+		for f, c := range counts {
+			nb.featureLogProb[k][f] = math.Log((c + nb.Alpha) / total)
+		}
+	}
+	nb.fitted = true
+	return nil
+}
+
+// jointLogLikelihood scores a row against every class.
+func (nb *MultinomialNBClassifier) jointLogLikelihood(row []float64) []float64 {
+	ret := make([]float64, len(nb.classes))
+	for k := range nb.classes {
+		ret[k] = nb.logPriors[k]
+		for f, v := range row {
+			if v != 0 {
+				ret[k] += v * nb.featureLogProb[k][f]
+			}
+		}
+	}
+	return ret
+}
+
+// Predict returns the most probable class of each row of X.
+func (nb *MultinomialNBClassifier) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return nb.predict(X, nb.jointLogLikelihood)
+}
+
+// PredictProba returns the posterior probability of each class,
+// as one FloatAttribute per class value.
+func (nb *MultinomialNBClassifier) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return nb.predictProba(X, nb.jointLogLikelihood)
+}
+
+// GetClasses returns the class values, in the order used by
+// PredictProba and GetFeatureLogProb.
+func (nb *MultinomialNBClassifier) GetClasses() []string {
+	return nb.classes
+}
+
+// GetFeatureLogProb returns log(p(f|c)), accessed as [c][f].
+func (nb *MultinomialNBClassifier) GetFeatureLogProb() [][]float64 {
+	return nb.featureLogProb
+}
+
+// A Complement Naive Bayes Classifier. This variant of the multinomial
+// model estimates each class' feature weights from the training
+// instances of every *other* class, which makes it much less sensitive
+// to imbalanced classes. An instance is assigned to the class whose
+// complement fits it worst:
+//
+// classScore = -\sum_{i}{F_i \log{p(f_i|\bar{C})}}
+//
+// Class priors aren't used, following the original paper.
+//
+// For more information:
+//
+// J. Rennie, L. Shih, J. Teevan and D. Karger (2003). Tackling the
+// poor assumptions of naive Bayes text classifiers. ICML, pp. 616-623.
+type ComplementNBClassifier struct {
+	classModel
+	// Additive (Laplace/Lidstone) smoothing parameter.
+	Alpha float64
+	// Normalise the weights of each class, as in the paper.
+	Norm bool
+	// Total count of each feature, by class: featureCounts[c][f]
+	featureCounts [][]float64
+	// Weight of each feature, by class
+	weights [][]float64
+}
+
+// NewComplementNBClassifier returns a ComplementNBClassifier with
+// Laplace smoothing (Alpha = 1) and no weight normalisation.
+func NewComplementNBClassifier() *ComplementNBClassifier {
+	return &ComplementNBClassifier{Alpha: 1.0}
+}
+
+// String returns a human-readable summary.
+func (nb *ComplementNBClassifier) String() string {
+	return fmt.Sprintf("ComplementNBClassifier(alpha: %g, norm: %t)", nb.Alpha, nb.Norm)
+}
+
+// Fit counts the occurrences of each feature within each class.
+// Every non-class FloatAttribute is treated as a (non-negative) count.
+func (nb *ComplementNBClassifier) Fit(X base.FixedDataGrid) error {
+	nb.fitted = false
+	return nb.PartialFit(X, classValues(X))
+}
+
+// PartialFit adds the feature counts of a batch of training data to
+// those seen so far.
+func (nb *ComplementNBClassifier) PartialFit(X base.FixedDataGrid, classes []string) error {
+	if nb.Alpha < 0 {
+		return fmt.Errorf("Alpha must not be negative")
+	}
+	if _, err := nb.partialFitCounts(X, classes, priorOptions{}, &nb.featureCounts); err != nil {
+		return err
+	}
+
+	allCounts := make([]float64, len(nb.attrs))
+	for _, counts := range nb.featureCounts {
+		for f, c := range counts {
+			allCounts[f] += c
+		}
+	}
+	nb.weights = make([][]float64, len(nb.classes))
+	for k, counts := range nb.featureCounts {
+		complement := make([]float64, len(counts))
+		total := 0.0
+		for f, c := range counts {
+			complement[f] = allCounts[f] - c + nb.Alpha
+			total += complement[f]
+		}
+		nb.weights[k] = make([]float64, len(counts))
+		norm := 0.0
+		for f, c := range complement {
+			nb.weights[k][f] = math.Log(c / total)
+			norm += nb.weights[k][f]
+		}
+		for f := range nb.weights[k] {
+			if nb.Norm {
+				nb.weights[k][f] /= norm
+			} else {
+				nb.weights[k][f] = -nb.weights[k][f]
+			}
+		}
+	}
+	nb.fitted = true
+	return nil
+}
+
+// jointLogLikelihood scores a row against every class.
+func (nb *ComplementNBClassifier) jointLogLikelihood(row []float64) []float64 {
+	ret := make([]float64, len(nb.classes))
+	for k := range nb.classes {
+		for f, v := range row {
+			if v != 0 {
+				ret[k] += v * nb.weights[k][f]
+			}
+		}
+		if len(nb.classes) == 1 {
+			ret[k] += nb.logPriors[k]
+		}
+	}
+	return ret
+}
+
+// Predict returns the most probable class of each row of X.
+func (nb *ComplementNBClassifier) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return nb.predict(X, nb.jointLogLikelihood)
+}
+
+// PredictProba returns the normalised class scores, as one
+// FloatAttribute per class value. These are poorly calibrated.
+func (nb *ComplementNBClassifier) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	return nb.predictProba(X, nb.jointLogLikelihood)
+}
+
+// GetClasses returns the class values, in the order used by
+// PredictProba and GetWeights.
+func (nb *ComplementNBClassifier) GetClasses() []string {
+	return nb.classes
+}
+
+// GetWeights returns the weight of each feature, accessed as [c][f].
+func (nb *ComplementNBClassifier) GetWeights() [][]float64 {
+	return nb.weights
+}
+
+// partialFitCounts updates the class statistics with a batch of
+// training data and adds the features of each class to counts, which
+// is (re)allocated on the first call. It returns the updated counts.
+func (m *classModel) partialFitCounts(X base.FixedDataGrid, classes []string, opts priorOptions, counts *[][]float64) ([][]float64, error) {
+	first := !m.fitted
+	classIndex, features, err := m.partialFitClasses(X, classes, opts, checkCounts)
+	if err != nil {
+		return nil, err
+	}
+	batch := make([][]float64, len(m.classes))
+	for k := range batch {
+		batch[k] = make([]float64, len(m.attrs))
+	}
+	for i, row := range features {
+		for f, v := range row {
+			batch[classIndex[i]][f] += v
+		}
+	}
+	if first {
+		*counts = batch
+		return batch, nil
+	}
+	for k := range batch {
+		for f, c := range batch[k] {
+			(*counts)[k][f] += c
+		}
+	}
+	return *counts, nil
+}
+
+// checkCounts checks that every feature value is a valid count.
+func checkCounts(features [][]float64) error {
+	for i, row := range features {
+		for f, v := range row {
+			if v < 0 || math.IsNaN(v) {
+				return fmt.Errorf("Feature counts can't be negative (row %d, feature %d)", i, f)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package naive
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestMultinomialNB(t *testing.T) {
+	Convey("Given simple count data", t, func() {
+		trainingData, err := base.ParseCSVToInstances("test/simple_train.csv", false)
+		So(err, ShouldBeNil)
+		testData, err := base.ParseCSVToInstances("test/simple_test.csv", false)
+		So(err, ShouldBeNil)
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewMultinomialNBClassifier().Predict(testData)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("A MultinomialNBClassifier", func() {
+			nb := NewMultinomialNBClassifier()
+			So(nb.Fit(trainingData), ShouldBeNil)
+
+			Convey("Should estimate smoothed feature probabilities", func() {
+				So(nb.GetClasses(), ShouldResemble, []string{"blue", "red"})
+				logProb := nb.GetFeatureLogProb()
+				So(logProb[0][0], ShouldAlmostEqual, math.Log(111.0/122.0), 1e-9)
+				So(logProb[0][1], ShouldAlmostEqual, math.Log(10.0/122.0), 1e-9)
+				So(logProb[0][2], ShouldAlmostEqual, math.Log(1.0/122.0), 1e-9)
+				So(logProb[1][0], ShouldAlmostEqual, math.Log(683.0/789.0), 1e-9)
+				So(logProb[1][1], ShouldAlmostEqual, math.Log(1.0/789.0), 1e-9)
+				So(logProb[1][2], ShouldAlmostEqual, math.Log(105.0/789.0), 1e-9)
+			})
+
+			Convey("Should predict the test data correctly", func() {
+				predictions, err := nb.Predict(testData)
+				So(err, ShouldBeNil)
+				So(base.GetClass(predictions, 0), ShouldEqual, "blue")
+				So(base.GetClass(predictions, 1), ShouldEqual, "red")
+				So(base.GetClass(predictions, 2), ShouldEqual, "blue")
+				So(base.GetClass(predictions, 3), ShouldEqual, "red")
+			})
+		})
+
+		Convey("A ComplementNBClassifier", func() {
+			nb := NewComplementNBClassifier()
+			So(nb.Fit(trainingData), ShouldBeNil)
+
+			Convey("Should weight features by the complement class", func() {
+				w := nb.GetWeights()
+				So(w[0][1], ShouldAlmostEqual, -math.Log(1.0/789.0), 1e-9)
+				So(w[1][1], ShouldAlmostEqual, -math.Log(10.0/122.0), 1e-9)
+			})
+
+			Convey("Should predict the test data correctly", func() {
+				predictions, err := nb.Predict(testData)
+				So(err, ShouldBeNil)
+				So(base.GetClass(predictions, 0), ShouldEqual, "blue")
+				So(base.GetClass(predictions, 1), ShouldEqual, "red")
+				So(base.GetClass(predictions, 2), ShouldEqual, "blue")
+				So(base.GetClass(predictions, 3), ShouldEqual, "red")
+			})
+
+			Convey("Normalised weights should predict the same way", func() {
+				nb.Norm = true
+				So(nb.Fit(trainingData), ShouldBeNil)
+				predictions, err := nb.Predict(testData)
+				So(err, ShouldBeNil)
+				So(base.GetClass(predictions, 0), ShouldEqual, "blue")
+				So(base.GetClass(predictions, 1), ShouldEqual, "red")
+			})
+		})
+
+		Convey("Negative counts should be rejected", func() {
+			specs := base.ResolveAttributes(trainingData, base.NonClassFloatAttributes(trainingData))
+			trainingData.Set(specs[0], 0, base.PackFloatToBytes(-1))
+			So(NewMultinomialNBClassifier().Fit(trainingData), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,107 @@
+package naive
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+// splitRows divides the rows of inst into batches of the given size.
+func splitRows(inst base.FixedDataGrid, size int) []base.FixedDataGrid {
+	_, rows := inst.Size()
+	ret := make([]base.FixedDataGrid, 0)
+	for start := 0; start < rows; start += size {
+		batch := make([]int, 0, size)
+		for i := start; i < start+size && i < rows; i++ {
+			batch = append(batch, i)
+		}
+		ret = append(ret, base.NewInstancesViewFromVisible(inst, batch, inst.AllAttributes()))
+	}
+	return ret
+}
+
+func TestPartialFit(t *testing.T) {
+	Convey("Given the iris dataset split into batches", t, func() {
+		inst, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		// Shuffle so that the batches contain a mix of classes
+		inst = base.Shuffle(inst).(*base.DenseInstances)
+		batches := splitRows(inst, 40)
+		classes := []string{"Iris-setosa", "Iris-versicolor", "Iris-virginica"}
+
+		Convey("GaussianNB trained incrementally should match Fit", func() {
+			full := NewGaussianNBClassifier()
+			So(full.Fit(inst), ShouldBeNil)
+			online := NewGaussianNBClassifier()
+			for _, b := range batches {
+				So(online.PartialFit(b, classes), ShouldBeNil)
+			}
+			for k := range classes {
+				for f := range full.GetMeans()[k] {
+					So(online.GetMeans()[k][f], ShouldAlmostEqual, full.GetMeans()[k][f], 1e-9)
+					So(online.GetVariances()[k][f], ShouldAlmostEqual, full.GetVariances()[k][f], 1e-6)
+				}
+			}
+		})
+
+		Convey("MultinomialNB trained incrementally should match Fit", func() {
+			full := NewMultinomialNBClassifier()
+			So(full.Fit(inst), ShouldBeNil)
+			online := NewMultinomialNBClassifier()
+			for _, b := range batches {
+				So(online.PartialFit(b, classes), ShouldBeNil)
+			}
+			for k := range classes {
+				for f, v := range full.GetFeatureLogProb()[k] {
+					So(online.GetFeatureLogProb()[k][f], ShouldAlmostEqual, v, 1e-9)
+				}
+			}
+		})
+
+		Convey("MixedNB trained incrementally should match Fit", func() {
+			full := NewMixedNBClassifier()
+			So(full.Fit(inst), ShouldBeNil)
+			online := NewMixedNBClassifier()
+			for _, b := range batches {
+				So(online.PartialFit(b, classes), ShouldBeNil)
+			}
+			fullProbs, err := full.PredictProba(inst)
+			So(err, ShouldBeNil)
+			onlineProbs, err := online.PredictProba(inst)
+			So(err, ShouldBeNil)
+			specs := base.ResolveAttributes(fullProbs, fullProbs.AllAttributes())
+			for i := 0; i < 150; i += 10 {
+				for _, s := range specs {
+					So(base.UnpackBytesToFloat(onlineProbs.Get(s, i)), ShouldAlmostEqual, base.UnpackBytesToFloat(fullProbs.Get(s, i)), 1e-6)
+				}
+			}
+		})
+
+		Convey("The classes must be given on the first call", func() {
+			So(NewGaussianNBClassifier().PartialFit(batches[0], nil), ShouldNotBeNil)
+		})
+
+		Convey("Classes which weren't declared should be rejected", func() {
+			nb := NewGaussianNBClassifier()
+			So(nb.PartialFit(batches[0], []string{"Iris-setosa"}), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given simple binary data split into two batches", t, func() {
+		trainingData, err := base.ParseCSVToInstances("test/simple_train.csv", false)
+		So(err, ShouldBeNil)
+		binary := convertToBinary(trainingData)
+		batches := splitRows(binary, 2)
+
+		Convey("BernoulliNB trained incrementally should match Fit", func() {
+			full := NewBernoulliNBClassifier()
+			So(full.Fit(binary), ShouldBeNil)
+			online := NewBernoulliNBClassifier()
+			for _, b := range batches {
+				So(online.PartialFit(b, []string{"blue", "red"}), ShouldBeNil)
+			}
+			So(online.condProb, ShouldResemble, full.condProb)
+			So(online.classInstances, ShouldResemble, full.classInstances)
+		})
+	})
+}
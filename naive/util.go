@@ -0,0 +1,309 @@
+package naive
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/utilities"
+	"math"
+	"sort"
+)
+
+// classModel holds the parts shared by the Naive Bayes variants: the
+// classes seen during training, their (log) prior probabilities and
+// the Attributes used as features. Each
+// variant supplies the per-class log-likelihood of a row, and
+// classModel turns those into predictions and posteriors.
+type classModel struct {
+	// Class values, sorted
+	classes []string
+	// Number of training rows in each class
+	classCounts []float64
+	// log(p(C)) for each class
+	logPriors []float64
+	// Attributes used as features
+	attrs []base.Attribute
+	// Number of training rows
+	trainingInstances int
+	fitted            bool
+}
+
+// priorOptions determine how a classModel sets its class priors.
+type priorOptions struct {
+	// Class probabilities, or nil to estimate them from the class
+	// frequencies
+	priors map[string]float64
+	// Weight of each class when estimating the priors. Classes which
+	// aren't mentioned are weighted 1.
+	weights map[string]float64
+	// If true (and weights is nil), weight each class in inverse
+	// proportion to its frequency, which makes the priors uniform
+	balanced bool
+}
+
+// partialFitClasses is partialFitLabels for models which use every
+// non-class FloatAttribute as a feature. It also returns the feature
+// values of each row. If validate is non-nil, it's called on the
+// feature values before anything is updated.
+func (m *classModel) partialFitClasses(X base.FixedDataGrid, classes []string, opts priorOptions, validate func([][]float64) error) ([]int, [][]float64, error) {
+	attrs := m.attrs
+	if !m.fitted {
+		attrs = base.NonClassFloatAttributes(X)
+		if len(attrs) == 0 {
+			return nil, nil, fmt.Errorf("No FloatAttributes to fit")
+		}
+	}
+	features := floatFeatures(X, attrs)
+	if validate != nil {
+		if err := validate(features); err != nil {
+			return nil, nil, err
+		}
+	}
+	classIndex, err := m.partialFitLabels(X, attrs, classes, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return classIndex, features, nil
+}
+
+// partialFitLabels validates a batch of training data and updates the
+// class counts and priors with it. On the first call (before the model
+// has been fitted) it also records the given classes and feature
+// Attributes. The priors are set according to opts. It returns the
+// index into classes of each row's class.
+func (m *classModel) partialFitLabels(X base.FixedDataGrid, attrs []base.Attribute, classes []string, opts priorOptions) ([]int, error) {
+	classAttrs := X.AllClassAttributes()
+	if len(classAttrs) != 1 {
+		return nil, fmt.Errorf("Only one class Attribute can be used")
+	}
+	_, rows := X.Size()
+	if rows == 0 {
+		return nil, base.NoTrainingDataError
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	if !m.fitted {
+		if len(classes) == 0 {
+			return nil, fmt.Errorf("The class values must be given on the first call to PartialFit")
+		}
+		unique := make(map[string]bool)
+		m.classes = make([]string, 0, len(classes))
+		for _, c := range classes {
+			if !unique[c] {
+				unique[c] = true
+				m.classes = append(m.classes, c)
+			}
+		}
+		sort.Strings(m.classes)
+		m.classCounts = make([]float64, len(m.classes))
+		m.attrs = attrs
+		m.trainingInstances = 0
+	}
+	index := make(map[string]int)
+	for k, c := range m.classes {
+		index[c] = k
+	}
+
+	classIndex := make([]int, rows)
+	for i := range classIndex {
+		c := base.GetClass(X, i)
+		k, ok := index[c]
+		if !ok {
+			return nil, fmt.Errorf("Class %s wasn't in the list given to PartialFit", c)
+		}
+		classIndex[i] = k
+	}
+	for _, k := range classIndex {
+		m.classCounts[k]++
+	}
+	m.trainingInstances += rows
+	if err := m.setPriors(opts); err != nil {
+		return nil, err
+	}
+	return classIndex, nil
+}
+
+// classValues returns every distinct class value in X, or nil if X
+// doesn't have exactly one class Attribute.
+func classValues(X base.FixedDataGrid) []string {
+	if len(X.AllClassAttributes()) != 1 {
+		return nil
+	}
+	_, rows := X.Size()
+	seen := make(map[string]bool)
+	ret := make([]string, 0)
+	for i := 0; i < rows; i++ {
+		c := base.GetClass(X, i)
+		if !seen[c] {
+			seen[c] = true
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// validate checks that the options are consistent.
+func (o priorOptions) validate() error {
+	if o.priors != nil && (o.weights != nil || o.balanced) {
+		return fmt.Errorf("Class priors and class weights can't both be used")
+	}
+	for c, w := range o.weights {
+		if w <= 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return fmt.Errorf("Weight for class %s must be positive and finite", c)
+		}
+	}
+	return nil
+}
+
+// weight returns the weight of a class with the given number of
+// training rows.
+func (o priorOptions) weight(class string, count float64) float64 {
+	if o.weights != nil {
+		if w, ok := o.weights[class]; ok {
+			return w
+		}
+		return 1
+	}
+	if o.balanced && count > 0 {
+		return 1 / count
+	}
+	return 1
+}
+
+// setPriors computes logPriors from the given class probabilities, or
+// from the (weighted) class frequencies if there aren't any.
+func (m *classModel) setPriors(opts priorOptions) error {
+	m.logPriors = make([]float64, len(m.classes))
+	priors := opts.priors
+	if priors == nil {
+		total := 0.0
+		weighted := make([]float64, len(m.classCounts))
+		for k, c := range m.classCounts {
+			weighted[k] = opts.weight(m.classes[k], c) * c
+			total += weighted[k]
+		}
+		for k, c := range weighted {
+			m.logPriors[k] = math.Log(c / total)
+		}
+		return nil
+	}
+
+	sum := 0.0
+	for k, c := range m.classes {
+		p, ok := priors[c]
+		if !ok {
+			return fmt.Errorf("No prior given for class %s", c)
+		}
+		if p <= 0 {
+			return fmt.Errorf("Prior for class %s must be positive", c)
+		}
+		m.logPriors[k] = math.Log(p)
+		sum += p
+	}
+	if math.Abs(sum-1) > 1e-6 {
+		return fmt.Errorf("Class priors should sum to 1, got %.4f", sum)
+	}
+	return nil
+}
+
+// features returns the values of the fitted Attributes of X.
+func (m *classModel) features(X base.FixedDataGrid) [][]float64 {
+	return floatFeatures(X, m.attrs)
+}
+
+// floatFeatures returns the values of the given FloatAttributes of X.
+func floatFeatures(X base.FixedDataGrid, attrs []base.Attribute) [][]float64 {
+	_, rows := X.Size()
+	ret := make([][]float64, rows)
+	specs := base.ResolveAttributes(X, attrs)
+	X.MapOverRows(specs, func(row [][]byte, i int) (bool, error) {
+		ret[i] = make([]float64, len(row))
+		for j, v := range row {
+			ret[i][j] = base.UnpackBytesToFloat(v)
+		}
+		return true, nil
+	})
+	return ret
+}
+
+// jointLogLikelihood returns log(p(C)) + log(p(F1, F2... Fn|C)) for
+// every class, given a row of feature values.
+type jointLogLikelihood func(row []float64) []float64
+
+// rowLogLikelihood is the equivalent of jointLogLikelihood for models
+// which work on the raw values of their Attributes.
+type rowLogLikelihood func(row [][]byte, specs []base.AttributeSpec) []float64
+
+// fromFloats adapts a jointLogLikelihood to a rowLogLikelihood.
+func fromFloats(jll jointLogLikelihood) rowLogLikelihood {
+	return func(row [][]byte, specs []base.AttributeSpec) []float64 {
+		vals := make([]float64, len(row))
+		for j, v := range row {
+			vals[j] = base.UnpackBytesToFloat(v)
+		}
+		return jll(vals)
+	}
+}
+
+// predict returns the most probable class for each row of X.
+func (m *classModel) predict(X base.FixedDataGrid, jll jointLogLikelihood) (base.FixedDataGrid, error) {
+	return m.predictRows(X, fromFloats(jll))
+}
+
+// predictProba returns the posterior probability of each class for each
+// row of X, as one FloatAttribute per class value.
+func (m *classModel) predictProba(X base.FixedDataGrid, jll jointLogLikelihood) (base.FixedDataGrid, error) {
+	return m.predictProbaRows(X, fromFloats(jll))
+}
+
+// predictRows returns the most probable class for each row of X.
+func (m *classModel) predictRows(X base.FixedDataGrid, jll rowLogLikelihood) (base.FixedDataGrid, error) {
+	if !m.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	ret := base.GeneratePredictionVector(X)
+	specs := base.ResolveAttributes(X, m.attrs)
+	X.MapOverRows(specs, func(row [][]byte, i int) (bool, error) {
+		base.SetClass(ret, i, argmaxClass(m.classes, jll(row, specs)))
+		return true, nil
+	})
+	return ret, nil
+}
+
+// predictProbaRows returns the posterior probability of each class for
+// each row of X, as one FloatAttribute per class value.
+func (m *classModel) predictProbaRows(X base.FixedDataGrid, jll rowLogLikelihood) (base.FixedDataGrid, error) {
+	if !m.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	_, rows := X.Size()
+	ret := base.NewDenseInstances()
+	retSpecs := make([]base.AttributeSpec, len(m.classes))
+	for k, c := range m.classes {
+		retSpecs[k] = ret.AddAttribute(base.NewFloatAttribute(c))
+	}
+	ret.Extend(rows)
+	specs := base.ResolveAttributes(X, m.attrs)
+	X.MapOverRows(specs, func(row [][]byte, i int) (bool, error) {
+		scores := jll(row, specs)
+		norm := utilities.LogSumExp(scores)
+		for k, s := range scores {
+			ret.Set(retSpecs[k], i, base.PackFloatToBytes(math.Exp(s-norm)))
+		}
+		return true, nil
+	})
+	return ret, nil
+}
+
+// argmaxClass returns the class with the highest log-probability. Ties
+// are broken in favour of the class which appears first.
+func argmaxClass(classes []string, logProbs []float64) string {
+	best := 0
+	for k, v := range logProbs {
+		if v > logProbs[best] {
+			best = k
+		}
+	}
+	return classes[best]
+}
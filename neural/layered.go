@@ -18,15 +18,27 @@ import (
 // in a previous layer.
 //
 // Neurons can only be connected to neurons in the layer above.
+//
+// ClassWeights optionally scales the error of each training row by the
+// weight of its class; classes it doesn't mention are weighted 1. If
+// it's nil, the classes are weighted in inverse proportion to their
+// frequency when WeightClassesAutomatically is true, and equally
+// otherwise. Weighting requires a single class Attribute, and the
+// weights are scaled so that the average row is weighted 1.
 type MultiLayerNet struct {
-	network         *Network
-	attrs           map[base.Attribute]int
-	layers          []int
-	classAttrOffset int
-	classAttrCount  int
-	Convergence     float64
-	MaxIterations   int
-	LearningRate    float64
+	network                    *Network
+	attrs                      map[base.Attribute]int
+	trainingAttrs              map[string]int
+	outputAttrs                map[string]int
+	classAttr                  *base.CategoricalAttribute
+	layers                     []int
+	classAttrOffset            int
+	classAttrCount             int
+	Convergence                float64
+	MaxIterations              int
+	LearningRate               float64
+	ClassWeights               map[string]float64
+	WeightClassesAutomatically bool
 }
 
 // NewMultiLayerNet returns an underlying
@@ -38,12 +50,17 @@ func NewMultiLayerNet(layers []int) *MultiLayerNet {
 	return &MultiLayerNet{
 		nil,
 		make(map[base.Attribute]int),
+		nil,
+		nil,
+		nil,
 		layers,
 		0,
 		0,
 		0.001,
 		500,
 		0.90,
+		nil,
+		false,
 	}
 }
 
@@ -73,7 +90,7 @@ func (m *MultiLayerNet) convertToFloatInsts(X base.FixedDataGrid) base.FixedData
 func (m *MultiLayerNet) Predict(X base.FixedDataGrid) base.FixedDataGrid {
 
 	// Create the return vector
-	ret := base.GeneratePredictionVector(X)
+	ret := m.generatePredictionVector(X)
 
 	// Make sure everything's a FloatAttribute
 	insts := m.convertToFloatInsts(X)
@@ -156,16 +173,207 @@ func (m *MultiLayerNet) Predict(X base.FixedDataGrid) base.FixedDataGrid {
 
 }
 
+// generatePredictionVector returns somewhere to store the predicted
+// class of each row of X, using the network's own class Attribute if
+// PartialFit created one.
+func (m *MultiLayerNet) generatePredictionVector(X base.FixedDataGrid) base.UpdatableDataGrid {
+	if m.classAttr == nil {
+		return base.GeneratePredictionVector(X)
+	}
+	_, rows := X.Size()
+	ret := base.NewDenseInstances()
+	ret.AddAttribute(m.classAttr)
+	ret.AddClassAttribute(m.classAttr)
+	ret.Extend(rows)
+	return ret
+}
+
+// rowWeights returns the weight of each row of X given its class,
+// scaled so that their mean is 1, or nil if the classes are weighted
+// equally.
+func (m *MultiLayerNet) rowWeights(X base.FixedDataGrid) ([]float64, error) {
+	if m.ClassWeights == nil && !m.WeightClassesAutomatically {
+		return nil, nil
+	}
+	if len(X.AllClassAttributes()) != 1 {
+		return nil, fmt.Errorf("Class weights need exactly one class Attribute")
+	}
+	weights, err := base.GetClassWeights(X, m.ClassWeights, m.WeightClassesAutomatically)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := X.Size()
+	ret := make([]float64, rows)
+	total := 0.0
+	for i := range ret {
+		ret[i] = weights[base.GetClass(X, i)]
+		total += ret[i]
+	}
+	for i := range ret {
+		ret[i] *= float64(rows) / total
+	}
+	return ret, nil
+}
+
 // Fit trains the neural network on the given fixed datagrid.
 //
 // Training stops when the mean-squared error acheived is less
 // than the Convergence value, or when back-propagation has occured
 // more times than the value set by MaxIterations.
-func (m *MultiLayerNet) Fit(X base.FixedDataGrid) {
+//
+// Fit returns an error, without changing the network, if the class
+// weights are invalid.
+func (m *MultiLayerNet) Fit(X base.FixedDataGrid) error {
+
+	weights, err := m.rowWeights(X)
+	if err != nil {
+		return err
+	}
+
+	// Make sure everything's a FloatAttribute
+	insts := m.convertToFloatInsts(X)
+
+	// Build the network
+	m.classAttr = nil
+	m.initialise(insts)
+
+	// Feed-forward, compute error and update for each training example
+	// until convergence (what's that)
+	for iteration := 0; iteration < m.MaxIterations; iteration++ {
+		totalError := m.trainEpoch(insts, weights)
+		// If we've converged, no need to carry on
+		if totalError < m.Convergence {
+			break
+		}
+	}
+	return nil
+}
+
+// PartialFit makes a single back-propagation pass over a batch of
+// training data, building the network first if needed.
+//
+// The network's shape is fixed by the first batch, so for a
+// CategoricalAttribute class the network keeps its own copy of the
+// class Attribute, with the class values given on the first call added
+// so that every class has an output node. Subsequent batches must have
+// the same Attributes, and their CategoricalAttribute class must list
+// its values in the same order as the first.
+func (m *MultiLayerNet) PartialFit(X base.FixedDataGrid, classes []string) error {
+	classAttrs := X.AllClassAttributes()
+	if len(classAttrs) == 0 {
+		return fmt.Errorf("No class Attributes")
+	}
+	weights, err := m.rowWeights(X)
+	if err != nil {
+		return err
+	}
+	if m.network == nil {
+		m.classAttr = nil
+		for _, a := range classAttrs {
+			c, ok := a.(*base.CategoricalAttribute)
+			if !ok {
+				continue
+			}
+			if m.classAttr != nil {
+				return fmt.Errorf("Can't train more than one categorical class Attribute")
+			}
+			if len(classes) == 0 {
+				return fmt.Errorf("The class values must be given on the first call to PartialFit")
+			}
+			m.classAttr = base.NewCategoricalAttribute()
+			m.classAttr.SetName(c.GetName())
+			for _, v := range c.GetValues() {
+				m.classAttr.GetSysValFromString(v)
+			}
+			for _, v := range classes {
+				m.classAttr.GetSysValFromString(v)
+			}
+		}
+	}
+	if m.classAttr != nil {
+		if X, err = m.withClassAttr(X); err != nil {
+			return err
+		}
+	}
 
 	// Make sure everything's a FloatAttribute
 	insts := m.convertToFloatInsts(X)
 
+	if m.network == nil {
+		m.initialise(insts)
+	}
+
+	// Check the batch matches the network
+	for _, a := range base.NonClassAttributes(insts) {
+		if _, ok := m.trainingAttrs[a.GetName()]; !ok {
+			return fmt.Errorf("Attribute %s wasn't in the first batch", a.GetName())
+		}
+	}
+	for _, a := range insts.AllClassAttributes() {
+		if _, ok := m.outputAttrs[a.GetName()]; !ok {
+			return fmt.Errorf("Class Attribute %s wasn't in the first batch", a.GetName())
+		}
+	}
+
+	m.trainEpoch(insts, weights)
+	return nil
+}
+
+// withClassAttr returns a copy of X whose CategoricalAttribute class
+// is replaced by the network's own class Attribute.
+func (m *MultiLayerNet) withClassAttr(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	var cls base.Attribute
+	for _, a := range X.AllClassAttributes() {
+		if c, ok := a.(*base.CategoricalAttribute); ok {
+			known := m.classAttr.GetValues()
+			values := c.GetValues()
+			if len(values) > len(known) {
+				return nil, fmt.Errorf("Class Attribute %s has values which weren't given on the first call to PartialFit", c.GetName())
+			}
+			for i, v := range values {
+				if known[i] != v {
+					return nil, fmt.Errorf("Class Attribute %s must list its values in the same order as the first batch", c.GetName())
+				}
+			}
+			cls = a
+		}
+	}
+	if cls == nil {
+		return nil, fmt.Errorf("Class Attribute %s wasn't in the batch", m.classAttr.GetName())
+	}
+
+	ret := base.NewDenseInstances()
+	attrs := X.AllAttributes()
+	specs := make([]base.AttributeSpec, len(attrs))
+	for i, a := range attrs {
+		if a == cls {
+			a = m.classAttr
+		}
+		specs[i] = ret.AddAttribute(a)
+	}
+	for _, a := range X.AllClassAttributes() {
+		if a == cls {
+			a = m.classAttr
+		}
+		if err := ret.AddClassAttribute(a); err != nil {
+			return nil, err
+		}
+	}
+	_, rows := X.Size()
+	ret.Extend(rows)
+	err := X.MapOverRows(base.ResolveAttributes(X, attrs), func(row [][]byte, rowNo int) (bool, error) {
+		for i, v := range row {
+			ret.Set(specs[i], rowNo, v)
+		}
+		return true, nil
+	})
+	return ret, err
+}
+
+// initialise creates the underlying Network for the (converted)
+// training data insts, with small random weights.
+func (m *MultiLayerNet) initialise(insts base.FixedDataGrid) {
+
 	// The size of the first layer is the number of things
 	// in the revised instances which aren't class Attributes
 	inputAttrsVec := base.NonClassAttributes(insts)
@@ -174,10 +382,6 @@ func (m *MultiLayerNet) Fit(X base.FixedDataGrid) {
 	// in the revised instances which are class Attributes
 	classAttrsVec := insts.AllClassAttributes()
 
-	// The total number of layers is input layer + output layer
-	// plus number of layers specified
-	totalLayers := 2 + len(m.layers)
-
 	// The size is then augmented by the number of nodes
 	// in the centre
 	size := len(inputAttrsVec)
@@ -188,20 +392,22 @@ func (m *MultiLayerNet) Fit(X base.FixedDataGrid) {
 		hiddenSize += a
 	}
 
-	// Enumerate the Attributes
-	trainingAttrs := make(map[base.Attribute]int)
-	classAttrs := make(map[base.Attribute]int)
+	// Enumerate the Attributes, forgetting any from an earlier network
+	m.attrs = make(map[base.Attribute]int)
+	m.trainingAttrs = make(map[string]int)
+	m.outputAttrs = make(map[string]int)
+	m.classAttrCount = 0
 	attrCounter := 0
 	for i, a := range inputAttrsVec {
 		attrCounter = i
 		m.attrs[a] = attrCounter
-		trainingAttrs[a] = attrCounter
+		m.trainingAttrs[a.GetName()] = attrCounter
 	}
 	m.classAttrOffset = attrCounter + 1
 	for _, a := range classAttrsVec {
 		attrCounter++
 		m.attrs[a] = attrCounter + hiddenSize
-		classAttrs[a] = attrCounter + hiddenSize
+		m.outputAttrs[a.GetName()] = attrCounter + hiddenSize
 		m.classAttrCount++
 	}
 
@@ -276,6 +482,18 @@ func (m *MultiLayerNet) Fit(X base.FixedDataGrid) {
 		}
 	}
 
+}
+
+// trainEpoch back-propagates every row of insts through the network
+// once, returning the average error. If weights is non-nil, each row's
+// error is scaled by its weight.
+func (m *MultiLayerNet) trainEpoch(insts base.FixedDataGrid, weights []float64) float64 {
+
+	// The total number of layers is input layer + output layer
+	// plus number of layers specified
+	totalLayers := 2 + len(m.layers)
+	size := m.network.size
+
 	// Create the training activation vector
 	trainVec := mat64.NewDense(size, 1, make([]float64, size))
 	// Create the error vector
@@ -284,62 +502,58 @@ func (m *MultiLayerNet) Fit(X base.FixedDataGrid) {
 	// Resolve training AttributeSpecs
 	trainAs := base.ResolveAllAttributes(insts)
 
-	// Feed-forward, compute error and update for each training example
-	// until convergence (what's that)
-	for iteration := 0; iteration < m.MaxIterations; iteration++ {
-		totalError := 0.0
-		maxRow := 0
-		insts.MapOverRows(trainAs, func(row [][]byte, i int) (bool, error) {
-
-			maxRow = i
-			// Clear vectors
-			for i := 0; i < size; i++ {
-				trainVec.Set(i, 0, 0.0)
-				errVec.Set(i, 0, 0.0)
-			}
+	totalError := 0.0
+	maxRow := 0
+	insts.MapOverRows(trainAs, func(row [][]byte, rowNo int) (bool, error) {
 
-			// Build vectors
-			for i, vb := range row {
-				v := base.UnpackBytesToFloat(vb)
-				if attrIndex, ok := trainingAttrs[trainAs[i].GetAttribute()]; ok {
-					// Add to Activation vector
-					trainVec.Set(attrIndex, 0, v)
-				} else if attrIndex, ok := classAttrs[trainAs[i].GetAttribute()]; ok {
-					// Set to error vector
-					errVec.Set(attrIndex, 0, v)
-				} else {
-					panic("Should be able to find this Attribute!")
-				}
+		maxRow = rowNo
+		// Clear vectors
+		for i := 0; i < size; i++ {
+			trainVec.Set(i, 0, 0.0)
+			errVec.Set(i, 0, 0.0)
+		}
+
+		// Build vectors
+		for i, vb := range row {
+			v := base.UnpackBytesToFloat(vb)
+			name := trainAs[i].GetAttribute().GetName()
+			if attrIndex, ok := m.trainingAttrs[name]; ok {
+				// Add to Activation vector
+				trainVec.Set(attrIndex, 0, v)
+			} else if attrIndex, ok := m.outputAttrs[name]; ok {
+				// Set to error vector
+				errVec.Set(attrIndex, 0, v)
+			} else {
+				panic("Should be able to find this Attribute!")
 			}
+		}
 
-			// Activate the network
-			m.network.Activate(trainVec, totalLayers-1)
+		// Activate the network
+		m.network.Activate(trainVec, totalLayers-1)
 
-			// Compute the error
-			for a := range classAttrs {
-				cIndex := classAttrs[a]
-				errVec.Set(cIndex, 0, errVec.At(cIndex, 0)-trainVec.At(cIndex, 0))
-			}
+		// Compute the error
+		weight := 1.0
+		if weights != nil {
+			weight = weights[rowNo]
+		}
+		for _, cIndex := range m.outputAttrs {
+			errVec.Set(cIndex, 0, weight*(errVec.At(cIndex, 0)-trainVec.At(cIndex, 0)))
+		}
 
-			// Update total error
-			totalError += math.Abs(errVec.Sum())
+		// Update total error
+		totalError += math.Abs(errVec.Sum())
 
-			// Back-propagate the error
-			b := m.network.Error(trainVec, errVec, totalLayers)
+		// Back-propagate the error
+		b := m.network.Error(trainVec, errVec, totalLayers)
 
-			// Update the weights
-			m.network.UpdateWeights(trainVec, b, m.LearningRate)
+		// Update the weights
+		m.network.UpdateWeights(trainVec, b, m.LearningRate)
 
-			// Update the biases
-			m.network.UpdateBias(b, m.LearningRate)
+		// Update the biases
+		m.network.UpdateBias(b, m.LearningRate)
 
-			return true, nil
-		})
+		return true, nil
+	})
 
-		totalError /= float64(maxRow)
-		// If we've converged, no need to carry on
-		if totalError < m.Convergence {
-			break
-		}
-	}
+	return totalError / float64(maxRow)
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/gonum/matrix/mat64"
 	"github.com/sjwhitworth/golearn/base"
 	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
 	"testing"
 )
 
@@ -155,3 +156,181 @@ func TestLayeredXOR(t *testing.T) {
 	})
 
 }
+
+func TestLayeredPartialFit(t *testing.T) {
+
+	Convey("Given an OR dataset...", t, func() {
+		inst := base.NewDenseInstances()
+		specs := make([]base.AttributeSpec, 3)
+		for i, name := range []string{"a", "b", "y"} {
+			specs[i] = inst.AddAttribute(base.NewFloatAttribute(name))
+		}
+		inst.AddClassAttribute(specs[2].GetAttribute())
+		inst.Extend(4)
+		for i, row := range [][]float64{{0, 0, 0}, {0, 1, 1}, {1, 0, 1}, {1, 1, 1}} {
+			for j, v := range row {
+				inst.Set(specs[j], i, base.PackFloatToBytes(v))
+			}
+		}
+
+		Convey("Training one pass at a time with PartialFit should learn it", func() {
+			net := NewMultiLayerNet([]int{3})
+			for i := 0; i < 2000; i++ {
+				So(net.PartialFit(inst, nil), ShouldBeNil)
+			}
+
+			pred := net.Predict(inst)
+			spec := base.ResolveAttributes(pred, pred.AllClassAttributes())[0]
+			So(base.UnpackBytesToFloat(pred.Get(spec, 0)), ShouldBeLessThan, 0.5)
+			for i := 1; i < 4; i++ {
+				So(base.UnpackBytesToFloat(pred.Get(spec, i)), ShouldBeGreaterThan, 0.5)
+			}
+		})
+	})
+}
+
+func TestLayeredPartialFitClasses(t *testing.T) {
+
+	Convey("Given a batch which doesn't contain every class...", t, func() {
+		inst := base.NewDenseInstances()
+		x := base.NewFloatAttribute("x")
+		cls := base.NewCategoricalAttribute()
+		cls.SetName("class")
+		xSpec := inst.AddAttribute(x)
+		clsSpec := inst.AddAttribute(cls)
+		inst.AddClassAttribute(cls)
+		inst.Extend(4)
+		for i, v := range []string{"a", "b", "a", "b"} {
+			inst.Set(xSpec, i, base.PackFloatToBytes(float64(i)))
+			inst.Set(clsSpec, i, cls.GetSysValFromString(v))
+		}
+
+		Convey("PartialFit should leave the batch's class Attribute alone", func() {
+			net := NewMultiLayerNet([]int{2})
+			So(net.PartialFit(inst, []string{"a", "b", "c"}), ShouldBeNil)
+			So(cls.GetValues(), ShouldResemble, []string{"a", "b"})
+			So(net.PartialFit(inst, nil), ShouldBeNil)
+
+			Convey("But predict any of the classes it was given", func() {
+				pred := net.Predict(inst)
+				predCls := pred.AllClassAttributes()[0].(*base.CategoricalAttribute)
+				So(predCls.GetValues(), ShouldResemble, []string{"a", "b", "c"})
+				So(cls.GetValues(), ShouldResemble, []string{"a", "b"})
+			})
+
+			Convey("And reject batches whose class values are in another order", func() {
+				other := base.NewCategoricalAttribute()
+				other.SetName("class")
+				other.GetSysValFromString("b")
+				batch := base.NewDenseInstances()
+				otherXSpec := batch.AddAttribute(x)
+				otherSpec := batch.AddAttribute(other)
+				batch.AddClassAttribute(other)
+				batch.Extend(1)
+				batch.Set(otherXSpec, 0, base.PackFloatToBytes(1))
+				batch.Set(otherSpec, 0, other.GetSysValFromString("b"))
+				So(net.PartialFit(batch, nil), ShouldNotBeNil)
+			})
+		})
+
+		// predictsKnownClasses checks that every prediction comes from
+		// the output layer, which only has nodes for a and b after Fit
+		predictsKnownClasses := func(net *MultiLayerNet) {
+			pred := net.Predict(inst)
+			for i := 0; i < 4; i++ {
+				So(base.GetClass(pred, i), ShouldBeIn, []string{"a", "b"})
+			}
+		}
+
+		Convey("Fitting twice should rebuild the output layer", func() {
+			once := NewMultiLayerNet([]int{2})
+			So(once.Fit(inst), ShouldBeNil)
+			net := NewMultiLayerNet([]int{2})
+			So(net.Fit(inst), ShouldBeNil)
+			So(net.Fit(inst), ShouldBeNil)
+			So(net.classAttrCount, ShouldEqual, once.classAttrCount)
+			So(len(net.attrs), ShouldEqual, len(once.attrs))
+			predictsKnownClasses(net)
+		})
+
+		Convey("Fitting after PartialFit should rebuild the output layer", func() {
+			once := NewMultiLayerNet([]int{2})
+			So(once.Fit(inst), ShouldBeNil)
+			net := NewMultiLayerNet([]int{2})
+			So(net.PartialFit(inst, []string{"a", "b", "c"}), ShouldBeNil)
+			So(net.Fit(inst), ShouldBeNil)
+			So(net.classAttrCount, ShouldEqual, once.classAttrCount)
+			So(len(net.attrs), ShouldEqual, len(once.attrs))
+			predictsKnownClasses(net)
+		})
+	})
+}
+
+func TestLayeredClassWeights(t *testing.T) {
+
+	Convey("Given data where one input is usually 0 but sometimes 1...", t, func() {
+		rand.Seed(42)
+		inst := base.NewDenseInstances()
+		x := base.NewFloatAttribute("x")
+		y := base.NewFloatAttribute("y")
+		xSpec := inst.AddAttribute(x)
+		ySpec := inst.AddAttribute(y)
+		inst.AddClassAttribute(y)
+		inst.Extend(20)
+		for i := 0; i < 20; i++ {
+			xVal, yVal := 0.0, 0.0
+			if i >= 10 {
+				xVal = 1
+			}
+			if i == 19 {
+				yVal = 1
+			}
+			inst.Set(xSpec, i, base.PackFloatToBytes(xVal))
+			inst.Set(ySpec, i, base.PackFloatToBytes(yVal))
+		}
+		rare := base.GetClass(inst, 19)
+		output := func(net *MultiLayerNet) float64 {
+			pred := net.Predict(inst)
+			return base.UnpackBytesToFloat(pred.Get(base.ResolveAttributes(pred, pred.AllClassAttributes())[0], 19))
+		}
+
+		Convey("The row weights should be balanced and average 1", func() {
+			net := NewMultiLayerNet([]int{})
+			weights, err := net.rowWeights(inst)
+			So(err, ShouldBeNil)
+			So(weights, ShouldBeNil)
+
+			net.WeightClassesAutomatically = true
+			weights, err = net.rowWeights(inst)
+			So(err, ShouldBeNil)
+			So(weights[19], ShouldAlmostEqual, 19*weights[0])
+			total := 0.0
+			for _, w := range weights {
+				total += w
+			}
+			So(total, ShouldAlmostEqual, 20)
+
+			net.ClassWeights = map[string]float64{rare: -1}
+			_, err = net.rowWeights(inst)
+			So(err, ShouldNotBeNil)
+			So(net.Fit(inst), ShouldNotBeNil)
+			So(net.PartialFit(inst, nil), ShouldNotBeNil)
+			So(net.network, ShouldBeNil)
+		})
+
+		Convey("Without weights, the output for x = 1 should be near 0", func() {
+			net := NewMultiLayerNet([]int{})
+			net.MaxIterations = 2000
+			net.Fit(inst)
+			So(output(net), ShouldBeLessThan, 0.3)
+		})
+
+		Convey("Weighting the rare class should raise it", func() {
+			net := NewMultiLayerNet([]int{})
+			net.MaxIterations = 2000
+			net.WeightClassesAutomatically = true
+			net.Fit(inst)
+			So(output(net), ShouldBeGreaterThan, 0.5)
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package sampling
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+	"math/rand"
+)
+
+// RandomOverSampler balances the classes by repeating randomly chosen
+// rows of the smaller ones.
+type RandomOverSampler struct {
+	// Each class is given at least Ratio times as many rows as the
+	// largest class. Must be greater than 0 and at most 1.
+	Ratio float64
+}
+
+// NewRandomOverSampler returns a RandomOverSampler which makes every
+// class as large as the largest.
+func NewRandomOverSampler() *RandomOverSampler {
+	return &RandomOverSampler{Ratio: 1}
+}
+
+// String returns a human-readable summary.
+func (r *RandomOverSampler) String() string {
+	return fmt.Sprintf("RandomOverSampler(ratio: %g)", r.Ratio)
+}
+
+// Resample returns a view of X with every row, followed by the repeated
+// rows.
+func (r *RandomOverSampler) Resample(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if err := checkRatio(r.Ratio); err != nil {
+		return nil, err
+	}
+	classes, members, err := classRows(X)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := X.Size()
+	ret := make([]int, rows)
+	for i := range ret {
+		ret[i] = i
+	}
+	target := overSampleTarget(classes, members, r.Ratio)
+	for _, c := range classes {
+		m := members[c]
+		for n := len(m); n < target; n++ {
+			ret = append(ret, m[rand.Intn(len(m))])
+		}
+	}
+	return base.NewInstancesViewFromVisible(X, ret, X.AllAttributes()), nil
+}
+
+// RandomUnderSampler balances the classes by keeping a random subset of
+// the rows of the larger ones.
+type RandomUnderSampler struct {
+	// Each class is left with at most 1/Ratio times as many rows as
+	// the smallest class. Must be greater than 0 and at most 1.
+	Ratio float64
+}
+
+// NewRandomUnderSampler returns a RandomUnderSampler which makes every
+// class as small as the smallest.
+func NewRandomUnderSampler() *RandomUnderSampler {
+	return &RandomUnderSampler{Ratio: 1}
+}
+
+// String returns a human-readable summary.
+func (r *RandomUnderSampler) String() string {
+	return fmt.Sprintf("RandomUnderSampler(ratio: %g)", r.Ratio)
+}
+
+// Resample returns a view of the rows of X which were kept, in their
+// original order.
+func (r *RandomUnderSampler) Resample(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if err := checkRatio(r.Ratio); err != nil {
+		return nil, err
+	}
+	classes, members, err := classRows(X)
+	if err != nil {
+		return nil, err
+	}
+	smallest := -1
+	for _, c := range classes {
+		if smallest < 0 || len(members[c]) < smallest {
+			smallest = len(members[c])
+		}
+	}
+	target := int(math.Floor(float64(smallest)/r.Ratio + 0.5))
+
+	_, rows := X.Size()
+	keep := make([]bool, rows)
+	for _, c := range classes {
+		m := members[c]
+		if len(m) <= target {
+			for _, i := range m {
+				keep[i] = true
+			}
+			continue
+		}
+		for _, j := range rand.Perm(len(m))[:target] {
+			keep[m[j]] = true
+		}
+	}
+	ret := make([]int, 0, rows)
+	for i, k := range keep {
+		if k {
+			ret = append(ret, i)
+		}
+	}
+	return base.NewInstancesViewFromVisible(X, ret, X.AllAttributes()), nil
+}
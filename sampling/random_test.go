@@ -0,0 +1,97 @@
+package sampling
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+// imbalancedData returns rows with FloatAttributes x and y, and a
+// CategoricalAttribute colour. The first major rows are "common" and
+// lie around (0, 0) while the rest are "rare" and lie around (3, 3).
+// Rare rows are red, and common ones blue.
+func imbalancedData(major, minor int) *base.DenseInstances {
+	inst := base.NewDenseInstances()
+	xSpec := inst.AddAttribute(base.NewFloatAttribute("x"))
+	ySpec := inst.AddAttribute(base.NewFloatAttribute("y"))
+	colour := base.NewCategoricalAttribute()
+	colour.SetName("colour")
+	colourSpec := inst.AddAttribute(colour)
+	class := base.NewCategoricalAttribute()
+	class.SetName("class")
+	classSpec := inst.AddAttribute(class)
+	inst.AddClassAttribute(class)
+	inst.Extend(major + minor)
+	for i := 0; i < major+minor; i++ {
+		centre, c, col := 0.0, "common", "blue"
+		if i >= major {
+			centre, c, col = 3, "rare", "red"
+		}
+		inst.Set(xSpec, i, base.PackFloatToBytes(centre+rand.NormFloat64()*0.5))
+		inst.Set(ySpec, i, base.PackFloatToBytes(centre+rand.NormFloat64()*0.5))
+		inst.Set(colourSpec, i, colour.GetSysValFromString(col))
+		inst.Set(classSpec, i, class.GetSysValFromString(c))
+	}
+	return inst
+}
+
+func TestRandomSamplers(t *testing.T) {
+	Convey("Given an imbalanced dataset", t, func() {
+		rand.Seed(42)
+		inst := imbalancedData(90, 10)
+
+		Convey("Over-sampling should grow the rare class", func() {
+			ret, err := NewRandomOverSampler().Resample(inst)
+			So(err, ShouldBeNil)
+			dist := base.GetClassDistribution(ret)
+			So(dist["common"], ShouldEqual, 90)
+			So(dist["rare"], ShouldEqual, 90)
+			So(len(ret.AllClassAttributes()), ShouldEqual, 1)
+
+			Convey("Keeping the original rows first", func() {
+				for i := 0; i < 100; i++ {
+					So(ret.RowString(i), ShouldEqual, inst.RowString(i))
+				}
+			})
+
+			Convey("And only repeating rare rows", func() {
+				_, rows := ret.Size()
+				for i := 100; i < rows; i++ {
+					So(base.GetClass(ret, i), ShouldEqual, "rare")
+				}
+			})
+		})
+
+		Convey("Over-sampling with a ratio should stop short", func() {
+			s := NewRandomOverSampler()
+			s.Ratio = 0.5
+			ret, err := s.Resample(inst)
+			So(err, ShouldBeNil)
+			So(base.GetClassDistribution(ret)["rare"], ShouldEqual, 45)
+		})
+
+		Convey("Under-sampling should shrink the common class", func() {
+			ret, err := NewRandomUnderSampler().Resample(inst)
+			So(err, ShouldBeNil)
+			dist := base.GetClassDistribution(ret)
+			So(dist["common"], ShouldEqual, 10)
+			So(dist["rare"], ShouldEqual, 10)
+
+			s := NewRandomUnderSampler()
+			s.Ratio = 0.25
+			ret, err = s.Resample(inst)
+			So(err, ShouldBeNil)
+			dist = base.GetClassDistribution(ret)
+			So(dist["common"], ShouldEqual, 40)
+			So(dist["rare"], ShouldEqual, 10)
+		})
+
+		Convey("Invalid ratios should be rejected", func() {
+			_, err := (&RandomOverSampler{Ratio: 0}).Resample(inst)
+			So(err, ShouldNotBeNil)
+			_, err = (&RandomUnderSampler{Ratio: 1.5}).Resample(inst)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
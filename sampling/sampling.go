@@ -0,0 +1,102 @@
+// Package sampling rebalances datasets whose classes are very
+// different sizes (such as fraud or churn data, where the interesting
+// class is rare) by adding or removing rows.
+//
+// RandomOverSampler:
+//
+//	Repeats randomly chosen rows of the smaller classes.
+//
+// RandomUnderSampler:
+//
+//	Keeps a random subset of the rows of the larger classes.
+//
+// SMOTE:
+//
+//	Adds synthetic rows to the smaller classes, interpolated between
+//	existing rows and their nearest neighbours of the same class.
+//
+// TomekLinks:
+//
+//	Removes rows which are each other's nearest neighbour but belong
+//	to different classes, which cleans up the boundary between them.
+//
+// Each implements Resampler, and returns a new FixedDataGrid with the
+// same Attributes as its input. Only the training data should be
+// resampled: the test data should keep its natural class balance.
+// Nearest neighbours are found using the Euclidean distance between
+// the non-class FloatAttributes.
+package sampling
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+	"sort"
+)
+
+// Resampler implementations add or remove rows of a FixedDataGrid
+// with exactly one class Attribute.
+type Resampler interface {
+	Resample(base.FixedDataGrid) (base.FixedDataGrid, error)
+}
+
+// classRows returns the class values of X in sorted order, along with
+// the rows which belong to each.
+func classRows(X base.FixedDataGrid) ([]string, map[string][]int, error) {
+	if len(X.AllClassAttributes()) != 1 {
+		return nil, nil, fmt.Errorf("Exactly one class Attribute is needed")
+	}
+	_, rows := X.Size()
+	if rows == 0 {
+		return nil, nil, fmt.Errorf("No rows to resample")
+	}
+	members := make(map[string][]int)
+	for i := 0; i < rows; i++ {
+		c := base.GetClass(X, i)
+		members[c] = append(members[c], i)
+	}
+	classes := make([]string, 0, len(members))
+	for c := range members {
+		classes = append(classes, c)
+	}
+	sort.Strings(classes)
+	return classes, members, nil
+}
+
+// checkRatio returns an error if ratio isn't in (0, 1].
+func checkRatio(ratio float64) error {
+	if !(ratio > 0 && ratio <= 1) {
+		return fmt.Errorf("Ratio must be greater than 0 and at most 1")
+	}
+	return nil
+}
+
+// overSampleTarget returns the number of rows each class should have
+// after over-sampling: ratio times the size of the largest class.
+func overSampleTarget(classes []string, members map[string][]int, ratio float64) int {
+	largest := 0
+	for _, c := range classes {
+		if len(members[c]) > largest {
+			largest = len(members[c])
+		}
+	}
+	return int(math.Floor(ratio*float64(largest) + 0.5))
+}
+
+// floatRows returns the non-class FloatAttributes of each row of X.
+func floatRows(X base.FixedDataGrid) ([]base.Attribute, [][]float64, error) {
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return nil, nil, fmt.Errorf("No FloatAttributes to measure distances with")
+	}
+	values, err := base.CopyFloatRows(X, attrs)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, rows := X.Size()
+	ret := make([][]float64, rows)
+	for i := range ret {
+		ret[i] = values[i*len(attrs) : (i+1)*len(attrs)]
+	}
+	return attrs, ret, nil
+}
@@ -0,0 +1,144 @@
+package sampling
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/knn"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	"math/rand"
+)
+
+// SMOTE (Synthetic Minority Over-sampling TEchnique) balances the
+// classes by adding synthetic rows to the smaller ones. Each synthetic
+// row lies at a random point on the line between a randomly chosen row
+// and one of its K nearest neighbours from the same class, which
+// spreads the class out rather than repeating rows. The non-class
+// FloatAttributes are interpolated, and every other Attribute is copied
+// from the chosen row.
+//
+// For more information:
+//
+// N. V. Chawla, K. W. Bowyer, L. O. Hall and W. P. Kegelmeyer (2002).
+// SMOTE: Synthetic Minority Over-sampling Technique. Journal of
+// Artificial Intelligence Research, 16, pp. 321-357.
+type SMOTE struct {
+	// Number of nearest neighbours to interpolate towards. Classes
+	// with K rows or fewer use all of the others.
+	K int
+	// Each class is given at least Ratio times as many rows as the
+	// largest class. Must be greater than 0 and at most 1.
+	Ratio float64
+}
+
+// NewSMOTE returns a SMOTE which uses 5 neighbours and makes every class
+// as large as the largest.
+func NewSMOTE() *SMOTE {
+	return &SMOTE{K: 5, Ratio: 1}
+}
+
+// String returns a human-readable summary.
+func (s *SMOTE) String() string {
+	return fmt.Sprintf("SMOTE(k: %d, ratio: %g)", s.K, s.Ratio)
+}
+
+// nearest returns the k rows of index (other than row) closest to
+// values[row], as indices into candidates, which index was built over.
+func nearest(index knn.NeighbourIndex, values [][]float64, row int, candidates []int, k int) []int {
+	found, _ := index.Query(values[row], k+1)
+	ret := make([]int, 0, k)
+	for _, i := range found {
+		if candidates[i] != row && len(ret) < k {
+			ret = append(ret, candidates[i])
+		}
+	}
+	return ret
+}
+
+// Resample returns a new DenseInstances with every row of X, followed
+// by the synthetic rows.
+func (s *SMOTE) Resample(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if s.K < 1 {
+		return nil, fmt.Errorf("K must be at least 1")
+	}
+	if err := checkRatio(s.Ratio); err != nil {
+		return nil, err
+	}
+	classes, members, err := classRows(X)
+	if err != nil {
+		return nil, err
+	}
+	floatAttrs, values, err := floatRows(X)
+	if err != nil {
+		return nil, err
+	}
+
+	// Choose each synthetic row's source row, neighbour and position
+	type synthetic struct {
+		from, towards int
+		gap           float64
+	}
+	extra := make([]synthetic, 0)
+	target := overSampleTarget(classes, members, s.Ratio)
+	for _, c := range classes {
+		m := members[c]
+		if len(m) >= target {
+			continue
+		}
+		if len(m) < 2 {
+			return nil, fmt.Errorf("Class %s needs at least 2 rows to interpolate between", c)
+		}
+		classValues := make([][]float64, len(m))
+		for i, r := range m {
+			classValues[i] = values[r]
+		}
+		index := knn.NewBruteForceIndex(classValues, pairwise.NewEuclidean())
+		neighbours := make(map[int][]int)
+		for n := len(m); n < target; n++ {
+			from := m[rand.Intn(len(m))]
+			if _, ok := neighbours[from]; !ok {
+				neighbours[from] = nearest(index, values, from, m, s.K)
+			}
+			nn := neighbours[from]
+			extra = append(extra, synthetic{from, nn[rand.Intn(len(nn))], rand.Float64()})
+		}
+	}
+
+	// Copy the original rows
+	ret := base.NewDenseInstances()
+	attrs := X.AllAttributes()
+	retSpecs := make([]base.AttributeSpec, len(attrs))
+	for i, a := range attrs {
+		retSpecs[i] = ret.AddAttribute(a)
+	}
+	for _, a := range X.AllClassAttributes() {
+		if err := ret.AddClassAttribute(a); err != nil {
+			return nil, err
+		}
+	}
+	_, rows := X.Size()
+	ret.Extend(rows + len(extra))
+	specs := base.ResolveAttributes(X, attrs)
+	err = X.MapOverRows(specs, func(row [][]byte, rowNo int) (bool, error) {
+		for i, v := range row {
+			ret.Set(retSpecs[i], rowNo, v)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Add the synthetic ones
+	floatSpecs := base.ResolveAttributes(ret, floatAttrs)
+	for n, e := range extra {
+		row := rows + n
+		for i, spec := range specs {
+			ret.Set(retSpecs[i], row, X.Get(spec, e.from))
+		}
+		for f, spec := range floatSpecs {
+			a, b := values[e.from][f], values[e.towards][f]
+			ret.Set(spec, row, base.PackFloatToBytes(a+e.gap*(b-a)))
+		}
+	}
+	return ret, nil
+}
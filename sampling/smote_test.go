@@ -0,0 +1,66 @@
+package sampling
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSMOTE(t *testing.T) {
+	Convey("Given an imbalanced dataset", t, func() {
+		rand.Seed(42)
+		inst := imbalancedData(90, 10)
+
+		Convey("SMOTE should add synthetic rare rows", func() {
+			ret, err := NewSMOTE().Resample(inst)
+			So(err, ShouldBeNil)
+			dist := base.GetClassDistribution(ret)
+			So(dist["common"], ShouldEqual, 90)
+			So(dist["rare"], ShouldEqual, 90)
+			for i := 0; i < 100; i++ {
+				So(ret.RowString(i), ShouldEqual, inst.RowString(i))
+			}
+
+			Convey("Which lie within the rare rows' bounding box", func() {
+				attrs := base.NonClassFloatAttributes(inst)
+				values, err := base.CopyFloatRows(inst, attrs)
+				So(err, ShouldBeNil)
+				lo := []float64{math.Inf(1), math.Inf(1)}
+				hi := []float64{math.Inf(-1), math.Inf(-1)}
+				for i := 90; i < 100; i++ {
+					for f := range attrs {
+						lo[f] = math.Min(lo[f], values[i*2+f])
+						hi[f] = math.Max(hi[f], values[i*2+f])
+					}
+				}
+				synthetic, err := base.CopyFloatRows(ret, attrs)
+				So(err, ShouldBeNil)
+				colour := base.GetAttributeByName(ret, "colour")
+				colourSpec, err := ret.GetAttribute(colour)
+				So(err, ShouldBeNil)
+				distinct := make(map[float64]bool)
+				for i := 100; i < 180; i++ {
+					So(base.GetClass(ret, i), ShouldEqual, "rare")
+					So(colour.GetStringFromSysVal(ret.Get(colourSpec, i)), ShouldEqual, "red")
+					for f := range attrs {
+						v := synthetic[i*2+f]
+						So(v, ShouldBeBetweenOrEqual, lo[f], hi[f])
+						distinct[v] = true
+					}
+				}
+				So(len(distinct), ShouldBeGreaterThan, 100)
+			})
+		})
+
+		Convey("Invalid settings should be rejected", func() {
+			_, err := (&SMOTE{K: 0, Ratio: 1}).Resample(inst)
+			So(err, ShouldNotBeNil)
+			_, err = (&SMOTE{K: 5, Ratio: 2}).Resample(inst)
+			So(err, ShouldNotBeNil)
+			_, err = NewSMOTE().Resample(imbalancedData(10, 1))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,103 @@
+package sampling
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/knn"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+)
+
+// TomekLinks removes rows which form a Tomek link: a pair of rows from
+// different classes which are each other's nearest neighbour. Such
+// pairs are either noise or lie on the boundary between the classes,
+// so removing them (typically after over-sampling) makes the classes
+// easier to separate.
+//
+// By default only rows of classes larger than the smallest are
+// removed, so rare rows are never lost. Set RemoveBoth to clean up
+// classes which have already been balanced (such as by SMOTE).
+//
+// For more information:
+//
+// I. Tomek (1976). Two modifications of CNN. IEEE Transactions on
+// Systems, Man and Cybernetics, 6(11), pp. 769-772.
+type TomekLinks struct {
+	// Remove both rows of every link.
+	RemoveBoth bool
+}
+
+// NewTomekLinks returns a TomekLinks which only removes rows from the
+// larger classes.
+func NewTomekLinks() *TomekLinks {
+	return &TomekLinks{}
+}
+
+// String returns a human-readable summary.
+func (t *TomekLinks) String() string {
+	return fmt.Sprintf("TomekLinks(remove both: %t)", t.RemoveBoth)
+}
+
+// tomekLinks returns each pair of rows (lowest first) which are each
+// other's nearest neighbour but have different classes.
+func tomekLinks(values [][]float64, class []string) [][2]int {
+	all := make([]int, len(values))
+	for i := range all {
+		all[i] = i
+	}
+	index := knn.NewBruteForceIndex(values, pairwise.NewEuclidean())
+	nn := make([]int, len(values))
+	for i := range values {
+		nn[i] = -1
+		if n := nearest(index, values, i, all, 1); len(n) > 0 {
+			nn[i] = n[0]
+		}
+	}
+	ret := make([][2]int, 0)
+	for i, j := range nn {
+		if j > i && nn[j] == i && class[i] != class[j] {
+			ret = append(ret, [2]int{i, j})
+		}
+	}
+	return ret
+}
+
+// Resample returns a view of the rows of X which weren't removed, in
+// their original order.
+func (t *TomekLinks) Resample(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	classes, members, err := classRows(X)
+	if err != nil {
+		return nil, err
+	}
+	_, values, err := floatRows(X)
+	if err != nil {
+		return nil, err
+	}
+	smallest := len(members[classes[0]])
+	for _, c := range classes {
+		if len(members[c]) < smallest {
+			smallest = len(members[c])
+		}
+	}
+	class := make([]string, len(values))
+	for _, c := range classes {
+		for _, i := range members[c] {
+			class[i] = c
+		}
+	}
+
+	remove := make([]bool, len(values))
+	for _, link := range tomekLinks(values, class) {
+		for _, i := range link {
+			if t.RemoveBoth || len(members[class[i]]) > smallest {
+				remove[i] = true
+			}
+		}
+	}
+	ret := make([]int, 0, len(values))
+	for i, r := range remove {
+		if !r {
+			ret = append(ret, i)
+		}
+	}
+	return base.NewInstancesViewFromVisible(X, ret, X.AllAttributes()), nil
+}
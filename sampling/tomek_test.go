@@ -0,0 +1,65 @@
+package sampling
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestTomekLinks(t *testing.T) {
+	Convey("Given two classes which meet in the middle", t, func() {
+		inst := base.NewDenseInstances()
+		xSpec := inst.AddAttribute(base.NewFloatAttribute("x"))
+		class := base.NewCategoricalAttribute()
+		class.SetName("class")
+		classSpec := inst.AddAttribute(class)
+		inst.AddClassAttribute(class)
+		xs := []float64{0, 1, 2, 4.9, 7, 8, 5.1, 9}
+		cs := []string{"a", "a", "a", "a", "b", "b", "b", "b"}
+		inst.Extend(len(xs))
+		for i := range xs {
+			inst.Set(xSpec, i, base.PackFloatToBytes(xs[i]))
+			inst.Set(classSpec, i, class.GetSysValFromString(cs[i]))
+		}
+
+		Convey("The link should be found", func() {
+			values := make([][]float64, len(xs))
+			for i, x := range xs {
+				values[i] = []float64{x}
+			}
+			So(tomekLinks(values, cs), ShouldResemble, [][2]int{{3, 6}})
+		})
+
+		Convey("With balanced classes, rows should only be removed from both", func() {
+			ret, err := NewTomekLinks().Resample(inst)
+			So(err, ShouldBeNil)
+			_, rows := ret.Size()
+			So(rows, ShouldEqual, 8)
+
+			both := NewTomekLinks()
+			both.RemoveBoth = true
+			ret, err = both.Resample(inst)
+			So(err, ShouldBeNil)
+			_, rows = ret.Size()
+			So(rows, ShouldEqual, 6)
+			So(ret.RowString(3), ShouldEqual, inst.RowString(4))
+		})
+
+		Convey("With a smaller class, its row should be kept", func() {
+			view := base.NewInstancesViewFromVisible(inst, []int{0, 1, 2, 3, 4, 6}, inst.AllAttributes())
+			ret, err := NewTomekLinks().Resample(view)
+			So(err, ShouldBeNil)
+			dist := base.GetClassDistribution(ret)
+			So(dist["a"], ShouldEqual, 3)
+			So(dist["b"], ShouldEqual, 2)
+
+			both := NewTomekLinks()
+			both.RemoveBoth = true
+			ret, err = both.Resample(view)
+			So(err, ShouldBeNil)
+			dist = base.GetClassDistribution(ret)
+			So(dist["a"], ShouldEqual, 3)
+			So(dist["b"], ShouldEqual, 1)
+		})
+	})
+}
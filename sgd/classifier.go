@@ -0,0 +1,277 @@
+package sgd
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+	"sort"
+)
+
+// SGDClassifier is a linear classifier trained with stochastic gradient
+// descent. With two classes it fits a single decision function, which
+// is positive for the second class (in sorted order). With more, it
+// fits one decision function per class (one versus the rest) and
+// predicts the class whose function is largest.
+//
+// SGDClassifier implements base.OnlineClassifier.
+type SGDClassifier struct {
+	model
+	classes []string
+}
+
+// NewSGDClassifier returns an SGDClassifier which minimises the hinge
+// loss with a small L2 penalty (a linear SVM), using the optimal
+// learning rate.
+func NewSGDClassifier() *SGDClassifier {
+	return &SGDClassifier{
+		model: model{
+			Params: Params{
+				Loss:          HingeLoss,
+				Penalty:       L2Penalty,
+				Alpha:         0.0001,
+				L1Ratio:       0.15,
+				LearningRate:  OptimalSchedule,
+				Eta0:          0.01,
+				PowerT:        0.5,
+				MaxIterations: 1000,
+				Tolerance:     1e-3,
+				Shuffle:       true,
+				FitIntercept:  true,
+				Epsilon:       0.1,
+			},
+		},
+	}
+}
+
+// String returns a human-readable summary.
+func (c *SGDClassifier) String() string {
+	return fmt.Sprintf("SGDClassifier(loss: %s, penalty: %s, alpha: %g, learning rate: %s)", c.Loss, c.Penalty, c.Alpha, c.LearningRate)
+}
+
+// Fit trains the classifier from scratch on X.
+func (c *SGDClassifier) Fit(X base.FixedDataGrid) error {
+	features, targets, err := c.prepare(X, nil, true)
+	if err != nil {
+		return err
+	}
+	c.train(features, targets)
+	c.fitted = true
+	return nil
+}
+
+// PartialFit makes a single pass over a batch of training data. The
+// first call must list every class that will be seen.
+func (c *SGDClassifier) PartialFit(X base.FixedDataGrid, classes []string) error {
+	features, targets, err := c.prepare(X, classes, !c.fitted)
+	if err != nil {
+		return err
+	}
+	c.epoch(features, targets, c.Shuffle)
+	c.fitted = true
+	return nil
+}
+
+// prepare validates a batch of training data and returns its features
+// and the target of each row for each weightVector. If reset is true,
+// the classes (taken from X if nil) and features are recorded and the
+// weights are zeroed.
+func (c *SGDClassifier) prepare(X base.FixedDataGrid, classes []string, reset bool) ([][]float64, [][]float64, error) {
+	if err := c.validate(HingeLoss, LogLoss, ModifiedHuberLoss, SquaredLoss); err != nil {
+		return nil, nil, err
+	}
+	if len(X.AllClassAttributes()) != 1 {
+		return nil, nil, fmt.Errorf("Only 1 class variable is permitted")
+	}
+	_, rows := X.Size()
+	if rows == 0 {
+		return nil, nil, base.NoTrainingDataError
+	}
+
+	if reset {
+		attrs, err := featureAttributes(X)
+		if err != nil {
+			return nil, nil, err
+		}
+		if classes == nil {
+			for i := 0; i < rows; i++ {
+				classes = append(classes, base.GetClass(X, i))
+			}
+		}
+		unique := make(map[string]bool)
+		sorted := make([]string, 0)
+		for _, cls := range classes {
+			if !unique[cls] {
+				unique[cls] = true
+				sorted = append(sorted, cls)
+			}
+		}
+		if len(sorted) < 2 {
+			return nil, nil, fmt.Errorf("Need at least two classes")
+		}
+		sort.Strings(sorted)
+		c.classes = sorted
+		c.fitted = false
+		vectors := len(sorted)
+		if vectors == 2 {
+			vectors = 1
+		}
+		c.reset(vectors, attrs)
+	}
+
+	index := make(map[string]int)
+	for k, cls := range c.classes {
+		index[cls] = k
+	}
+	targets := make([][]float64, rows)
+	for i := range targets {
+		cls := base.GetClass(X, i)
+		k, ok := index[cls]
+		if !ok {
+			return nil, nil, fmt.Errorf("Class %s wasn't in the list given to PartialFit", cls)
+		}
+		targets[i] = make([]float64, len(c.vectors))
+		for j := range targets[i] {
+			targets[i][j] = -1
+		}
+		if len(c.vectors) == 1 {
+			if k == 1 {
+				targets[i][0] = 1
+			}
+		} else {
+			targets[i][k] = 1
+		}
+	}
+	features, err := c.readFeatures(X)
+	if err != nil {
+		return nil, nil, err
+	}
+	return features, targets, nil
+}
+
+// decisionFunction returns the value of each decision function for a
+// row of features.
+func (c *SGDClassifier) decisionFunction(x []float64) []float64 {
+	ret := make([]float64, len(c.vectors))
+	for k, v := range c.vectors {
+		w, b := v.weights(c.Average)
+		ret[k] = b
+		for j, val := range x {
+			ret[k] += w[j] * val
+		}
+	}
+	return ret
+}
+
+// predictOne returns the index of the predicted class.
+func (c *SGDClassifier) predictOne(x []float64) int {
+	scores := c.decisionFunction(x)
+	if len(scores) == 1 {
+		if scores[0] > 0 {
+			return 1
+		}
+		return 0
+	}
+	best := 0
+	for k, s := range scores {
+		if s > scores[best] {
+			best = k
+		}
+	}
+	return best
+}
+
+// Predict returns the predicted class of each row of X.
+func (c *SGDClassifier) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !c.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	features, err := c.readFeatures(X)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.GeneratePredictionVector(X)
+	for i, x := range features {
+		base.SetClass(ret, i, c.classes[c.predictOne(x)])
+	}
+	return ret, nil
+}
+
+// PredictProba returns the probability of each class, as one
+// FloatAttribute per class value. Only the log and modified Huber
+// losses support this. With more than two classes the one-versus-rest
+// probabilities are normalised to sum to one.
+func (c *SGDClassifier) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !c.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	if c.Loss != LogLoss && c.Loss != ModifiedHuberLoss {
+		return nil, fmt.Errorf("Probability estimates aren't available for the %s loss", c.Loss)
+	}
+	features, err := c.readFeatures(X)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, len(c.classes))
+	for k, cls := range c.classes {
+		specs[k] = ret.AddAttribute(base.NewFloatAttribute(cls))
+	}
+	ret.Extend(len(features))
+	for i, x := range features {
+		scores := c.decisionFunction(x)
+		probs := make([]float64, len(scores))
+		for k, s := range scores {
+			if c.Loss == LogLoss {
+				probs[k] = 1 / (1 + math.Exp(-s))
+			} else {
+				probs[k] = (math.Max(-1, math.Min(1, s)) + 1) / 2
+			}
+		}
+		if len(probs) == 1 {
+			probs = []float64{1 - probs[0], probs[0]}
+		} else {
+			total := 0.0
+			for _, p := range probs {
+				total += p
+			}
+			for k := range probs {
+				if total == 0 {
+					probs[k] = 1 / float64(len(probs))
+				} else {
+					probs[k] /= total
+				}
+			}
+		}
+		for k, p := range probs {
+			ret.Set(specs[k], i, base.PackFloatToBytes(p))
+		}
+	}
+	return ret, nil
+}
+
+// GetClasses returns the class values, sorted.
+func (c *SGDClassifier) GetClasses() []string {
+	return c.classes
+}
+
+// GetCoefficients returns the weights of each decision function, one
+// row per function. There's a single row with two classes, and one per
+// class otherwise.
+func (c *SGDClassifier) GetCoefficients() [][]float64 {
+	ret := make([][]float64, len(c.vectors))
+	for k, v := range c.vectors {
+		w, _ := v.weights(c.Average)
+		ret[k] = append([]float64(nil), w...)
+	}
+	return ret
+}
+
+// GetIntercepts returns the intercept of each decision function.
+func (c *SGDClassifier) GetIntercepts() []float64 {
+	ret := make([]float64, len(c.vectors))
+	for k, v := range c.vectors {
+		_, ret[k] = v.weights(c.Average)
+	}
+	return ret
+}
@@ -0,0 +1,138 @@
+package sgd
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSGDClassifier(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		rows := make([]int, 100)
+		for i := range rows {
+			rows[i] = i
+		}
+		binary := base.NewInstancesViewFromVisible(iris, rows, iris.AllAttributes())
+
+		accuracy := func(c *SGDClassifier, X base.FixedDataGrid) float64 {
+			predictions, err := c.Predict(X)
+			So(err, ShouldBeNil)
+			cf, err := evaluation.GetConfusionMatrix(X, predictions)
+			So(err, ShouldBeNil)
+			return evaluation.GetAccuracy(cf)
+		}
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewSGDClassifier().Predict(iris)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Fitting no rows should return a NoTrainingDataError", func() {
+			empty := base.NewInstancesViewFromVisible(iris, []int{}, iris.AllAttributes())
+			So(NewSGDClassifier().Fit(empty), ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Every loss should separate two species", func() {
+			for _, loss := range []Loss{HingeLoss, LogLoss, ModifiedHuberLoss, SquaredLoss} {
+				c := NewSGDClassifier()
+				c.Loss = loss
+				if loss == SquaredLoss {
+					// The optimal learning rate is far too large
+					c.LearningRate = InverseScalingSchedule
+				}
+				So(c.Fit(binary), ShouldBeNil)
+				So(accuracy(c, binary), ShouldEqual, 1.0)
+				So(len(c.GetCoefficients()), ShouldEqual, 1)
+				So(len(c.GetCoefficients()[0]), ShouldEqual, 4)
+			}
+		})
+
+		Convey("Three species should be classified one versus the rest", func() {
+			c := NewSGDClassifier()
+			c.Loss = LogLoss
+			c.Average = true
+			So(c.Fit(iris), ShouldBeNil)
+			So(accuracy(c, iris), ShouldBeGreaterThan, 0.8)
+			So(len(c.GetCoefficients()), ShouldEqual, 3)
+			So(len(c.GetIntercepts()), ShouldEqual, 3)
+
+			Convey("And the probabilities should sum to one", func() {
+				probs, err := c.PredictProba(iris)
+				So(err, ShouldBeNil)
+				_, rows := probs.Size()
+				specs := base.ResolveAllAttributes(probs)
+				So(len(specs), ShouldEqual, 3)
+				for i := 0; i < rows; i++ {
+					total := 0.0
+					for _, s := range specs {
+						total += base.UnpackBytesToFloat(probs.Get(s, i))
+					}
+					So(total, ShouldAlmostEqual, 1.0, 1e-9)
+				}
+			})
+		})
+
+		Convey("The L1 penalty should give sparse weights", func() {
+			c := NewSGDClassifier()
+			c.Penalty = L1Penalty
+			c.Alpha = 0.1
+			So(c.Fit(binary), ShouldBeNil)
+			So(accuracy(c, binary), ShouldEqual, 1.0)
+			zeros := 0
+			for _, w := range c.GetCoefficients()[0] {
+				if w == 0 {
+					zeros++
+				}
+			}
+			So(zeros, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("Fitting twice with the same Seed should give the same model", func() {
+			c := NewSGDClassifier()
+			c.Seed = 7
+			So(c.Fit(binary), ShouldBeNil)
+			first := c.GetCoefficients()
+			So(c.Fit(binary), ShouldBeNil)
+			So(c.GetCoefficients(), ShouldResemble, first)
+		})
+
+		Convey("The hinge loss shouldn't give probabilities", func() {
+			c := NewSGDClassifier()
+			So(c.Fit(binary), ShouldBeNil)
+			_, err := c.PredictProba(binary)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Invalid settings should be rejected", func() {
+			c := NewSGDClassifier()
+			c.Loss = EpsilonInsensitiveLoss
+			So(c.Fit(binary), ShouldNotBeNil)
+			c = NewSGDClassifier()
+			c.Alpha = 0
+			So(c.Fit(binary), ShouldNotBeNil)
+		})
+
+		Convey("Repeated PartialFit calls should also separate two species", func() {
+			c := NewSGDClassifier()
+			classes := []string{"Iris-setosa", "Iris-versicolor"}
+			shuffled := base.Shuffle(base.NewInstancesViewFromVisible(iris, rows, iris.AllAttributes()))
+			for epoch := 0; epoch < 10; epoch++ {
+				for start := 0; start < 100; start += 25 {
+					batch := make([]int, 25)
+					for i := range batch {
+						batch[i] = start + i
+					}
+					So(c.PartialFit(base.NewInstancesViewFromVisible(shuffled, batch, shuffled.AllAttributes()), classes), ShouldBeNil)
+				}
+			}
+			So(accuracy(c, binary), ShouldEqual, 1.0)
+
+			Convey("But not with a class it wasn't told about", func() {
+				So(c.PartialFit(iris, nil), ShouldNotBeNil)
+			})
+		})
+	})
+}
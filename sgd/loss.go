@@ -0,0 +1,115 @@
+package sgd
+
+import (
+	"fmt"
+	"math"
+)
+
+// Loss is the function of the prediction p and the target y minimised
+// by SGD. Classification losses take y to be +1 or -1.
+type Loss int
+
+const (
+	// HingeLoss is max(0, 1 - py), as used by linear SVMs.
+	HingeLoss Loss = iota
+	// LogLoss is log(1 + exp(-py)), as used by logistic regression.
+	// It supports probability estimates.
+	LogLoss
+	// ModifiedHuberLoss is max(0, 1 - py)^2 when py >= -1 and -4py
+	// otherwise. It is tolerant of outliers and supports probability
+	// estimates.
+	ModifiedHuberLoss
+	// SquaredLoss is (p - y)^2 / 2, as used by ordinary least squares.
+	SquaredLoss
+	// EpsilonInsensitiveLoss is max(0, |p - y| - Epsilon), as used by
+	// support vector regression.
+	EpsilonInsensitiveLoss
+)
+
+// String returns a human-readable name for the Loss.
+func (l Loss) String() string {
+	switch l {
+	case HingeLoss:
+		return "hinge"
+	case LogLoss:
+		return "log"
+	case ModifiedHuberLoss:
+		return "modified_huber"
+	case SquaredLoss:
+		return "squared"
+	case EpsilonInsensitiveLoss:
+		return "epsilon_insensitive"
+	}
+	return fmt.Sprintf("Loss(%d)", int(l))
+}
+
+// loss returns the value of the loss.
+func (l Loss) loss(p, y, epsilon float64) float64 {
+	switch l {
+	case HingeLoss:
+		return math.Max(0, 1-p*y)
+	case LogLoss:
+		z := p * y
+		if z > 18 {
+			return math.Exp(-z)
+		}
+		if z < -18 {
+			return -z
+		}
+		return math.Log(1 + math.Exp(-z))
+	case ModifiedHuberLoss:
+		z := p * y
+		if z >= 1 {
+			return 0
+		}
+		if z >= -1 {
+			return (1 - z) * (1 - z)
+		}
+		return -4 * z
+	case SquaredLoss:
+		return 0.5 * (p - y) * (p - y)
+	case EpsilonInsensitiveLoss:
+		return math.Max(0, math.Abs(y-p)-epsilon)
+	}
+	panic(fmt.Sprintf("Unknown loss: %s", l))
+}
+
+// derivative returns the derivative of the loss with respect to p.
+func (l Loss) derivative(p, y, epsilon float64) float64 {
+	switch l {
+	case HingeLoss:
+		if p*y < 1 {
+			return -y
+		}
+		return 0
+	case LogLoss:
+		z := p * y
+		if z > 18 {
+			return -y * math.Exp(-z)
+		}
+		if z < -18 {
+			return -y
+		}
+		return -y / (1 + math.Exp(z))
+	case ModifiedHuberLoss:
+		z := p * y
+		if z >= 1 {
+			return 0
+		}
+		if z >= -1 {
+			return -2 * y * (1 - z)
+		}
+		return -4 * y
+	case SquaredLoss:
+		return p - y
+	case EpsilonInsensitiveLoss:
+		if y-p > epsilon {
+			return -1
+		}
+		if p-y > epsilon {
+			return 1
+		}
+		return 0
+	}
+	panic(fmt.Sprintf("Unknown loss: %s", l))
+}
@@ -0,0 +1,249 @@
+package sgd
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math/rand"
+	"sort"
+)
+
+// Perceptron is a linear classifier trained with the perceptron rule.
+// Each class has its own weight vector (one versus the rest), and
+// whenever a training row falls on the wrong side of a class'
+// hyperplane that class' weights are moved towards it.
+//
+// Only non-class FloatAttributes are used as features. Perceptron
+// implements base.OnlineClassifier, so it can be trained one batch at
+// a time with PartialFit.
+type Perceptron struct {
+	// Size of each update
+	LearningRate float64
+	// Maximum number of passes over the data made by Fit
+	MaxIterations int
+	// Visit the rows in a random order on each pass made by Fit
+	Shuffle bool
+	// Seeds the random order of the rows, so that fitting the same
+	// data twice gives the same weights
+	Seed    int64
+	classes []string
+	attrs   []base.Attribute
+	// One row of weights per class
+	weights [][]float64
+	bias    []float64
+	fitted  bool
+}
+
+// NewPerceptron returns a Perceptron which makes up to 100 passes over
+// the training data, in a random order.
+func NewPerceptron() *Perceptron {
+	return &Perceptron{
+		LearningRate:  1.0,
+		MaxIterations: 100,
+		Shuffle:       true,
+	}
+}
+
+// String returns a human-readable summary.
+func (p *Perceptron) String() string {
+	return fmt.Sprintf("Perceptron(learning rate: %.3f, max iterations: %d)", p.LearningRate, p.MaxIterations)
+}
+
+// Fit trains the Perceptron on X, stopping early once every training
+// row is classified correctly.
+func (p *Perceptron) Fit(X base.FixedDataGrid) error {
+	if len(X.AllClassAttributes()) != 1 {
+		return fmt.Errorf("Only 1 class variable is permitted")
+	}
+	_, rows := X.Size()
+	classes := make([]string, 0)
+	seen := make(map[string]bool)
+	for i := 0; i < rows; i++ {
+		if c := base.GetClass(X, i); !seen[c] {
+			seen[c] = true
+			classes = append(classes, c)
+		}
+	}
+
+	p.fitted = false
+	if err := p.setup(X, classes); err != nil {
+		return err
+	}
+	features, labels, err := p.readBatch(X)
+	if err != nil {
+		return err
+	}
+	order := make([]int, rows)
+	for i := range order {
+		order[i] = i
+	}
+	rng := rand.New(rand.NewSource(p.Seed))
+	for iteration := 0; iteration < p.MaxIterations; iteration++ {
+		if p.Shuffle {
+			for i := range order {
+				j := rng.Intn(i + 1)
+				order[i], order[j] = order[j], order[i]
+			}
+		}
+		if p.epoch(features, labels, order) == 0 {
+			break
+		}
+	}
+	p.fitted = true
+	return nil
+}
+
+// PartialFit makes a single pass over a batch of training data.
+func (p *Perceptron) PartialFit(X base.FixedDataGrid, classes []string) error {
+	if len(X.AllClassAttributes()) != 1 {
+		return fmt.Errorf("Only 1 class variable is permitted")
+	}
+	if !p.fitted {
+		if err := p.setup(X, classes); err != nil {
+			return err
+		}
+	}
+	features, labels, err := p.readBatch(X)
+	if err != nil {
+		return err
+	}
+	order := make([]int, len(features))
+	for i := range order {
+		order[i] = i
+	}
+	p.epoch(features, labels, order)
+	p.fitted = true
+	return nil
+}
+
+// setup records the classes and features and zeroes the weights.
+func (p *Perceptron) setup(X base.FixedDataGrid, classes []string) error {
+	unique := make(map[string]bool)
+	sorted := make([]string, 0)
+	for _, cls := range classes {
+		if !unique[cls] {
+			unique[cls] = true
+			sorted = append(sorted, cls)
+		}
+	}
+	if len(sorted) < 2 {
+		return fmt.Errorf("Need at least two classes")
+	}
+	p.attrs = base.NonClassFloatAttributes(X)
+	if len(p.attrs) == 0 {
+		return fmt.Errorf("No FloatAttributes to fit")
+	}
+	sort.Strings(sorted)
+	p.classes = sorted
+	p.weights = make([][]float64, len(p.classes))
+	for k := range p.weights {
+		p.weights[k] = make([]float64, len(p.attrs))
+	}
+	p.bias = make([]float64, len(p.classes))
+	return nil
+}
+
+// readBatch returns the features of each row of X and the index of
+// its class.
+func (p *Perceptron) readBatch(X base.FixedDataGrid) ([][]float64, []int, error) {
+	index := make(map[string]int)
+	for k, c := range p.classes {
+		index[c] = k
+	}
+	_, rows := X.Size()
+	features := make([][]float64, rows)
+	labels := make([]int, rows)
+	for i := range labels {
+		c := base.GetClass(X, i)
+		k, ok := index[c]
+		if !ok {
+			return nil, nil, fmt.Errorf("Class %s wasn't in the list given to PartialFit", c)
+		}
+		labels[i] = k
+	}
+	X.MapOverRows(base.ResolveAttributes(X, p.attrs), func(row [][]byte, i int) (bool, error) {
+		features[i] = make([]float64, len(row))
+		for j, v := range row {
+			features[i][j] = base.UnpackBytesToFloat(v)
+		}
+		return true, nil
+	})
+	return features, labels, nil
+}
+
+// epoch applies the perceptron rule to each row in the given order,
+// returning the number of rows which were misclassified.
+func (p *Perceptron) epoch(features [][]float64, labels []int, order []int) int {
+	mistakes := 0
+	for _, i := range order {
+		row := features[i]
+		if p.predictOne(row) != labels[i] {
+			mistakes++
+		}
+		for k := range p.classes {
+			y := -1.0
+			if labels[i] == k {
+				y = 1.0
+			}
+			if y*p.score(k, row) <= 0 {
+				for j, v := range row {
+					p.weights[k][j] += p.LearningRate * y * v
+				}
+				p.bias[k] += p.LearningRate * y
+			}
+		}
+	}
+	return mistakes
+}
+
+// score returns the signed distance (up to scale) of row from the
+// k-th class' hyperplane.
+func (p *Perceptron) score(k int, row []float64) float64 {
+	ret := p.bias[k]
+	for j, v := range row {
+		ret += p.weights[k][j] * v
+	}
+	return ret
+}
+
+// predictOne returns the index of the highest-scoring class.
+func (p *Perceptron) predictOne(row []float64) int {
+	best := 0
+	for k := range p.classes {
+		if p.score(k, row) > p.score(best, row) {
+			best = k
+		}
+	}
+	return best
+}
+
+// Predict returns the highest-scoring class for each row of X.
+func (p *Perceptron) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !p.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	ret := base.GeneratePredictionVector(X)
+	row := make([]float64, len(p.attrs))
+	X.MapOverRows(base.ResolveAttributes(X, p.attrs), func(rowBytes [][]byte, i int) (bool, error) {
+		for j, v := range rowBytes {
+			row[j] = base.UnpackBytesToFloat(v)
+		}
+		base.SetClass(ret, i, p.classes[p.predictOne(row)])
+		return true, nil
+	})
+	return ret, nil
+}
+
+// GetClasses returns the class values, in the order used by GetWeights.
+func (p *Perceptron) GetClasses() []string {
+	return p.classes
+}
+
+// GetWeights returns the weights of each class, one row per class,
+// with the bias in the final column.
+func (p *Perceptron) GetWeights() [][]float64 {
+	ret := make([][]float64, len(p.weights))
+	for k, w := range p.weights {
+		ret[k] = append(append([]float64(nil), w...), p.bias[k])
+	}
+	return ret
+}
@@ -0,0 +1,77 @@
+package sgd
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestPerceptron(t *testing.T) {
+	Convey("Given two linearly separable iris species", t, func() {
+		iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+		rows := make([]int, 100)
+		for i := range rows {
+			rows[i] = i
+		}
+		inst := base.NewInstancesViewFromVisible(iris, rows, iris.AllAttributes())
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewPerceptron().Predict(inst)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Fit should separate them perfectly", func() {
+			p := NewPerceptron()
+			So(p.Fit(inst), ShouldBeNil)
+			predictions, err := p.Predict(inst)
+			So(err, ShouldBeNil)
+			cf, err := evaluation.GetConfusionMatrix(inst, predictions)
+			So(err, ShouldBeNil)
+			So(evaluation.GetAccuracy(cf), ShouldEqual, 1.0)
+			So(len(p.GetWeights()), ShouldEqual, 2)
+		})
+
+		Convey("Fitting twice with the same Seed should give the same weights", func() {
+			p := NewPerceptron()
+			p.Seed = 7
+			So(p.Fit(inst), ShouldBeNil)
+			first := p.GetWeights()
+			So(p.Fit(inst), ShouldBeNil)
+			So(p.GetWeights(), ShouldResemble, first)
+		})
+
+		Convey("Repeated PartialFit calls over batches should too", func() {
+			p := NewPerceptron()
+			classes := []string{"Iris-setosa", "Iris-versicolor"}
+			shuffled := base.Shuffle(base.NewInstancesViewFromVisible(iris, rows, iris.AllAttributes()))
+			for epoch := 0; epoch < 20; epoch++ {
+				for start := 0; start < 100; start += 25 {
+					batch := make([]int, 25)
+					for i := range batch {
+						batch[i] = start + i
+					}
+					So(p.PartialFit(base.NewInstancesViewFromVisible(shuffled, batch, shuffled.AllAttributes()), classes), ShouldBeNil)
+				}
+			}
+			predictions, err := p.Predict(inst)
+			So(err, ShouldBeNil)
+			cf, err := evaluation.GetConfusionMatrix(inst, predictions)
+			So(err, ShouldBeNil)
+			So(evaluation.GetAccuracy(cf), ShouldEqual, 1.0)
+		})
+
+		Convey("PartialFit should reject undeclared classes", func() {
+			p := NewPerceptron()
+			So(p.PartialFit(inst, []string{"Iris-setosa", "Iris-virginica"}), ShouldNotBeNil)
+		})
+
+		Convey("PartialFit should ignore repeated classes", func() {
+			p := NewPerceptron()
+			So(p.PartialFit(inst, []string{"Iris-setosa", "Iris-setosa"}), ShouldNotBeNil)
+			So(p.PartialFit(inst, []string{"Iris-versicolor", "Iris-setosa", "Iris-versicolor"}), ShouldBeNil)
+			So(p.classes, ShouldResemble, []string{"Iris-setosa", "Iris-versicolor"})
+		})
+	})
+}
@@ -0,0 +1,148 @@
+package sgd
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// SGDRegressor is a linear regression model trained with stochastic
+// gradient descent. The class Attribute must be a FloatAttribute.
+type SGDRegressor struct {
+	model
+	cls base.Attribute
+}
+
+// NewSGDRegressor returns an SGDRegressor which minimises the squared
+// loss with a small L2 penalty (ridge regression), using an
+// inverse-scaling learning rate.
+func NewSGDRegressor() *SGDRegressor {
+	return &SGDRegressor{
+		model: model{
+			Params: Params{
+				Loss:          SquaredLoss,
+				Penalty:       L2Penalty,
+				Alpha:         0.0001,
+				L1Ratio:       0.15,
+				LearningRate:  InverseScalingSchedule,
+				Eta0:          0.01,
+				PowerT:        0.25,
+				MaxIterations: 1000,
+				Tolerance:     1e-3,
+				Shuffle:       true,
+				FitIntercept:  true,
+				Epsilon:       0.1,
+			},
+		},
+	}
+}
+
+// String returns a human-readable summary.
+func (r *SGDRegressor) String() string {
+	return fmt.Sprintf("SGDRegressor(loss: %s, penalty: %s, alpha: %g, learning rate: %s)", r.Loss, r.Penalty, r.Alpha, r.LearningRate)
+}
+
+// Fit trains the regressor from scratch on X.
+func (r *SGDRegressor) Fit(X base.FixedDataGrid) error {
+	features, targets, err := r.prepare(X, true)
+	if err != nil {
+		return err
+	}
+	r.train(features, targets)
+	r.fitted = true
+	return nil
+}
+
+// PartialFit makes a single pass over a batch of training data.
+func (r *SGDRegressor) PartialFit(X base.FixedDataGrid) error {
+	features, targets, err := r.prepare(X, !r.fitted)
+	if err != nil {
+		return err
+	}
+	r.epoch(features, targets, r.Shuffle)
+	r.fitted = true
+	return nil
+}
+
+// prepare validates a batch of training data and returns its features
+// and targets. If reset is true the features are recorded and the
+// weights are zeroed.
+func (r *SGDRegressor) prepare(X base.FixedDataGrid, reset bool) ([][]float64, [][]float64, error) {
+	if err := r.validate(SquaredLoss, EpsilonInsensitiveLoss); err != nil {
+		return nil, nil, err
+	}
+	classAttrs := X.AllClassAttributes()
+	if len(classAttrs) != 1 {
+		return nil, nil, fmt.Errorf("Only 1 class variable is permitted")
+	}
+	if _, ok := classAttrs[0].(*base.FloatAttribute); !ok {
+		return nil, nil, fmt.Errorf("%s: class Attribute must be a FloatAttribute", classAttrs[0])
+	}
+	_, rows := X.Size()
+	if rows == 0 {
+		return nil, nil, base.NoTrainingDataError
+	}
+
+	if reset {
+		attrs, err := featureAttributes(X)
+		if err != nil {
+			return nil, nil, err
+		}
+		r.fitted = false
+		r.cls = classAttrs[0]
+		r.reset(1, attrs)
+	}
+
+	features, err := r.readFeatures(X)
+	if err != nil {
+		return nil, nil, err
+	}
+	targets := make([][]float64, rows)
+	X.MapOverRows(base.ResolveAttributes(X, classAttrs), func(row [][]byte, i int) (bool, error) {
+		targets[i] = []float64{base.UnpackBytesToFloat(row[0])}
+		return true, nil
+	})
+	return features, targets, nil
+}
+
+// Predict returns the predicted value of each row of X.
+func (r *SGDRegressor) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !r.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	features, err := r.readFeatures(X)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.GeneratePredictionVector(X)
+	clsSpec, err := ret.GetAttribute(r.cls)
+	if err != nil {
+		return nil, err
+	}
+	w, b := r.vectors[0].weights(r.Average)
+	for i, x := range features {
+		prediction := b
+		for j, val := range x {
+			prediction += w[j] * val
+		}
+		ret.Set(clsSpec, i, base.PackFloatToBytes(prediction))
+	}
+	return ret, nil
+}
+
+// GetCoefficients returns the weight of each feature.
+func (r *SGDRegressor) GetCoefficients() []float64 {
+	if r.vectors == nil {
+		return nil
+	}
+	w, _ := r.vectors[0].weights(r.Average)
+	return append([]float64(nil), w...)
+}
+
+// GetIntercept returns the intercept.
+func (r *SGDRegressor) GetIntercept() float64 {
+	if r.vectors == nil {
+		return 0
+	}
+	_, b := r.vectors[0].weights(r.Average)
+	return b
+}
@@ -0,0 +1,108 @@
+package sgd
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+// linearData returns rows of three features in [-1, 1] whose class is
+// 3 x1 - 2 x2 + 1. The third feature is noise.
+func linearData(rows int, rng *rand.Rand) base.FixedDataGrid {
+	ret := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, 3)
+	for j, name := range []string{"x1", "x2", "x3"} {
+		specs[j] = ret.AddAttribute(base.NewFloatAttribute(name))
+	}
+	y := base.NewFloatAttribute("y")
+	ySpec := ret.AddAttribute(y)
+	ret.AddClassAttribute(y)
+	ret.Extend(rows)
+	for i := 0; i < rows; i++ {
+		x := make([]float64, 3)
+		for j := range x {
+			x[j] = 2*rng.Float64() - 1
+			ret.Set(specs[j], i, base.PackFloatToBytes(x[j]))
+		}
+		ret.Set(ySpec, i, base.PackFloatToBytes(3*x[0]-2*x[1]+1))
+	}
+	return ret
+}
+
+func TestSGDRegressor(t *testing.T) {
+	Convey("Given some linear data", t, func() {
+		data := linearData(200, rand.New(rand.NewSource(42)))
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewSGDRegressor().Predict(data)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Fitting no rows should return a NoTrainingDataError", func() {
+			empty := base.NewInstancesViewFromVisible(data, []int{}, data.AllAttributes())
+			So(NewSGDRegressor().Fit(empty), ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("The squared loss should recover the coefficients", func() {
+			r := NewSGDRegressor()
+			r.Tolerance = 0
+			r.MaxIterations = 100
+			So(r.Fit(data), ShouldBeNil)
+			coefs := r.GetCoefficients()
+			So(coefs[0], ShouldAlmostEqual, 3, 0.05)
+			So(coefs[1], ShouldAlmostEqual, -2, 0.05)
+			So(coefs[2], ShouldAlmostEqual, 0, 0.05)
+			So(r.GetIntercept(), ShouldAlmostEqual, 1, 0.05)
+
+			Convey("And the predictions should be close", func() {
+				predictions, err := r.Predict(data)
+				So(err, ShouldBeNil)
+				_, rows := data.Size()
+				for i := 0; i < rows; i++ {
+					expected := base.UnpackBytesToFloat(data.Get(base.ResolveAttributes(data, data.AllClassAttributes())[0], i))
+					actual := base.UnpackBytesToFloat(predictions.Get(base.ResolveAttributes(predictions, predictions.AllClassAttributes())[0], i))
+					So(actual, ShouldAlmostEqual, expected, 0.2)
+				}
+			})
+		})
+
+		Convey("The epsilon-insensitive loss should too", func() {
+			r := NewSGDRegressor()
+			r.Loss = EpsilonInsensitiveLoss
+			r.Epsilon = 0.01
+			r.LearningRate = ConstantSchedule
+			r.Tolerance = 0
+			r.MaxIterations = 100
+			So(r.Fit(data), ShouldBeNil)
+			coefs := r.GetCoefficients()
+			So(coefs[0], ShouldAlmostEqual, 3, 0.1)
+			So(coefs[1], ShouldAlmostEqual, -2, 0.1)
+			So(r.GetIntercept(), ShouldAlmostEqual, 1, 0.1)
+		})
+
+		Convey("The elastic-net penalty should remove the noise feature", func() {
+			r := NewSGDRegressor()
+			r.Penalty = ElasticNetPenalty
+			r.L1Ratio = 0.9
+			r.Alpha = 0.05
+			So(r.Fit(data), ShouldBeNil)
+			So(r.GetCoefficients()[2], ShouldEqual, 0)
+		})
+
+		Convey("Repeated PartialFit calls should converge too", func() {
+			r := NewSGDRegressor()
+			r.LearningRate = ConstantSchedule
+			for i := 0; i < 50; i++ {
+				So(r.PartialFit(data), ShouldBeNil)
+			}
+			So(r.GetCoefficients()[0], ShouldAlmostEqual, 3, 0.05)
+		})
+
+		Convey("A classification loss should be rejected", func() {
+			r := NewSGDRegressor()
+			r.Loss = HingeLoss
+			So(r.Fit(data), ShouldNotBeNil)
+		})
+	})
+}
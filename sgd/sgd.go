@@ -0,0 +1,402 @@
+// Package sgd implements linear models trained with stochastic gradient
+// descent, written in pure Go so they can be used without cgo.
+//
+// SGDClassifier:
+//
+//	Fits one linear decision function per class (one versus the rest)
+//	with the hinge, log, modified Huber or squared loss.
+//
+// SGDRegressor:
+//
+//	Fits a single linear function with the squared or
+//	epsilon-insensitive loss.
+//
+// Perceptron:
+//
+//	Fits one linear decision function per class with the perceptron
+//	rule, which only updates the weights on mistakes.
+//
+// SGDClassifier and SGDRegressor are regularised with an L2, L1 or
+// elastic-net penalty, support constant, inverse-scaling and "optimal"
+// learning-rate schedules, and can average their weights over every
+// update. All three can be trained one batch at a time with
+// PartialFit.
+//
+// Only non-class FloatAttributes are used as features. SGD is sensitive
+// to feature scaling, so these should usually be standardised first.
+//
+// For more information:
+//
+// L. Bottou (2010). Large-scale machine learning with stochastic
+// gradient descent. COMPSTAT, pp. 177-186.
+package sgd
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+	"math/rand"
+)
+
+// Penalty is the regularisation term added to the loss.
+type Penalty int
+
+const (
+	// NoPenalty doesn't regularise the weights.
+	NoPenalty Penalty = iota
+	// L2Penalty adds Alpha/2 times the squared L2 norm of the weights.
+	L2Penalty
+	// L1Penalty adds Alpha times the L1 norm of the weights, which
+	// drives the weights of uninformative features to zero.
+	L1Penalty
+	// ElasticNetPenalty mixes the two: L1Ratio of the penalty is L1
+	// and the rest is L2.
+	ElasticNetPenalty
+)
+
+// String returns a human-readable name for the Penalty.
+func (p Penalty) String() string {
+	switch p {
+	case NoPenalty:
+		return "none"
+	case L2Penalty:
+		return "l2"
+	case L1Penalty:
+		return "l1"
+	case ElasticNetPenalty:
+		return "elasticnet"
+	}
+	return fmt.Sprintf("Penalty(%d)", int(p))
+}
+
+// Schedule controls how the learning rate changes with the number of
+// updates t made so far.
+type Schedule int
+
+const (
+	// ConstantSchedule uses Eta0 throughout.
+	ConstantSchedule Schedule = iota
+	// InverseScalingSchedule uses Eta0 / t^PowerT.
+	InverseScalingSchedule
+	// OptimalSchedule uses 1 / (Alpha (t0 + t)), where t0 is chosen
+	// with Bottou's heuristic. It needs Alpha to be positive.
+	OptimalSchedule
+)
+
+// String returns a human-readable name for the Schedule.
+func (s Schedule) String() string {
+	switch s {
+	case ConstantSchedule:
+		return "constant"
+	case InverseScalingSchedule:
+		return "invscaling"
+	case OptimalSchedule:
+		return "optimal"
+	}
+	return fmt.Sprintf("Schedule(%d)", int(s))
+}
+
+// Params holds the settings shared by SGDClassifier and SGDRegressor.
+type Params struct {
+	// Loss function to minimise.
+	Loss Loss
+	// Regularisation term.
+	Penalty Penalty
+	// Strength of the regularisation.
+	Alpha float64
+	// Fraction of an ElasticNetPenalty which is L1.
+	L1Ratio float64
+	// Learning-rate schedule.
+	LearningRate Schedule
+	// Initial learning rate for the constant and inverse-scaling
+	// schedules.
+	Eta0 float64
+	// Exponent of the inverse-scaling schedule.
+	PowerT float64
+	// Maximum number of passes over the data made by Fit.
+	MaxIterations int
+	// Fit stops once the average loss of a pass has failed to improve
+	// on the best so far by at least Tolerance for five passes in a
+	// row. If zero, every iteration is run.
+	Tolerance float64
+	// Visit the rows in a random order on each pass.
+	Shuffle bool
+	// Seeds the random order of the rows. Training starts again from
+	// the seed on each Fit, so fitting the same data twice gives the
+	// same model.
+	Seed int64
+	// Report the average of the weights over every update rather than
+	// the final weights.
+	Average bool
+	// Fit an (unregularised) intercept.
+	FitIntercept bool
+	// Width of the epsilon-insensitive zone.
+	Epsilon float64
+}
+
+// noImprovementLimit is the number of passes without an improvement in
+// the loss that Fit tolerates before stopping.
+const noImprovementLimit = 5
+
+// validate checks the settings, given the losses the model supports.
+func (p *Params) validate(losses ...Loss) error {
+	supported := false
+	for _, l := range losses {
+		if p.Loss == l {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("Unsupported loss: %s", p.Loss)
+	}
+	if p.Alpha < 0 {
+		return fmt.Errorf("Alpha must not be negative")
+	}
+	if p.L1Ratio < 0 || p.L1Ratio > 1 {
+		return fmt.Errorf("L1Ratio must be between 0 and 1")
+	}
+	if p.Penalty < NoPenalty || p.Penalty > ElasticNetPenalty {
+		return fmt.Errorf("Unsupported penalty: %s", p.Penalty)
+	}
+	switch p.LearningRate {
+	case ConstantSchedule, InverseScalingSchedule:
+		if p.Eta0 <= 0 {
+			return fmt.Errorf("Eta0 must be positive")
+		}
+	case OptimalSchedule:
+		if p.Alpha <= 0 {
+			return fmt.Errorf("The optimal learning rate needs a positive Alpha")
+		}
+	default:
+		return fmt.Errorf("Unsupported learning rate: %s", p.LearningRate)
+	}
+	if p.MaxIterations < 1 {
+		return fmt.Errorf("MaxIterations must be positive")
+	}
+	return nil
+}
+
+// l1Strength and l2Strength split Alpha between the two penalties.
+func (p *Params) l1Strength() float64 {
+	switch p.Penalty {
+	case L1Penalty:
+		return p.Alpha
+	case ElasticNetPenalty:
+		return p.Alpha * p.L1Ratio
+	}
+	return 0
+}
+
+func (p *Params) l2Strength() float64 {
+	switch p.Penalty {
+	case L2Penalty:
+		return p.Alpha
+	case ElasticNetPenalty:
+		return p.Alpha * (1 - p.L1Ratio)
+	}
+	return 0
+}
+
+// weightVector is a single linear function w.x + b, along with the
+// state needed by the L1 penalty and averaging.
+type weightVector struct {
+	w []float64
+	b float64
+	// Running averages of w and b
+	avgW []float64
+	avgB float64
+	// Total L1 penalty each weight has actually received, for the
+	// cumulative penalty of Tsuruoka et al.
+	q []float64
+}
+
+func newWeightVector(features int) *weightVector {
+	return &weightVector{
+		w:    make([]float64, features),
+		avgW: make([]float64, features),
+		q:    make([]float64, features),
+	}
+}
+
+// dot returns w.x + b.
+func (v *weightVector) dot(x []float64) float64 {
+	ret := v.b
+	for j, val := range x {
+		ret += v.w[j] * val
+	}
+	return ret
+}
+
+// weights returns the weights and intercept that should be used for
+// predictions.
+func (v *weightVector) weights(average bool) ([]float64, float64) {
+	if average {
+		return v.avgW, v.avgB
+	}
+	return v.w, v.b
+}
+
+// model holds the training state shared by the estimators: one
+// weightVector per binary problem, and the number of updates so far.
+type model struct {
+	Params
+	vectors []*weightVector
+	attrs   []base.Attribute
+	// Number of rows seen, which sets the learning rate
+	t float64
+	// Total L1 penalty that could have been applied to each weight
+	u float64
+	// t0 for the optimal learning rate
+	optimalInit float64
+	// Source of the order in which rows are visited
+	rng    *rand.Rand
+	fitted bool
+}
+
+// reset allocates n weightVectors for the given features.
+func (m *model) reset(n int, attrs []base.Attribute) {
+	m.attrs = attrs
+	m.vectors = make([]*weightVector, n)
+	for k := range m.vectors {
+		m.vectors[k] = newWeightVector(len(attrs))
+	}
+	m.t = 1
+	m.u = 0
+	m.rng = rand.New(rand.NewSource(m.Seed))
+	if m.LearningRate == OptimalSchedule {
+		// Bottou's heuristic: pick t0 so the initial step wouldn't
+		// make a weight of typical size much larger.
+		typical := math.Sqrt(1.0 / math.Sqrt(m.Alpha))
+		eta0 := typical / math.Max(1.0, m.Loss.derivative(-typical, 1.0, m.Epsilon))
+		m.optimalInit = 1.0 / (eta0 * m.Alpha)
+	}
+}
+
+// eta returns the current learning rate.
+func (m *model) eta() float64 {
+	switch m.LearningRate {
+	case InverseScalingSchedule:
+		return m.Eta0 / math.Pow(m.t, m.PowerT)
+	case OptimalSchedule:
+		return 1.0 / (m.Alpha * (m.optimalInit + m.t - 1))
+	}
+	return m.Eta0
+}
+
+// step updates every weightVector with a single row x, whose target for
+// vector k is targets[k]. It returns the sum of the losses before the
+// update.
+func (m *model) step(x []float64, targets []float64) float64 {
+	eta := m.eta()
+	l1 := m.l1Strength()
+	l2 := m.l2Strength()
+	m.u += eta * l1
+	total := 0.0
+	for k, v := range m.vectors {
+		p := v.dot(x)
+		total += m.Loss.loss(p, targets[k], m.Epsilon)
+		d := m.Loss.derivative(p, targets[k], m.Epsilon)
+
+		if l2 > 0 {
+			shrink := math.Max(0, 1-eta*l2)
+			for j := range v.w {
+				v.w[j] *= shrink
+			}
+		}
+		if d != 0 {
+			for j, val := range x {
+				v.w[j] -= eta * d * val
+			}
+			if m.FitIntercept {
+				v.b -= eta * d
+			}
+		}
+		if l1 > 0 {
+			for j, w := range v.w {
+				if w > 0 {
+					v.w[j] = math.Max(0, w-(m.u+v.q[j]))
+				} else if w < 0 {
+					v.w[j] = math.Min(0, w+(m.u-v.q[j]))
+				}
+				v.q[j] += v.w[j] - w
+			}
+		}
+		if m.Average {
+			for j := range v.w {
+				v.avgW[j] += (v.w[j] - v.avgW[j]) / m.t
+			}
+			v.avgB += (v.b - v.avgB) / m.t
+		}
+	}
+	m.t++
+	return total
+}
+
+// epoch makes one pass over the rows, returning the average loss.
+func (m *model) epoch(features [][]float64, targets [][]float64, shuffle bool) float64 {
+	order := make([]int, len(features))
+	for i := range order {
+		order[i] = i
+	}
+	if shuffle {
+		for i := range order {
+			j := m.rng.Intn(i + 1)
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+	total := 0.0
+	for _, i := range order {
+		total += m.step(features[i], targets[i])
+	}
+	return total / float64(len(features))
+}
+
+// train makes up to MaxIterations passes over the rows, stopping early
+// once the loss stops improving.
+func (m *model) train(features [][]float64, targets [][]float64) {
+	best := math.Inf(1)
+	noImprovement := 0
+	for iteration := 0; iteration < m.MaxIterations; iteration++ {
+		loss := m.epoch(features, targets, m.Shuffle)
+		if m.Tolerance <= 0 {
+			continue
+		}
+		if loss > best-m.Tolerance {
+			noImprovement++
+		} else {
+			noImprovement = 0
+		}
+		best = math.Min(best, loss)
+		if noImprovement >= noImprovementLimit {
+			break
+		}
+	}
+}
+
+// readFeatures returns the values of the model's Attributes in X.
+func (m *model) readFeatures(X base.FixedDataGrid) ([][]float64, error) {
+	for _, a := range m.attrs {
+		if _, err := X.GetAttribute(a); err != nil {
+			return nil, fmt.Errorf("Attribute %s is missing: %s", a.GetName(), err)
+		}
+	}
+	_, rows := X.Size()
+	ret := make([][]float64, rows)
+	X.MapOverRows(base.ResolveAttributes(X, m.attrs), func(row [][]byte, i int) (bool, error) {
+		ret[i] = make([]float64, len(row))
+		for j, v := range row {
+			ret[i][j] = base.UnpackBytesToFloat(v)
+		}
+		return true, nil
+	})
+	return ret, nil
+}
+
+// featureAttributes returns the non-class FloatAttributes of X.
+func featureAttributes(X base.FixedDataGrid) ([]base.Attribute, error) {
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return nil, fmt.Errorf("No FloatAttributes to fit")
+	}
+	return attrs, nil
+}
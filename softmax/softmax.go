@@ -0,0 +1,471 @@
+// Package softmax implements multinomial logistic regression, written
+// in pure Go so it can be used without cgo.
+//
+// SoftmaxRegression:
+//
+//	Fits one linear function per class, all at once, and turns them
+//	into class probabilities with the softmax function. It supports
+//	L1 and L2 penalties.
+package softmax
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+	"math"
+	"sort"
+)
+
+// SoftmaxRegression is multinomial logistic regression. It models the
+// probability of each class k as
+//
+// P(k | x) = \frac{\exp(w_k x + b_k)}{\sum_j \exp(w_j x + b_j)}
+//
+// and fits every class at once (rather than one versus the rest) by
+// minimising
+//
+// C \sum_i -\log P(y_i | x_i) + R(w)
+//
+// where R is \frac{1}{2} ||w||^2 for the "l2" penalty, ||w||_1 for
+// "l1" and zero for "none". The intercepts are not penalised. The
+// objective is minimised by accelerated proximal gradient descent, so
+// the L1 penalty sets the weights of uninformative features to exactly
+// zero.
+//
+// Only non-class FloatAttributes are used as features. There must be a
+// single class Attribute, with at least two distinct values.
+//
+// For more information:
+//
+// A. Beck and M. Teboulle (2009). A fast iterative shrinkage-thresholding
+// algorithm for linear inverse problems. SIAM Journal on Imaging
+// Sciences, 2(1), pp. 183-202.
+type SoftmaxRegression struct {
+	// Regularisation: "l1", "l2" or "none".
+	Penalty string
+	// Inverse of the strength of the penalty, as in linear_models.LogisticRegression.
+	C float64
+	// Fitting stops once no weight changed by more than Tolerance
+	// times the largest weight in an iteration.
+	Tolerance float64
+	// Maximum number of gradient steps.
+	MaxIterations int
+	// Fit an intercept for each class.
+	FitIntercept bool
+
+	attrs   []base.Attribute
+	classes []string
+	// One row of weights per class
+	weights    [][]float64
+	intercepts []float64
+	// Number of steps made by the last Fit
+	iterations int
+	fitted     bool
+}
+
+// NewSoftmaxRegression returns a SoftmaxRegression with the given
+// penalty ("l1", "l2" or "none"), inverse penalty strength C and
+// tolerance eps, which fits an intercept.
+func NewSoftmaxRegression(penalty string, C float64, eps float64) (*SoftmaxRegression, error) {
+	s := &SoftmaxRegression{
+		Penalty:       penalty,
+		C:             C,
+		Tolerance:     eps,
+		MaxIterations: 1000,
+		FitIntercept:  true,
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// String returns a human-readable summary.
+func (s *SoftmaxRegression) String() string {
+	return fmt.Sprintf("SoftmaxRegression(penalty: %s, C: %g)", s.Penalty, s.C)
+}
+
+// validate checks the settings.
+func (s *SoftmaxRegression) validate() error {
+	switch s.Penalty {
+	case "l1", "l2", "none":
+	default:
+		return fmt.Errorf("Invalid penalty '%s'", s.Penalty)
+	}
+	if s.C <= 0 {
+		return fmt.Errorf("C must be positive")
+	}
+	if s.Tolerance < 0 {
+		return fmt.Errorf("Tolerance must not be negative")
+	}
+	if s.MaxIterations < 1 {
+		return fmt.Errorf("MaxIterations must be positive")
+	}
+	return nil
+}
+
+// softmaxProblem is training data ready for fitting. The parameters
+// are stored in a single vector, one block of (features + 1) per class
+// with the intercept last.
+type softmaxProblem struct {
+	// One row of features after another
+	x        []float64
+	features int
+	y        []int
+	classes  int
+	// Strengths of the penalties on the mean loss
+	l1, l2 float64
+	// Whether the intercepts are fitted
+	intercept bool
+}
+
+// Fit estimates the weights from X.
+func (s *SoftmaxRegression) Fit(X base.FixedDataGrid) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	if len(X.AllClassAttributes()) != 1 {
+		return fmt.Errorf("Only 1 class variable is permitted")
+	}
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return fmt.Errorf("No FloatAttributes to fit")
+	}
+	_, rows := X.Size()
+	if rows == 0 {
+		return base.NoTrainingDataError
+	}
+
+	// Collect the classes in sorted order
+	labels := make([]string, rows)
+	unique := make(map[string]bool)
+	classes := make([]string, 0)
+	for i := range labels {
+		labels[i] = base.GetClass(X, i)
+		if !unique[labels[i]] {
+			unique[labels[i]] = true
+			classes = append(classes, labels[i])
+		}
+	}
+	if len(classes) < 2 {
+		return fmt.Errorf("Need at least two classes")
+	}
+	sort.Strings(classes)
+	index := make(map[string]int)
+	for k, c := range classes {
+		index[c] = k
+	}
+
+	x, err := base.CopyFloatRows(X, attrs)
+	if err != nil {
+		return err
+	}
+	p := &softmaxProblem{
+		x:         x,
+		features:  len(attrs),
+		y:         make([]int, rows),
+		classes:   len(classes),
+		intercept: s.FitIntercept,
+	}
+	for i, l := range labels {
+		p.y[i] = index[l]
+	}
+	lambda := 1 / (s.C * float64(rows))
+	switch s.Penalty {
+	case "l1":
+		p.l1 = lambda
+	case "l2":
+		p.l2 = lambda
+	}
+
+	theta, iterations := p.minimise(s.MaxIterations, s.Tolerance)
+	width := len(attrs) + 1
+	s.weights = make([][]float64, len(classes))
+	s.intercepts = make([]float64, len(classes))
+	for k := range classes {
+		s.weights[k] = theta[k*width : k*width+len(attrs)]
+		s.intercepts[k] = theta[k*width+len(attrs)]
+	}
+	s.attrs = attrs
+	s.classes = classes
+	s.iterations = iterations
+	s.fitted = true
+	return nil
+}
+
+// scores sets out[k] to w_k x + b_k for each class.
+func (p *softmaxProblem) scores(theta, x, out []float64) {
+	width := len(x) + 1
+	for k := range out {
+		w := theta[k*width : (k+1)*width]
+		out[k] = w[len(x)]
+		for j, v := range x {
+			out[k] += w[j] * v
+		}
+	}
+}
+
+// normalise turns scores into probabilities in place, returning the
+// log of their normalising constant.
+func normalise(scores []float64) float64 {
+	max := math.Inf(-1)
+	for _, s := range scores {
+		max = math.Max(max, s)
+	}
+	total := 0.0
+	for k, s := range scores {
+		scores[k] = math.Exp(s - max)
+		total += scores[k]
+	}
+	for k := range scores {
+		scores[k] /= total
+	}
+	return max + math.Log(total)
+}
+
+// smooth returns the mean loss plus the L2 penalty at theta, and
+// writes its gradient to grad if that isn't nil.
+func (p *softmaxProblem) smooth(theta, grad []float64) float64 {
+	features := p.features
+	width := features + 1
+	if grad != nil {
+		for i := range grad {
+			grad[i] = 0
+		}
+	}
+	n := float64(len(p.y))
+	probs := make([]float64, p.classes)
+	loss := 0.0
+	for i := range p.y {
+		x := p.x[i*features : (i+1)*features]
+		p.scores(theta, x, probs)
+		score := probs[p.y[i]]
+		loss += normalise(probs) - score
+		if grad == nil {
+			continue
+		}
+		for k, prob := range probs {
+			d := prob
+			if k == p.y[i] {
+				d -= 1
+			}
+			g := grad[k*width : (k+1)*width]
+			for j, v := range x {
+				g[j] += d * v / n
+			}
+			g[features] += d / n
+		}
+	}
+	loss /= n
+	for k := 0; k < p.classes; k++ {
+		for j := 0; j < features; j++ {
+			w := theta[k*width+j]
+			loss += p.l2 * w * w / 2
+			if grad != nil {
+				grad[k*width+j] += p.l2 * w
+			}
+		}
+		if grad != nil && !p.intercept {
+			grad[k*width+features] = 0
+		}
+	}
+	return loss
+}
+
+// prox applies the proximal operator of the L1 penalty with the given
+// step size, soft-thresholding every weight except the intercepts.
+func (p *softmaxProblem) prox(theta []float64, step float64) {
+	if p.l1 == 0 {
+		return
+	}
+	width := len(theta) / p.classes
+	threshold := step * p.l1
+	for i, w := range theta {
+		if i%width == width-1 {
+			continue
+		}
+		if w > threshold {
+			theta[i] = w - threshold
+		} else if w < -threshold {
+			theta[i] = w + threshold
+		} else {
+			theta[i] = 0
+		}
+	}
+}
+
+// l1Norm returns the L1 penalty at theta.
+func (p *softmaxProblem) l1Norm(theta []float64) float64 {
+	width := len(theta) / p.classes
+	ret := 0.0
+	for i, w := range theta {
+		if i%width != width-1 {
+			ret += math.Abs(w)
+		}
+	}
+	return p.l1 * ret
+}
+
+// minimise runs FISTA with a backtracking line search and adaptive
+// restarts, starting from zero. It returns the parameters and the
+// number of iterations made.
+func (p *softmaxProblem) minimise(maxIterations int, tolerance float64) ([]float64, int) {
+	size := p.classes * (p.features + 1)
+	theta := make([]float64, size)
+	momentum := make([]float64, size)
+	next := make([]float64, size)
+	grad := make([]float64, size)
+	lipschitz := 1.0
+	t := 1.0
+	objective := p.smooth(theta, nil) + p.l1Norm(theta)
+
+	iteration := 0
+	for iteration < maxIterations {
+		iteration++
+		f := p.smooth(momentum, grad)
+		var fNext float64
+		for {
+			for i := range next {
+				next[i] = momentum[i] - grad[i]/lipschitz
+			}
+			p.prox(next, 1/lipschitz)
+			// Accept the step once the quadratic model bounds the loss
+			bound := f
+			for i := range next {
+				d := next[i] - momentum[i]
+				bound += grad[i]*d + lipschitz*d*d/2
+			}
+			fNext = p.smooth(next, nil)
+			if fNext <= bound+1e-12 || lipschitz > 1e12 {
+				break
+			}
+			lipschitz *= 2
+		}
+
+		change, largest := 0.0, 0.0
+		for i := range next {
+			change = math.Max(change, math.Abs(next[i]-theta[i]))
+			largest = math.Max(largest, math.Abs(next[i]))
+		}
+
+		nextObjective := fNext + p.l1Norm(next)
+		if nextObjective > objective {
+			// Restart the momentum if it took us uphill
+			t = 1
+			copy(momentum, theta)
+			continue
+		}
+		tNext := (1 + math.Sqrt(1+4*t*t)) / 2
+		for i := range momentum {
+			momentum[i] = next[i] + (t-1)/tNext*(next[i]-theta[i])
+		}
+		copy(theta, next)
+		t = tNext
+		objective = nextObjective
+		// Let the step size grow again
+		lipschitz /= 1.1
+
+		if change <= tolerance*math.Max(1, largest) {
+			break
+		}
+	}
+	return theta, iteration
+}
+
+// PredictProba returns the probability of each class, as one
+// FloatAttribute per class value.
+func (s *SoftmaxRegression) PredictProba(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	probs, err := s.probabilities(X)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, len(s.classes))
+	for k, c := range s.classes {
+		specs[k] = ret.AddAttribute(base.NewFloatAttribute(c))
+	}
+	ret.Extend(len(probs))
+	for i, row := range probs {
+		for k, prob := range row {
+			ret.Set(specs[k], i, base.PackFloatToBytes(prob))
+		}
+	}
+	return ret, nil
+}
+
+// Predict returns the most probable class of each row of X. Ties go
+// to the class which sorts first.
+func (s *SoftmaxRegression) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	probs, err := s.probabilities(X)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.GeneratePredictionVector(X)
+	for i, row := range probs {
+		best := 0
+		for k, prob := range row {
+			if prob > row[best] {
+				best = k
+			}
+		}
+		base.SetClass(ret, i, s.classes[best])
+	}
+	return ret, nil
+}
+
+// probabilities returns the probability of each class for each row of
+// X.
+func (s *SoftmaxRegression) probabilities(X base.FixedDataGrid) ([][]float64, error) {
+	if !s.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	for _, a := range s.attrs {
+		if _, err := X.GetAttribute(a); err != nil {
+			return nil, fmt.Errorf("Attribute %s is missing: %s", a.GetName(), err)
+		}
+	}
+	values, err := base.CopyFloatRows(X, s.attrs)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := X.Size()
+	features := len(s.attrs)
+	ret := make([][]float64, rows)
+	for i := range ret {
+		x := values[i*features : (i+1)*features]
+		ret[i] = make([]float64, len(s.classes))
+		for k := range s.classes {
+			ret[i][k] = s.intercepts[k]
+			for j, v := range x {
+				ret[i][k] += s.weights[k][j] * v
+			}
+		}
+		normalise(ret[i])
+	}
+	return ret, nil
+}
+
+// GetClasses returns the class values, sorted.
+func (s *SoftmaxRegression) GetClasses() []string {
+	return s.classes
+}
+
+// GetCoefficients returns the weights of each class, one row per class
+// (in the order of GetClasses) and one column per feature.
+func (s *SoftmaxRegression) GetCoefficients() [][]float64 {
+	ret := make([][]float64, len(s.weights))
+	for k, w := range s.weights {
+		ret[k] = append([]float64(nil), w...)
+	}
+	return ret
+}
+
+// GetIntercepts returns the intercept of each class.
+func (s *SoftmaxRegression) GetIntercepts() []float64 {
+	return append([]float64(nil), s.intercepts...)
+}
+
+// GetAttributes returns the features used by the model, in the order
+// of the columns of GetCoefficients.
+func (s *SoftmaxRegression) GetAttributes() []base.Attribute {
+	return s.attrs
+}
@@ -0,0 +1,136 @@
+package softmax
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestSoftmaxRegression(t *testing.T) {
+	Convey("Given the iris dataset", t, func() {
+		iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("Invalid settings should be rejected", func() {
+			_, err := NewSoftmaxRegression("l3", 1.0, 1e-4)
+			So(err, ShouldNotBeNil)
+			_, err = NewSoftmaxRegression("l2", 0, 1e-4)
+			So(err, ShouldNotBeNil)
+
+			s, err := NewSoftmaxRegression("l2", 1.0, 1e-4)
+			So(err, ShouldBeNil)
+			s.MaxIterations = 0
+			So(s.Fit(iris), ShouldNotBeNil)
+		})
+
+		Convey("Predicting before Fit should return an error", func() {
+			s, err := NewSoftmaxRegression("l2", 1.0, 1e-4)
+			So(err, ShouldBeNil)
+			_, err = s.Predict(iris)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+			_, err = s.PredictProba(iris)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Fitting no rows should return a NoTrainingDataError", func() {
+			s, err := NewSoftmaxRegression("l2", 1.0, 1e-4)
+			So(err, ShouldBeNil)
+			empty := base.NewInstancesViewFromVisible(iris, []int{}, iris.AllAttributes())
+			So(s.Fit(empty), ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("With an L2 penalty", func() {
+			s, err := NewSoftmaxRegression("l2", 10.0, 1e-6)
+			So(err, ShouldBeNil)
+			So(s.Fit(iris), ShouldBeNil)
+			So(s.GetClasses(), ShouldResemble, []string{"Iris-setosa", "Iris-versicolor", "Iris-virginica"})
+			So(len(s.GetCoefficients()), ShouldEqual, 3)
+			So(len(s.GetCoefficients()[0]), ShouldEqual, 4)
+
+			Convey("It should fit the training data well", func() {
+				predictions, err := s.Predict(iris)
+				So(err, ShouldBeNil)
+				cf, err := evaluation.GetConfusionMatrix(iris, predictions)
+				So(err, ShouldBeNil)
+				So(evaluation.GetAccuracy(cf), ShouldBeGreaterThan, 0.95)
+			})
+
+			Convey("The probabilities should sum to one and agree with Predict", func() {
+				probs, err := s.PredictProba(iris)
+				So(err, ShouldBeNil)
+				predictions, err := s.Predict(iris)
+				So(err, ShouldBeNil)
+				specs := base.ResolveAttributes(probs, probs.AllAttributes())
+				_, rows := probs.Size()
+				for i := 0; i < rows; i++ {
+					total, best := 0.0, 0
+					for k, spec := range specs {
+						p := base.UnpackBytesToFloat(probs.Get(spec, i))
+						total += p
+						if p > base.UnpackBytesToFloat(probs.Get(specs[best], i)) {
+							best = k
+						}
+					}
+					So(total, ShouldAlmostEqual, 1.0, 1e-9)
+					So(base.GetClass(predictions, i), ShouldEqual, s.GetClasses()[best])
+				}
+			})
+		})
+
+		Convey("A strong L1 penalty should zero some weights", func() {
+			weak, err := NewSoftmaxRegression("l1", 100.0, 1e-6)
+			So(err, ShouldBeNil)
+			So(weak.Fit(iris), ShouldBeNil)
+			strong, err := NewSoftmaxRegression("l1", 0.05, 1e-6)
+			So(err, ShouldBeNil)
+			So(strong.Fit(iris), ShouldBeNil)
+
+			zeros := func(s *SoftmaxRegression) int {
+				ret := 0
+				for _, row := range s.GetCoefficients() {
+					for _, w := range row {
+						if w == 0 {
+							ret++
+						}
+					}
+				}
+				return ret
+			}
+			So(zeros(strong), ShouldBeGreaterThan, zeros(weak))
+			So(zeros(strong), ShouldBeGreaterThan, 0)
+		})
+
+		Convey("Without an intercept, the intercepts should stay at zero", func() {
+			s, err := NewSoftmaxRegression("none", 1.0, 1e-4)
+			So(err, ShouldBeNil)
+			s.FitIntercept = false
+			So(s.Fit(iris), ShouldBeNil)
+			So(s.GetIntercepts(), ShouldResemble, []float64{0, 0, 0})
+		})
+	})
+
+	Convey("The gradient should match finite differences", t, func() {
+		p := &softmaxProblem{
+			x:         []float64{1, 2, -1, 0.5, 0.3, -2, 2, 2},
+			features:  2,
+			y:         []int{0, 1, 2, 1},
+			classes:   3,
+			l2:        0.1,
+			intercept: true,
+		}
+		theta := []float64{0.1, -0.2, 0.3, 0.5, 0.4, -0.1, -0.3, 0.2, 0.05}
+		grad := make([]float64, len(theta))
+		p.smooth(theta, grad)
+		for i := range theta {
+			old := theta[i]
+			theta[i] = old + 1e-6
+			up := p.smooth(theta, nil)
+			theta[i] = old - 1e-6
+			down := p.smooth(theta, nil)
+			theta[i] = old
+			So(math.Abs(grad[i]-(up-down)/2e-6), ShouldBeLessThan, 1e-6)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package svm
+
+import (
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"sort"
+)
+
+// SVC is a kernel support vector classifier. A binary problem is
+// solved for every pair of classes, and each row is assigned to the
+// class which wins the most of them (ties go to the class which comes
+// first in sorted order).
+type SVC struct {
+	Kernel Kernel
+	// Penalty for margin violations. Larger values fit the training
+	// data more closely.
+	C float64
+	// Stop once the largest violation of the optimality conditions is
+	// below Tolerance.
+	Tolerance float64
+	// Size of the kernel cache used while training, in megabytes.
+	CacheSize float64
+	// Maximum number of SMO iterations for each pair of classes.
+	MaxIterations int
+	attrs         []base.Attribute
+	classes       []string
+	// One decision function per pair of classes a < b, ordered
+	// (0, 1), (0, 2)... (1, 2)... and positive for class a
+	pairs  []*supportVectors
+	fitted bool
+}
+
+// NewSVC returns an SVC using the given kernel, with C = 1.
+func NewSVC(kernel Kernel) *SVC {
+	return &SVC{
+		Kernel:        kernel,
+		C:             1.0,
+		Tolerance:     1e-3,
+		CacheSize:     100,
+		MaxIterations: 1000000,
+	}
+}
+
+// String returns a human-readable summary.
+func (s *SVC) String() string {
+	return fmt.Sprintf("SVC(C: %g, tolerance: %g)", s.C, s.Tolerance)
+}
+
+// Fit trains one binary support vector machine per pair of classes.
+func (s *SVC) Fit(X base.FixedDataGrid) error {
+	if err := validateParameters(s.Kernel, s.C, s.Tolerance, s.CacheSize, s.MaxIterations); err != nil {
+		return err
+	}
+	attrs, err := featureAttributes(X)
+	if err != nil {
+		return err
+	}
+	vectors, err := readVectors(X, attrs)
+	if err != nil {
+		return err
+	}
+
+	// Group the rows by class
+	byClass := make(map[string][]int)
+	for i := range vectors {
+		c := base.GetClass(X, i)
+		byClass[c] = append(byClass[c], i)
+	}
+	classes := make([]string, 0, len(byClass))
+	for c := range byClass {
+		classes = append(classes, c)
+	}
+	if len(classes) < 2 {
+		return fmt.Errorf("Need at least two classes")
+	}
+	sort.Strings(classes)
+
+	pairs := make([]*supportVectors, 0)
+	for a := 0; a < len(classes); a++ {
+		for b := a + 1; b < len(classes); b++ {
+			pairVectors := make([]*mat64.Dense, 0)
+			y := make([]float64, 0)
+			for _, i := range byClass[classes[a]] {
+				pairVectors = append(pairVectors, vectors[i])
+				y = append(y, 1)
+			}
+			for _, i := range byClass[classes[b]] {
+				pairVectors = append(pairVectors, vectors[i])
+				y = append(y, -1)
+			}
+			sv, err := s.fitPair(pairVectors, y)
+			if err != nil {
+				return fmt.Errorf("%s versus %s: %s", classes[a], classes[b], err)
+			}
+			pairs = append(pairs, sv)
+		}
+	}
+
+	s.attrs = attrs
+	s.classes = classes
+	s.pairs = pairs
+	s.fitted = true
+	return nil
+}
+
+// fitPair solves the binary problem of separating vectors labelled +1
+// from those labelled -1.
+func (s *SVC) fitPair(vectors []*mat64.Dense, y []float64) (*supportVectors, error) {
+	p := make([]float64, len(y))
+	sample := make([]int, len(y))
+	for t := range p {
+		p[t] = -1
+		sample[t] = t
+	}
+	problem := &smoProblem{
+		y:             y,
+		p:             p,
+		sample:        sample,
+		c:             s.C,
+		cache:         newKernelCache(s.Kernel, vectors, s.CacheSize),
+		tolerance:     s.Tolerance,
+		maxIterations: s.MaxIterations,
+	}
+	alpha, rho, err := problem.solve()
+	if err != nil {
+		return nil, err
+	}
+	ret := &supportVectors{rho: rho}
+	for t, a := range alpha {
+		if a > 0 {
+			ret.vectors = append(ret.vectors, vectors[t])
+			ret.coefficients = append(ret.coefficients, y[t]*a)
+		}
+	}
+	return ret, nil
+}
+
+// Predict returns the class of each row of X which wins the most
+// pairwise contests.
+func (s *SVC) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !s.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	vectors, err := readVectors(X, s.attrs)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.GeneratePredictionVector(X)
+	for i, x := range vectors {
+		votes := make([]int, len(s.classes))
+		k := 0
+		for a := 0; a < len(s.classes); a++ {
+			for b := a + 1; b < len(s.classes); b++ {
+				if s.pairs[k].decision(s.Kernel, x) > 0 {
+					votes[a]++
+				} else {
+					votes[b]++
+				}
+				k++
+			}
+		}
+		best := 0
+		for c, v := range votes {
+			if v > votes[best] {
+				best = c
+			}
+		}
+		base.SetClass(ret, i, s.classes[best])
+	}
+	return ret, nil
+}
+
+// DecisionFunction returns the value of each pairwise decision
+// function for each row of X, as one FloatAttribute per pair named
+// "a vs b". Positive values favour a.
+func (s *SVC) DecisionFunction(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if !s.fitted {
+		return nil, base.NoTrainingDataError
+	}
+	vectors, err := readVectors(X, s.attrs)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.NewDenseInstances()
+	specs := make([]base.AttributeSpec, 0, len(s.pairs))
+	for a := 0; a < len(s.classes); a++ {
+		for b := a + 1; b < len(s.classes); b++ {
+			name := fmt.Sprintf("%s vs %s", s.classes[a], s.classes[b])
+			specs = append(specs, ret.AddAttribute(base.NewFloatAttribute(name)))
+		}
+	}
+	ret.Extend(len(vectors))
+	for i, x := range vectors {
+		for k, pair := range s.pairs {
+			ret.Set(specs[k], i, base.PackFloatToBytes(pair.decision(s.Kernel, x)))
+		}
+	}
+	return ret, nil
+}
+
+// GetClasses returns the class values, sorted.
+func (s *SVC) GetClasses() []string {
+	return s.classes
+}
+
+// GetSupportVectorCount returns the number of distinct training rows
+// used as support vectors by any of the pairwise decision functions.
+func (s *SVC) GetSupportVectorCount() int {
+	seen := make(map[*mat64.Dense]bool)
+	for _, pair := range s.pairs {
+		for _, v := range pair.vectors {
+			seen[v] = true
+		}
+	}
+	return len(seen)
+}
@@ -0,0 +1,115 @@
+package svm
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/evaluation"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+// circles returns points on two concentric circles, labelled by which
+// circle they're on. They can't be separated linearly.
+func circles(points int) base.FixedDataGrid {
+	ret := base.NewDenseInstances()
+	xSpec := ret.AddAttribute(base.NewFloatAttribute("x"))
+	ySpec := ret.AddAttribute(base.NewFloatAttribute("y"))
+	cls := base.NewCategoricalAttribute()
+	cls.SetName("circle")
+	clsSpec := ret.AddAttribute(cls)
+	ret.AddClassAttribute(cls)
+	ret.Extend(2 * points)
+	for i := 0; i < 2*points; i++ {
+		radius, label := 1.0, "inner"
+		if i >= points {
+			radius, label = 3.0, "outer"
+		}
+		angle := 2 * math.Pi * float64(i) / float64(points)
+		ret.Set(xSpec, i, base.PackFloatToBytes(radius*math.Cos(angle)))
+		ret.Set(ySpec, i, base.PackFloatToBytes(radius*math.Sin(angle)))
+		ret.Set(clsSpec, i, cls.GetSysValFromString(label))
+	}
+	return ret
+}
+
+func TestSVC(t *testing.T) {
+	accuracy := func(s *SVC, X base.FixedDataGrid) float64 {
+		predictions, err := s.Predict(X)
+		So(err, ShouldBeNil)
+		cf, err := evaluation.GetConfusionMatrix(X, predictions)
+		So(err, ShouldBeNil)
+		return evaluation.GetAccuracy(cf)
+	}
+
+	Convey("Given two concentric circles", t, func() {
+		data := circles(40)
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewSVC(pairwise.NewRBFKernel(0.5)).Predict(data)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("Fitting no rows should return a NoTrainingDataError", func() {
+			empty := base.NewInstancesViewFromVisible(data, []int{}, data.AllAttributes())
+			So(NewSVC(pairwise.NewRBFKernel(0.5)).Fit(empty), ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("An RBF kernel should separate them", func() {
+			s := NewSVC(pairwise.NewRBFKernel(0.5))
+			So(s.Fit(data), ShouldBeNil)
+			So(accuracy(s, data), ShouldEqual, 1.0)
+			So(s.GetSupportVectorCount(), ShouldBeLessThan, 80)
+
+			Convey("Even with a tiny kernel cache", func() {
+				small := NewSVC(pairwise.NewRBFKernel(0.5))
+				small.CacheSize = 1e-6
+				So(small.Fit(data), ShouldBeNil)
+				expected, err := s.DecisionFunction(data)
+				So(err, ShouldBeNil)
+				actual, err := small.DecisionFunction(data)
+				So(err, ShouldBeNil)
+				spec := base.ResolveAllAttributes(expected)[0]
+				for i := 0; i < 80; i++ {
+					So(base.UnpackBytesToFloat(actual.Get(spec, i)), ShouldAlmostEqual, base.UnpackBytesToFloat(expected.Get(spec, i)), 1e-6)
+				}
+			})
+		})
+
+		Convey("So should a quadratic kernel", func() {
+			s := NewSVC(pairwise.NewPolyKernel(2))
+			So(s.Fit(data), ShouldBeNil)
+			So(accuracy(s, data), ShouldEqual, 1.0)
+		})
+
+		Convey("But a linear kernel shouldn't", func() {
+			s := NewSVC(pairwise.NewEuclidean())
+			So(s.Fit(data), ShouldBeNil)
+			So(accuracy(s, data), ShouldBeLessThan, 0.9)
+		})
+	})
+
+	Convey("Given the iris dataset", t, func() {
+		iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+		So(err, ShouldBeNil)
+
+		Convey("All three species should be classified one versus one", func() {
+			s := NewSVC(pairwise.NewRBFKernel(0.1))
+			s.C = 10
+			So(s.Fit(iris), ShouldBeNil)
+			So(accuracy(s, iris), ShouldBeGreaterThan, 0.96)
+			So(s.GetClasses(), ShouldResemble, []string{"Iris-setosa", "Iris-versicolor", "Iris-virginica"})
+
+			decisions, err := s.DecisionFunction(iris)
+			So(err, ShouldBeNil)
+			So(len(decisions.AllAttributes()), ShouldEqual, 3)
+		})
+
+		Convey("Invalid settings should be rejected", func() {
+			s := NewSVC(pairwise.NewRBFKernel(0.1))
+			s.C = 0
+			So(s.Fit(iris), ShouldNotBeNil)
+			So(NewSVC(nil).Fit(iris), ShouldNotBeNil)
+		})
+	})
+}
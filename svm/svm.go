@@ -0,0 +1,314 @@
+// Package svm implements kernel support vector machines, trained with
+// sequential minimal optimisation (SMO) and written in pure Go.
+//
+// SVC:
+//
+//	C-support vector classification. Multi-class problems are split
+//	into one binary problem per pair of classes (one versus one),
+//	and each prediction is decided by a vote.
+//
+// SVR:
+//
+//	Epsilon-support vector regression.
+//
+// Both accept any Kernel, such as pairwise.RBFKernel or
+// pairwise.PolyKernel, and use every non-class FloatAttribute as a
+// feature. The solver follows LIBSVM, selecting the working set with
+// second order information and caching recently used kernel rows.
+//
+// For more information:
+//
+// R.-E. Fan, P.-H. Chen and C.-J. Lin (2005). Working set selection
+// using second order information for training support vector machines.
+// Journal of Machine Learning Research, 6, pp. 1889-1918.
+//
+// C.-C. Chang and C.-J. Lin (2011). LIBSVM: a library for support
+// vector machines. ACM Transactions on Intelligent Systems and
+// Technology, 2(3), 27:1-27:27.
+package svm
+
+import (
+	"container/list"
+	"fmt"
+	"github.com/gonum/matrix/mat64"
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	"math"
+)
+
+// Kernel computes the inner product of two column vectors in some
+// feature space. Every pairwise.Kernel qualifies.
+type Kernel interface {
+	pairwise.Kernel
+}
+
+// tau replaces non-positive curvature in the working set selection.
+const tau = 1e-12
+
+// kernelCache computes rows of the kernel matrix of a set of vectors,
+// keeping the most recently used ones.
+type kernelCache struct {
+	kernel  Kernel
+	vectors []*mat64.Dense
+	// Maximum number of rows kept
+	capacity int
+	rows     map[int]*list.Element
+	// Least recently used at the back
+	order *list.List
+}
+
+type cachedRow struct {
+	index  int
+	values []float64
+}
+
+// newKernelCache returns a cache holding up to megabytes of kernel
+// rows (at least two).
+func newKernelCache(kernel Kernel, vectors []*mat64.Dense, megabytes float64) *kernelCache {
+	capacity := int(megabytes * (1 << 20) / (8 * float64(len(vectors))))
+	if capacity < 2 {
+		capacity = 2
+	}
+	return &kernelCache{
+		kernel:   kernel,
+		vectors:  vectors,
+		capacity: capacity,
+		rows:     make(map[int]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// row returns K(x_i, x_j) for every j.
+func (c *kernelCache) row(i int) []float64 {
+	if e, ok := c.rows[i]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*cachedRow).values
+	}
+	values := make([]float64, len(c.vectors))
+	for j, v := range c.vectors {
+		values[j] = c.kernel.InnerProduct(c.vectors[i], v)
+	}
+	if c.order.Len() >= c.capacity {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.rows, last.Value.(*cachedRow).index)
+	}
+	c.rows[i] = c.order.PushFront(&cachedRow{i, values})
+	return values
+}
+
+// smoProblem is the dual problem
+//
+// min 0.5 a'Qa + p'a  subject to  y'a = constant, 0 <= a_t <= C
+//
+// where Q_st = y_s y_t K(x_sample[s], x_sample[t]).
+type smoProblem struct {
+	y      []float64
+	p      []float64
+	sample []int
+	c      float64
+	cache  *kernelCache
+	// Stop once the maximal violation is below tolerance
+	tolerance     float64
+	maxIterations int
+}
+
+// solve returns the optimal a and the offset rho of the decision
+// function sum_t y_t a_t K(x_t, x) - rho.
+func (s *smoProblem) solve() ([]float64, float64, error) {
+	n := len(s.y)
+	alpha := make([]float64, n)
+	gradient := append([]float64(nil), s.p...)
+	diag := make([]float64, n)
+	for t := range diag {
+		diag[t] = s.cache.row(s.sample[t])[s.sample[t]]
+	}
+	upper := func(t int) bool {
+		return (s.y[t] > 0 && alpha[t] < s.c) || (s.y[t] < 0 && alpha[t] > 0)
+	}
+	lower := func(t int) bool {
+		return (s.y[t] > 0 && alpha[t] > 0) || (s.y[t] < 0 && alpha[t] < s.c)
+	}
+
+	converged := false
+	for iteration := 0; iteration < s.maxIterations; iteration++ {
+		// Pick i with the largest violation
+		i, gMax := -1, math.Inf(-1)
+		for t := 0; t < n; t++ {
+			if upper(t) && -s.y[t]*gradient[t] > gMax {
+				i, gMax = t, -s.y[t]*gradient[t]
+			}
+		}
+		if i < 0 {
+			converged = true
+			break
+		}
+		ki := s.cache.row(s.sample[i])
+
+		// Pick j to give the largest decrease in the objective
+		j, gMin, best := -1, math.Inf(1), math.Inf(1)
+		for t := 0; t < n; t++ {
+			if !lower(t) {
+				continue
+			}
+			g := -s.y[t] * gradient[t]
+			gMin = math.Min(gMin, g)
+			b := gMax - g
+			if b <= 0 {
+				continue
+			}
+			a := diag[i] + diag[t] - 2*ki[s.sample[t]]
+			if a <= 0 {
+				a = tau
+			}
+			if -b*b/a < best {
+				j, best = t, -b*b/a
+			}
+		}
+		if j < 0 || gMax-gMin < s.tolerance {
+			converged = true
+			break
+		}
+		kj := s.cache.row(s.sample[j])
+
+		// Solve the two variable sub-problem analytically
+		oldI, oldJ := alpha[i], alpha[j]
+		quad := diag[i] + diag[j] - 2*ki[s.sample[j]]
+		if quad <= 0 {
+			quad = tau
+		}
+		if s.y[i] != s.y[j] {
+			delta := (-gradient[i] - gradient[j]) / quad
+			diff := alpha[i] - alpha[j]
+			alpha[i] += delta
+			alpha[j] += delta
+			if diff > 0 && alpha[j] < 0 {
+				alpha[j], alpha[i] = 0, diff
+			} else if diff <= 0 && alpha[i] < 0 {
+				alpha[i], alpha[j] = 0, -diff
+			}
+			if diff > 0 && alpha[i] > s.c {
+				alpha[i], alpha[j] = s.c, s.c-diff
+			} else if diff <= 0 && alpha[j] > s.c {
+				alpha[j], alpha[i] = s.c, s.c+diff
+			}
+		} else {
+			delta := (gradient[i] - gradient[j]) / quad
+			sum := alpha[i] + alpha[j]
+			alpha[i] -= delta
+			alpha[j] += delta
+			if sum > s.c && alpha[i] > s.c {
+				alpha[i], alpha[j] = s.c, sum-s.c
+			} else if sum <= s.c && alpha[j] < 0 {
+				alpha[j], alpha[i] = 0, sum
+			}
+			if sum > s.c && alpha[j] > s.c {
+				alpha[j], alpha[i] = s.c, sum-s.c
+			} else if sum <= s.c && alpha[i] < 0 {
+				alpha[i], alpha[j] = 0, sum
+			}
+		}
+
+		// Update the gradient
+		di, dj := alpha[i]-oldI, alpha[j]-oldJ
+		for t := 0; t < n; t++ {
+			gradient[t] += s.y[t] * (s.y[i]*ki[s.sample[t]]*di + s.y[j]*kj[s.sample[t]]*dj)
+		}
+	}
+	if !converged {
+		return nil, 0, fmt.Errorf("SMO didn't converge in %d iterations", s.maxIterations)
+	}
+
+	// rho is the average of y_t G_t over the free variables, or the
+	// middle of its feasible range if there aren't any
+	free, sum := 0, 0.0
+	ub, lb := math.Inf(1), math.Inf(-1)
+	for t := 0; t < n; t++ {
+		yg := s.y[t] * gradient[t]
+		if alpha[t] > 0 && alpha[t] < s.c {
+			free++
+			sum += yg
+		} else if (alpha[t] >= s.c && s.y[t] < 0) || (alpha[t] <= 0 && s.y[t] > 0) {
+			ub = math.Min(ub, yg)
+		} else {
+			lb = math.Max(lb, yg)
+		}
+	}
+	if free > 0 {
+		return alpha, sum / float64(free), nil
+	}
+	return alpha, (ub + lb) / 2, nil
+}
+
+// supportVectors holds a fitted decision function
+// sum_t coefficients[t] K(vectors[t], x) - rho.
+type supportVectors struct {
+	vectors      []*mat64.Dense
+	coefficients []float64
+	rho          float64
+}
+
+// decision evaluates the decision function at x.
+func (s *supportVectors) decision(kernel Kernel, x *mat64.Dense) float64 {
+	ret := -s.rho
+	for t, v := range s.vectors {
+		ret += s.coefficients[t] * kernel.InnerProduct(v, x)
+	}
+	return ret
+}
+
+// validateParameters checks the settings shared by SVC and SVR.
+func validateParameters(kernel Kernel, c, tolerance, cacheSize float64, maxIterations int) error {
+	if kernel == nil {
+		return fmt.Errorf("No kernel")
+	}
+	if c <= 0 {
+		return fmt.Errorf("C must be positive")
+	}
+	if tolerance <= 0 {
+		return fmt.Errorf("Tolerance must be positive")
+	}
+	if cacheSize <= 0 {
+		return fmt.Errorf("CacheSize must be positive")
+	}
+	if maxIterations < 1 {
+		return fmt.Errorf("MaxIterations must be positive")
+	}
+	return nil
+}
+
+// readVectors returns each row of the given FloatAttributes of X as a
+// column vector.
+func readVectors(X base.FixedDataGrid, attrs []base.Attribute) ([]*mat64.Dense, error) {
+	for _, a := range attrs {
+		if _, err := X.GetAttribute(a); err != nil {
+			return nil, fmt.Errorf("Attribute %s is missing: %s", a.GetName(), err)
+		}
+	}
+	values, err := base.CopyFloatRows(X, attrs)
+	if err != nil {
+		return nil, err
+	}
+	_, rows := X.Size()
+	ret := make([]*mat64.Dense, rows)
+	for i := range ret {
+		ret[i] = mat64.NewDense(len(attrs), 1, values[i*len(attrs):(i+1)*len(attrs)])
+	}
+	return ret, nil
+}
+
+// featureAttributes returns the non-class FloatAttributes of X, after
+// checking there's exactly one class Attribute and some rows.
+func featureAttributes(X base.FixedDataGrid) ([]base.Attribute, error) {
+	if len(X.AllClassAttributes()) != 1 {
+		return nil, fmt.Errorf("Only 1 class variable is permitted")
+	}
+	if _, rows := X.Size(); rows == 0 {
+		return nil, base.NoTrainingDataError
+	}
+	attrs := base.NonClassFloatAttributes(X)
+	if len(attrs) == 0 {
+		return nil, fmt.Errorf("No FloatAttributes to fit")
+	}
+	return attrs, nil
+}
@@ -0,0 +1,137 @@
+package svm
+
+import (
+	"fmt"
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// SVR is epsilon-support vector regression. Errors smaller than
+// Epsilon are ignored, and larger ones are penalised linearly, so the
+// fit depends only on the rows outside the epsilon tube. The class
+// Attribute must be a FloatAttribute.
+type SVR struct {
+	Kernel Kernel
+	// Penalty for errors larger than Epsilon.
+	C float64
+	// Half-width of the tube within which errors are ignored.
+	Epsilon float64
+	// Stop once the largest violation of the optimality conditions is
+	// below Tolerance.
+	Tolerance float64
+	// Size of the kernel cache used while training, in megabytes.
+	CacheSize float64
+	// Maximum number of SMO iterations.
+	MaxIterations int
+	attrs         []base.Attribute
+	cls           base.Attribute
+	model         *supportVectors
+}
+
+// NewSVR returns an SVR using the given kernel, with C = 1 and
+// Epsilon = 0.1.
+func NewSVR(kernel Kernel) *SVR {
+	return &SVR{
+		Kernel:        kernel,
+		C:             1.0,
+		Epsilon:       0.1,
+		Tolerance:     1e-3,
+		CacheSize:     100,
+		MaxIterations: 1000000,
+	}
+}
+
+// String returns a human-readable summary.
+func (s *SVR) String() string {
+	return fmt.Sprintf("SVR(C: %g, epsilon: %g, tolerance: %g)", s.C, s.Epsilon, s.Tolerance)
+}
+
+// Fit solves the dual problem, which has two variables per row (one
+// for errors above the tube and one for errors below it).
+func (s *SVR) Fit(X base.FixedDataGrid) error {
+	if err := validateParameters(s.Kernel, s.C, s.Tolerance, s.CacheSize, s.MaxIterations); err != nil {
+		return err
+	}
+	if s.Epsilon < 0 {
+		return fmt.Errorf("Epsilon must not be negative")
+	}
+	attrs, err := featureAttributes(X)
+	if err != nil {
+		return err
+	}
+	classAttrs := X.AllClassAttributes()
+	if _, ok := classAttrs[0].(*base.FloatAttribute); !ok {
+		return fmt.Errorf("%s: class Attribute must be a FloatAttribute", classAttrs[0])
+	}
+	vectors, err := readVectors(X, attrs)
+	if err != nil {
+		return err
+	}
+	n := len(vectors)
+	targets := make([]float64, n)
+	X.MapOverRows(base.ResolveAttributes(X, classAttrs), func(row [][]byte, i int) (bool, error) {
+		targets[i] = base.UnpackBytesToFloat(row[0])
+		return true, nil
+	})
+
+	y := make([]float64, 2*n)
+	p := make([]float64, 2*n)
+	sample := make([]int, 2*n)
+	for i, z := range targets {
+		y[i], p[i], sample[i] = 1, s.Epsilon-z, i
+		y[i+n], p[i+n], sample[i+n] = -1, s.Epsilon+z, i
+	}
+	problem := &smoProblem{
+		y:             y,
+		p:             p,
+		sample:        sample,
+		c:             s.C,
+		cache:         newKernelCache(s.Kernel, vectors, s.CacheSize),
+		tolerance:     s.Tolerance,
+		maxIterations: s.MaxIterations,
+	}
+	alpha, rho, err := problem.solve()
+	if err != nil {
+		return err
+	}
+
+	model := &supportVectors{rho: rho}
+	for i, v := range vectors {
+		if c := alpha[i] - alpha[i+n]; c != 0 {
+			model.vectors = append(model.vectors, v)
+			model.coefficients = append(model.coefficients, c)
+		}
+	}
+	s.attrs = attrs
+	s.cls = classAttrs[0]
+	s.model = model
+	return nil
+}
+
+// Predict returns the predicted value of each row of X.
+func (s *SVR) Predict(X base.FixedDataGrid) (base.FixedDataGrid, error) {
+	if s.model == nil {
+		return nil, base.NoTrainingDataError
+	}
+	vectors, err := readVectors(X, s.attrs)
+	if err != nil {
+		return nil, err
+	}
+	ret := base.GeneratePredictionVector(X)
+	clsSpec, err := ret.GetAttribute(s.cls)
+	if err != nil {
+		return nil, err
+	}
+	for i, x := range vectors {
+		ret.Set(clsSpec, i, base.PackFloatToBytes(s.model.decision(s.Kernel, x)))
+	}
+	return ret, nil
+}
+
+// GetSupportVectorCount returns the number of training rows on or
+// outside the epsilon tube.
+func (s *SVR) GetSupportVectorCount() int {
+	if s.model == nil {
+		return 0
+	}
+	return len(s.model.vectors)
+}
@@ -0,0 +1,79 @@
+package svm
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+	"github.com/sjwhitworth/golearn/metrics/pairwise"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+// curve returns points (x, f(x)) for x evenly spaced in [0, 2 pi].
+func curve(points int, f func(float64) float64) base.FixedDataGrid {
+	ret := base.NewDenseInstances()
+	xSpec := ret.AddAttribute(base.NewFloatAttribute("x"))
+	y := base.NewFloatAttribute("y")
+	ySpec := ret.AddAttribute(y)
+	ret.AddClassAttribute(y)
+	ret.Extend(points)
+	for i := 0; i < points; i++ {
+		x := 2 * math.Pi * float64(i) / float64(points-1)
+		ret.Set(xSpec, i, base.PackFloatToBytes(x))
+		ret.Set(ySpec, i, base.PackFloatToBytes(f(x)))
+	}
+	return ret
+}
+
+func TestSVR(t *testing.T) {
+	Convey("Given points on a sine curve", t, func() {
+		data := curve(60, math.Sin)
+		xSpec := base.ResolveAttributes(data, base.NonClassFloatAttributes(data))[0]
+
+		Convey("Predicting before Fit should return an error", func() {
+			_, err := NewSVR(pairwise.NewRBFKernel(1)).Predict(data)
+			So(err, ShouldEqual, base.NoTrainingDataError)
+		})
+
+		Convey("An RBF kernel should follow the curve", func() {
+			s := NewSVR(pairwise.NewRBFKernel(1))
+			s.C = 10
+			s.Epsilon = 0.05
+			So(s.Fit(data), ShouldBeNil)
+			predictions, err := s.Predict(data)
+			So(err, ShouldBeNil)
+			spec := base.ResolveAttributes(predictions, predictions.AllClassAttributes())[0]
+			for i := 0; i < 60; i++ {
+				x := base.UnpackBytesToFloat(data.Get(xSpec, i))
+				So(base.UnpackBytesToFloat(predictions.Get(spec, i)), ShouldAlmostEqual, math.Sin(x), 0.1)
+			}
+
+			Convey("Using only the rows outside the tube", func() {
+				So(s.GetSupportVectorCount(), ShouldBeGreaterThan, 0)
+				So(s.GetSupportVectorCount(), ShouldBeLessThan, 60)
+			})
+		})
+	})
+
+	Convey("Given points on a line", t, func() {
+		data := curve(20, func(x float64) float64 { return 2*x + 1 })
+
+		Convey("A linear kernel should fit it to within epsilon", func() {
+			s := NewSVR(pairwise.NewEuclidean())
+			s.C = 100
+			So(s.Fit(data), ShouldBeNil)
+			predictions, err := s.Predict(data)
+			So(err, ShouldBeNil)
+			expected := base.ResolveAttributes(data, data.AllClassAttributes())[0]
+			actual := base.ResolveAttributes(predictions, predictions.AllClassAttributes())[0]
+			for i := 0; i < 20; i++ {
+				So(base.UnpackBytesToFloat(predictions.Get(actual, i)), ShouldAlmostEqual, base.UnpackBytesToFloat(data.Get(expected, i)), s.Epsilon+1e-3)
+			}
+		})
+
+		Convey("A categorical class should be rejected", func() {
+			iris, err := base.ParseCSVToInstances("../examples/datasets/iris_headers.csv", true)
+			So(err, ShouldBeNil)
+			So(NewSVR(pairwise.NewRBFKernel(1)).Fit(iris), ShouldNotBeNil)
+		})
+	})
+}
@@ -13,6 +13,13 @@ import (
 // InformationGainRuleGenerator generates DecisionTreeRules which
 // maximize information gain at each node.
 type InformationGainRuleGenerator struct {
+	classWeights map[string]float64
+}
+
+// SetClassWeights weights each class when computing entropy (nil
+// weights every class equally).
+func (r *InformationGainRuleGenerator) SetClassWeights(weights map[string]float64) {
+	r.classWeights = weights
 }
 
 // GenerateSplitRule returns a DecisionTreeNode based on a non-class Attribute
@@ -50,7 +57,7 @@ func (r *InformationGainRuleGenerator) GetSplitRuleFromSelection(consideredAttri
 
 	// Compute the base entropy
 	classDist := base.GetClassDistribution(f)
-	baseEntropy := getBaseEntropy(classDist)
+	baseEntropy := getBaseEntropy(classDist, r.classWeights)
 
 	// Compute the information gain for each attribute
 	for _, s := range consideredAttributes {
@@ -58,11 +65,11 @@ func (r *InformationGainRuleGenerator) GetSplitRuleFromSelection(consideredAttri
 		var splitVal float64
 		if fAttr, ok := s.(*base.FloatAttribute); ok {
 			var attributeEntropy float64
-			attributeEntropy, splitVal = getNumericAttributeEntropy(f, fAttr)
+			attributeEntropy, splitVal = getNumericAttributeEntropy(f, fAttr, r.classWeights)
 			informationGain = baseEntropy - attributeEntropy
 		} else {
 			proposedClassDist := base.GetClassDistributionAfterSplit(f, s)
-			localEntropy := getSplitEntropy(proposedClassDist)
+			localEntropy := getSplitEntropy(proposedClassDist, r.classWeights)
 			informationGain = baseEntropy - localEntropy
 		}
 
@@ -92,7 +99,7 @@ func (a splitVec) Len() int           { return len(a) }
 func (a splitVec) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a splitVec) Less(i, j int) bool { return a[i].val < a[j].val }
 
-func getNumericAttributeEntropy(f base.FixedDataGrid, attr *base.FloatAttribute) (float64, float64) {
+func getNumericAttributeEntropy(f base.FixedDataGrid, attr *base.FloatAttribute, weights map[string]float64) (float64, float64) {
 
 	// Resolve Attribute
 	attrSpec, err := f.GetAttribute(attr)
@@ -136,7 +143,7 @@ func getNumericAttributeEntropy(f base.FixedDataGrid, attr *base.FloatAttribute)
 		val := refs[i].val + refs[i+1].val
 		val /= 2
 		splitDist := generateCandidateSplitDistribution(val)
-		splitEntropy := getSplitEntropy(splitDist)
+		splitEntropy := getSplitEntropy(splitDist, weights)
 		if splitEntropy < minSplitEntropy {
 			minSplitEntropy = splitEntropy
 			minSplitVal = val
@@ -147,38 +154,51 @@ func getNumericAttributeEntropy(f base.FixedDataGrid, attr *base.FloatAttribute)
 }
 
 // getSplitEntropy determines the entropy of the target
-// class distribution after splitting on an base.Attribute
-func getSplitEntropy(s map[string]map[string]int) float64 {
+// class distribution after splitting on an base.Attribute,
+// with each class weighted by weights (if non-nil)
+func getSplitEntropy(s map[string]map[string]int, weights map[string]float64) float64 {
 	ret := 0.0
-	count := 0
+	count := 0.0
 	for a := range s {
 		for c := range s[a] {
-			count += s[a][c]
+			count += classWeight(weights, c) * float64(s[a][c])
 		}
 	}
 	for a := range s {
 		total := 0.0
 		for c := range s[a] {
-			total += float64(s[a][c])
+			total += classWeight(weights, c) * float64(s[a][c])
+		}
+		if total == 0 {
+			continue
 		}
 		for c := range s[a] {
-			ret -= float64(s[a][c]) / float64(count) * math.Log(float64(s[a][c])/float64(count)) / math.Log(2)
+			if s[a][c] == 0 {
+				continue
+			}
+			p := classWeight(weights, c) * float64(s[a][c]) / count
+			ret -= p * math.Log(p) / math.Log(2)
 		}
-		ret += total / float64(count) * math.Log(total/float64(count)) / math.Log(2)
+		ret += total / count * math.Log(total/count) / math.Log(2)
 	}
 	return ret
 }
 
 // getBaseEntropy determines the entropy of the target
-// class distribution before splitting on an base.Attribute
-func getBaseEntropy(s map[string]int) float64 {
+// class distribution before splitting on an base.Attribute,
+// with each class weighted by weights (if non-nil)
+func getBaseEntropy(s map[string]int, weights map[string]float64) float64 {
 	ret := 0.0
-	count := 0
+	count := 0.0
 	for k := range s {
-		count += s[k]
+		count += classWeight(weights, k) * float64(s[k])
 	}
 	for k := range s {
-		ret -= float64(s[k]) / float64(count) * math.Log(float64(s[k])/float64(count)) / math.Log(2)
+		if s[k] == 0 {
+			continue
+		}
+		p := classWeight(weights, k) * float64(s[k]) / count
+		ret -= p * math.Log(p) / math.Log(2)
 	}
 	return ret
 }
@@ -12,6 +12,13 @@ import (
 // GiniCoefficientRuleGenerator generates DecisionTreeRules which minimize
 // the Geni impurity coefficient at each node.
 type GiniCoefficientRuleGenerator struct {
+	classWeights map[string]float64
+}
+
+// SetClassWeights weights each class when computing the Gini impurity
+// (nil weights every class equally).
+func (g *GiniCoefficientRuleGenerator) SetClassWeights(weights map[string]float64) {
+	g.classWeights = weights
 }
 
 // GenerateSplitRule returns the non-class Attribute-based DecisionTreeRule
@@ -48,12 +55,12 @@ func (g *GiniCoefficientRuleGenerator) GetSplitRuleFromSelection(consideredAttri
 		var proposedDist map[string]map[string]int
 		var splitVal float64
 		if fAttr, ok := s.(*base.FloatAttribute); ok {
-			_, splitVal = getNumericAttributeEntropy(f, fAttr)
+			_, splitVal = getNumericAttributeEntropy(f, fAttr, g.classWeights)
 			proposedDist = base.GetClassDistributionAfterThreshold(f, fAttr, splitVal)
 		} else {
 			proposedDist = base.GetClassDistributionAfterSplit(f, s)
 		}
-		avgGini := computeAverageGiniIndex(proposedDist)
+		avgGini := computeAverageGiniIndex(proposedDist, g.classWeights)
 		if avgGini < minGini {
 			minGini = avgGini
 			selectedAttribute = s
@@ -68,34 +75,36 @@ func (g *GiniCoefficientRuleGenerator) GetSplitRuleFromSelection(consideredAttri
 // Utility functions
 //
 
-// computeGini computes the Gini impurity measure
-func computeGini(s map[string]int) float64 {
-	// Compute probability map
-	p := make(map[string]float64)
-	for i := range s {
-		if p[i] == 0 {
-			continue
-		}
-		p[i] = 1.0 / float64(p[i])
+// computeGini computes the Gini impurity measure, with each
+// class weighted by weights (if non-nil)
+func computeGini(s map[string]int, weights map[string]float64) float64 {
+	// Compute the total weight
+	total := 0.0
+	for c := range s {
+		total += classWeight(weights, c) * float64(s[c])
+	}
+	if total == 0 {
+		return 0
 	}
-	// Compute overall sum
+	// Compute the sum of squared probabilities
 	sum := 0.0
-	for i := range p {
-		sum += p[i] * p[i]
+	for c := range s {
+		p := classWeight(weights, c) * float64(s[c]) / total
+		sum += p * p
 	}
 
 	return 1.0 - sum
 }
 
-// computeGiniImpurity computes the average Gini index of a
+// computeAverageGiniIndex computes the average Gini index of a
 // proposed split
-func computeAverageGiniIndex(s map[string]map[string]int) float64 {
+func computeAverageGiniIndex(s map[string]map[string]int, weights map[string]float64) float64 {
 
-	// Figure out the total number of things in this map
-	total := 0
+	// Figure out the total weight of things in this map
+	total := 0.0
 	for i := range s {
 		for j := range s[i] {
-			total += s[i][j]
+			total += classWeight(weights, j) * float64(s[i][j])
 		}
 	}
 
@@ -103,10 +112,10 @@ func computeAverageGiniIndex(s map[string]map[string]int) float64 {
 	for i := range s {
 		subtotal := 0.0
 		for j := range s[i] {
-			subtotal += float64(s[i][j])
+			subtotal += classWeight(weights, j) * float64(s[i][j])
 		}
-		cf := subtotal / float64(total)
-		cf *= computeGini(s[i])
+		cf := subtotal / total
+		cf *= computeGini(s[i], weights)
 		sum += cf
 	}
 	return sum
@@ -12,6 +12,13 @@ import (
 // InformationGainRatioRuleGenerator generates DecisionTreeRules which
 // maximise the InformationGain at each node.
 type InformationGainRatioRuleGenerator struct {
+	classWeights map[string]float64
+}
+
+// SetClassWeights weights each class when computing entropy (nil
+// weights every class equally).
+func (r *InformationGainRatioRuleGenerator) SetClassWeights(weights map[string]float64) {
+	r.classWeights = weights
 }
 
 // GenerateSplitRule returns a DecisionTreeRule which maximises information
@@ -49,7 +56,7 @@ func (r *InformationGainRatioRuleGenerator) GetSplitRuleFromSelection(considered
 
 	// Compute the base entropy
 	classDist := base.GetClassDistribution(f)
-	baseEntropy := getBaseEntropy(classDist)
+	baseEntropy := getBaseEntropy(classDist, r.classWeights)
 
 	// Compute the information gain for each attribute
 	for _, s := range consideredAttributes {
@@ -57,10 +64,10 @@ func (r *InformationGainRatioRuleGenerator) GetSplitRuleFromSelection(considered
 		var localEntropy float64
 		var splitVal float64
 		if fAttr, ok := s.(*base.FloatAttribute); ok {
-			localEntropy, splitVal = getNumericAttributeEntropy(f, fAttr)
+			localEntropy, splitVal = getNumericAttributeEntropy(f, fAttr, r.classWeights)
 		} else {
 			proposedClassDist := base.GetClassDistributionAfterSplit(f, s)
-			localEntropy = getSplitEntropy(proposedClassDist)
+			localEntropy = getSplitEntropy(proposedClassDist, r.classWeights)
 		}
 		informationGain = baseEntropy - localEntropy
 		informationGainRatio := informationGain / localEntropy
@@ -24,6 +24,26 @@ type RuleGenerator interface {
 	GenerateSplitRule(base.FixedDataGrid) *DecisionTreeRule
 }
 
+// ClassWeightedRuleGenerator implementations can weight some classes
+// more heavily than others when determining the best split (for
+// example, when some classes are much rarer than the rest).
+type ClassWeightedRuleGenerator interface {
+	RuleGenerator
+	// SetClassWeights sets the weight of each class value. Classes
+	// which aren't mentioned are weighted 1, and nil weights every
+	// class equally.
+	SetClassWeights(map[string]float64)
+}
+
+// classWeight returns the weight of class c, which is 1 if weights is
+// nil or doesn't mention it.
+func classWeight(weights map[string]float64, c string) float64 {
+	if w, ok := weights[c]; ok {
+		return w
+	}
+	return 1
+}
+
 // DecisionTreeRule represents the "decision" in "decision tree".
 type DecisionTreeRule struct {
 	SplitAttr base.Attribute
@@ -56,6 +76,18 @@ func getClassAttr(from base.FixedDataGrid) base.Attribute {
 // InferID3Tree builds a decision tree using a RuleGenerator
 // from a set of Instances (implements the ID3 algorithm)
 func InferID3Tree(from base.FixedDataGrid, with RuleGenerator) *DecisionTreeNode {
+	return inferID3Tree(from, with, nil)
+}
+
+// InferWeightedID3Tree builds a decision tree like InferID3Tree, but
+// weights each class value by weights when choosing splits and the
+// class of each node.
+func InferWeightedID3Tree(from base.FixedDataGrid, with ClassWeightedRuleGenerator, weights map[string]float64) *DecisionTreeNode {
+	with.SetClassWeights(weights)
+	return inferID3Tree(from, with, weights)
+}
+
+func inferID3Tree(from base.FixedDataGrid, with RuleGenerator, weights map[string]float64) *DecisionTreeNode {
 	// Count the number of classes at this node
 	classes := base.GetClassDistribution(from)
 	// If there's only one class, return a DecisionTreeLeaf with
@@ -77,12 +109,12 @@ func InferID3Tree(from base.FixedDataGrid, with RuleGenerator) *DecisionTreeNode
 	}
 
 	// Only have the class attribute
-	maxVal := 0
+	maxVal := 0.0
 	maxClass := ""
 	for i := range classes {
-		if classes[i] > maxVal {
+		if v := classWeight(weights, i) * float64(classes[i]); v > maxVal {
 			maxClass = i
-			maxVal = classes[i]
+			maxVal = v
 		}
 	}
 
@@ -130,7 +162,7 @@ func InferID3Tree(from base.FixedDataGrid, with RuleGenerator) *DecisionTreeNode
 	ret.Children = make(map[string]*DecisionTreeNode)
 	for k := range splitInstances {
 		newInstances := splitInstances[k]
-		ret.Children[k] = InferID3Tree(newInstances, with)
+		ret.Children[k] = inferID3Tree(newInstances, with, weights)
 	}
 	ret.SplitRule = splitRule
 	return ret
@@ -172,14 +204,37 @@ func (d *DecisionTreeNode) String() string {
 	return d.getNestedString(0)
 }
 
-// computeAccuracy is a helper method for Prune()
-func computeAccuracy(predictions base.FixedDataGrid, from base.FixedDataGrid) float64 {
+// computeAccuracy is a helper method for Prune(), which weights each
+// row by the weight of its class (if weights is non-nil)
+func computeAccuracy(predictions base.FixedDataGrid, from base.FixedDataGrid, weights map[string]float64) float64 {
 	cf, _ := evaluation.GetConfusionMatrix(from, predictions)
-	return evaluation.GetAccuracy(cf)
+	if weights == nil {
+		return evaluation.GetAccuracy(cf)
+	}
+	correct, total := 0.0, 0.0
+	for ref, row := range cf {
+		w := classWeight(weights, ref)
+		for gen, count := range row {
+			total += w * float64(count)
+			if gen == ref {
+				correct += w * float64(count)
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return correct / total
 }
 
 // Prune eliminates branches which hurt accuracy
 func (d *DecisionTreeNode) Prune(using base.FixedDataGrid) {
+	d.prune(using, nil)
+}
+
+// prune eliminates branches which hurt accuracy, with each row
+// weighted by the weight of its class
+func (d *DecisionTreeNode) prune(using base.FixedDataGrid, weights map[string]float64) {
 	// If you're a leaf, you're already pruned
 	if d.Children == nil {
 		return
@@ -198,19 +253,19 @@ func (d *DecisionTreeNode) Prune(using base.FixedDataGrid) {
 		if subH == 0 || subV == 0 {
 			continue
 		}
-		d.Children[k].Prune(sub[k])
+		d.Children[k].prune(sub[k], weights)
 	}
 
 	// Get a baseline accuracy
 	predictions, _ := d.Predict(using)
-	baselineAccuracy := computeAccuracy(predictions, using)
+	baselineAccuracy := computeAccuracy(predictions, using, weights)
 
 	// Speculatively remove the children and re-evaluate
 	tmpChildren := d.Children
 	d.Children = nil
 
 	predictions, _ = d.Predict(using)
-	newAccuracy := computeAccuracy(predictions, using)
+	newAccuracy := computeAccuracy(predictions, using, weights)
 
 	// Keep the children removed if better, else restore
 	if newAccuracy < baselineAccuracy {
@@ -284,11 +339,20 @@ func (d *DecisionTreeNode) Predict(what base.FixedDataGrid) (base.FixedDataGrid,
 // ID3DecisionTree represents an ID3-based decision tree
 // using the Information Gain metric to select which attributes
 // to split on at each node.
+//
+// ClassWeights optionally weights each class value when choosing
+// splits, leaf classes and what to prune; classes it doesn't mention
+// are weighted 1. If it's nil, the classes are weighted in inverse
+// proportion to their frequency when WeightClassesAutomatically is
+// true, and equally otherwise. Weighting requires a Rule which
+// implements ClassWeightedRuleGenerator.
 type ID3DecisionTree struct {
 	base.BaseClassifier
-	Root       *DecisionTreeNode
-	PruneSplit float64
-	Rule       RuleGenerator
+	Root                       *DecisionTreeNode
+	PruneSplit                 float64
+	Rule                       RuleGenerator
+	ClassWeights               map[string]float64
+	WeightClassesAutomatically bool
 }
 
 // NewID3DecisionTree returns a new ID3DecisionTree with the specified test-prune
@@ -300,6 +364,8 @@ func NewID3DecisionTree(prune float64) *ID3DecisionTree {
 		nil,
 		prune,
 		new(InformationGainRuleGenerator),
+		nil,
+		false,
 	}
 }
 
@@ -311,19 +377,36 @@ func NewID3DecisionTreeFromRule(prune float64, rule RuleGenerator) *ID3DecisionT
 		nil,
 		prune,
 		rule,
+		nil,
+		false,
 	}
 }
 
 // Fit builds the ID3 decision tree
 func (t *ID3DecisionTree) Fit(on base.FixedDataGrid) error {
+	weights, err := base.GetClassWeights(on, t.ClassWeights, t.WeightClassesAutomatically)
+	if err != nil {
+		return err
+	}
+	infer := func(from base.FixedDataGrid) (*DecisionTreeNode, error) {
+		if rule, ok := t.Rule.(ClassWeightedRuleGenerator); ok {
+			return InferWeightedID3Tree(from, rule, weights), nil
+		}
+		if weights != nil {
+			return nil, fmt.Errorf("%T can't weight classes", t.Rule)
+		}
+		return InferID3Tree(from, t.Rule), nil
+	}
 	if t.PruneSplit > 0.001 {
 		trainData, testData := base.InstancesTrainTestSplit(on, t.PruneSplit)
-		t.Root = InferID3Tree(trainData, t.Rule)
-		t.Root.Prune(testData)
+		if t.Root, err = infer(trainData); err != nil {
+			return err
+		}
+		t.Root.prune(testData, weights)
 	} else {
-		t.Root = InferID3Tree(on, t.Rule)
+		t.Root, err = infer(on)
 	}
-	return nil
+	return err
 }
 
 // Predict outputs predictions from the ID3 decision tree
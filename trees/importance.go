@@ -0,0 +1,63 @@
+package trees
+
+import (
+	"github.com/sjwhitworth/golearn/base"
+)
+
+// addImportances adds the decrease in Gini impurity of every split
+// below (and including) d to ret, credited to the split Attribute and
+// weighted by the number of training rows reaching each node.
+func (d *DecisionTreeNode) addImportances(ret map[base.Attribute]float64) {
+	if d == nil || d.Children == nil || d.SplitRule == nil || d.SplitRule.SplitAttr == nil {
+		return
+	}
+	rows := func(n *DecisionTreeNode) float64 {
+		total := 0
+		for _, c := range n.ClassDist {
+			total += c
+		}
+		return float64(total)
+	}
+	decrease := rows(d) * computeGini(d.ClassDist, nil)
+	for _, child := range d.Children {
+		decrease -= rows(child) * computeGini(child.ClassDist, nil)
+		child.addImportances(ret)
+	}
+	ret[d.SplitRule.SplitAttr] += decrease
+}
+
+// normaliseImportances scales importances so that they sum to 1.
+func normaliseImportances(importances map[base.Attribute]float64) map[base.Attribute]float64 {
+	total := 0.0
+	for _, v := range importances {
+		total += v
+	}
+	if total > 0 {
+		for a := range importances {
+			importances[a] /= total
+		}
+	}
+	return importances
+}
+
+// GetFeatureImportances returns the total decrease in Gini impurity
+// brought about by splitting on each Attribute, weighted by the number
+// of training rows reaching each split and scaled to sum to 1.
+// Attributes which the tree never splits on are left out.
+func (d *DecisionTreeNode) GetFeatureImportances() map[base.Attribute]float64 {
+	ret := make(map[base.Attribute]float64)
+	d.addImportances(ret)
+	return normaliseImportances(ret)
+}
+
+// GetFeatureImportances returns the importance of each Attribute
+// the tree splits on (see DecisionTreeNode.GetFeatureImportances).
+func (t *ID3DecisionTree) GetFeatureImportances() map[base.Attribute]float64 {
+	return t.Root.GetFeatureImportances()
+}
+
+// GetFeatureImportances returns the importance of each Attribute
+// the tree splits on (see DecisionTreeNode.GetFeatureImportances).
+func (rt *RandomTree) GetFeatureImportances() map[base.Attribute]float64 {
+	return rt.Root.GetFeatureImportances()
+}
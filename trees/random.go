@@ -46,12 +46,27 @@ func (r *RandomTreeRuleGenerator) GenerateSplitRule(f base.FixedDataGrid) *Decis
 	return r.internalRule.GetSplitRuleFromSelection(consideredAttributes, f)
 }
 
+// SetClassWeights weights each class when computing information gain
+// (nil weights every class equally).
+func (r *RandomTreeRuleGenerator) SetClassWeights(weights map[string]float64) {
+	r.internalRule.SetClassWeights(weights)
+}
+
 // RandomTree builds a decision tree by considering a fixed number
 // of randomly-chosen attributes at each node
+//
+// ClassWeights optionally weights each class value when choosing
+// splits, leaf classes and what to prune; classes it doesn't mention
+// are weighted 1. If it's nil, the classes are weighted in inverse
+// proportion to their frequency when WeightClassesAutomatically is
+// true, and equally otherwise.
 type RandomTree struct {
 	base.BaseClassifier
-	Root *DecisionTreeNode
-	Rule *RandomTreeRuleGenerator
+	Root                       *DecisionTreeNode
+	Rule                       *RandomTreeRuleGenerator
+	ClassWeights               map[string]float64
+	WeightClassesAutomatically bool
+	weights                    map[string]float64
 }
 
 // NewRandomTree returns a new RandomTree which considers attrs randomly
@@ -64,12 +79,20 @@ func NewRandomTree(attrs int) *RandomTree {
 			attrs,
 			InformationGainRuleGenerator{},
 		},
+		nil,
+		false,
+		nil,
 	}
 }
 
 // Fit builds a RandomTree suitable for prediction
 func (rt *RandomTree) Fit(from base.FixedDataGrid) error {
-	rt.Root = InferID3Tree(from, rt.Rule)
+	weights, err := base.GetClassWeights(from, rt.ClassWeights, rt.WeightClassesAutomatically)
+	if err != nil {
+		return err
+	}
+	rt.Root = InferWeightedID3Tree(from, rt.Rule, weights)
+	rt.weights = weights
 	return nil
 }
 
@@ -86,5 +109,5 @@ func (rt *RandomTree) String() string {
 // Prune removes nodes from the tree which are detrimental
 // to determining the accuracy of the test set (with)
 func (rt *RandomTree) Prune(with base.FixedDataGrid) {
-	rt.Root.Prune(with)
+	rt.Root.prune(with, rt.weights)
 }
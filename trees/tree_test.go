@@ -156,7 +156,7 @@ func TestPRIVATEgetSplitEntropy(t *testing.T) {
 	outlook["rain"]["noplay"] = 2
 
 	Convey("Should calculate split entropy accurately", t, func() {
-		So(getSplitEntropy(outlook), ShouldAlmostEqual, 0.694, 0.001)
+		So(getSplitEntropy(outlook, nil), ShouldAlmostEqual, 0.694, 0.001)
 	})
 }
 
@@ -178,6 +178,24 @@ func TestID3Inference(t *testing.T) {
 			root := tree.Root
 
 			itBuildsTheCorrectDecisionTree(root)
+
+			Convey("Feature importances should credit the decrease in Gini impurity at each split", func() {
+				importances := make(map[string]float64)
+				for a, v := range tree.GetFeatureImportances() {
+					importances[a.GetName()] = v
+				}
+				So(len(importances), ShouldEqual, 3)
+				So(importances["outlook"], ShouldAlmostEqual, 1-4.8*14/90, 1e-9)
+				So(importances["humidity"], ShouldAlmostEqual, 2.4*14/90, 1e-9)
+				So(importances["windy"], ShouldAlmostEqual, 2.4*14/90, 1e-9)
+			})
+		})
+
+		Convey("Using a GiniCoefficientRuleGenerator", func() {
+			rule := new(GiniCoefficientRuleGenerator)
+			root := InferID3Tree(instances, rule)
+
+			itBuildsTheCorrectDecisionTree(root)
 		})
 	})
 }
@@ -190,13 +208,148 @@ func TestPRIVATEgetNumericAttributeEntropy(t *testing.T) {
 			attr := base.GetAttributeByName(instances, "Attribute2")
 			So(attr, ShouldNotEqual, nil)
 			Convey("Finding the threshold...", func() {
-				_, threshold := getNumericAttributeEntropy(instances, attr.(*base.FloatAttribute))
+				_, threshold := getNumericAttributeEntropy(instances, attr.(*base.FloatAttribute), nil)
 				So(threshold, ShouldAlmostEqual, 82.5)
 			})
 		})
 	})
 }
 
+func TestPRIVATEweightedImpurity(t *testing.T) {
+	Convey("Weighting the classes should change the impurity", t, func() {
+		dist := map[string]int{"a": 1, "b": 3}
+		weights := map[string]float64{"a": 3}
+		So(getBaseEntropy(dist, nil), ShouldAlmostEqual, 0.811, 0.001)
+		So(getBaseEntropy(dist, weights), ShouldAlmostEqual, 1.0)
+		So(computeGini(dist, nil), ShouldAlmostEqual, 0.375)
+		So(computeGini(dist, weights), ShouldAlmostEqual, 0.5)
+	})
+}
+
+// imbalancedInstances returns rows with CategoricalAttributes x and y:
+// when x is p, 8 rows are neg and 2 are pos, and when x is q all 10 are
+// neg. y is always the same, so the tree splits on x and then stops.
+func imbalancedInstances() *base.DenseInstances {
+	inst := base.NewDenseInstances()
+	x := base.NewCategoricalAttribute()
+	x.SetName("x")
+	y := base.NewCategoricalAttribute()
+	y.SetName("y")
+	class := base.NewCategoricalAttribute()
+	class.SetName("class")
+	xSpec := inst.AddAttribute(x)
+	ySpec := inst.AddAttribute(y)
+	classSpec := inst.AddAttribute(class)
+	inst.AddClassAttribute(class)
+	inst.Extend(20)
+	for i := 0; i < 20; i++ {
+		xVal, classVal := "q", "neg"
+		if i < 10 {
+			xVal = "p"
+		}
+		if i < 2 {
+			classVal = "pos"
+		}
+		inst.Set(xSpec, i, x.GetSysValFromString(xVal))
+		inst.Set(ySpec, i, y.GetSysValFromString("a"))
+		inst.Set(classSpec, i, class.GetSysValFromString(classVal))
+	}
+	return inst
+}
+
+// unweightedRule can't weight classes.
+type unweightedRule struct {
+	rule InformationGainRuleGenerator
+}
+
+func (u *unweightedRule) GenerateSplitRule(f base.FixedDataGrid) *DecisionTreeRule {
+	return u.rule.GenerateSplitRule(f)
+}
+
+func TestClassWeightedTrees(t *testing.T) {
+	Convey("Given an imbalanced dataset", t, func() {
+		rand.Seed(42)
+		inst := imbalancedInstances()
+		predictP := func(c base.Classifier) string {
+			predictions, err := c.Predict(inst)
+			So(err, ShouldBeNil)
+			return base.GetClass(predictions, 0)
+		}
+
+		Convey("An unweighted tree should predict the majority class", func() {
+			tree := NewID3DecisionTree(0)
+			So(tree.Fit(inst), ShouldBeNil)
+			So(predictP(tree), ShouldEqual, "neg")
+		})
+
+		Convey("Weighting automatically should favour the rare class", func() {
+			tree := NewID3DecisionTree(0)
+			tree.WeightClassesAutomatically = true
+			So(tree.Fit(inst), ShouldBeNil)
+			So(predictP(tree), ShouldEqual, "pos")
+
+			for _, rule := range []ClassWeightedRuleGenerator{
+				new(GiniCoefficientRuleGenerator),
+				new(InformationGainRatioRuleGenerator),
+			} {
+				tree = NewID3DecisionTreeFromRule(0, rule)
+				tree.WeightClassesAutomatically = true
+				So(tree.Fit(inst), ShouldBeNil)
+				So(predictP(tree), ShouldEqual, "pos")
+			}
+		})
+
+		Convey("Given weights should be used", func() {
+			tree := NewID3DecisionTree(0)
+			tree.ClassWeights = map[string]float64{"pos": 5}
+			So(tree.Fit(inst), ShouldBeNil)
+			So(predictP(tree), ShouldEqual, "pos")
+
+			tree.ClassWeights = map[string]float64{"pos": 3}
+			So(tree.Fit(inst), ShouldBeNil)
+			So(predictP(tree), ShouldEqual, "neg")
+		})
+
+		Convey("RandomTrees should be weighted too", func() {
+			// Automatic weights would tie the classes if y is
+			// chosen at the root
+			tree := NewRandomTree(1)
+			tree.ClassWeights = map[string]float64{"pos": 10}
+			So(tree.Fit(inst), ShouldBeNil)
+			So(predictP(tree), ShouldEqual, "pos")
+			tree.Prune(inst)
+			So(predictP(tree), ShouldEqual, "pos")
+		})
+
+		Convey("Invalid weights should be rejected", func() {
+			tree := NewID3DecisionTree(0)
+			tree.ClassWeights = map[string]float64{"pos": -1}
+			So(tree.Fit(inst), ShouldNotBeNil)
+
+			tree = NewID3DecisionTreeFromRule(0, new(unweightedRule))
+			tree.WeightClassesAutomatically = true
+			So(tree.Fit(inst), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestPRIVATEcomputeGini(t *testing.T) {
+	Convey("Should calculate the Gini impurity of a distribution", t, func() {
+		So(computeGini(map[string]int{"a": 1, "b": 3}, nil), ShouldAlmostEqual, 0.375)
+		So(computeGini(map[string]int{"a": 4}, nil), ShouldAlmostEqual, 0)
+		So(computeGini(map[string]int{}, nil), ShouldAlmostEqual, 0)
+	})
+
+	Convey("Should weight the Gini index of a split by the size of each side", t, func() {
+		outlook := map[string]map[string]int{
+			"sunny":    {"yes": 2, "no": 3},
+			"overcast": {"yes": 4},
+			"rainy":    {"yes": 3, "no": 2},
+		}
+		So(computeAverageGiniIndex(outlook, nil), ShouldAlmostEqual, 0.343, 0.001)
+	})
+}
+
 func itBuildsTheCorrectDecisionTree(root *DecisionTreeNode) {
 	Convey("The root should be 'outlook'", func() {
 		So(root.SplitRule.SplitAttr.GetName(), ShouldEqual, "outlook")
@@ -0,0 +1,100 @@
+package utilities
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+// SymmetricEigen returns the eigenvalues of a symmetric matrix in
+// descending order, with the matching eigenvectors as the columns of
+// the second return value. The sign of each eigenvector is chosen so
+// that its largest-magnitude element is positive.
+func SymmetricEigen(a *mat64.Dense) ([]float64, *mat64.Dense) {
+	n, _ := a.Dims()
+	eigen := mat64.Eigen(a, 1e-12)
+	d := eigen.D()
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Sort(sort.Reverse(byValue{order, d}))
+
+	vals := make([]float64, n)
+	vecs := mat64.NewDense(n, n, nil)
+	for i, o := range order {
+		vals[i] = d.At(o, o)
+		largest := 0.0
+		for j := 0; j < n; j++ {
+			if v := eigen.V.At(j, o); math.Abs(v) > math.Abs(largest) {
+				largest = v
+			}
+		}
+		sign := 1.0
+		if largest < 0 {
+			sign = -1.0
+		}
+		for j := 0; j < n; j++ {
+			vecs.Set(j, i, sign*eigen.V.At(j, o))
+		}
+	}
+	return vals, vecs
+}
+
+// NotPositiveDefiniteError is returned by InverseFactor when the matrix
+// is singular, or has a negative eigenvalue.
+var NotPositiveDefiniteError = errors.New("matrix isn't positive definite")
+
+// InverseFactor returns a matrix P with a^{-1} = P P^T, along with
+// log |a|, for a symmetric positive definite matrix a. Eigenvalues no
+// greater than 1e-12 times the largest are treated as zero, in which
+// case NotPositiveDefiniteError is returned.
+func InverseFactor(a *mat64.Dense) (*mat64.Dense, float64, error) {
+	// a = V D V^T, so a^{-1} = (V D^{-1/2})(V D^{-1/2})^T
+	vals, vecs := SymmetricEigen(a)
+	n := len(vals)
+	if n == 0 || vals[0] <= 0 || vals[n-1] <= 1e-12*vals[0] {
+		return nil, 0, NotPositiveDefiniteError
+	}
+	ret := mat64.NewDense(n, n, nil)
+	logDet := 0.0
+	for i, v := range vals {
+		logDet += math.Log(v)
+		scale := 1 / math.Sqrt(v)
+		for j := 0; j < n; j++ {
+			ret.Set(j, i, vecs.At(j, i)*scale)
+		}
+	}
+	return ret, logDet, nil
+}
+
+// byValue sorts indices by the corresponding diagonal entry of d.
+type byValue struct {
+	idx []int
+	d   *mat64.Dense
+}
+
+func (b byValue) Len() int           { return len(b.idx) }
+func (b byValue) Swap(i, j int)      { b.idx[i], b.idx[j] = b.idx[j], b.idx[i] }
+func (b byValue) Less(i, j int) bool { return b.d.At(b.idx[i], b.idx[i]) < b.d.At(b.idx[j], b.idx[j]) }
+
+// LogSumExp computes log(sum(exp(vals))) without overflowing.
+func LogSumExp(vals []float64) float64 {
+	max := math.Inf(-1)
+	for _, v := range vals {
+		if v > max {
+			max = v
+		}
+	}
+	if math.IsInf(max, -1) {
+		return max
+	}
+	sum := 0.0
+	for _, v := range vals {
+		sum += math.Exp(v - max)
+	}
+	return max + math.Log(sum)
+}
@@ -0,0 +1,99 @@
+package utilities
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSymmetricEigen(t *testing.T) {
+	Convey("Given a symmetric matrix", t, func() {
+		a := mat64.NewDense(3, 3, []float64{
+			2, 1, 0,
+			1, 2, 0,
+			0, 0, 5,
+		})
+		vals, vecs := SymmetricEigen(a)
+
+		Convey("The eigenvalues should be in descending order", func() {
+			So(vals[0], ShouldAlmostEqual, 5)
+			So(vals[1], ShouldAlmostEqual, 3)
+			So(vals[2], ShouldAlmostEqual, 1)
+		})
+
+		Convey("Each column should be an eigenvector with a positive largest element", func() {
+			for i, v := range vals {
+				col := vecs.Col(nil, i)
+				largest := 0.0
+				for _, x := range col {
+					if math.Abs(x) > math.Abs(largest) {
+						largest = x
+					}
+				}
+				So(largest, ShouldBeGreaterThan, 0)
+				for r := 0; r < 3; r++ {
+					sum := 0.0
+					for c := 0; c < 3; c++ {
+						sum += a.At(r, c) * col[c]
+					}
+					So(sum, ShouldAlmostEqual, v*col[r])
+				}
+			}
+		})
+	})
+}
+
+func TestInverseFactor(t *testing.T) {
+	Convey("Given a positive definite matrix", t, func() {
+		a := mat64.NewDense(3, 3, []float64{
+			4, 1, 0,
+			1, 3, 1,
+			0, 1, 2,
+		})
+		p, logDet, err := InverseFactor(a)
+		So(err, ShouldBeNil)
+
+		Convey("a P P^T should be the identity", func() {
+			var pT, inverse, product mat64.Dense
+			pT.TCopy(p)
+			inverse.Mul(p, &pT)
+			product.Mul(a, &inverse)
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 3; j++ {
+					expected := 0.0
+					if i == j {
+						expected = 1
+					}
+					So(product.At(i, j), ShouldAlmostEqual, expected)
+				}
+			}
+		})
+
+		Convey("The log-determinant should be correct", func() {
+			So(logDet, ShouldAlmostEqual, math.Log(18))
+		})
+	})
+
+	Convey("Singular and indefinite matrices should be rejected", t, func() {
+		_, _, err := InverseFactor(mat64.NewDense(2, 2, []float64{1, 1, 1, 1}))
+		So(err, ShouldEqual, NotPositiveDefiniteError)
+		_, _, err = InverseFactor(mat64.NewDense(2, 2, []float64{1, 0, 0, -1}))
+		So(err, ShouldEqual, NotPositiveDefiniteError)
+	})
+}
+
+func TestLogSumExp(t *testing.T) {
+	Convey("LogSumExp should match the direct calculation", t, func() {
+		So(LogSumExp([]float64{1, 2, 3}), ShouldAlmostEqual, math.Log(math.Exp(1)+math.Exp(2)+math.Exp(3)))
+	})
+
+	Convey("LogSumExp shouldn't overflow", t, func() {
+		So(LogSumExp([]float64{1000, 1000}), ShouldAlmostEqual, 1000+math.Log(2))
+	})
+
+	Convey("LogSumExp of impossible values should be -Inf", t, func() {
+		So(math.IsInf(LogSumExp([]float64{math.Inf(-1)}), -1), ShouldBeTrue)
+	})
+}